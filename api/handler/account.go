@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+const accountExportZipFormat = "zip"
+
+type accountHandler struct {
+	i              *do.Injector
+	accountService domain.AccountService
+}
+
+func NewAccountHandler(i *do.Injector) (domain.AccountHandler, error) {
+	accountService, err := do.Invoke[domain.AccountService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountHandler{
+		i:              i,
+		accountService: accountService,
+	}, nil
+}
+
+// Export answers with a single JSON document, or with a ZIP archive holding
+// one JSON file per section when called with format=zip.
+func (a *accountHandler) Export(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "account"),
+		slog.String("func", "Export"),
+	)
+
+	log.Info("Initializing export account process")
+
+	format := ctx.QueryParam("format")
+	if format != "" && format != "json" && format != accountExportZipFormat {
+		log.Warn("Invalid export format", slog.String("format", format))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "The format must be json or zip.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	export, err := a.accountService.Export(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("Unauthorized attempt to export account", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to export account", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	if format != accountExportZipFormat {
+		log.Info("Export account process executed successfully")
+		return ctx.JSON(http.StatusOK, export)
+	}
+
+	archive, err := zipAccountExport(export)
+	if err != nil {
+		log.Error("Failed to build export archive", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="account-export.zip"`)
+
+	log.Info("Export account process executed successfully")
+	return ctx.Blob(http.StatusOK, "application/zip", archive)
+}
+
+func (a *accountHandler) Close(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "account"),
+		slog.String("func", "Close"),
+	)
+
+	log.Info("Initializing close account process")
+
+	var payload domain.CloseAccountPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if err := a.accountService.Close(ctx.Request().Context(), &payload); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("Unauthorized attempt to close account", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrInvalidPassword) {
+			log.Warn("Invalid password", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized credentials", "The password is invalid.")
+			return ctx.JSON(http.StatusUnauthorized, apiError)
+		}
+
+		if errors.Is(err, domain.ErrAccountHasBalance) {
+			log.Warn("Account still has balance", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "Withdraw or transfer the balance of every wallet before closing the account.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		if errors.Is(err, domain.ErrAccountHasOpenFunds) {
+			log.Warn("Account has open holds or escrows", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "Wait for every hold and escrow of the account to settle before closing it.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		log.Error("Failed to close account", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Close account process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// zipAccountExport writes each section of export to its own JSON file.
+func zipAccountExport(export *domain.AccountExport) ([]byte, error) {
+	sections := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"wallets.json", export.Wallets},
+		{"transfers.json", export.Transfers},
+		{"sessions.json", export.Sessions},
+	}
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	for _, section := range sections {
+		file, err := writer.CreateHeader(&zip.FileHeader{
+			Name:     section.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := jsoniter.MarshalIndent(section.data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := file.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
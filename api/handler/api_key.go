@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type apiKeyHandler struct {
+	i             *do.Injector
+	apiKeyService domain.APIKeyService
+}
+
+func NewAPIKeyHandler(i *do.Injector) (domain.APIKeyHandler, error) {
+	apiKeyService, err := do.Invoke[domain.APIKeyService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKeyHandler{
+		i:             i,
+		apiKeyService: apiKeyService,
+	}, nil
+}
+
+func (a *apiKeyHandler) Create(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "apiKey"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create api key process")
+
+	var payload domain.APIKeyPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := a.apiKeyService.Create(ctx.Request().Context(), &payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to create api key", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			log.Warn("Wallet not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Wallet not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		if errors.Is(err, domain.ErrAPIKeyMerchantOnly) {
+			log.Warn("Api key requested for a non merchant wallet", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "Api keys can only be issued for merchant wallets.")
+			return ctx.JSON(http.StatusForbidden, apiError)
+		}
+
+		log.Error("Failed to create api key", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Create api key process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (a *apiKeyHandler) GetAll(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "apiKey"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get api keys process")
+
+	response, err := a.apiKeyService.GetAll(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to get api keys", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to get api keys", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Get api keys process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (a *apiKeyHandler) Revoke(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "apiKey"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing revoke api key process")
+
+	apiKeyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid api key id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid api key id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if err := a.apiKeyService.Revoke(ctx.Request().Context(), apiKeyID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to revoke api key", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			log.Warn("Api key to revoke not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Api key not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		log.Error("Failed to revoke api key", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Revoke api key process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type campaignHandler struct {
+	i               *do.Injector
+	campaignService domain.CampaignService
+}
+
+func NewCampaignHandler(i *do.Injector) (domain.CampaignHandler, error) {
+	campaignService, err := do.Invoke[domain.CampaignService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignHandler{
+		i:               i,
+		campaignService: campaignService,
+	}, nil
+}
+
+func (c *campaignHandler) Create(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "campaign"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create campaign process")
+
+	var payload domain.CampaignPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := c.campaignService.Create(ctx.Request().Context(), &payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrFundingWalletNotFound) {
+			log.Warn("Campaign funding wallet not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Funding wallet not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		if errors.Is(err, domain.ErrCampaignMerchantInvalid) {
+			log.Warn("Campaign merchant is not a merchant", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Every campaign merchant must have a merchant wallet.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		log.Error("Failed to create campaign", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Create campaign process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (c *campaignHandler) GetAll(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "campaign"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get campaigns process")
+
+	response, err := c.campaignService.GetAll(ctx.Request().Context())
+	if err != nil {
+		log.Error("Failed to get campaigns", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Get campaigns process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *campaignHandler) GetRewards(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "campaign"),
+		slog.String("func", "GetRewards"),
+	)
+
+	log.Info("Initializing get rewards process")
+
+	response, err := c.campaignService.GetRewards(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to get rewards", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to get rewards", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Get rewards process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type contactHandler struct {
+	i              *do.Injector
+	contactService domain.ContactService
+}
+
+func NewContactHandler(i *do.Injector) (domain.ContactHandler, error) {
+	contactService, err := do.Invoke[domain.ContactService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contactHandler{
+		i:              i,
+		contactService: contactService,
+	}, nil
+}
+
+func (c *contactHandler) GetAll(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "contact"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get contacts process")
+
+	response, err := c.contactService.GetAll(ctx.Request().Context())
+	if err != nil {
+		return c.handleError(ctx, log, err)
+	}
+
+	log.Info("Get contacts process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *contactHandler) Create(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "contact"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing contact creation process")
+
+	var payload domain.ContactPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := c.contactService.Create(ctx.Request().Context(), &payload)
+	if err != nil {
+		return c.handleError(ctx, log, err)
+	}
+
+	log.Info("Contact creation process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (c *contactHandler) Update(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "contact"),
+		slog.String("func", "Update"),
+	)
+
+	log.Info("Initializing update contact process")
+
+	contactID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid contact id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid contact id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.UpdateContactPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := c.contactService.Update(ctx.Request().Context(), contactID, &payload)
+	if err != nil {
+		return c.handleError(ctx, log, err)
+	}
+
+	log.Info("Update contact process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *contactHandler) Delete(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "contact"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing delete contact process")
+
+	contactID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid contact id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid contact id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if err := c.contactService.Delete(ctx.Request().Context(), contactID); err != nil {
+		return c.handleError(ctx, log, err)
+	}
+
+	log.Info("Delete contact process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (c *contactHandler) GetRecentPayees(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "contact"),
+		slog.String("func", "GetRecentPayees"),
+	)
+
+	log.Info("Initializing get recent payees process")
+
+	query, validationErrors := domain.NewRecentPayeesQuery(ctx.QueryParam("limit"))
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := c.contactService.GetRecentPayees(ctx.Request().Context(), query)
+	if err != nil {
+		return c.handleError(ctx, log, err)
+	}
+
+	log.Info("Get recent payees process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *contactHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		log.Warn("Unauthorized attempt to operate contacts", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+	}
+
+	if errors.Is(err, domain.ErrContactNotFound) {
+		log.Warn("Contact not found", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Contact not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrContactUserNotFound) {
+		log.Warn("No user found for the contact lookup", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "No user found with this document or email.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrSelfContactNotAllowed) {
+		log.Warn("Self contact attempt", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "You cannot add yourself as a contact.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if errors.Is(err, domain.ErrContactAlreadyExists) {
+		log.Warn("Contact already exists", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "This user is already one of your contacts.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	log.Error("Failed to process contacts", slog.String("error", err.Error()))
+	return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+}
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type disputeHandler struct {
+	i              *do.Injector
+	disputeService domain.DisputeService
+}
+
+func NewDisputeHandler(i *do.Injector) (domain.DisputeHandler, error) {
+	disputeService, err := do.Invoke[domain.DisputeService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &disputeHandler{
+		i:              i,
+		disputeService: disputeService,
+	}, nil
+}
+
+func (d *disputeHandler) Open(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "dispute"),
+		slog.String("func", "Open"),
+	)
+
+	log.Info("Initializing open dispute process")
+
+	var payload domain.OpenDisputePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := d.disputeService.Open(ctx.Request().Context(), &payload)
+	if err != nil {
+		return d.handleError(ctx, log, err)
+	}
+
+	log.Info("Open dispute process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (d *disputeHandler) GetByID(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "dispute"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get dispute process")
+
+	disputeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid dispute id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid dispute id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	response, err := d.disputeService.GetByID(ctx.Request().Context(), disputeID)
+	if err != nil {
+		return d.handleError(ctx, log, err)
+	}
+
+	log.Info("Get dispute process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (d *disputeHandler) Respond(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "dispute"),
+		slog.String("func", "Respond"),
+	)
+
+	log.Info("Initializing respond dispute process")
+
+	disputeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid dispute id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid dispute id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.RespondDisputePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := d.disputeService.Respond(ctx.Request().Context(), disputeID, &payload)
+	if err != nil {
+		return d.handleError(ctx, log, err)
+	}
+
+	log.Info("Respond dispute process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (d *disputeHandler) UploadEvidence(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "dispute"),
+		slog.String("func", "UploadEvidence"),
+	)
+
+	log.Info("Initializing upload evidence process")
+
+	disputeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid dispute id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid dispute id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		log.Warn("Failed to read uploaded file", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(map[string]string{"file": "This field is required"})
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error("Failed to open uploaded file", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error("Failed to close uploaded file", slog.String("error", err.Error()))
+		}
+	}()
+
+	contentType := fileHeader.Header.Get(echo.HeaderContentType)
+	if contentType == "" {
+		contentType = echo.MIMEOctetStream
+	}
+
+	upload := &domain.EvidenceUpload{
+		FileName:    fileHeader.Filename,
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+		Content:     file,
+	}
+
+	response, err := d.disputeService.UploadEvidence(ctx.Request().Context(), disputeID, upload)
+	if err != nil {
+		return d.handleError(ctx, log, err)
+	}
+
+	log.Info("Upload evidence process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (d *disputeHandler) DownloadEvidence(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "dispute"),
+		slog.String("func", "DownloadEvidence"),
+	)
+
+	log.Info("Initializing download evidence process")
+
+	disputeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid dispute id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid dispute id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	evidenceID, err := uuid.Parse(ctx.Param("evidenceId"))
+	if err != nil {
+		log.Warn("Invalid evidence id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid evidence id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	evidence, content, err := d.disputeService.DownloadEvidence(ctx.Request().Context(), disputeID, evidenceID)
+	if err != nil {
+		return d.handleError(ctx, log, err)
+	}
+	defer func() {
+		if err := content.Close(); err != nil {
+			log.Error("Failed to close evidence content", slog.String("error", err.Error()))
+		}
+	}()
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", evidence.FileName))
+
+	log.Info("Download evidence process executed successfully")
+	return ctx.Stream(http.StatusOK, evidence.ContentType, content)
+}
+
+func (d *disputeHandler) Resolve(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "dispute"),
+		slog.String("func", "Resolve"),
+	)
+
+	log.Info("Initializing resolve dispute process")
+
+	disputeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid dispute id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid dispute id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.ResolveDisputePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := d.disputeService.Resolve(ctx.Request().Context(), disputeID, &payload)
+	if err != nil {
+		return d.handleError(ctx, log, err)
+	}
+
+	log.Info("Resolve dispute process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (d *disputeHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		log.Warn("Unauthorized attempt to operate dispute", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+	}
+
+	if errors.Is(err, domain.ErrTransferNotFound) {
+		log.Warn("Transfer not found", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Transfer not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrDisputeNotFound) {
+		log.Warn("Dispute not found", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Dispute not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrEvidenceNotFound) {
+		log.Warn("Evidence not found", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Evidence not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrDisputeForbidden) {
+		log.Warn("Dispute operation forbidden", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "You are not allowed to perform this operation on the dispute.")
+		return ctx.JSON(http.StatusForbidden, apiError)
+	}
+
+	if errors.Is(err, domain.ErrDisputeNotAllowed) {
+		log.Warn("Transfer cannot be disputed", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "This transfer cannot be disputed.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if errors.Is(err, domain.ErrDisputeAlreadyExists) {
+		log.Warn("Dispute already exists", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "There is already a dispute for this transfer.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrDisputeAlreadyResolved) || errors.Is(err, domain.ErrDisputeInvalidTransition) {
+		log.Warn("Dispute status does not allow this operation", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The dispute status does not allow this operation.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrEvidenceTooLarge) {
+		log.Warn("Evidence file too large", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusRequestEntityTooLarge, "Payload Too Large", "The evidence file exceeds the maximum allowed size.")
+		return ctx.JSON(http.StatusRequestEntityTooLarge, apiError)
+	}
+
+	log.Error("Failed to process dispute", slog.String("error", err.Error()))
+	return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+}
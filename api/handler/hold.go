@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type holdHandler struct {
+	i           *do.Injector
+	holdService domain.HoldService
+}
+
+func NewHoldHandler(i *do.Injector) (domain.HoldHandler, error) {
+	holdService, err := do.Invoke[domain.HoldService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &holdHandler{
+		i:           i,
+		holdService: holdService,
+	}, nil
+}
+
+func (h *holdHandler) Create(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "hold"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create hold process")
+
+	var payload domain.HoldPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := h.holdService.Create(ctx.Request().Context(), &payload)
+	if err != nil {
+		return h.handleError(ctx, log, err)
+	}
+
+	log.Info("Create hold process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (h *holdHandler) GetByID(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "hold"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get hold process")
+
+	holdID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid hold id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid hold id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	response, err := h.holdService.GetByID(ctx.Request().Context(), holdID)
+	if err != nil {
+		return h.handleError(ctx, log, err)
+	}
+
+	log.Info("Get hold process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (h *holdHandler) Capture(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "hold"),
+		slog.String("func", "Capture"),
+	)
+
+	log.Info("Initializing capture hold process")
+
+	holdID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid hold id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid hold id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.CaptureHoldPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := h.holdService.Capture(ctx.Request().Context(), holdID, &payload)
+	if err != nil {
+		return h.handleError(ctx, log, err)
+	}
+
+	log.Info("Capture hold process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (h *holdHandler) Void(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "hold"),
+		slog.String("func", "Void"),
+	)
+
+	log.Info("Initializing void hold process")
+
+	holdID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid hold id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid hold id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	response, err := h.holdService.Void(ctx.Request().Context(), holdID)
+	if err != nil {
+		return h.handleError(ctx, log, err)
+	}
+
+	log.Info("Void hold process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (h *holdHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		log.Warn("Unauthorized attempt to operate hold", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+	}
+
+	if errors.Is(err, domain.ErrAPIKeyWalletMismatch) {
+		log.Warn("Api key used for another wallet", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.APIKeyWalletAPIError)
+	}
+
+	if errors.Is(err, domain.ErrSelfTransactionNotAllowed) {
+		log.Warn("Hold failed due to self-hold attempt", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "You cannot create a hold in favour of yourself.")
+		return ctx.JSON(http.StatusForbidden, apiError)
+	}
+
+	if errors.Is(err, domain.ErrEmailNotVerified) {
+		log.Warn("Email verification pending", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.EmailNotVerifiedAPIError)
+	}
+
+	if apiError := pinAPIError(ctx, err); apiError != nil {
+		log.Warn("Hold pin check failed", slog.String("error", err.Error()))
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if errors.Is(err, domain.ErrPayerWalletNotFound) {
+		log.Warn("Hold failed due to missing payer wallet", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Payer wallet not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrPayeeWalletNotFound) {
+		log.Warn("Hold failed due to missing payee wallet", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Payee wallet not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrTransferNotAllowedForWalletType) {
+		log.Warn("Hold failed due to wallet type restriction", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "Holds are not allowed for this wallet type.")
+		return ctx.JSON(http.StatusForbidden, apiError)
+	}
+
+	if errors.Is(err, domain.ErrHoldPayeeNotMerchant) {
+		log.Warn("Hold failed due to payee wallet type", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Holds can only be created in favour of merchants.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if errors.Is(err, domain.ErrInsufficientBalance) {
+		log.Warn("Hold failed due to insufficient balance", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Insufficient available balance for the operation.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if errors.Is(err, domain.ErrTransferNotAuthorized) || errors.Is(err, client.ErrCheckAuthorization) {
+		log.Warn("Hold failed due to authorization error", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized", "Hold not authorized.")
+		return ctx.JSON(http.StatusUnauthorized, apiError)
+	}
+
+	if errors.Is(err, domain.ErrHoldNotFound) {
+		log.Warn("Hold not found", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Hold not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrHoldForbidden) {
+		log.Warn("Hold operation forbidden", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "You are not allowed to perform this operation on the hold.")
+		return ctx.JSON(http.StatusForbidden, apiError)
+	}
+
+	if errors.Is(err, domain.ErrHoldNotActive) || errors.Is(err, domain.ErrHoldExpired) {
+		log.Warn("Hold is no longer active", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The hold is no longer active.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCTransferLimitExceeded) {
+		log.Warn("Hold over the kyc limit", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusForbidden, "Limit Exceeded", "The value exceeds the transfer limit of your verification level. Upgrade it to send more.")
+		return ctx.JSON(http.StatusForbidden, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCBalanceLimitExceeded) {
+		log.Warn("Payee balance over the kyc limit", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusUnprocessableEntity, "Limit Exceeded", "The payee cannot receive this value at their verification level.")
+		return ctx.JSON(http.StatusUnprocessableEntity, apiError)
+	}
+
+	if errors.Is(err, domain.ErrHoldCaptureExceedsAmount) {
+		log.Warn("Capture exceeds hold amount", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "The capture value exceeds the remaining hold amount.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	log.Error("Failed to process hold", slog.String("error", err.Error()))
+	return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/keyring"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type keyHandler struct {
+	i       *do.Injector
+	keyring keyring.Keyring
+}
+
+func NewKeyHandler(i *do.Injector) (domain.KeyHandler, error) {
+	keys, err := do.Invoke[keyring.Keyring](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyHandler{
+		i:       i,
+		keyring: keys,
+	}, nil
+}
+
+// JWKS publishes the public keys that verify our access tokens, the active
+// one first.
+func (k *keyHandler) JWKS(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "key"),
+		slog.String("func", "JWKS"),
+	)
+
+	set, err := k.keyring.JWKS()
+	if err != nil {
+		log.Error("Failed to get JWKS", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return ctx.JSON(http.StatusOK, set)
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+// kycDocumentKinds are the multipart fields read by Submit, one file each.
+var kycDocumentKinds = []domain.KYCDocumentKind{
+	domain.KYCDocumentIdentity,
+	domain.KYCDocumentSelfie,
+	domain.KYCDocumentProofOfAddress,
+}
+
+type kycHandler struct {
+	i          *do.Injector
+	kycService domain.KYCService
+}
+
+func NewKYCHandler(i *do.Injector) (domain.KYCHandler, error) {
+	kycService, err := do.Invoke[domain.KYCService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kycHandler{
+		i:          i,
+		kycService: kycService,
+	}, nil
+}
+
+func (k *kycHandler) GetStatus(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "kyc"),
+		slog.String("func", "GetStatus"),
+	)
+
+	log.Info("Initializing get kyc status process")
+
+	response, err := k.kycService.GetStatus(ctx.Request().Context())
+	if err != nil {
+		return k.handleError(ctx, log, err)
+	}
+
+	log.Info("Get kyc status process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Submit reads the documents from the multipart fields named after their
+// kind, such as identity and selfie.
+func (k *kycHandler) Submit(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "kyc"),
+		slog.String("func", "Submit"),
+	)
+
+	log.Info("Initializing kyc submission process")
+
+	var uploads []*domain.KYCDocumentUpload
+	for _, kind := range kycDocumentKinds {
+		fileHeader, err := ctx.FormFile(string(kind))
+		if err != nil {
+			continue
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Error("Failed to open uploaded file", slog.String("kind", string(kind)), slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+		}
+		defer func(file multipart.File) {
+			if err := file.Close(); err != nil {
+				log.Error("Failed to close uploaded file", slog.String("error", err.Error()))
+			}
+		}(file)
+
+		contentType := fileHeader.Header.Get(echo.HeaderContentType)
+		if contentType == "" {
+			contentType = echo.MIMEOctetStream
+		}
+
+		uploads = append(uploads, &domain.KYCDocumentUpload{
+			Kind:        kind,
+			FileName:    fileHeader.Filename,
+			ContentType: contentType,
+			Size:        fileHeader.Size,
+			Content:     file,
+		})
+	}
+
+	response, err := k.kycService.Submit(ctx.Request().Context(), uploads)
+	if err != nil {
+		return k.handleError(ctx, log, err)
+	}
+
+	log.Info("Kyc submission process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (k *kycHandler) GetPendingSubmissions(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "kyc"),
+		slog.String("func", "GetPendingSubmissions"),
+	)
+
+	log.Info("Initializing get pending kyc submissions process")
+
+	response, err := k.kycService.GetPendingSubmissions(ctx.Request().Context())
+	if err != nil {
+		return k.handleError(ctx, log, err)
+	}
+
+	log.Info("Get pending kyc submissions process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (k *kycHandler) DownloadDocument(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "kyc"),
+		slog.String("func", "DownloadDocument"),
+	)
+
+	log.Info("Initializing download kyc document process")
+
+	submissionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid kyc submission id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid kyc submission id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	documentID, err := uuid.Parse(ctx.Param("documentId"))
+	if err != nil {
+		log.Warn("Invalid kyc document id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid kyc document id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	document, content, err := k.kycService.DownloadDocument(ctx.Request().Context(), submissionID, documentID)
+	if err != nil {
+		return k.handleError(ctx, log, err)
+	}
+	defer func() {
+		if err := content.Close(); err != nil {
+			log.Error("Failed to close kyc document content", slog.String("error", err.Error()))
+		}
+	}()
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", document.FileName))
+
+	log.Info("Download kyc document process executed successfully")
+	return ctx.Stream(http.StatusOK, document.ContentType, content)
+}
+
+func (k *kycHandler) Review(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "kyc"),
+		slog.String("func", "Review"),
+	)
+
+	log.Info("Initializing review kyc submission process")
+
+	submissionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid kyc submission id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid kyc submission id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.ReviewKYCPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := k.kycService.Review(ctx.Request().Context(), submissionID, &payload)
+	if err != nil {
+		return k.handleError(ctx, log, err)
+	}
+
+	log.Info("Review kyc submission process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (k *kycHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+		log.Warn("Unauthorized attempt to operate kyc", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+	}
+
+	if errors.Is(err, domain.ErrEmailNotVerified) {
+		log.Warn("Email verification pending", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.EmailNotVerifiedAPIError)
+	}
+
+	if errors.Is(err, domain.ErrKYCSubmissionNotFound) || errors.Is(err, domain.ErrKYCDocumentNotFound) {
+		log.Warn("Kyc submission or document not found", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Kyc submission or document not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCSelfReview) {
+		log.Warn("Kyc self review attempt", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "You cannot review your own kyc submission.")
+		return ctx.JSON(http.StatusForbidden, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCMaxTier) {
+		log.Warn("User already has the highest kyc tier", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "Your account already has the highest verification level.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCSubmissionPending) {
+		log.Warn("Kyc submission already under review", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "There is already a verification under review.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCSubmissionReviewed) {
+		log.Warn("Kyc submission already reviewed", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The kyc submission was already reviewed.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCDocumentMissing) {
+		log.Warn("Kyc document missing", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Send every document required for the next verification level.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCDocumentType) {
+		log.Warn("Kyc document has an unsupported type", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusUnsupportedMediaType, "Unsupported Media Type", "Documents must be jpeg, png or pdf files.")
+		return ctx.JSON(http.StatusUnsupportedMediaType, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCRejectionReasonMissing) {
+		log.Warn("Kyc rejection without reason", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(map[string]string{"reason": "A reason is required to reject a submission"})
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCDocumentTooLarge) {
+		log.Warn("Kyc document too large", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusRequestEntityTooLarge, "Payload Too Large", "The document exceeds the maximum allowed size.")
+		return ctx.JSON(http.StatusRequestEntityTooLarge, apiError)
+	}
+
+	log.Error("Failed to process kyc", slog.String("error", err.Error()))
+	return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+}
@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type oauthHandler struct {
+	i            *do.Injector
+	oauthService domain.OAuthService
+}
+
+func NewOAuthHandler(i *do.Injector) (domain.OAuthHandler, error) {
+	oauthService, err := do.Invoke[domain.OAuthService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauthHandler{
+		i:            i,
+		oauthService: oauthService,
+	}, nil
+}
+
+func (o *oauthHandler) CreateClient(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "CreateClient"),
+	)
+
+	log.Info("Initializing create oauth client process")
+
+	var payload domain.OAuthClientPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := o.oauthService.CreateClient(ctx.Request().Context(), &payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to create oauth client", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to create oauth client", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Create oauth client process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (o *oauthHandler) GetClients(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "GetClients"),
+	)
+
+	log.Info("Initializing get oauth clients process")
+
+	response, err := o.oauthService.GetClients(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to get oauth clients", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to get oauth clients", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Get oauth clients process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (o *oauthHandler) DeleteClient(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "DeleteClient"),
+	)
+
+	log.Info("Initializing delete oauth client process")
+
+	clientID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid client id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid client id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if err := o.oauthService.DeleteClient(ctx.Request().Context(), clientID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to delete oauth client", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrOAuthClientNotFound) {
+			log.Warn("Oauth client to delete not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Oauth client not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		log.Error("Failed to delete oauth client", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Delete oauth client process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (o *oauthHandler) GetConsent(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "GetConsent"),
+	)
+
+	log.Info("Initializing get oauth consent process")
+
+	request := &domain.OAuthAuthorizeRequest{
+		ResponseType:        ctx.QueryParam("response_type"),
+		ClientID:            ctx.QueryParam("client_id"),
+		RedirectURI:         ctx.QueryParam("redirect_uri"),
+		Scope:               ctx.QueryParam("scope"),
+		State:               ctx.QueryParam("state"),
+		CodeChallenge:       ctx.QueryParam("code_challenge"),
+		CodeChallengeMethod: ctx.QueryParam("code_challenge_method"),
+	}
+	request.Trim()
+
+	response, err := o.oauthService.GetConsent(ctx.Request().Context(), request)
+	if err != nil {
+		return o.handleAuthorizeError(ctx, log, err)
+	}
+
+	log.Info("Get oauth consent process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (o *oauthHandler) Authorize(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "Authorize"),
+	)
+
+	log.Info("Initializing oauth authorize process")
+
+	var request domain.OAuthAuthorizeRequest
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&request); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+	request.Trim()
+
+	response, err := o.oauthService.Authorize(ctx.Request().Context(), &request)
+	if err != nil {
+		return o.handleAuthorizeError(ctx, log, err)
+	}
+
+	log.Info("Oauth authorize process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Token is the OAuth token endpoint. Like the introspection and revocation
+// endpoints it takes a form body and answers with the OAuth error format.
+func (o *oauthHandler) Token(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "Token"),
+	)
+
+	log.Info("Initializing oauth token process")
+
+	request := &domain.OAuthTokenRequest{
+		OAuthClientCredentials: o.clientCredentials(ctx),
+		GrantType:              ctx.FormValue("grant_type"),
+		Code:                   ctx.FormValue("code"),
+		RedirectURI:            ctx.FormValue("redirect_uri"),
+		CodeVerifier:           ctx.FormValue("code_verifier"),
+		RefreshToken:           ctx.FormValue("refresh_token"),
+		Scope:                  ctx.FormValue("scope"),
+	}
+
+	response, err := o.oauthService.Token(ctx.Request().Context(), request)
+	if err != nil {
+		return o.handleTokenError(ctx, log, err)
+	}
+
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	ctx.Response().Header().Set("Pragma", "no-cache")
+
+	log.Info("Oauth token process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (o *oauthHandler) Introspect(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "Introspect"),
+	)
+
+	log.Info("Initializing oauth introspect process")
+
+	credentials := o.clientCredentials(ctx)
+	token := ctx.FormValue("token")
+	if token == "" {
+		return o.handleTokenError(ctx, log, domain.ErrOAuthInvalidRequest)
+	}
+
+	response, err := o.oauthService.Introspect(ctx.Request().Context(), &credentials, token)
+	if err != nil {
+		return o.handleTokenError(ctx, log, err)
+	}
+
+	log.Info("Oauth introspect process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (o *oauthHandler) Revoke(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing oauth revoke process")
+
+	credentials := o.clientCredentials(ctx)
+	token := ctx.FormValue("token")
+	if token == "" {
+		return o.handleTokenError(ctx, log, domain.ErrOAuthInvalidRequest)
+	}
+
+	if err := o.oauthService.Revoke(ctx.Request().Context(), &credentials, token); err != nil {
+		return o.handleTokenError(ctx, log, err)
+	}
+
+	log.Info("Oauth revoke process executed successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (o *oauthHandler) UserInfo(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "oauth"),
+		slog.String("func", "UserInfo"),
+	)
+
+	response, err := o.oauthService.UserInfo(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to get user info", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to get user info", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// clientCredentials reads HTTP Basic client authentication, falling back to
+// client_id and client_secret in the form body.
+func (o *oauthHandler) clientCredentials(ctx echo.Context) domain.OAuthClientCredentials {
+	if clientID, secret, ok := ctx.Request().BasicAuth(); ok {
+		return domain.OAuthClientCredentials{ClientID: clientID, Secret: secret}
+	}
+
+	return domain.OAuthClientCredentials{
+		ClientID: ctx.FormValue("client_id"),
+		Secret:   ctx.FormValue("client_secret"),
+	}
+}
+
+func (o *oauthHandler) handleAuthorizeError(ctx echo.Context, log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		log.Warn("Unauthorized attempt to authorize oauth client", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+	}
+
+	var oauthError *domain.OAuthError
+	if errors.As(err, &oauthError) {
+		log.Warn("Authorization request rejected", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, oauthError)
+	}
+
+	log.Error("Failed to authorize oauth client", slog.String("error", err.Error()))
+	return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+}
+
+func (o *oauthHandler) handleTokenError(ctx echo.Context, log *slog.Logger, err error) error {
+	var oauthError *domain.OAuthError
+	if !errors.As(err, &oauthError) {
+		log.Error("Oauth token endpoint failed", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Warn("Oauth token request rejected", slog.String("error", err.Error()))
+	if errors.Is(err, domain.ErrOAuthInvalidClient) {
+		ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		return ctx.JSON(http.StatusUnauthorized, oauthError)
+	}
+
+	return ctx.JSON(http.StatusBadRequest, oauthError)
+}
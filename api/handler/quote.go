@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type quoteHandler struct {
+	i            *do.Injector
+	quoteService domain.QuoteService
+}
+
+func NewQuoteHandler(i *do.Injector) (domain.QuoteHandler, error) {
+	quoteService, err := do.Invoke[domain.QuoteService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quoteHandler{
+		i:            i,
+		quoteService: quoteService,
+	}, nil
+}
+
+func (q *quoteHandler) Create(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "quote"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create quote process")
+
+	var payload domain.QuotePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := q.quoteService.Create(ctx.Request().Context(), &payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to create quote", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrUnsupportedCurrency) {
+			log.Warn("Quote requested for unsupported currency", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "No exchange rate is available for this currency pair.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		log.Error("Failed to create quote", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Create quote process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
@@ -8,9 +8,24 @@ import (
 )
 
 func SetupRoutes(e *echo.Echo, i *do.Injector) {
+	e.Use(middleware.RequestID())
+
 	setupUserRoutes(e, i)
+	setupAccountRoutes(e, i)
+	setupSessionRoutes(e, i)
+	setupKeyRoutes(e, i)
+	setupTwoFactorRoutes(e, i)
+	setupTransactionPINRoutes(e, i)
 	setupWalletRoutes(e, i)
 	setupTransferRoutes(e, i)
+	setupQuoteRoutes(e, i)
+	setupHoldRoutes(e, i)
+	setupDisputeRoutes(e, i)
+	setupKYCRoutes(e, i)
+	setupContactRoutes(e, i)
+	setupCampaignRoutes(e, i)
+	setupAPIKeyRoutes(e, i)
+	setupOAuthRoutes(e, i)
 }
 
 func setupUserRoutes(e *echo.Echo, i *do.Injector) {
@@ -22,6 +37,74 @@ func setupUserRoutes(e *echo.Echo, i *do.Injector) {
 	group := e.Group("/v1/users")
 	group.POST("", userHandler.Create)
 	group.POST("/sign-in", userHandler.SignIn)
+	group.POST("/sign-in/2fa", userHandler.SignInTwoFactor)
+	group.POST("/refresh", userHandler.Refresh)
+	group.POST("/password/forgot", userHandler.ForgotPassword)
+	group.POST("/password/reset", userHandler.ResetPassword)
+	group.POST("/password/change", userHandler.ChangePassword, middleware.CheckLoggedIn(i))
+	group.POST("/email/verify", userHandler.VerifyEmail)
+	group.POST("/email/verify/resend", userHandler.ResendEmailVerification, middleware.CheckLoggedIn(i))
+	group.GET("/me", userHandler.GetProfile, middleware.CheckLoggedIn(i))
+	group.PATCH("/me", userHandler.UpdateProfile, middleware.CheckLoggedIn(i))
+	group.POST("/:id/unlock", userHandler.Unlock, middleware.CheckLoggedIn(i), middleware.RequirePermission(domain.PermissionUsersUnlock))
+	group.PUT("/:id/role", userHandler.UpdateRole, middleware.CheckLoggedIn(i), middleware.RequirePermission(domain.PermissionUsersRoles))
+}
+
+func setupAccountRoutes(e *echo.Echo, i *do.Injector) {
+	accountHandler, err := do.Invoke[domain.AccountHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/users/me", middleware.CheckLoggedIn(i))
+	group.GET("/export", accountHandler.Export)
+	group.DELETE("", accountHandler.Close)
+}
+
+func setupSessionRoutes(e *echo.Echo, i *do.Injector) {
+	sessionHandler, err := do.Invoke[domain.SessionHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	e.POST("/v1/users/sign-out", sessionHandler.SignOut, middleware.CheckLoggedIn(i))
+
+	group := e.Group("v1/sessions", middleware.CheckLoggedIn(i))
+	group.GET("", sessionHandler.GetAll)
+	group.DELETE("/:id", sessionHandler.Delete)
+}
+
+func setupTwoFactorRoutes(e *echo.Echo, i *do.Injector) {
+	twoFactorHandler, err := do.Invoke[domain.TwoFactorHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/users/2fa", middleware.CheckLoggedIn(i))
+	group.POST("/enroll", twoFactorHandler.Enroll)
+	group.POST("/activate", twoFactorHandler.Activate)
+	group.POST("/disable", twoFactorHandler.Disable)
+}
+
+func setupTransactionPINRoutes(e *echo.Echo, i *do.Injector) {
+	transactionPINHandler, err := do.Invoke[domain.TransactionPINHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/users/pin", middleware.CheckLoggedIn(i))
+	group.POST("", transactionPINHandler.Set)
+	group.PUT("", transactionPINHandler.Change)
+	group.POST("/reset", transactionPINHandler.Reset)
+}
+
+func setupKeyRoutes(e *echo.Echo, i *do.Injector) {
+	keyHandler, err := do.Invoke[domain.KeyHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	e.GET("/.well-known/jwks.json", keyHandler.JWKS)
 }
 
 func setupWalletRoutes(e *echo.Echo, i *do.Injector) {
@@ -30,8 +113,10 @@ func setupWalletRoutes(e *echo.Echo, i *do.Injector) {
 		panic(err)
 	}
 
-	group := e.Group("v1/wallets", middleware.CheckLoggedIn(i))
-	group.POST("", walletHandler.Create)
+	group := e.Group("v1/wallets")
+	group.POST("", walletHandler.Create, middleware.CheckLoggedIn(i))
+	group.GET("", walletHandler.GetAll, middleware.CheckOAuthOr(i, domain.OAuthScopeWalletsRead, middleware.CheckLoggedIn(i)))
+	group.GET("/:currency/statement", walletHandler.GetStatement, middleware.CheckOAuthOr(i, domain.OAuthScopeStatementsRead, middleware.CheckAPIKeyOrLoggedIn(i, domain.APIKeyScopeStatementsRead)))
 }
 
 func setupTransferRoutes(e *echo.Echo, i *do.Injector) {
@@ -40,6 +125,125 @@ func setupTransferRoutes(e *echo.Echo, i *do.Injector) {
 		panic(err)
 	}
 
+	e.POST("v1/transfers", transferHandler.Transfer, middleware.CheckOAuthOr(i, domain.OAuthScopeTransfersWrite, middleware.CheckLoggedIn(i)))
+
 	group := e.Group("v1/transfers", middleware.CheckLoggedIn(i))
-	group.POST("", transferHandler.Transfer)
+	group.POST("/:id/confirm", transferHandler.ConfirmEscrow)
+	group.POST("/:id/dispute", transferHandler.DisputeEscrow)
+	group.POST("/:id/resolve", transferHandler.ResolveEscrow, middleware.RequirePermission(domain.PermissionEscrowsResolve))
+
+	e.POST("v1/transfers/:id/refund", transferHandler.Refund, middleware.CheckAPIKeyOrLoggedIn(i, domain.APIKeyScopeRefundsWrite))
+}
+
+func setupQuoteRoutes(e *echo.Echo, i *do.Injector) {
+	quoteHandler, err := do.Invoke[domain.QuoteHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/quotes", middleware.CheckLoggedIn(i))
+	group.POST("", quoteHandler.Create)
+}
+
+func setupHoldRoutes(e *echo.Echo, i *do.Injector) {
+	holdHandler, err := do.Invoke[domain.HoldHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/holds", middleware.CheckLoggedIn(i))
+	group.POST("", holdHandler.Create)
+	group.GET("/:id", holdHandler.GetByID)
+	group.POST("/:id/void", holdHandler.Void)
+
+	e.POST("v1/holds/:id/capture", holdHandler.Capture, middleware.CheckAPIKeyOrLoggedIn(i, domain.APIKeyScopeChargesWrite))
+}
+
+func setupDisputeRoutes(e *echo.Echo, i *do.Injector) {
+	disputeHandler, err := do.Invoke[domain.DisputeHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/disputes", middleware.CheckLoggedIn(i))
+	group.POST("", disputeHandler.Open)
+	group.GET("/:id", disputeHandler.GetByID)
+	group.POST("/:id/response", disputeHandler.Respond)
+	group.POST("/:id/evidence", disputeHandler.UploadEvidence)
+	group.GET("/:id/evidence/:evidenceId", disputeHandler.DownloadEvidence)
+	group.POST("/:id/resolve", disputeHandler.Resolve, middleware.RequirePermission(domain.PermissionDisputesResolve))
+}
+
+func setupKYCRoutes(e *echo.Echo, i *do.Injector) {
+	kycHandler, err := do.Invoke[domain.KYCHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/kyc", middleware.CheckLoggedIn(i))
+	group.GET("", kycHandler.GetStatus)
+	group.POST("/submissions", kycHandler.Submit)
+	group.GET("/submissions", kycHandler.GetPendingSubmissions, middleware.RequirePermission(domain.PermissionKYCReview))
+	group.GET("/submissions/:id/documents/:documentId", kycHandler.DownloadDocument, middleware.RequirePermission(domain.PermissionKYCReview))
+	group.POST("/submissions/:id/review", kycHandler.Review, middleware.RequirePermission(domain.PermissionKYCReview))
+}
+
+func setupContactRoutes(e *echo.Echo, i *do.Injector) {
+	contactHandler, err := do.Invoke[domain.ContactHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/contacts", middleware.CheckLoggedIn(i))
+	group.GET("", contactHandler.GetAll)
+	group.POST("", contactHandler.Create)
+	group.GET("/recent", contactHandler.GetRecentPayees)
+	group.PATCH("/:id", contactHandler.Update)
+	group.DELETE("/:id", contactHandler.Delete)
+}
+
+func setupCampaignRoutes(e *echo.Echo, i *do.Injector) {
+	campaignHandler, err := do.Invoke[domain.CampaignHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/campaigns", middleware.CheckLoggedIn(i))
+	group.POST("", campaignHandler.Create, middleware.RequirePermission(domain.PermissionCampaignsManage))
+	group.GET("", campaignHandler.GetAll, middleware.RequirePermission(domain.PermissionCampaignsRead))
+
+	rewards := e.Group("v1/rewards", middleware.CheckLoggedIn(i))
+	rewards.GET("", campaignHandler.GetRewards)
+}
+
+func setupAPIKeyRoutes(e *echo.Echo, i *do.Injector) {
+	apiKeyHandler, err := do.Invoke[domain.APIKeyHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	group := e.Group("v1/api-keys", middleware.CheckLoggedIn(i), middleware.RequirePermission(domain.PermissionAPIKeysManage))
+	group.POST("", apiKeyHandler.Create)
+	group.GET("", apiKeyHandler.GetAll)
+	group.DELETE("/:id", apiKeyHandler.Revoke)
+}
+
+func setupOAuthRoutes(e *echo.Echo, i *do.Injector) {
+	oauthHandler, err := do.Invoke[domain.OAuthHandler](i)
+	if err != nil {
+		panic(err)
+	}
+
+	clients := e.Group("v1/oauth/clients", middleware.CheckLoggedIn(i))
+	clients.POST("", oauthHandler.CreateClient)
+	clients.GET("", oauthHandler.GetClients)
+	clients.DELETE("/:id", oauthHandler.DeleteClient)
+
+	group := e.Group("v1/oauth")
+	group.GET("/authorize", oauthHandler.GetConsent, middleware.CheckLoggedIn(i))
+	group.POST("/authorize", oauthHandler.Authorize, middleware.CheckLoggedIn(i))
+	group.POST("/token", oauthHandler.Token)
+	group.POST("/introspect", oauthHandler.Introspect)
+	group.POST("/revoke", oauthHandler.Revoke)
+	group.GET("/userinfo", oauthHandler.UserInfo, middleware.CheckOAuthOr(i, domain.OAuthScopeProfile, middleware.CheckLoggedIn(i)))
 }
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type sessionHandler struct {
+	i              *do.Injector
+	sessionService domain.SessionService
+}
+
+func NewSessionHandler(i *do.Injector) (domain.SessionHandler, error) {
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionHandler{
+		i:              i,
+		sessionService: sessionService,
+	}, nil
+}
+
+func (s *sessionHandler) GetAll(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "session"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get sessions process")
+
+	response, err := s.sessionService.GetAll(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to get sessions", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to get sessions", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Get sessions process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *sessionHandler) Delete(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "session"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing revoke session process")
+
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid session id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid session id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if err := s.sessionService.Revoke(ctx.Request().Context(), sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to revoke session", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrUnknownSession) {
+			log.Warn("Session to revoke not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Session not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		log.Error("Failed to revoke session", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Revoke session process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *sessionHandler) SignOut(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "session"),
+		slog.String("func", "SignOut"),
+	)
+
+	log.Info("Initializing sign out process")
+
+	var payload domain.SignOutPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	if err := s.sessionService.SignOut(ctx.Request().Context(), &payload); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to sign out", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to sign out", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Sign out process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
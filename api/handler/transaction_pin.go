@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type transactionPINHandler struct {
+	i                     *do.Injector
+	transactionPINService domain.TransactionPINService
+}
+
+func NewTransactionPINHandler(i *do.Injector) (domain.TransactionPINHandler, error) {
+	transactionPINService, err := do.Invoke[domain.TransactionPINService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionPINHandler{
+		i:                     i,
+		transactionPINService: transactionPINService,
+	}, nil
+}
+
+func (t *transactionPINHandler) Set(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "transactionPIN"),
+		slog.String("func", "Set"),
+	)
+
+	log.Info("Initializing set transaction pin process")
+
+	var payload domain.SetPINPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	payload.IP = ctx.RealIP()
+
+	if err := t.transactionPINService.Set(ctx.Request().Context(), &payload); err != nil {
+		if errors.Is(err, domain.ErrPINAlreadySet) {
+			log.Warn("Transaction pin already set", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "Conflict", "A transaction PIN is already set. Change or reset it instead.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		return t.handleError(ctx, log, err)
+	}
+
+	log.Info("Set transaction pin process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (t *transactionPINHandler) Change(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "transactionPIN"),
+		slog.String("func", "Change"),
+	)
+
+	log.Info("Initializing change transaction pin process")
+
+	var payload domain.ChangePINPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if err := t.transactionPINService.Change(ctx.Request().Context(), &payload); err != nil {
+		return t.handleError(ctx, log, err)
+	}
+
+	log.Info("Change transaction pin process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (t *transactionPINHandler) Reset(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "transactionPIN"),
+		slog.String("func", "Reset"),
+	)
+
+	log.Info("Initializing reset transaction pin process")
+
+	var payload domain.ResetPINPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	payload.IP = ctx.RealIP()
+
+	if err := t.transactionPINService.Reset(ctx.Request().Context(), &payload); err != nil {
+		if apiError := twoFactorLockedAPIError(ctx, err); apiError != nil {
+			log.Warn("Transaction pin reset step-up locked", slog.String("error", err.Error()))
+			return ctx.JSON(apiError.Status, apiError)
+		}
+
+		if errors.Is(err, domain.ErrStepUpRequired) || errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			log.Warn("Transaction pin reset step-up failed", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "A valid two-factor code is required to reset the transaction PIN.")
+			return ctx.JSON(http.StatusForbidden, apiError)
+		}
+
+		return t.handleError(ctx, log, err)
+	}
+
+	log.Info("Reset transaction pin process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (t *transactionPINHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		log.Warn("Unauthorized attempt to manage transaction pin", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+	}
+
+	if errors.Is(err, domain.ErrInvalidPassword) {
+		log.Warn("Invalid password", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized credentials", "The password entered is invalid.")
+		return ctx.JSON(http.StatusUnauthorized, apiError)
+	}
+
+	var retryAfterErr *domain.RetryAfterError
+	if (errors.Is(err, domain.ErrAccountLocked) || errors.Is(err, domain.ErrTooManySignInAttempts)) && errors.As(err, &retryAfterErr) {
+		log.Warn("Password check throttled", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusTooManyRequests, "Too Many Requests", "Too many wrong passwords. Please wait before trying again.").
+			WithRetryAfter(retryAfterErr.RetryAfter)
+		ctx.Response().Header().Set("Retry-After", strconv.Itoa(apiError.RetryAfter))
+		return ctx.JSON(http.StatusTooManyRequests, apiError)
+	}
+
+	if apiError := pinAPIError(ctx, err); apiError != nil {
+		log.Warn("Transaction pin rejected", slog.String("error", err.Error()))
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	log.Error("Failed to manage transaction pin", slog.String("error", err.Error()))
+	return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+}
+
+// pinAPIError maps the errors of a transaction PIN check, shared by every
+// endpoint that moves money, and returns nil for any other error.
+func pinAPIError(ctx echo.Context, err error) *domain.APIError {
+	var retryAfterErr *domain.RetryAfterError
+	if errors.Is(err, domain.ErrPINLocked) && errors.As(err, &retryAfterErr) {
+		apiError := domain.NewAPIError(http.StatusTooManyRequests, "Transaction PIN Locked", "Your transaction PIN is locked after too many wrong attempts. Reset it or try again later.").
+			WithRetryAfter(retryAfterErr.RetryAfter)
+		ctx.Response().Header().Set("Retry-After", strconv.Itoa(apiError.RetryAfter))
+		return apiError
+	}
+
+	if errors.Is(err, domain.ErrPINNotSet) {
+		return domain.NewAPIError(http.StatusForbidden, "Transaction PIN Required", "Set a transaction PIN before moving money.")
+	}
+
+	if errors.Is(err, domain.ErrPINInvalid) {
+		return domain.NewAPIError(http.StatusForbidden, "Forbidden", "Invalid transaction PIN.")
+	}
+
+	return nil
+}
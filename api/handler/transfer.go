@@ -2,11 +2,13 @@ package handler
 
 import (
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 
 	"github.com/GSVillas/pic-pay-desafio/client"
 	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/do"
@@ -30,7 +32,7 @@ func NewTransferHandler(i *do.Injector) (domain.TransferHandler, error) {
 }
 
 func (t *transferHandler) Transfer(ctx echo.Context) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
 		slog.String("handler", "transfer"),
 		slog.String("func", "Transfer"),
 	)
@@ -51,7 +53,8 @@ func (t *transferHandler) Transfer(ctx echo.Context) error {
 		return ctx.JSON(apiError.Status, apiError)
 	}
 
-	if err := t.transferService.Transfer(ctx.Request().Context(), &payload); err != nil {
+	response, err := t.transferService.Transfer(ctx.Request().Context(), &payload)
+	if err != nil {
 
 		if errors.Is(err, domain.ErrSelfTransactionNotAllowed) {
 			log.Warn("Transfer failed due to self-transfer attempt", slog.String("error", err.Error()))
@@ -59,6 +62,45 @@ func (t *transferHandler) Transfer(ctx echo.Context) error {
 			return ctx.JSON(http.StatusForbidden, apiError)
 		}
 
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			log.Warn("Email verification pending", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.EmailNotVerifiedAPIError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+			log.Warn("Transfer requires two-factor authentication", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "Enable two-factor authentication to make transfers of this value.")
+			return ctx.JSON(http.StatusForbidden, apiError)
+		}
+
+		if apiError := twoFactorLockedAPIError(ctx, err); apiError != nil {
+			log.Warn("Transfer step-up locked", slog.String("error", err.Error()))
+			return ctx.JSON(apiError.Status, apiError)
+		}
+
+		if errors.Is(err, domain.ErrStepUpRequired) || errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			log.Warn("Transfer step-up failed", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "A valid two-factor code is required for transfers of this value.")
+			return ctx.JSON(http.StatusForbidden, apiError)
+		}
+
+		if errors.Is(err, domain.ErrKYCTransferLimitExceeded) {
+			log.Warn("Transfer over the kyc limit", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusForbidden, "Limit Exceeded", "The value exceeds the transfer limit of your verification level. Upgrade it to send more.")
+			return ctx.JSON(http.StatusForbidden, apiError)
+		}
+
+		if errors.Is(err, domain.ErrKYCBalanceLimitExceeded) {
+			log.Warn("Payee balance over the kyc limit", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusUnprocessableEntity, "Limit Exceeded", "The payee cannot receive this value at their verification level.")
+			return ctx.JSON(http.StatusUnprocessableEntity, apiError)
+		}
+
+		if apiError := pinAPIError(ctx, err); apiError != nil {
+			log.Warn("Transfer pin check failed", slog.String("error", err.Error()))
+			return ctx.JSON(apiError.Status, apiError)
+		}
+
 		if errors.Is(err, domain.ErrPayerWalletNotFound) {
 			log.Warn("Transfer failed due to missing payer wallet", slog.String("error", err.Error()))
 			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Payer wallet not found.")
@@ -77,6 +119,24 @@ func (t *transferHandler) Transfer(ctx echo.Context) error {
 			return ctx.JSON(http.StatusForbidden, apiError)
 		}
 
+		if errors.Is(err, domain.ErrQuoteRequired) {
+			log.Warn("Transfer failed due to missing quote", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "The payee has no wallet in this currency. Request a quote to convert the transfer.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		if errors.Is(err, domain.ErrQuoteNotFound) {
+			log.Warn("Transfer failed due to unknown quote", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Quote not found or expired.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		if errors.Is(err, domain.ErrQuoteMismatch) {
+			log.Warn("Transfer failed due to quote mismatch", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "The quote does not match the transfer currency and value.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
 		if errors.Is(err, domain.ErrInsufficientBalance) {
 			log.Warn("Transfer failed due to insufficient balance", slog.String("error", err.Error()))
 			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Insufficient balance for the transaction.")
@@ -94,5 +154,206 @@ func (t *transferHandler) Transfer(ctx echo.Context) error {
 	}
 
 	log.Info("Transfer completed successfully")
-	return ctx.NoContent(http.StatusCreated)
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (t *transferHandler) ConfirmEscrow(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "transfer"),
+		slog.String("func", "ConfirmEscrow"),
+	)
+
+	log.Info("Initializing confirm escrow process")
+
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid transfer id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid transfer id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	response, err := t.transferService.ConfirmEscrow(ctx.Request().Context(), transferID)
+	if err != nil {
+		return t.handleEscrowError(ctx, log, err)
+	}
+
+	log.Info("Confirm escrow process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (t *transferHandler) DisputeEscrow(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "transfer"),
+		slog.String("func", "DisputeEscrow"),
+	)
+
+	log.Info("Initializing dispute escrow process")
+
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid transfer id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid transfer id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.DisputeEscrowPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := t.transferService.DisputeEscrow(ctx.Request().Context(), transferID, &payload)
+	if err != nil {
+		return t.handleEscrowError(ctx, log, err)
+	}
+
+	log.Info("Dispute escrow process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (t *transferHandler) ResolveEscrow(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "transfer"),
+		slog.String("func", "ResolveEscrow"),
+	)
+
+	log.Info("Initializing resolve escrow process")
+
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid transfer id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid transfer id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.ResolveEscrowPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := t.transferService.ResolveEscrow(ctx.Request().Context(), transferID, &payload)
+	if err != nil {
+		return t.handleEscrowError(ctx, log, err)
+	}
+
+	log.Info("Resolve escrow process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (t *transferHandler) Refund(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "transfer"),
+		slog.String("func", "Refund"),
+	)
+
+	log.Info("Initializing refund process")
+
+	transferID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid transfer id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid transfer id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.RefundPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := t.transferService.Refund(ctx.Request().Context(), transferID, &payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyWalletMismatch) {
+			log.Warn("Api key used for another wallet", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.APIKeyWalletAPIError)
+		}
+
+		if errors.Is(err, domain.ErrRefundNotAllowed) {
+			log.Warn("Transfer cannot be refunded", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "This transfer cannot be refunded.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		if errors.Is(err, domain.ErrRefundExceedsValue) {
+			log.Warn("Refund exceeds refundable value", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "The refund value exceeds what is left to refund on this transfer.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			log.Warn("Refund failed due to insufficient balance", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Insufficient balance for the refund.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		return t.handleEscrowError(ctx, log, err)
+	}
+
+	log.Info("Refund process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (t *transferHandler) handleEscrowError(ctx echo.Context, log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		log.Warn("Unauthorized attempt to operate escrow", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+	}
+
+	if errors.Is(err, domain.ErrTransferNotFound) {
+		log.Warn("Transfer not found", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Transfer not found.")
+		return ctx.JSON(http.StatusNotFound, apiError)
+	}
+
+	if errors.Is(err, domain.ErrTransferForbidden) {
+		log.Warn("Escrow operation forbidden", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "You are not allowed to perform this operation on the transfer.")
+		return ctx.JSON(http.StatusForbidden, apiError)
+	}
+
+	if errors.Is(err, domain.ErrEscrowNotHeld) {
+		log.Warn("Transfer is not held in escrow", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The transfer funds are not held in escrow.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrEscrowNotDisputed) {
+		log.Warn("Escrow transfer is not disputed", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The escrow transfer is not under dispute.")
+		return ctx.JSON(http.StatusConflict, apiError)
+	}
+
+	if errors.Is(err, domain.ErrKYCBalanceLimitExceeded) {
+		log.Warn("Escrow release over the payee balance limit", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusUnprocessableEntity, "Limit Exceeded", "The payee cannot receive this value at their verification level. The funds stay in escrow.")
+		return ctx.JSON(http.StatusUnprocessableEntity, apiError)
+	}
+
+	log.Error("Failed to process escrow operation", slog.String("error", err.Error()))
+	return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
 }
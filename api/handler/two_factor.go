@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type twoFactorHandler struct {
+	i                *do.Injector
+	twoFactorService domain.TwoFactorService
+}
+
+func NewTwoFactorHandler(i *do.Injector) (domain.TwoFactorHandler, error) {
+	twoFactorService, err := do.Invoke[domain.TwoFactorService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactorHandler{
+		i:                i,
+		twoFactorService: twoFactorService,
+	}, nil
+}
+
+func (t *twoFactorHandler) Enroll(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "twoFactor"),
+		slog.String("func", "Enroll"),
+	)
+
+	log.Info("Initializing two-factor enrollment process")
+
+	response, err := t.twoFactorService.Enroll(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to enroll two-factor", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+			log.Warn("Two-factor already enabled", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "Conflict", "Two-factor authentication is already enabled.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		log.Error("Failed to enroll two-factor", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Two-factor enrollment process executed successfully")
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (t *twoFactorHandler) Activate(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "twoFactor"),
+		slog.String("func", "Activate"),
+	)
+
+	log.Info("Initializing two-factor activation process")
+
+	var payload domain.TwoFactorCodePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := t.twoFactorService.Activate(ctx.Request().Context(), &payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to activate two-factor", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorNotEnrolled) {
+			log.Warn("Two-factor enrollment not started", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Start the two-factor enrollment first.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+			log.Warn("Two-factor already enabled", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "Conflict", "Two-factor authentication is already enabled.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			log.Warn("Invalid two-factor code", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid two-factor code.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		log.Error("Failed to activate two-factor", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Two-factor activation process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (t *twoFactorHandler) Disable(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "twoFactor"),
+		slog.String("func", "Disable"),
+	)
+
+	log.Info("Initializing two-factor disable process")
+
+	var payload domain.TwoFactorCodePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if err := t.twoFactorService.Disable(ctx.Request().Context(), &payload); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to disable two-factor", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+			log.Warn("Two-factor not enabled", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Two-factor authentication is not enabled.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			log.Warn("Invalid two-factor code", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid two-factor code.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		if apiError := twoFactorLockedAPIError(ctx, err); apiError != nil {
+			log.Warn("Two-factor locked", slog.String("error", err.Error()))
+			return ctx.JSON(apiError.Status, apiError)
+		}
+
+		log.Error("Failed to disable two-factor", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Two-factor disable process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// twoFactorLockedAPIError maps the lockout of two-factor codes, shared by
+// every endpoint asking for one, and returns nil for any other error.
+func twoFactorLockedAPIError(ctx echo.Context, err error) *domain.APIError {
+	var retryAfterErr *domain.RetryAfterError
+	if !errors.Is(err, domain.ErrTwoFactorLocked) || !errors.As(err, &retryAfterErr) {
+		return nil
+	}
+
+	apiError := domain.NewAPIError(http.StatusTooManyRequests, "Two-Factor Locked", "Two-factor codes are locked after too many wrong attempts. Please try again later.").
+		WithRetryAfter(retryAfterErr.RetryAfter)
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(apiError.RetryAfter))
+	return apiError
+}
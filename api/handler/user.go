@@ -4,8 +4,11 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 
+	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/do"
@@ -29,7 +32,7 @@ func NewUserHandler(i *do.Injector) (domain.UserHandler, error) {
 }
 
 func (u *userHandler) Create(ctx echo.Context) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
 		slog.String("handler", "user"),
 		slog.String("func", "Create"),
 	)
@@ -58,9 +61,9 @@ func (u *userHandler) Create(ctx echo.Context) error {
 			return ctx.JSON(http.StatusConflict, apiError)
 		}
 
-		if errors.Is(err, domain.ErrCPFAlreadyRegister) {
+		if errors.Is(err, domain.ErrDocumentAlreadyRegister) {
 			log.Warn("Fail to create user", slog.String("error", err.Error()))
-			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The cpf already registered. Please try again with a different cpf.")
+			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The document already registered. Please try again with a different document.")
 			return ctx.JSON(http.StatusConflict, apiError)
 		}
 
@@ -73,7 +76,7 @@ func (u *userHandler) Create(ctx echo.Context) error {
 }
 
 func (u *userHandler) SignIn(ctx echo.Context) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
 		slog.String("handler", "user"),
 		slog.String("func", "SignIn"),
 	)
@@ -94,9 +97,24 @@ func (u *userHandler) SignIn(ctx echo.Context) error {
 		return ctx.JSON(apiError.Status, apiError)
 	}
 
+	payload.IP = ctx.RealIP()
+	payload.UserAgent = ctx.Request().UserAgent()
+
 	response, err := u.userService.SignIn(ctx.Request().Context(), &payload)
 	if err != nil {
 
+		var retryAfterErr *domain.RetryAfterError
+		if errors.As(err, &retryAfterErr) {
+			log.Warn("Sign in attempt throttled", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusTooManyRequests, "Too Many Requests", "Too many failed sign in attempts. Please wait before trying again.")
+			if errors.Is(err, domain.ErrAccountLocked) {
+				apiError = domain.NewAPIError(http.StatusTooManyRequests, "Account Locked", "Sign in to this account is locked after too many failed attempts. Please try again later.")
+			}
+			apiError.WithRetryAfter(retryAfterErr.RetryAfter)
+			ctx.Response().Header().Set("Retry-After", strconv.Itoa(apiError.RetryAfter))
+			return ctx.JSON(http.StatusTooManyRequests, apiError)
+		}
+
 		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrInvalidPassword) {
 			log.Warn("Fail to excute user sign in", slog.String("error", err.Error()))
 			apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized credentials", "Unauthorized credentials. Review the data sent")
@@ -110,3 +128,454 @@ func (u *userHandler) SignIn(ctx echo.Context) error {
 	log.Info("user sign in executed succefully")
 	return ctx.JSON(http.StatusOK, response)
 }
+
+func (u *userHandler) Refresh(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "Refresh"),
+	)
+
+	log.Info("Initializing user refresh process")
+
+	var payload domain.RefreshPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := u.userService.Refresh(ctx.Request().Context(), &payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenInvalid) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			log.Warn("Fail to refresh user session", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized", "Refresh token is invalid or expired. Sign in again.")
+			return ctx.JSON(http.StatusUnauthorized, apiError)
+		}
+
+		log.Error("Fail to refresh user session", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("user refresh executed succefully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (u *userHandler) SignInTwoFactor(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "SignInTwoFactor"),
+	)
+
+	log.Info("Initializing user two-factor sign in process")
+
+	var payload domain.SignInTwoFactorPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := u.userService.SignInTwoFactor(ctx.Request().Context(), &payload)
+	if err != nil {
+		if apiError := twoFactorLockedAPIError(ctx, err); apiError != nil {
+			log.Warn("Two-factor sign in locked", slog.String("error", err.Error()))
+			return ctx.JSON(apiError.Status, apiError)
+		}
+
+		if errors.Is(err, domain.ErrSignInChallengeInvalid) || errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("Fail to excute user two-factor sign in", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized", "Sign-in challenge is invalid or expired. Sign in again.")
+			return ctx.JSON(http.StatusUnauthorized, apiError)
+		}
+
+		if errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			log.Warn("Fail to excute user two-factor sign in", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized credentials", "Invalid two-factor code.")
+			return ctx.JSON(http.StatusUnauthorized, apiError)
+		}
+
+		log.Error("Fail to excute user two-factor sign in", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("user two-factor sign in executed succefully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (u *userHandler) ForgotPassword(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "ForgotPassword"),
+	)
+
+	log.Info("Initializing forgot password process")
+
+	var payload domain.ForgotPasswordPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	payload.IP = ctx.RealIP()
+
+	if err := u.userService.ForgotPassword(ctx.Request().Context(), &payload); err != nil {
+		var retryAfterErr *domain.RetryAfterError
+		if errors.As(err, &retryAfterErr) {
+			log.Warn("Password reset request throttled", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusTooManyRequests, "Too Many Requests", "Too many password reset requests. Please wait before trying again.").
+				WithRetryAfter(retryAfterErr.RetryAfter)
+			ctx.Response().Header().Set("Retry-After", strconv.Itoa(apiError.RetryAfter))
+			return ctx.JSON(http.StatusTooManyRequests, apiError)
+		}
+
+		log.Error("Fail to request password reset", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Forgot password process executed successfully")
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (u *userHandler) ResetPassword(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "ResetPassword"),
+	)
+
+	log.Info("Initializing reset password process")
+
+	var payload domain.ResetPasswordPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if err := u.userService.ResetPassword(ctx.Request().Context(), &payload); err != nil {
+		if errors.Is(err, domain.ErrResetTokenInvalid) {
+			log.Warn("Invalid password reset token", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "The password reset link is invalid or expired.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		log.Error("Fail to reset password", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Reset password process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) ChangePassword(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "ChangePassword"),
+	)
+
+	log.Info("Initializing change password process")
+
+	var payload domain.ChangePasswordPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if err := u.userService.ChangePassword(ctx.Request().Context(), &payload); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to change password", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrInvalidPassword) {
+			log.Warn("Invalid current password", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusUnauthorized, "Unauthorized credentials", "The current password is invalid.")
+			return ctx.JSON(http.StatusUnauthorized, apiError)
+		}
+
+		log.Error("Fail to change password", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Change password process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) VerifyEmail(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "VerifyEmail"),
+	)
+
+	log.Info("Initializing verify email process")
+
+	var payload domain.VerifyEmailPayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if err := u.userService.VerifyEmail(ctx.Request().Context(), &payload); err != nil {
+		if errors.Is(err, domain.ErrVerifyTokenInvalid) {
+			log.Warn("Invalid email verification token", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "The email verification link is invalid or expired.")
+			return ctx.JSON(http.StatusBadRequest, apiError)
+		}
+
+		log.Error("Fail to verify email", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Verify email process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) ResendEmailVerification(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "ResendEmailVerification"),
+	)
+
+	log.Info("Initializing resend email verification process")
+
+	if err := u.userService.ResendEmailVerification(ctx.Request().Context()); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to resend email verification", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrEmailAlreadyVerified) {
+			log.Warn("Email already verified", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "Your email is already verified.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		if errors.Is(err, domain.ErrVerificationTooSoon) {
+			log.Warn("Email verification resent too soon", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusTooManyRequests, "Too Many Requests", "A verification email was sent recently. Please wait before asking for another one.").
+				WithRetryAfter(config.Env.EmailVerificationResendInterval)
+			ctx.Response().Header().Set("Retry-After", strconv.Itoa(apiError.RetryAfter))
+			return ctx.JSON(http.StatusTooManyRequests, apiError)
+		}
+
+		log.Error("Fail to resend email verification", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Resend email verification process executed successfully")
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (u *userHandler) Unlock(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "Unlock"),
+	)
+
+	log.Info("Initializing unlock user process")
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid user id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid user id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	if err := u.userService.Unlock(ctx.Request().Context(), userID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("User not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "User not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		log.Error("Fail to unlock user", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Unlock user process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) UpdateRole(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	log.Info("Initializing update role process")
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		log.Warn("Invalid user id", slog.String("error", err.Error()))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Bad Request", "Invalid user id.")
+		return ctx.JSON(http.StatusBadRequest, apiError)
+	}
+
+	var payload domain.RolePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	if err := u.userService.UpdateRole(ctx.Request().Context(), userID, &payload); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to update role", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrRoleSelfChange) {
+			log.Warn("Attempt to change own role", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "You cannot change your own role.")
+			return ctx.JSON(http.StatusForbidden, apiError)
+		}
+
+		if errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("User not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "User not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		log.Error("Fail to update role", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Update role process executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) GetProfile(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "GetProfile"),
+	)
+
+	response, err := u.userService.GetProfile(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("Unauthorized attempt to get profile", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Failed to get profile", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "private, no-cache")
+	ctx.Response().Header().Set("ETag", response.ETag)
+	if ctx.Request().Header.Get("If-None-Match") == response.ETag {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateProfile requires the ETag of the profile in If-Match, so a change
+// based on a stale read is refused instead of overwriting a newer one.
+func (u *userHandler) UpdateProfile(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "user"),
+		slog.String("func", "UpdateProfile"),
+	)
+
+	log.Info("Initializing update profile process")
+
+	ifMatch := ctx.Request().Header.Get("If-Match")
+	if ifMatch == "" {
+		log.Warn("Profile update without If-Match")
+		apiError := domain.NewAPIError(http.StatusPreconditionRequired, "Precondition Required", "Send the ETag of the profile in the If-Match header.")
+		return ctx.JSON(http.StatusPreconditionRequired, apiError)
+	}
+
+	var payload domain.UpdateProfilePayload
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Failed to decode JSON payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, domain.CannotBindPayloadAPIError)
+	}
+
+	validationErrors := payload.Validate()
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := u.userService.UpdateProfile(ctx.Request().Context(), &payload, ifMatch)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("Unauthorized attempt to update profile", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrProfileModified) {
+			log.Warn("Profile changed since it was read", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusPreconditionFailed, "Precondition Failed", "The profile was changed since you read it. Get it again and retry.")
+			return ctx.JSON(http.StatusPreconditionFailed, apiError)
+		}
+
+		if errors.Is(err, domain.ErrEmailAlreadyRegister) {
+			log.Warn("Email already registered", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "Conflict", "This email is already in use.")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		log.Error("Failed to update profile", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Update profile process executed successfully")
+	ctx.Response().Header().Set("ETag", response.ETag)
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -2,12 +2,15 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/middleware"
 	"github.com/GSVillas/pic-pay-desafio/mocks"
 	"github.com/GSVillas/pic-pay-desafio/utils"
 	"github.com/golang/mock/gomock"
@@ -28,7 +31,8 @@ func TestUserHandler_Create_Success(t *testing.T) {
 
 	payload := domain.UserPayload{
 		Name:            "Test User",
-		CPF:             "077.351.310-89",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "077.351.310-89",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        utils.Password,
@@ -52,6 +56,48 @@ func TestUserHandler_Create_Success(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, rec.Code)
 }
 
+func TestUserHandler_Create_WhenClientSendsOnlyCPF_ShouldRegisterAnIndividual(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+
+	handler := &userHandler{
+		userService: userServiceMock,
+	}
+
+	jsonPayload, _ := jsoniter.Marshal(map[string]string{
+		"name":            "Test User",
+		"cpf":             "077.351.310-89",
+		"email":           "test@example.com",
+		"confirmEmail":    "test@example.com",
+		"password":        utils.Password,
+		"confirmPassword": utils.Password,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader(jsonPayload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	ctx := e.NewContext(req, rec)
+
+	userServiceMock.EXPECT().Create(gomock.Any(), &domain.UserPayload{
+		Name:            "Test User",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "077.351.310-89",
+		Email:           "test@example.com",
+		ConfirmEmail:    "test@example.com",
+		Password:        utils.Password,
+		ConfirmPassword: utils.Password,
+	}).Return(nil)
+
+	err := handler.Create(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
 func TestUserHandler_Create_WhenValidationFails_ShouldReturnBadRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -64,7 +110,8 @@ func TestUserHandler_Create_WhenValidationFails_ShouldReturnBadRequest(t *testin
 
 	payload := domain.UserPayload{
 		Name:            "",
-		CPF:             "077.351.310-89",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "077.351.310-89",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        utils.Password,
@@ -117,7 +164,8 @@ func TestUserHandler_Create_WhenServiceFails_ShouldReturnInternalServerError(t *
 
 	payload := domain.UserPayload{
 		Name:            "Test User",
-		CPF:             "077.351.310-89",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "077.351.310-89",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        utils.Password,
@@ -155,3 +203,72 @@ func TestUserHandler_Create_WhenServiceFails_ShouldReturnInternalServerError(t *
 
 	assert.Equal(t, expectedResponse, actualResponse)
 }
+
+func TestUserHandler_SignIn_WhenForwardedHeaderIsSpoofed_ShouldThrottleTheConnectionAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+
+	handler := &userHandler{
+		userService: userServiceMock,
+	}
+
+	jsonPayload, _ := jsoniter.Marshal(domain.SignInPayload{Email: "test@example.com", Password: utils.Password})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/users/sign-in", bytes.NewReader(jsonPayload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.5")
+	req.Header.Set(echo.HeaderXRealIP, "203.0.113.5")
+	req.RemoteAddr = "192.0.2.10:5123"
+	rec := httptest.NewRecorder()
+
+	ipExtractor, err := middleware.IPExtractor("")
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.IPExtractor = ipExtractor
+	ctx := e.NewContext(req, rec)
+
+	userServiceMock.EXPECT().SignIn(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, payload *domain.SignInPayload) (*domain.SignInResponse, error) {
+			assert.Equal(t, "192.0.2.10", payload.IP)
+			return nil, &domain.RetryAfterError{Err: domain.ErrTooManySignInAttempts, RetryAfter: time.Minute}
+		})
+
+	err = handler.SignIn(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestUserHandler_ForgotPassword_WhenThrottled_ShouldReturnTooManyRequestsWithRetryAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+
+	handler := &userHandler{
+		userService: userServiceMock,
+	}
+
+	jsonPayload, _ := jsoniter.Marshal(domain.ForgotPasswordPayload{Email: "test@example.com"})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/users/password/forgot", bytes.NewReader(jsonPayload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.RemoteAddr = "192.0.2.10:5123"
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	ctx := e.NewContext(req, rec)
+
+	userServiceMock.EXPECT().ForgotPassword(gomock.Any(), &domain.ForgotPasswordPayload{Email: "test@example.com", IP: "192.0.2.10"}).
+		Return(&domain.RetryAfterError{Err: domain.ErrTooManyResetRequests, RetryAfter: time.Minute})
+
+	err := handler.ForgotPassword(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+}
@@ -29,7 +29,7 @@ func NewWalletHandler(i *do.Injector) (domain.WalletHandler, error) {
 }
 
 func (w *walletHandler) Create(ctx echo.Context) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
 		slog.String("handler", "wallet"),
 		slog.String("func", "Create"),
 	)
@@ -57,9 +57,26 @@ func (w *walletHandler) Create(ctx echo.Context) error {
 			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
 		}
 
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			log.Warn("Email verification pending", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.EmailNotVerifiedAPIError)
+		}
+
+		if errors.Is(err, domain.ErrMerchantRequiresCNPJ) {
+			log.Warn("Fail to create wallet", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "Only users registered with a CNPJ can open merchant wallets")
+			return ctx.JSON(http.StatusForbidden, apiError)
+		}
+
 		if errors.Is(err, domain.ErrWalletAlredyRegister) {
 			log.Warn("Fail to create wallet", slog.String("error", err.Error()))
-			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The user already has a wallet")
+			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "The user already has a wallet in this currency")
+			return ctx.JSON(http.StatusConflict, apiError)
+		}
+
+		if errors.Is(err, domain.ErrWalletTypeMismatch) {
+			log.Warn("Fail to create wallet", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusConflict, "conflict", "All wallets of a user must have the same type")
 			return ctx.JSON(http.StatusConflict, apiError)
 		}
 
@@ -70,3 +87,68 @@ func (w *walletHandler) Create(ctx echo.Context) error {
 	log.Info("Create wallet process executed succefully")
 	return ctx.NoContent(http.StatusCreated)
 }
+
+func (w *walletHandler) GetAll(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "wallet"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get wallets process")
+
+	response, err := w.walletService.GetAll(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to get wallets", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		log.Error("Fail to get user wallets", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Get wallets process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (w *walletHandler) GetStatement(ctx echo.Context) error {
+	log := domain.LoggerFromContext(ctx.Request().Context()).With(
+		slog.String("handler", "wallet"),
+		slog.String("func", "GetStatement"),
+	)
+
+	log.Info("Initializing get statement process")
+
+	query, validationErrors := domain.NewStatementQuery(ctx.QueryParam("from"), ctx.QueryParam("to"), ctx.QueryParam("limit"))
+	if validationErrors != nil {
+		log.Warn("Validation failed", slog.Any("errors", validationErrors))
+		apiError := domain.NewAPIError(http.StatusBadRequest, "Validation Failed", "One or more fields failed validation").
+			WithErrors(validationErrors)
+		return ctx.JSON(apiError.Status, apiError)
+	}
+
+	response, err := w.walletService.GetStatement(ctx.Request().Context(), ctx.Param("currency"), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Unauthorized attempt to get statement", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+		}
+
+		if errors.Is(err, domain.ErrAPIKeyWalletMismatch) {
+			log.Warn("Api key used for another wallet", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, domain.APIKeyWalletAPIError)
+		}
+
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			log.Warn("Wallet not found", slog.String("error", err.Error()))
+			apiError := domain.NewAPIError(http.StatusNotFound, "Not Found", "Wallet not found.")
+			return ctx.JSON(http.StatusNotFound, apiError)
+		}
+
+		log.Error("Fail to get statement", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+	}
+
+	log.Info("Get statement process executed successfully")
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/samber/do"
 )
@@ -54,7 +55,7 @@ func NewAuthorizationService(i *do.Injector) (AuthorizationService, error) {
 }
 
 func (a *authorizationService) CheckAuthorization(ctx context.Context) (*AuthorizationResponse, error) {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "authorization"),
 		slog.String("func", "CheckAuthorization"),
 	)
@@ -0,0 +1,106 @@
+package client
+
+//go:generate mockgen -source=email.go -destination=../mocks/email_mock.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+)
+
+var (
+	ErrEmailFailed           = errors.New("failed to send email")
+	ErrEmailUnexpectedStatus = func(statusCode int) error {
+		return fmt.Errorf("unexpected status code from email API: %d", statusCode)
+	}
+)
+
+type Email struct {
+	To      string
+	Subject string
+	Text    string
+}
+
+type EmailService interface {
+	Send(ctx context.Context, email *Email) error
+}
+
+// resendEmail is the body of the Resend send email API.
+type resendEmail struct {
+	From    string   `json:"from"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+}
+
+type emailService struct {
+	i          *do.Injector
+	httpClient *http.Client
+}
+
+func NewEmailService(i *do.Injector) (EmailService, error) {
+	httpClient, err := do.Invoke[*http.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emailService{
+		i:          i,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (e *emailService) Send(ctx context.Context, email *Email) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "email"),
+		slog.String("func", "Send"),
+	)
+
+	log.Info("Initializing send email process", slog.String("subject", email.Subject))
+
+	body, err := jsoniter.Marshal(&resendEmail{
+		From:    config.Env.EmailFrom,
+		To:      []string{email.To},
+		Subject: email.Subject,
+		Text:    email.Text,
+	})
+	if err != nil {
+		log.Error("Failed to marshal email", slog.String("error", err.Error()))
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.Env.ResendURL, bytes.NewReader(body))
+	if err != nil {
+		log.Error("Failed to create request", slog.String("error", err.Error()))
+		return ErrEmailFailed
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+config.Env.ResendKey)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		log.Error("Failed to perform HTTP request", slog.String("error", err.Error()))
+		return ErrEmailFailed
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error("Failed to close response body", slog.String("error", err.Error()))
+		}
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		log.Warn("Unexpected status code received", slog.Int("statusCode", resp.StatusCode))
+		return ErrEmailUnexpectedStatus(resp.StatusCode)
+	}
+
+	log.Info("Send email process executed successfully")
+	return nil
+}
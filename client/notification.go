@@ -0,0 +1,93 @@
+package client
+
+//go:generate mockgen -source=notification.go -destination=../mocks/notification_mock.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+)
+
+var (
+	ErrNotificationFailed           = errors.New("failed to send notification")
+	ErrNotificationUnexpectedStatus = func(statusCode int) error {
+		return fmt.Errorf("unexpected status code from notification API: %d", statusCode)
+	}
+)
+
+type Notification struct {
+	UserID  uuid.UUID `json:"userId"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+}
+
+type NotificationService interface {
+	Notify(ctx context.Context, notification *Notification) error
+}
+
+type notificationService struct {
+	i          *do.Injector
+	httpClient *http.Client
+}
+
+func NewNotificationService(i *do.Injector) (NotificationService, error) {
+	httpClient, err := do.Invoke[*http.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationService{
+		i:          i,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (n *notificationService) Notify(ctx context.Context, notification *Notification) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "notification"),
+		slog.String("func", "Notify"),
+	)
+
+	log.Info("Initializing notify process", slog.String("userID", notification.UserID.String()))
+
+	body, err := jsoniter.Marshal(notification)
+	if err != nil {
+		log.Error("Failed to marshal notification", slog.String("error", err.Error()))
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.Env.NotificationURL, bytes.NewReader(body))
+	if err != nil {
+		log.Error("Failed to create request", slog.String("error", err.Error()))
+		return ErrNotificationFailed
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		log.Error("Failed to perform HTTP request", slog.String("error", err.Error()))
+		return ErrNotificationFailed
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error("Failed to close response body", slog.String("error", err.Error()))
+		}
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		log.Warn("Unexpected status code received", slog.Int("statusCode", resp.StatusCode))
+		return ErrNotificationUnexpectedStatus(resp.StatusCode)
+	}
+
+	log.Info("Notify process executed successfully")
+	return nil
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// requestIDTransport forwards the ID of the request being handled to the
+// services it calls, so their logs can be tied to ours.
+type requestIDTransport struct {
+	next http.RoundTripper
+}
+
+func NewRequestIDTransport(next http.RoundTripper) http.RoundTripper {
+	return &requestIDTransport{next: next}
+}
+
+func (r *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID := domain.RequestIDFromContext(req.Context())
+	if requestID == "" || req.Header.Get(echo.HeaderXRequestID) != "" {
+		return r.next.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the request it is given.
+	req = req.Clone(req.Context())
+	req.Header.Set(echo.HeaderXRequestID, requestID)
+	return r.next.RoundTrip(req)
+}
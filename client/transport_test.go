@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRequestIDServer(t *testing.T) (*httptest.Server, *string) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(echo.HeaderXRequestID)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &received
+}
+
+func TestRequestIDTransport_ShouldForwardTheRequestIDWithoutChangingTheRequest(t *testing.T) {
+	server, received := newTestRequestIDServer(t)
+	httpClient := &http.Client{Transport: NewRequestIDTransport(http.DefaultTransport)}
+
+	ctx := context.WithValue(context.Background(), domain.RequestIDKey, "abc-123")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	resp, err := httpClient.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "abc-123", *received)
+	assert.Empty(t, req.Header.Get(echo.HeaderXRequestID))
+}
+
+func TestRequestIDTransport_WhenRequestAlreadyHasAnID_ShouldKeepIt(t *testing.T) {
+	server, received := newTestRequestIDServer(t)
+	httpClient := &http.Client{Transport: NewRequestIDTransport(http.DefaultTransport)}
+
+	ctx := context.WithValue(context.Background(), domain.RequestIDKey, "abc-123")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderXRequestID, "set-by-caller")
+
+	resp, err := httpClient.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "set-by-caller", *received)
+}
+
+func TestRequestIDTransport_WhenOutsideOfARequest_ShouldSendNoID(t *testing.T) {
+	server, received := newTestRequestIDServer(t)
+	httpClient := &http.Client{Transport: NewRequestIDTransport(http.DefaultTransport)}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	resp, err := httpClient.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Empty(t, *received)
+}
@@ -0,0 +1,92 @@
+// Command keys manages the keyring that signs access tokens.
+//
+//	go run ./cmd/keys generate          create the keyring with one active key
+//	go run ./cmd/keys rotate [-keep 2]  activate a new key, keep older ones for verification
+//	go run ./cmd/keys list              show the keys of the keyring
+//
+// The keyring directory comes from KEYS_DIR (default "keys") or the -dir flag.
+// Generate keeps the legacy ec_private_key.pem, when present, as a retired key
+// so the access tokens it signed keep verifying after the keyring is deployed.
+// Keep at least one retired key while access tokens signed by it can still be
+// alive, or their holders will be signed out until they refresh.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/keyring"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	defaultDir := os.Getenv("KEYS_DIR")
+	if defaultDir == "" {
+		defaultDir = "keys"
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dir := flags.String("dir", defaultDir, "keyring directory")
+	keep := flags.Int("keep", 2, "retired keys kept for verification after a rotation")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		fail(err)
+	}
+
+	switch os.Args[1] {
+	case "generate":
+		key, err := keyring.Generate(*dir)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("generated keyring in %s with active key %s\n", *dir, key.ID)
+
+		keys, err := keyring.Read(*dir)
+		if err != nil {
+			fail(err)
+		}
+		for _, legacy := range keys[1:] {
+			fmt.Printf("kept legacy key %s for verification\n", legacy.ID)
+		}
+	case "rotate":
+		if *keep < 0 {
+			fail(fmt.Errorf("keep must not be negative"))
+		}
+		key, err := keyring.Rotate(*dir, *keep)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("rotated keyring in %s, active key is now %s\n", *dir, key.ID)
+	case "list":
+		keys, err := keyring.Read(*dir)
+		if err != nil {
+			fail(err)
+		}
+		for _, key := range keys {
+			status := "active"
+			if key.RetiredAt != nil {
+				status = "retired " + key.RetiredAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%s\tcreated %s\t%s\n", key.ID, key.CreatedAt.Format(time.RFC3339), status)
+		}
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: keys <generate|rotate|list> [-dir keys] [-keep 2]")
+	os.Exit(2)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "keys:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,210 @@
+// Command pii manages the keys that encrypt the personal data of users.
+//
+//	go run ./cmd/pii generate             create the master key, when local, and the keyring
+//	go run ./cmd/pii rotate               activate a new data key, keep older ones for decryption
+//	go run ./cmd/pii encrypt [-batch 500] encrypt plaintext rows and re-encrypt rows under older keys
+//
+// The keyring comes from PII_KEYRING_FILE and is wrapped by the KMS chosen
+// with PII_KMS_DRIVER. Encrypt runs against a live database: every row is
+// written only if it did not change since it was read, and rows skipped that
+// way are picked up by running it again. Run it after every rotation and
+// after deploying the encryption, until it reports no rows left.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/config/database"
+	"github.com/GSVillas/pic-pay-desafio/encryption"
+	"gorm.io/gorm"
+)
+
+// userRow is read with raw queries, bypassing the serializer, so the stored
+// values can be compared and replaced as they are.
+type userRow struct {
+	ID           string  `gorm:"column:id"`
+	Email        string  `gorm:"column:email"`
+	Document     string  `gorm:"column:document"`
+	Phone        string  `gorm:"column:phone"`
+	EmailHash    *string `gorm:"column:emailHash"`
+	DocumentHash *string `gorm:"column:documentHash"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	config.LoadEnvironments()
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	batch := flags.Int("batch", 500, "users read per query when encrypting")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		fail(err)
+	}
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "generate":
+		if config.Env.PIIKMSDriver == encryption.DriverLocal {
+			err := encryption.GenerateMasterKey(config.Env.PIIMasterKeyFile)
+			if err == nil {
+				fmt.Printf("generated master key in %s\n", config.Env.PIIMasterKeyFile)
+			} else if !errors.Is(err, encryption.ErrMasterKeyExists) {
+				fail(err)
+			}
+		}
+
+		kms, err := encryption.NewKMS()
+		if err != nil {
+			fail(err)
+		}
+
+		keyID, err := encryption.Generate(ctx, kms, config.Env.PIIKeyringFile)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("generated keyring in %s with active key %s\n", config.Env.PIIKeyringFile, keyID)
+	case "rotate":
+		kms, err := encryption.NewKMS()
+		if err != nil {
+			fail(err)
+		}
+
+		keyID, err := encryption.Rotate(ctx, kms, config.Env.PIIKeyringFile)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("rotated keyring in %s, active key is now %s\n", config.Env.PIIKeyringFile, keyID)
+	case "encrypt":
+		if *batch <= 0 {
+			fail(fmt.Errorf("batch must be positive"))
+		}
+
+		cipher, err := encryption.NewCipher(nil)
+		if err != nil {
+			fail(err)
+		}
+
+		connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		db, err := database.NewMysqlConnection(connectCtx)
+		cancel()
+		if err != nil {
+			fail(err)
+		}
+
+		updated, skipped, err := encryptUsers(ctx, db, cipher, *batch)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("encrypted %d users, %d changed while encrypting and were skipped\n", updated, skipped)
+	default:
+		usage()
+	}
+}
+
+// encryptUsers walks every user, closed ones included, and rewrites those
+// stored in plaintext, under an older data key or without blind indexes.
+func encryptUsers(ctx context.Context, db *gorm.DB, cipher encryption.Cipher, batch int) (updated, skipped int, err error) {
+	lastID := ""
+	for {
+		var rows []userRow
+		err := db.WithContext(ctx).Raw(
+			"SELECT id, email, document, phone, emailHash, documentHash FROM User WHERE id > ? ORDER BY id LIMIT ?",
+			lastID, batch,
+		).Scan(&rows).Error
+		if err != nil {
+			return updated, skipped, err
+		}
+
+		for _, row := range rows {
+			if isEncrypted(cipher, &row) {
+				continue
+			}
+
+			ok, err := encryptUser(ctx, db, cipher, &row)
+			if err != nil {
+				return updated, skipped, fmt.Errorf("user %s: %w", row.ID, err)
+			}
+
+			if ok {
+				updated++
+			} else {
+				skipped++
+			}
+		}
+
+		if len(rows) < batch {
+			return updated, skipped, nil
+		}
+		lastID = rows[len(rows)-1].ID
+	}
+}
+
+func isEncrypted(cipher encryption.Cipher, row *userRow) bool {
+	return row.EmailHash != nil && row.DocumentHash != nil &&
+		!cipher.NeedsReencrypt(row.Email) && !cipher.NeedsReencrypt(row.Document) && !cipher.NeedsReencrypt(row.Phone)
+}
+
+// encryptUser reports false when the row was changed by the API since it was
+// read.
+func encryptUser(ctx context.Context, db *gorm.DB, cipher encryption.Cipher, row *userRow) (bool, error) {
+	email, err := cipher.Decrypt(row.Email, "email")
+	if err != nil {
+		return false, err
+	}
+
+	document, err := cipher.Decrypt(row.Document, "document")
+	if err != nil {
+		return false, err
+	}
+
+	phone, err := cipher.Decrypt(row.Phone, "phone")
+	if err != nil {
+		return false, err
+	}
+
+	encryptedEmail, err := cipher.Encrypt(email, "email")
+	if err != nil {
+		return false, err
+	}
+
+	encryptedDocument, err := cipher.Encrypt(document, "document")
+	if err != nil {
+		return false, err
+	}
+
+	encryptedPhone, err := cipher.Encrypt(phone, "phone")
+	if err != nil {
+		return false, err
+	}
+
+	result := db.WithContext(ctx).Exec(
+		"UPDATE User SET email = ?, emailHash = ?, document = ?, documentHash = ?, phone = ? "+
+			"WHERE id = ? AND email = ? AND document = ? AND phone = ?",
+		encryptedEmail, cipher.BlindIndex(email, "email"), encryptedDocument, cipher.BlindIndex(document, "document"), encryptedPhone,
+		row.ID, row.Email, row.Document, row.Phone,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: pii <generate|rotate|encrypt> [-batch 500]")
+	os.Exit(2)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "pii:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/encryption"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// userTable is an in-memory User table answering the two statements
+// encryptUsers runs, so it can be tested without a MySQL server.
+type userTable struct {
+	mu   sync.Mutex
+	rows map[string]*userRow
+
+	// beforeUpdate runs ahead of every UPDATE, to change a row the way a
+	// concurrent API request would.
+	beforeUpdate func(id string)
+}
+
+func (u *userTable) Connect(ctx context.Context) (driver.Conn, error) {
+	return &userTableConn{table: u}, nil
+}
+
+func (u *userTable) Driver() driver.Driver {
+	return nil
+}
+
+type userTableConn struct {
+	table *userTable
+}
+
+func (c *userTableConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *userTableConn) Close() error {
+	return nil
+}
+
+func (c *userTableConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *userTableConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, "SELECT id, email, document, phone, emailHash, documentHash FROM User WHERE id > ?") {
+		return nil, errors.New("unexpected query: " + query)
+	}
+
+	lastID := args[0].Value.(string)
+	limit := int(args[1].Value.(int64))
+
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	ids := make([]string, 0, len(c.table.rows))
+	for id := range c.table.rows {
+		if id > lastID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	rows := &userTableRows{}
+	for _, id := range ids {
+		row := *c.table.rows[id]
+		rows.rows = append(rows.rows, row)
+	}
+	return rows, nil
+}
+
+func (c *userTableConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.HasPrefix(query, "UPDATE User SET email = ?, emailHash = ?, document = ?, documentHash = ?, phone = ?") {
+		return nil, errors.New("unexpected statement: " + query)
+	}
+
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value.(string)
+	}
+
+	if c.table.beforeUpdate != nil {
+		c.table.beforeUpdate(values[5])
+	}
+
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	row, ok := c.table.rows[values[5]]
+	if !ok || row.Email != values[6] || row.Document != values[7] || row.Phone != values[8] {
+		return driver.RowsAffected(0), nil
+	}
+
+	row.Email, row.EmailHash = values[0], &values[1]
+	row.Document, row.DocumentHash = values[2], &values[3]
+	row.Phone = values[4]
+	return driver.RowsAffected(1), nil
+}
+
+type userTableRows struct {
+	rows []userRow
+	next int
+}
+
+func (r *userTableRows) Columns() []string {
+	return []string{"id", "email", "document", "phone", "emailHash", "documentHash"}
+}
+
+func (r *userTableRows) Close() error {
+	return nil
+}
+
+func (r *userTableRows) Next(dest []driver.Value) error {
+	if r.next == len(r.rows) {
+		return io.EOF
+	}
+
+	row := r.rows[r.next]
+	r.next++
+
+	dest[0], dest[1], dest[2], dest[3] = row.ID, row.Email, row.Document, row.Phone
+	dest[4], dest[5] = nil, nil
+	if row.EmailHash != nil {
+		dest[4] = *row.EmailHash
+	}
+	if row.DocumentHash != nil {
+		dest[5] = *row.DocumentHash
+	}
+	return nil
+}
+
+func newTestUserTable(t *testing.T, rows ...userRow) (*userTable, *gorm.DB) {
+	table := &userTable{rows: make(map[string]*userRow, len(rows))}
+	for i := range rows {
+		table.rows[rows[i].ID] = &rows[i]
+	}
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(table),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	assert.NoError(t, err)
+
+	return table, db
+}
+
+func newTestCipher(t *testing.T) encryption.Cipher {
+	dir := t.TempDir()
+	config.Env.PIIKMSDriver = encryption.DriverLocal
+	config.Env.PIIMasterKeyFile = filepath.Join(dir, "pii-master.key")
+	config.Env.PIIKeyringFile = filepath.Join(dir, "pii-keyring.json")
+
+	assert.NoError(t, encryption.GenerateMasterKey(config.Env.PIIMasterKeyFile))
+
+	kms, err := encryption.NewKMS()
+	assert.NoError(t, err)
+
+	_, err = encryption.Generate(context.Background(), kms, config.Env.PIIKeyringFile)
+	assert.NoError(t, err)
+
+	cipher, err := encryption.NewCipher(nil)
+	assert.NoError(t, err)
+
+	return cipher
+}
+
+func TestEncryptUsers_WhenRowsArePlaintext_ShouldEncryptThemAndBackfillBlindIndexes(t *testing.T) {
+	cipher := newTestCipher(t)
+
+	encryptedEmail, _ := cipher.Encrypt("ana@example.com", "email")
+	encryptedDocument, _ := cipher.Encrypt("11144477735", "document")
+	emailHash := cipher.BlindIndex("ana@example.com", "email")
+	documentHash := cipher.BlindIndex("11144477735", "document")
+
+	table, db := newTestUserTable(t,
+		userRow{ID: "1", Email: "maria@example.com", Document: "52998224725", Phone: "+5511987654321"},
+		userRow{ID: "2", Email: "joao@example.com", Document: "11222333000181"},
+		userRow{ID: "3", Email: "ana@example.com", Document: "11144477735", EmailHash: &emailHash},
+		userRow{ID: "4", Email: encryptedEmail, Document: encryptedDocument, EmailHash: &emailHash, DocumentHash: &documentHash},
+	)
+
+	updated, skipped, err := encryptUsers(context.Background(), db, cipher, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated)
+	assert.Equal(t, 0, skipped)
+
+	plaintext := map[string][3]string{
+		"1": {"maria@example.com", "52998224725", "+5511987654321"},
+		"2": {"joao@example.com", "11222333000181", ""},
+		"3": {"ana@example.com", "11144477735", ""},
+	}
+	for id, want := range plaintext {
+		row := table.rows[id]
+
+		assert.True(t, isEncrypted(cipher, row), "user %s", id)
+		assert.Equal(t, cipher.BlindIndex(want[0], "email"), *row.EmailHash)
+		assert.Equal(t, cipher.BlindIndex(want[1], "document"), *row.DocumentHash)
+
+		email, err := cipher.Decrypt(row.Email, "email")
+		assert.NoError(t, err)
+		assert.Equal(t, want[0], email)
+
+		document, err := cipher.Decrypt(row.Document, "document")
+		assert.NoError(t, err)
+		assert.Equal(t, want[1], document)
+
+		phone, err := cipher.Decrypt(row.Phone, "phone")
+		assert.NoError(t, err)
+		assert.Equal(t, want[2], phone)
+	}
+
+	assert.Equal(t, encryptedEmail, table.rows["4"].Email)
+
+	updated, skipped, err = encryptUsers(context.Background(), db, cipher, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, updated)
+	assert.Equal(t, 0, skipped)
+}
+
+func TestEncryptUsers_WhenRowChangesWhileEncrypting_ShouldSkipIt(t *testing.T) {
+	cipher := newTestCipher(t)
+
+	table, db := newTestUserTable(t,
+		userRow{ID: "1", Email: "maria@example.com", Document: "52998224725"},
+		userRow{ID: "2", Email: "joao@example.com", Document: "11222333000181"},
+	)
+
+	table.beforeUpdate = func(id string) {
+		if id != "1" {
+			return
+		}
+
+		table.mu.Lock()
+		defer table.mu.Unlock()
+		table.rows["1"].Email = "maria.silva@example.com"
+	}
+
+	updated, skipped, err := encryptUsers(context.Background(), db, cipher, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, "maria.silva@example.com", table.rows["1"].Email)
+	assert.Nil(t, table.rows["1"].EmailHash)
+	assert.True(t, isEncrypted(cipher, table.rows["2"]))
+}
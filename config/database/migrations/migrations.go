@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/config/database"
 	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/encryption"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -20,9 +23,208 @@ func main() {
 		log.Fatal("Fail to connect to mysql: ", err)
 	}
 
-	if err := db.AutoMigrate(&domain.User{}, &domain.Transfer{}, &domain.Wallet{}); err != nil {
+	cipher, err := encryption.NewCipher(nil)
+	if err != nil {
+		log.Fatal("Fail to load pii keyring, generate it with go run ./cmd/pii generate: ", err)
+	}
+
+	if err := migrateUserDocument(db); err != nil {
+		log.Fatal("Fail to migrate user document: ", err)
+	}
+
+	hasEmailVerifiedAt := db.Migrator().HasColumn(&domain.User{}, "emailVerifiedAt")
+	hasRole := db.Migrator().HasColumn(&domain.User{}, "role")
+	hasContacts := db.Migrator().HasTable(&domain.Contact{})
+
+	if err := db.AutoMigrate(&domain.User{}, &domain.Transfer{}, &domain.Wallet{}, &domain.Hold{}, &domain.Dispute{}, &domain.DisputeEvidence{}, &domain.Campaign{}, &domain.CampaignMerchant{}, &domain.Reward{}, &domain.TwoFactor{}, &domain.RecoveryCode{}, &domain.TransactionPIN{}, &domain.KYCSubmission{}, &domain.KYCDocument{}, &domain.Contact{}, &domain.APIKey{}, &domain.OAuthClient{}); err != nil {
 		log.Fatal("Fail to migrate: ", err)
 	}
 
+	if err := migrateWalletPrimaryKey(db); err != nil {
+		log.Fatal("Fail to migrate wallet primary key: ", err)
+	}
+
+	if err := backfillUserBlindIndexes(db, cipher); err != nil {
+		log.Fatal("Fail to backfill user blind indexes: ", err)
+	}
+
+	if err := dropUserPlaintextIndexes(db); err != nil {
+		log.Fatal("Fail to drop user plaintext indexes: ", err)
+	}
+
+	if !hasEmailVerifiedAt {
+		if err := backfillEmailVerifiedAt(db); err != nil {
+			log.Fatal("Fail to backfill email verification: ", err)
+		}
+	}
+
+	if !hasRole {
+		if err := backfillMerchantAdmins(db); err != nil {
+			log.Fatal("Fail to backfill merchant admins: ", err)
+		}
+	}
+
+	if !hasContacts {
+		if err := backfillContacts(db); err != nil {
+			log.Fatal("Fail to backfill contacts: ", err)
+		}
+	}
+
+	if err := promoteAdmins(db, config.Env.AdminUserIDs); err != nil {
+		log.Fatal("Fail to promote admins: ", err)
+	}
+
 	log.Println("Migration executed successfully")
 }
+
+// migrateWalletPrimaryKey widens the primary key of wallets created before
+// multi-currency support from userId to (userId, currency). AutoMigrate adds
+// the currency column, defaulting existing wallets to BRL, but never changes
+// an existing primary key.
+func migrateWalletPrimaryKey(db *gorm.DB) error {
+	var columns int64
+	err := db.Raw(
+		"SELECT COUNT(*) FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'",
+		domain.Wallet{}.TableName(),
+	).Scan(&columns).Error
+	if err != nil {
+		return err
+	}
+
+	if columns != 1 {
+		return nil
+	}
+
+	return db.Exec("ALTER TABLE Wallet DROP PRIMARY KEY, ADD PRIMARY KEY (userId, currency)").Error
+}
+
+// migrateUserDocument renames the cpf column of users registered before CNPJ
+// support to document, so AutoMigrate widens it instead of adding an empty
+// column, and drops its old unique index in favour of the one on document.
+// The new documentType column defaults those users to cpf.
+func migrateUserDocument(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&domain.User{}) || !migrator.HasColumn(&domain.User{}, "cpf") {
+		return nil
+	}
+
+	if err := migrator.RenameColumn(&domain.User{}, "cpf", "document"); err != nil {
+		return err
+	}
+
+	if migrator.HasIndex(&domain.User{}, "idx_User_cpf") {
+		return migrator.DropIndex(&domain.User{}, "idx_User_cpf")
+	}
+
+	return nil
+}
+
+// backfillUserBlindIndexes computes the blind indexes of the users stored
+// before encryption, so their unique indexes cover every user before the
+// plaintext ones are dropped. Encrypting those users is left to
+// go run ./cmd/pii encrypt, which can run while the API serves requests. A
+// user changed since it was read already got its indexes from the API.
+func backfillUserBlindIndexes(db *gorm.DB, cipher encryption.Cipher) error {
+	for {
+		var users []struct {
+			ID       string
+			Email    string
+			Document string
+		}
+		err := db.Raw("SELECT id, email, document FROM User WHERE emailHash IS NULL OR documentHash IS NULL LIMIT 500").Scan(&users).Error
+		if err != nil {
+			return err
+		}
+
+		if len(users) == 0 {
+			return nil
+		}
+
+		for _, user := range users {
+			email, err := cipher.Decrypt(user.Email, "email")
+			if err != nil {
+				return err
+			}
+
+			document, err := cipher.Decrypt(user.Document, "document")
+			if err != nil {
+				return err
+			}
+
+			err = db.Exec(
+				"UPDATE User SET emailHash = ?, documentHash = ? WHERE id = ? AND email = ? AND document = ?",
+				cipher.BlindIndex(email, "email"), cipher.BlindIndex(document, "document"), user.ID, user.Email, user.Document,
+			).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// dropUserPlaintextIndexes drops the unique indexes on the email and document
+// of users, which are encrypted with a random nonce and can no longer be
+// compared. Uniqueness is enforced on their blind indexes instead, which
+// backfillUserBlindIndexes fills in first.
+func dropUserPlaintextIndexes(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, index := range []string{"idx_User_email", "idx_User_document"} {
+		if !migrator.HasIndex(&domain.User{}, index) {
+			continue
+		}
+
+		if err := migrator.DropIndex(&domain.User{}, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillEmailVerifiedAt marks users created before email verification
+// existed as verified, so they keep access to their wallets. It only runs
+// on the migration that adds the column.
+func backfillEmailVerifiedAt(db *gorm.DB) error {
+	return db.Exec("UPDATE User SET emailVerifiedAt = createdAt WHERE emailVerifiedAt IS NULL").Error
+}
+
+// backfillMerchantAdmins gives the merchant admin role to the users who
+// already own merchant wallets when roles are introduced. New merchants get
+// it when they open their first merchant wallet.
+func backfillMerchantAdmins(db *gorm.DB) error {
+	return db.Exec(
+		"UPDATE User SET role = ? WHERE role = ? AND id IN (SELECT userId FROM Wallet WHERE type = ?)",
+		domain.RoleMerchantAdmin, domain.RoleCustomer, domain.WalletTypeMERCHANT,
+	).Error
+}
+
+// backfillContacts adds everyone a user already transferred to as a contact
+// when contacts are introduced. Hold captures and refunds are not counted,
+// as they are not transfers the payer started. Later transfers are recorded
+// as they happen.
+func backfillContacts(db *gorm.DB) error {
+	return db.Exec(
+		"INSERT INTO Contact (userId, contactId, transferCount, lastTransferAt, createdAt, updatedAt) " +
+			"SELECT payerId, payeeId, COUNT(*), MAX(createdAt), MIN(createdAt), MAX(createdAt) FROM Transfer " +
+			"WHERE holdId IS NULL AND reversalOfId IS NULL AND payerId <> payeeId AND deletedAt IS NULL " +
+			"GROUP BY payerId, payeeId",
+	).Error
+}
+
+// promoteAdmins bootstraps the admins listed in ADMIN_USER_IDS. Other roles
+// are managed through the API by those admins; a listed user demoted there
+// is promoted again on the next migration until removed from the list.
+func promoteAdmins(db *gorm.DB, adminUserIDs string) error {
+	var ids []string
+	for _, id := range strings.Split(adminUserIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return db.Model(&domain.User{}).Where("id IN ?", ids).Update("role", domain.RoleAdmin).Error
+}
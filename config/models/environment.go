@@ -1,17 +1,78 @@
 package models
 
-import "crypto/ecdsa"
+import (
+	"time"
+)
 
 type Environment struct {
-	ConnectionString string `env:"CONNECTION_STRING"`
-	RedisAdress      string `env:"REDIS_ADRESS"`
-	RedisPassword    string `env:"REDIS_PASSWORD"`
-	RedisDB          int    `env:"REDIS_DB"`
-	APIPort          string `env:"API_PORT"`
-	SessionExp       int    `env:"SESSION_EXP"`
-	ResendKey        string `env:"RESEND_KEY"`
-	AuthorizationURL string `env:"AUTHORIZATION_API_URL"`
-	NotificationURL  string `env:"NOTIFICATION_API_URL"`
-	PrivateKey       *ecdsa.PrivateKey
-	PublicKey        *ecdsa.PublicKey
+	ConnectionString                string        `env:"CONNECTION_STRING"`
+	RedisAdress                     string        `env:"REDIS_ADRESS"`
+	RedisPassword                   string        `env:"REDIS_PASSWORD"`
+	RedisDB                         int           `env:"REDIS_DB"`
+	APIPort                         string        `env:"API_PORT"`
+	TrustedProxies                  string        `env:"TRUSTED_PROXIES"`
+	SessionExp                      int           `env:"SESSION_EXP"`
+	AccessTokenTTL                  time.Duration `env:"ACCESS_TOKEN_TTL,default=15m"`
+	TokenIssuer                     string        `env:"TOKEN_ISSUER,default=pic-pay-desafio"`
+	TokenAudience                   string        `env:"TOKEN_AUDIENCE,default=pic-pay-desafio-api"`
+	PasswordHashAlgorithm           string        `env:"PASSWORD_HASH_ALGORITHM,default=argon2id"`
+	BcryptCost                      int           `env:"BCRYPT_COST,default=10"`
+	Argon2Memory                    uint32        `env:"ARGON2_MEMORY,default=19456"`
+	Argon2Iterations                uint32        `env:"ARGON2_ITERATIONS,default=2"`
+	Argon2Parallelism               uint8         `env:"ARGON2_PARALLELISM,default=1"`
+	KeysDir                         string        `env:"KEYS_DIR,default=keys"`
+	PIIKMSDriver                    string        `env:"PII_KMS_DRIVER,default=local"`
+	PIIMasterKeyFile                string        `env:"PII_MASTER_KEY_FILE,default=keys/pii-master.key"`
+	PIIKeyringFile                  string        `env:"PII_KEYRING_FILE,default=keys/pii-keyring.json"`
+	TwoFactorIssuer                 string        `env:"TWO_FACTOR_ISSUER,default=PicPay Desafio"`
+	SignInChallengeTTL              time.Duration `env:"SIGN_IN_CHALLENGE_TTL,default=5m"`
+	TwoFactorMaxAttempts            int64         `env:"TWO_FACTOR_MAX_ATTEMPTS,default=5"`
+	TwoFactorLockoutDuration        time.Duration `env:"TWO_FACTOR_LOCKOUT_DURATION,default=15m"`
+	StepUpTransferValue             float64       `env:"STEP_UP_TRANSFER_VALUE,default=1000"`
+	KYCLimitCurrency                string        `env:"KYC_LIMIT_CURRENCY,default=BRL"`
+	KYCBasicTransferLimit           float64       `env:"KYC_BASIC_TRANSFER_LIMIT,default=500"`
+	KYCBasicBalanceLimit            float64       `env:"KYC_BASIC_BALANCE_LIMIT,default=2000"`
+	KYCVerifiedTransferLimit        float64       `env:"KYC_VERIFIED_TRANSFER_LIMIT,default=10000"`
+	KYCVerifiedBalanceLimit         float64       `env:"KYC_VERIFIED_BALANCE_LIMIT,default=50000"`
+	KYCEnhancedTransferLimit        float64       `env:"KYC_ENHANCED_TRANSFER_LIMIT,default=0"`
+	KYCEnhancedBalanceLimit         float64       `env:"KYC_ENHANCED_BALANCE_LIMIT,default=0"`
+	TransactionPINMaxAttempts       int64         `env:"TRANSACTION_PIN_MAX_ATTEMPTS,default=3"`
+	TransactionPINLockoutDuration   time.Duration `env:"TRANSACTION_PIN_LOCKOUT_DURATION,default=24h"`
+	OAuthCodeTTL                    time.Duration `env:"OAUTH_CODE_TTL,default=1m"`
+	OAuthAccessTokenTTL             time.Duration `env:"OAUTH_ACCESS_TOKEN_TTL,default=15m"`
+	OAuthRefreshTokenTTL            time.Duration `env:"OAUTH_REFRESH_TOKEN_TTL,default=720h"`
+	SignInMaxAttempts               int64         `env:"SIGN_IN_MAX_ATTEMPTS,default=5"`
+	SignInIPMaxAttempts             int64         `env:"SIGN_IN_IP_MAX_ATTEMPTS,default=20"`
+	SignInAttemptWindow             time.Duration `env:"SIGN_IN_ATTEMPT_WINDOW,default=15m"`
+	SignInDelayBase                 time.Duration `env:"SIGN_IN_DELAY_BASE,default=1s"`
+	SignInLockoutDuration           time.Duration `env:"SIGN_IN_LOCKOUT_DURATION,default=15m"`
+	ResendKey                       string        `env:"RESEND_KEY"`
+	ResendURL                       string        `env:"RESEND_API_URL,default=https://api.resend.com/emails"`
+	EmailFrom                       string        `env:"EMAIL_FROM"`
+	PasswordResetURL                string        `env:"PASSWORD_RESET_URL"`
+	PasswordResetTTL                time.Duration `env:"PASSWORD_RESET_TTL,default=30m"`
+	PasswordResetMaxRequests        int64         `env:"PASSWORD_RESET_MAX_REQUESTS,default=3"`
+	PasswordResetIPMaxRequests      int64         `env:"PASSWORD_RESET_IP_MAX_REQUESTS,default=20"`
+	PasswordResetRequestWindow      time.Duration `env:"PASSWORD_RESET_REQUEST_WINDOW,default=1h"`
+	EmailVerificationURL            string        `env:"EMAIL_VERIFICATION_URL"`
+	EmailVerificationTTL            time.Duration `env:"EMAIL_VERIFICATION_TTL,default=24h"`
+	EmailVerificationResendInterval time.Duration `env:"EMAIL_VERIFICATION_RESEND_INTERVAL,default=1m"`
+	AuthorizationURL                string        `env:"AUTHORIZATION_API_URL"`
+	NotificationURL                 string        `env:"NOTIFICATION_API_URL"`
+	HoldExpiration                  time.Duration `env:"HOLD_DEFAULT_EXPIRATION,default=168h"`
+	HoldSweepInterval               time.Duration `env:"HOLD_SWEEP_INTERVAL,default=1m"`
+	EscrowTimeout                   time.Duration `env:"ESCROW_TIMEOUT,default=168h"`
+	EscrowReleaseInterval           time.Duration `env:"ESCROW_RELEASE_INTERVAL,default=1m"`
+	AdminUserIDs                    string        `env:"ADMIN_USER_IDS"`
+	StorageDriver                   string        `env:"STORAGE_DRIVER,default=local"`
+	StorageLocalPath                string        `env:"STORAGE_LOCAL_PATH,default=uploads"`
+	S3Endpoint                      string        `env:"S3_ENDPOINT"`
+	S3Region                        string        `env:"S3_REGION,default=us-east-1"`
+	S3Bucket                        string        `env:"S3_BUCKET"`
+	S3AccessKey                     string        `env:"S3_ACCESS_KEY"`
+	S3SecretKey                     string        `env:"S3_SECRET_KEY"`
+	S3UsePathStyle                  bool          `env:"S3_USE_PATH_STYLE,default=true"`
+	MaxUploadSize                   int64         `env:"MAX_UPLOAD_SIZE,default=10485760"`
+	RatesFilePath                   string        `env:"RATES_FILE_PATH,default=rates.json"`
+	QuoteTTL                        time.Duration `env:"QUOTE_TTL,default=30s"`
 }
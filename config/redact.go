@@ -0,0 +1,200 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// secretKeys are dropped whatever their value. Keys containing one of
+	// secretKeyParts, such as refreshToken or passwordHash, are too.
+	secretKeys     = map[string]bool{"authorization": true, "cookie": true, "code": true, "pin": true, "otp": true}
+	secretKeyParts = []string{"token", "password", "secret"}
+
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	apiKeyPattern = regexp.MustCompile(`(ppk_[A-Za-z0-9_-]+\.)[A-Za-z0-9_-]+`)
+	cnpjPattern   = regexp.MustCompile(`\b\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}\b`)
+	cpfPattern    = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)
+
+	// opaqueTokenPattern finds runs of URL-safe base64. Refresh tokens,
+	// OAuth codes and the other secure.GenerateToken tokens have no prefix,
+	// so they are told apart by their length: 32 random bytes encode to 43
+	// characters.
+	opaqueTokenPattern = regexp.MustCompile(`[A-Za-z0-9_-]{43,}`)
+)
+
+const opaqueTokenLength = 43
+
+// redactHandler keeps secrets and personal data out of the logs. Values of
+// secret keys are replaced, emails, documents and phones are masked, and
+// JWTs, API keys, opaque tokens, CPFs or CNPJs found in any other string are
+// scrubbed, so a careless log line does not leak them.
+type redactHandler struct {
+	next slog.Handler
+}
+
+func NewRedactHandler(next slog.Handler) slog.Handler {
+	return &redactHandler{next: next}
+}
+
+func (r *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return r.next.Enabled(ctx, level)
+}
+
+func (r *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, redactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		clean.AddAttrs(redactAttr(attr))
+		return true
+	})
+
+	return r.next.Handle(ctx, clean)
+}
+
+func (r *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		clean = append(clean, redactAttr(attr))
+	}
+
+	return &redactHandler{next: r.next.WithAttrs(clean)}
+}
+
+func (r *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: r.next.WithGroup(name)}
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		clean := make([]any, 0, len(group))
+		for _, member := range group {
+			clean = append(clean, redactAttr(member))
+		}
+		return slog.Group(attr.Key, clean...)
+	}
+
+	key := strings.ToLower(strings.Trim(attr.Key, ": "))
+	if isSecretKey(key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	if attr.Value.Kind() == slog.KindAny {
+		return slog.Any(attr.Key, redactAny(attr.Value.Any()))
+	}
+
+	if attr.Value.Kind() != slog.KindString {
+		return attr
+	}
+
+	value := attr.Value.String()
+	switch key {
+	case "email":
+		return slog.String(attr.Key, maskEmail(value))
+	case "cpf", "cnpj", "document":
+		return slog.String(attr.Key, maskDigits(value, 3))
+	case "phone":
+		return slog.String(attr.Key, maskDigits(value, 2))
+	}
+
+	return slog.String(attr.Key, redactString(value))
+}
+
+// redactAny scrubs the text of errors and Stringers. Other values, such as
+// structs and maps, are logged as they are unless their text holds a secret
+// or a document, in which case only the scrubbed text is logged.
+func redactAny(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case error:
+		return redactString(v.Error())
+	case fmt.Stringer:
+		return redactString(v.String())
+	}
+
+	text := fmt.Sprintf("%+v", value)
+	if clean := redactString(text); clean != text {
+		return clean
+	}
+
+	return value
+}
+
+func isSecretKey(key string) bool {
+	if secretKeys[key] {
+		return true
+	}
+
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactString keeps the prefix of API keys, which is stored in clear and
+// identifies the key, and scrubs everything else.
+func redactString(value string) string {
+	value = jwtPattern.ReplaceAllString(value, redacted)
+	value = apiKeyPattern.ReplaceAllString(value, "${1}"+redacted)
+	value = opaqueTokenPattern.ReplaceAllStringFunc(value, func(token string) string {
+		if len(token) != opaqueTokenLength {
+			return token
+		}
+		return redacted
+	})
+	value = cnpjPattern.ReplaceAllStringFunc(value, func(cnpj string) string {
+		return maskDigits(cnpj, 3)
+	})
+	return cpfPattern.ReplaceAllStringFunc(value, func(cpf string) string {
+		return maskDigits(cpf, 3)
+	})
+}
+
+// maskEmail keeps the first letter and the domain, as in a***@example.com.
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskDigits keeps only the last visible digits of a number.
+func maskDigits(value string, visible int) string {
+	digits := 0
+	for _, char := range value {
+		if char >= '0' && char <= '9' {
+			digits++
+		}
+	}
+
+	if digits <= visible {
+		return redacted
+	}
+
+	var masked strings.Builder
+	seen := 0
+	for _, char := range value {
+		if char >= '0' && char <= '9' {
+			seen++
+			if seen <= digits-visible {
+				masked.WriteByte('*')
+				continue
+			}
+		}
+		masked.WriteRune(char)
+	}
+
+	return masked.String()
+}
@@ -2,13 +2,17 @@ package domain
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"time"
 )
 
 var (
 	CannotBindPayloadAPIError = NewAPIError(http.StatusUnprocessableEntity, "Invalid Request", "Failed to process the payload")
 	InternalServerAPIError    = NewAPIError(http.StatusInternalServerError, "Internal Server Error", "Failed to process the payload")
 	SessionNotFoundAPIError   = NewAPIError(http.StatusForbidden, "Authentication Required", "You must be logged in to perform this action. Please log in and try again.")
+	APIKeyWalletAPIError      = NewAPIError(http.StatusForbidden, "Forbidden", "This api key is not allowed to operate this wallet.")
+	EmailNotVerifiedAPIError  = NewAPIError(http.StatusForbidden, "Email Verification Pending", "Confirm your email using the link we sent before creating wallets or making transfers.")
 )
 
 type APIError struct {
@@ -16,6 +20,8 @@ type APIError struct {
 	Title  string            `json:"title"`
 	Detail string            `json:"detail"`
 	Errors map[string]string `json:"errors,omitempty"`
+	// RetryAfter is the number of seconds to wait before trying again.
+	RetryAfter int `json:"retryAfter,omitempty"`
 }
 
 func NewAPIError(status int, title, detail string) *APIError {
@@ -31,6 +37,11 @@ func (e *APIError) WithErrors(errors map[string]string) *APIError {
 	return e
 }
 
+func (e *APIError) WithRetryAfter(retryAfter time.Duration) *APIError {
+	e.RetryAfter = int(math.Ceil(retryAfter.Seconds()))
+	return e
+}
+
 func (e *APIError) ToJSON() ([]byte, error) {
 	return json.Marshal(e)
 }
@@ -0,0 +1,97 @@
+package domain
+
+//go:generate mockgen -source=account.go -destination=../mocks/account_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrAccountHasBalance   = errors.New("account still has balance in its wallets")
+	ErrAccountHasOpenFunds = errors.New("account has active holds or escrows")
+	ErrExportAccount       = errors.New("export account fail")
+	ErrCloseAccount        = errors.New("close account fail")
+)
+
+// ClosedAccountName replaces the name of users who closed their account.
+const ClosedAccountName = "Closed account"
+
+// CloseAccountPayload confirms the closure with the password of the user.
+type CloseAccountPayload struct {
+	Password string `json:"password,omitempty" validate:"required"`
+}
+
+// AccountExport is every record kept about a user, as required by the LGPD
+// right of access.
+type AccountExport struct {
+	Profile    *UserProfileResponse `json:"profile"`
+	Wallets    []*WalletResponse    `json:"wallets"`
+	Transfers  []*TransferResponse  `json:"transfers"`
+	Sessions   []*SessionResponse   `json:"sessions"`
+	ExportedAt time.Time            `json:"exportedAt"`
+}
+
+type AccountHandler interface {
+	Export(ctx echo.Context) error
+	Close(ctx echo.Context) error
+}
+
+type AccountService interface {
+	Export(ctx context.Context) (*AccountExport, error)
+	Close(ctx context.Context, payload *CloseAccountPayload) error
+}
+
+type AccountRepository interface {
+	Close(ctx context.Context, user *User) error
+}
+
+func (c *CloseAccountPayload) Validate() map[string]string {
+	return ValidateStruct(c)
+}
+
+// Pseudonymize replaces the personal data of the user with values derived
+// from its ID, so the transfers it took part in can be kept for regulatory
+// retention without identifying the person. Email and document stay unique,
+// which frees the originals for a new registration.
+func (u *User) Pseudonymize() {
+	id := strings.ReplaceAll(u.ID.String(), "-", "")
+
+	u.Name = ClosedAccountName
+	u.Document = id[:14]
+	u.LegalName = ""
+	u.TradeName = ""
+	u.Email = fmt.Sprintf("closed-%s@closed.invalid", id)
+	u.Phone = ""
+	u.PasswordHash = ""
+	u.EmailVerifiedAt = nil
+}
+
+func NewAccountExport(user *User, wallets []*Wallet, transfers []*Transfer, sessions []*SessionResponse) *AccountExport {
+	export := &AccountExport{
+		Profile:    user.ToProfileResponse(),
+		Wallets:    make([]*WalletResponse, 0, len(wallets)),
+		Transfers:  make([]*TransferResponse, 0, len(transfers)),
+		Sessions:   sessions,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	for _, wallet := range wallets {
+		export.Wallets = append(export.Wallets, wallet.ToResponse())
+	}
+
+	for _, transfer := range transfers {
+		export.Transfers = append(export.Transfers, transfer.ToResponse())
+	}
+
+	if export.Sessions == nil {
+		export.Sessions = []*SessionResponse{}
+	}
+
+	return export
+}
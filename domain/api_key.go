@@ -0,0 +1,218 @@
+package domain
+
+//go:generate mockgen -source=api_key.go -destination=../mocks/api_key_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// APIKeyTokenPrefix starts every API key, telling them apart from session
+// tokens in the Authorization header. The full key reads
+// ppk_<prefix>.<secret>; only the prefix is stored in clear.
+const APIKeyTokenPrefix = "ppk_"
+
+var (
+	ErrAPIKeyNotFound        = errors.New("api key not found")
+	ErrAPIKeyInvalid         = errors.New("invalid api key")
+	ErrAPIKeyIPNotAllowed    = errors.New("api key is not allowed from this ip")
+	ErrAPIKeyScopeNotAllowed = errors.New("api key lacks the required scope")
+	ErrAPIKeyWalletMismatch  = errors.New("api key is not allowed to operate this wallet")
+	ErrAPIKeyMerchantOnly    = errors.New("api keys can only be issued for merchant wallets")
+	ErrCreateAPIKey          = errors.New("create api key fail")
+	ErrGetAPIKeys            = errors.New("get api keys fail")
+	ErrRevokeAPIKey          = errors.New("revoke api key fail")
+)
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeStatementsRead APIKeyScope = "statements:read"
+	APIKeyScopeChargesWrite   APIKeyScope = "charges:write"
+	APIKeyScopeRefundsWrite   APIKeyScope = "refunds:write"
+)
+
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case APIKeyScopeStatementsRead, APIKeyScopeChargesWrite, APIKeyScopeRefundsWrite:
+		return true
+	}
+	return false
+}
+
+// APIKey lets a merchant backend act on one of its wallets without a user
+// session. Scopes and AllowedIPs are stored comma separated; an empty
+// AllowedIPs accepts any address.
+type APIKey struct {
+	ID         uuid.UUID      `gorm:"column:id;type:char(36);primaryKey"`
+	UserID     uuid.UUID      `gorm:"column:userId;type:char(36);not null;index"`
+	User       User           `gorm:"foreignKey:UserID"`
+	Currency   string         `gorm:"column:currency;type:char(3);not null"`
+	Name       string         `gorm:"column:name;type:varchar(100);not null"`
+	Prefix     string         `gorm:"column:prefix;type:varchar(16);uniqueIndex;not null"`
+	SecretHash string         `gorm:"column:secretHash;type:char(64);not null"`
+	Scopes     string         `gorm:"column:scopes;type:varchar(255);not null"`
+	AllowedIPs string         `gorm:"column:allowedIps;type:varchar(1000);default:NULL"`
+	LastUsedAt *time.Time     `gorm:"column:lastUsedAt;default:NULL"`
+	CreatedAt  time.Time      `gorm:"column:createdAt;not null"`
+	DeletedAt  gorm.DeletedAt `gorm:"column:deletedAt;index"`
+}
+
+func (APIKey) TableName() string {
+	return "APIKey"
+}
+
+type APIKeyPayload struct {
+	Name       string        `json:"name" validate:"required,min=1,max=100"`
+	Currency   string        `json:"currency" validate:"required,iso4217"`
+	Scopes     []APIKeyScope `json:"scopes" validate:"required,min=1,dive,apikeyscope"`
+	AllowedIPs []string      `json:"allowedIps" validate:"omitempty,max=20,dive,ip|cidr"`
+}
+
+type APIKeyResponse struct {
+	ID         uuid.UUID     `json:"id"`
+	Name       string        `json:"name"`
+	Currency   string        `json:"currency"`
+	Prefix     string        `json:"prefix"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	AllowedIPs []string      `json:"allowedIps,omitempty"`
+	LastUsedAt *time.Time    `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+// CreateAPIKeyResponse is the only time the full key is returned.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeyHandler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Revoke(ctx echo.Context) error
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, payload *APIKeyPayload) (*CreateAPIKeyResponse, error)
+	GetAll(ctx context.Context) ([]*APIKeyResponse, error)
+	Revoke(ctx context.Context, apiKeyID uuid.UUID) error
+	Authenticate(ctx context.Context, key, ip string) (*Session, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, apiKey *APIKey) error
+	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	Touch(ctx context.Context, apiKeyID uuid.UUID, usedAt time.Time) error
+	Delete(ctx context.Context, userID, apiKeyID uuid.UUID) error
+}
+
+func (a *APIKeyPayload) Validate() map[string]string {
+	a.Name = strings.TrimSpace(a.Name)
+	a.Currency = NormalizeCurrency(a.Currency)
+	for i, ip := range a.AllowedIPs {
+		a.AllowedIPs[i] = strings.TrimSpace(ip)
+	}
+	return ValidateStruct(a)
+}
+
+func (a *APIKeyPayload) ToAPIKey(userID uuid.UUID, prefix, secretHash string) *APIKey {
+	scopes := make([]string, 0, len(a.Scopes))
+	for _, scope := range a.Scopes {
+		if !slices.Contains(scopes, string(scope)) {
+			scopes = append(scopes, string(scope))
+		}
+	}
+
+	return &APIKey{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Currency:   a.Currency,
+		Name:       a.Name,
+		Prefix:     prefix,
+		SecretHash: secretHash,
+		Scopes:     strings.Join(scopes, ","),
+		AllowedIPs: strings.Join(a.AllowedIPs, ","),
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func (a *APIKey) ScopeList() []APIKeyScope {
+	var scopes []APIKeyScope
+	for _, scope := range splitList(a.Scopes) {
+		scopes = append(scopes, APIKeyScope(scope))
+	}
+	return scopes
+}
+
+func (a *APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(a.ScopeList(), scope)
+}
+
+// AllowsIP reports whether ip matches one of the allowed addresses or CIDR
+// ranges. Keys without an allowlist accept any address.
+func (a *APIKey) AllowsIP(ip string) bool {
+	allowed := splitList(a.AllowedIPs)
+	if len(allowed) == 0 {
+		return true
+	}
+
+	address := net.ParseIP(ip)
+	if address == nil {
+		return false
+	}
+
+	for _, entry := range allowed {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(address) {
+				return true
+			}
+			continue
+		}
+
+		if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *APIKey) ToResponse() *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         a.ID,
+		Name:       a.Name,
+		Currency:   a.Currency,
+		Prefix:     a.Prefix,
+		Scopes:     a.ScopeList(),
+		AllowedIPs: splitList(a.AllowedIPs),
+		LastUsedAt: a.LastUsedAt,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+// ToSession builds the principal of requests authenticated by the key, so
+// handlers and services read it the same way as a signed in user.
+func (a *APIKey) ToSession(user *User) *Session {
+	return &Session{
+		Name:   user.Name,
+		UserID: user.ID,
+		Email:  user.Email,
+		APIKey: a,
+	}
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,227 @@
+package domain
+
+//go:generate mockgen -source=campaign.go -destination=../mocks/campaign_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFundingWalletNotFound   = errors.New("campaign funding wallet not found")
+	ErrCampaignMerchantInvalid = errors.New("campaign merchant must have a merchant wallet")
+	ErrCampaignBudgetExhausted = errors.New("campaign budget exhausted")
+	ErrCreateCampaign          = errors.New("fail to create campaign")
+	ErrGetCampaigns            = errors.New("fail to get campaigns")
+	ErrGetRewards              = errors.New("fail to get rewards")
+)
+
+// Campaign grants cashback to payers of whitelisted merchants on transfers made
+// in Currency. Rewards are paid out of the funding wallet, identified by its
+// owner's user ID, and are limited per user by PerUserCap and overall by
+// RemainingBudget.
+type Campaign struct {
+	ID              uuid.UUID          `gorm:"column:id;type:char(36);primaryKey"`
+	Name            string             `gorm:"column:name;type:varchar(255);not null"`
+	FundingWalletID uuid.UUID          `gorm:"column:fundingWalletId;type:char(36);not null;index"`
+	Currency        string             `gorm:"column:currency;type:char(3);not null;default:'BRL'"`
+	Percentage      float64            `gorm:"column:percentage;type:decimal(5, 2);not null"`
+	PerUserCap      float64            `gorm:"column:perUserCap;type:decimal(15, 2);not null"`
+	Budget          float64            `gorm:"column:budget;type:decimal(15, 2);not null"`
+	RemainingBudget float64            `gorm:"column:remainingBudget;type:decimal(15, 2);not null"`
+	StartsAt        time.Time          `gorm:"column:startsAt;not null;index"`
+	EndsAt          time.Time          `gorm:"column:endsAt;not null;index"`
+	Merchants       []CampaignMerchant `gorm:"foreignKey:CampaignID"`
+	CreatedAt       time.Time          `gorm:"column:createdAt;not null"`
+	UpdatedAt       time.Time          `gorm:"column:updatedAt;default:NULL"`
+	DeletedAt       gorm.DeletedAt     `gorm:"column:deletedAt;index"`
+}
+
+func (Campaign) TableName() string {
+	return "Campaign"
+}
+
+func (c *Campaign) BeforeUpdate(tx *gorm.DB) (err error) {
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+func (c *Campaign) IsActive(now time.Time) bool {
+	return !now.Before(c.StartsAt) && now.Before(c.EndsAt) && c.RemainingBudget > 0
+}
+
+// RewardFor returns the cashback earned on value, limited by what is left of
+// the user's cap and of the campaign budget.
+func (c *Campaign) RewardFor(value, alreadyRewarded float64) float64 {
+	reward := math.Floor(value*c.Percentage) / 100
+	reward = math.Min(reward, c.PerUserCap-alreadyRewarded)
+	reward = math.Min(reward, c.RemainingBudget)
+	return math.Max(reward, 0)
+}
+
+// CampaignMerchant is the merchant whitelist of a campaign: only transfers to
+// these payees earn cashback.
+type CampaignMerchant struct {
+	CampaignID uuid.UUID `gorm:"column:campaignId;type:char(36);primaryKey"`
+	MerchantID uuid.UUID `gorm:"column:merchantId;type:char(36);primaryKey;index"`
+}
+
+func (CampaignMerchant) TableName() string {
+	return "CampaignMerchant"
+}
+
+// Reward is the cashback paid on a transfer. ClawedBack is the part taken
+// back from the user after the transfer was refunded or reversed by a
+// dispute; it is returned to the campaign budget.
+type Reward struct {
+	ID         uuid.UUID `gorm:"column:id;type:char(36);primaryKey"`
+	CampaignID uuid.UUID `gorm:"column:campaignId;type:char(36);not null;uniqueIndex:idx_reward_campaign_transfer;index:idx_reward_campaign_user"`
+	UserID     uuid.UUID `gorm:"column:userId;type:char(36);not null;index:idx_reward_campaign_user"`
+	TransferID uuid.UUID `gorm:"column:transferId;type:char(36);not null;uniqueIndex:idx_reward_campaign_transfer"`
+	Value      float64   `gorm:"column:value;type:decimal(15, 2);not null"`
+	ClawedBack float64   `gorm:"column:clawedBack;type:decimal(15, 2);not null;default:0"`
+	CreatedAt  time.Time `gorm:"column:createdAt;not null"`
+}
+
+func (Reward) TableName() string {
+	return "Reward"
+}
+
+type CampaignPayload struct {
+	Name            string      `json:"name" validate:"required,min=1,max=255"`
+	FundingWalletID uuid.UUID   `json:"fundingWalletId" validate:"required,uuid"`
+	Currency        string      `json:"currency" validate:"required,iso4217"`
+	Percentage      float64     `json:"percentage" validate:"required,gt=0,lte=100"`
+	PerUserCap      float64     `json:"perUserCap" validate:"required,gt=0"`
+	Budget          float64     `json:"budget" validate:"required,gt=0"`
+	StartsAt        time.Time   `json:"startsAt" validate:"required"`
+	EndsAt          time.Time   `json:"endsAt" validate:"required,gtfield=StartsAt"`
+	MerchantIDs     []uuid.UUID `json:"merchantIds" validate:"required,min=1"`
+}
+
+type CampaignResponse struct {
+	ID              uuid.UUID   `json:"id"`
+	Name            string      `json:"name"`
+	FundingWalletID uuid.UUID   `json:"fundingWalletId"`
+	Currency        string      `json:"currency"`
+	Percentage      float64     `json:"percentage"`
+	PerUserCap      float64     `json:"perUserCap"`
+	Budget          float64     `json:"budget"`
+	RemainingBudget float64     `json:"remainingBudget"`
+	StartsAt        time.Time   `json:"startsAt"`
+	EndsAt          time.Time   `json:"endsAt"`
+	MerchantIDs     []uuid.UUID `json:"merchantIds"`
+}
+
+type RewardResponse struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaignId"`
+	TransferID uuid.UUID `json:"transferId"`
+	Value      float64   `json:"value"`
+	ClawedBack float64   `json:"clawedBack"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type CampaignHandler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	GetRewards(ctx echo.Context) error
+}
+
+type CampaignService interface {
+	Create(ctx context.Context, payload *CampaignPayload) (*CampaignResponse, error)
+	GetAll(ctx context.Context) ([]*CampaignResponse, error)
+	GetRewards(ctx context.Context) ([]*RewardResponse, error)
+	EvaluateTransfer(ctx context.Context, transfer *Transfer) error
+}
+
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *Campaign) error
+	GetAll(ctx context.Context) ([]*Campaign, error)
+	GetActiveByMerchantID(ctx context.Context, merchantID uuid.UUID, currency string, now time.Time) ([]*Campaign, error)
+	GrantReward(ctx context.Context, campaignID uuid.UUID, transfer *Transfer) (*Reward, error)
+	GetRewardsByUserID(ctx context.Context, userID uuid.UUID) ([]*Reward, error)
+}
+
+func (c *CampaignPayload) Validate() map[string]string {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Currency = NormalizeCurrency(c.Currency)
+	return ValidateStruct(c)
+}
+
+func (c *CampaignPayload) ToCampaign() *Campaign {
+	campaign := &Campaign{
+		ID:              uuid.New(),
+		Name:            c.Name,
+		FundingWalletID: c.FundingWalletID,
+		Currency:        c.Currency,
+		Percentage:      c.Percentage,
+		PerUserCap:      c.PerUserCap,
+		Budget:          c.Budget,
+		RemainingBudget: c.Budget,
+		StartsAt:        c.StartsAt.UTC(),
+		EndsAt:          c.EndsAt.UTC(),
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	for _, merchantID := range c.MerchantIDs {
+		campaign.Merchants = append(campaign.Merchants, CampaignMerchant{
+			CampaignID: campaign.ID,
+			MerchantID: merchantID,
+		})
+	}
+
+	return campaign
+}
+
+func (c *Campaign) ToResponse() *CampaignResponse {
+	merchantIDs := make([]uuid.UUID, 0, len(c.Merchants))
+	for _, merchant := range c.Merchants {
+		merchantIDs = append(merchantIDs, merchant.MerchantID)
+	}
+
+	return &CampaignResponse{
+		ID:              c.ID,
+		Name:            c.Name,
+		FundingWalletID: c.FundingWalletID,
+		Currency:        c.Currency,
+		Percentage:      c.Percentage,
+		PerUserCap:      c.PerUserCap,
+		Budget:          c.Budget,
+		RemainingBudget: c.RemainingBudget,
+		StartsAt:        c.StartsAt,
+		EndsAt:          c.EndsAt,
+		MerchantIDs:     merchantIDs,
+	}
+}
+
+// ClawbackFor returns what is still to be taken back of the reward once
+// reversed, out of transferValue, has been given back to the payer. The
+// reward is clawed back in proportion and in full once the whole transfer
+// is reversed.
+func (r *Reward) ClawbackFor(transferValue, reversed float64) float64 {
+	target := r.Value
+	if reversed < transferValue {
+		target = math.Floor(r.Value*reversed/transferValue*100) / 100
+	}
+
+	return math.Max(target-r.ClawedBack, 0)
+}
+
+func (r *Reward) ToResponse() *RewardResponse {
+	return &RewardResponse{
+		ID:         r.ID,
+		CampaignID: r.CampaignID,
+		TransferID: r.TransferID,
+		Value:      r.Value,
+		ClawedBack: r.ClawedBack,
+		CreatedAt:  r.CreatedAt,
+	}
+}
@@ -0,0 +1,231 @@
+package domain
+
+//go:generate mockgen -source=contact.go -destination=../mocks/contact_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/klassmann/cpfcnpj"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrContactNotFound       = errors.New("contact not found")
+	ErrContactAlreadyExists  = errors.New("contact already exists")
+	ErrSelfContactNotAllowed = errors.New("users cannot add themselves as a contact")
+	ErrContactUserNotFound   = errors.New("no user found for the contact lookup")
+	ErrGetContacts           = errors.New("get contacts fail")
+	ErrSaveContact           = errors.New("save contact fail")
+	ErrDeleteContact         = errors.New("delete contact fail")
+	ErrRecordContactTransfer = errors.New("record contact transfer fail")
+)
+
+const (
+	DefaultRecentPayeesLimit = 10
+	MaxRecentPayeesLimit     = 50
+	// recentPayeeHalfLife is how long it takes for a past transfer to count
+	// half as much in the ranking of recent payees.
+	recentPayeeHalfLife = 30 * 24 * time.Hour
+)
+
+// Contact is a user that the owner pays or plans to pay. Contacts are
+// created by hand or by the first transfer to that user, and keep count of
+// the transfers made to them.
+type Contact struct {
+	UserID         uuid.UUID  `gorm:"column:userId;type:char(36);primaryKey"`
+	ContactID      uuid.UUID  `gorm:"column:contactId;type:char(36);primaryKey"`
+	Payee          User       `gorm:"foreignKey:ContactID"`
+	Nickname       string     `gorm:"column:nickname;type:varchar(50);not null;default:''"`
+	Favorite       bool       `gorm:"column:favorite;not null;default:false;index"`
+	TransferCount  int        `gorm:"column:transferCount;not null;default:0"`
+	LastTransferAt *time.Time `gorm:"column:lastTransferAt;default:NULL"`
+	CreatedAt      time.Time  `gorm:"column:createdAt;not null"`
+	UpdatedAt      time.Time  `gorm:"column:updatedAt;default:NULL"`
+}
+
+func (Contact) TableName() string {
+	return "Contact"
+}
+
+// RecentScore ranks the contact among recent payees: each transfer counts
+// once, halving every recentPayeeHalfLife since the last one.
+func (c *Contact) RecentScore(now time.Time) float64 {
+	if c.LastTransferAt == nil {
+		return 0
+	}
+
+	age := now.Sub(*c.LastTransferAt)
+	if age < 0 {
+		age = 0
+	}
+
+	return float64(c.TransferCount) * math.Pow(0.5, float64(age)/float64(recentPayeeHalfLife))
+}
+
+// ContactPayload adds the user with Document or Email as a contact. Exactly
+// one of them must be sent.
+type ContactPayload struct {
+	Document string `json:"document,omitempty" validate:"required_without=Email,excluded_with=Email,omitempty,cpf|cnpj"`
+	Email    string `json:"email,omitempty" validate:"required_without=Document,excluded_with=Document,omitempty,email"`
+	Nickname string `json:"nickname" validate:"max=50"`
+	Favorite bool   `json:"favorite"`
+}
+
+// UpdateContactPayload changes only the fields sent.
+type UpdateContactPayload struct {
+	Nickname *string `json:"nickname" validate:"omitempty,max=50"`
+	Favorite *bool   `json:"favorite"`
+}
+
+type RecentPayeesQuery struct {
+	Limit int
+}
+
+// ContactResponse identifies the contact by name while masking its email and
+// document. ID is the user ID to send as the payee of a transfer.
+type ContactResponse struct {
+	ID             uuid.UUID    `json:"id"`
+	Name           string       `json:"name"`
+	Nickname       string       `json:"nickname,omitempty"`
+	Favorite       bool         `json:"favorite"`
+	DocumentType   DocumentType `json:"documentType"`
+	Document       string       `json:"document"`
+	Email          string       `json:"email"`
+	TransferCount  int          `json:"transferCount"`
+	LastTransferAt *time.Time   `json:"lastTransferAt,omitempty"`
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+type ContactHandler interface {
+	GetAll(ctx echo.Context) error
+	Create(ctx echo.Context) error
+	Update(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+	GetRecentPayees(ctx echo.Context) error
+}
+
+type ContactService interface {
+	GetAll(ctx context.Context) ([]*ContactResponse, error)
+	Create(ctx context.Context, payload *ContactPayload) (*ContactResponse, error)
+	Update(ctx context.Context, contactID uuid.UUID, payload *UpdateContactPayload) (*ContactResponse, error)
+	Delete(ctx context.Context, contactID uuid.UUID) error
+	GetRecentPayees(ctx context.Context, query *RecentPayeesQuery) ([]*ContactResponse, error)
+	RecordTransfer(ctx context.Context, transfer *Transfer) error
+}
+
+type ContactRepository interface {
+	Create(ctx context.Context, contact *Contact) error
+	Get(ctx context.Context, userID, contactID uuid.UUID) (*Contact, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*Contact, error)
+	GetPaidByUserID(ctx context.Context, userID uuid.UUID) ([]*Contact, error)
+	Update(ctx context.Context, contact *Contact) error
+	Delete(ctx context.Context, userID, contactID uuid.UUID) (bool, error)
+	RecordTransfer(ctx context.Context, userID, contactID uuid.UUID, at time.Time) error
+}
+
+func (c *ContactPayload) Validate() map[string]string {
+	c.Document = strings.TrimSpace(c.Document)
+	c.Email = strings.TrimSpace(strings.ToLower(c.Email))
+	c.Nickname = strings.TrimSpace(c.Nickname)
+
+	return ValidateStruct(c)
+}
+
+// CleanDocument returns the document with only its digits, as stored.
+func (c *ContactPayload) CleanDocument() string {
+	return cpfcnpj.Clean(c.Document)
+}
+
+func (u *UpdateContactPayload) Validate() map[string]string {
+	if u.Nickname != nil {
+		*u.Nickname = strings.TrimSpace(*u.Nickname)
+	}
+
+	return ValidateStruct(u)
+}
+
+// NewRecentPayeesQuery parses the limit of the recent payees query string.
+// An empty value keeps the default.
+func NewRecentPayeesQuery(limit string) (*RecentPayeesQuery, map[string]string) {
+	query := &RecentPayeesQuery{Limit: DefaultRecentPayeesLimit}
+
+	if limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > MaxRecentPayeesLimit {
+			return nil, map[string]string{"limit": "Limit must be between 1 and " + strconv.Itoa(MaxRecentPayeesLimit)}
+		}
+		query.Limit = parsed
+	}
+
+	return query, nil
+}
+
+// SortContacts puts favorites first, then orders by nickname, or name when
+// there is none.
+func SortContacts(contacts []*Contact) {
+	sort.SliceStable(contacts, func(i, j int) bool {
+		if contacts[i].Favorite != contacts[j].Favorite {
+			return contacts[i].Favorite
+		}
+		return strings.ToLower(contacts[i].DisplayName()) < strings.ToLower(contacts[j].DisplayName())
+	})
+}
+
+// SortRecentPayees orders contacts by RecentScore, highest first.
+func SortRecentPayees(contacts []*Contact, now time.Time) {
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return contacts[i].RecentScore(now) > contacts[j].RecentScore(now)
+	})
+}
+
+func (c *Contact) DisplayName() string {
+	if c.Nickname != "" {
+		return c.Nickname
+	}
+	return c.Payee.Name
+}
+
+func (c *Contact) ToResponse() *ContactResponse {
+	return &ContactResponse{
+		ID:             c.ContactID,
+		Name:           c.Payee.Name,
+		Nickname:       c.Nickname,
+		Favorite:       c.Favorite,
+		DocumentType:   c.Payee.DocumentType,
+		Document:       MaskDocument(c.Payee.Document),
+		Email:          MaskEmail(c.Payee.Email),
+		TransferCount:  c.TransferCount,
+		LastTransferAt: c.LastTransferAt,
+		CreatedAt:      c.CreatedAt,
+	}
+}
+
+// MaskEmail keeps the first letter of the local part and the domain, as in
+// a***@example.com.
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// MaskDocument shows only the middle digits of a CPF, the way receipts do
+// (***.982.247-**), and the root of a CNPJ, which identifies the company
+// rather than a person.
+func MaskDocument(document string) string {
+	switch len(document) {
+	case 11:
+		return "***." + document[3:6] + "." + document[6:9] + "-**"
+	case 14:
+		return document[:2] + "." + document[2:5] + "." + document[5:8] + "/****-**"
+	}
+	return "***"
+}
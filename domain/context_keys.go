@@ -1,7 +1,31 @@
 package domain
 
+import (
+	"context"
+	"log/slog"
+)
+
 type ContextKey string
 
 const (
-	SessionKey ContextKey = "session"
+	SessionKey   ContextKey = "session"
+	RequestIDKey ContextKey = "requestID"
+	LoggerKey    ContextKey = "logger"
 )
+
+// LoggerFromContext returns the logger of the request ctx belongs to, which
+// tags every line with the request ID and, once signed in, the user ID.
+// Outside of a request, such as in jobs, it returns the default logger.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(LoggerKey).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// RequestIDFromContext returns the ID of the request ctx belongs to, or an
+// empty string outside of a request.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"net/url"
+
 	"github.com/dlclark/regexp2"
 	"github.com/google/uuid"
 	"github.com/klassmann/cpfcnpj"
@@ -9,17 +11,37 @@ import (
 )
 
 const (
-	StrongPasswordTag = "strongpassword"
-	CPFTag            = "cpf"
-	UUIDTag           = "uuid"
-	WalletTypeTag     = "wallettype"
+	StrongPasswordTag   = "strongpassword"
+	CPFTag              = "cpf"
+	CNPJTag             = "cnpj"
+	DocumentTypeTag     = "documenttype"
+	UUIDTag             = "uuid"
+	WalletTypeTag       = "wallettype"
+	EscrowResolutionTag = "escrowresolution"
+	DisputeOutcomeTag   = "disputeoutcome"
+	APIKeyScopeTag      = "apikeyscope"
+	OAuthScopeTag       = "oauthscope"
+	RedirectURITag      = "redirecturi"
+	RoleTag             = "role"
+	PINTag              = "pin"
+	KYCDecisionTag      = "kycdecision"
 )
 
 func SetupCustomValidations(validator *validator.Validate) {
 	validator.RegisterValidation("strongpassword", strongPasswordValidator)
 	validator.RegisterValidation("cpf", cpfValidator)
+	validator.RegisterValidation("cnpj", cnpjValidator)
+	validator.RegisterValidation("documenttype", documentTypeValidator)
 	validator.RegisterValidation("uuid", uuidValidator)
 	validator.RegisterValidation("wallettype", walletTypeValidator)
+	validator.RegisterValidation("escrowresolution", escrowResolutionValidator)
+	validator.RegisterValidation("disputeoutcome", disputeOutcomeValidator)
+	validator.RegisterValidation("apikeyscope", apiKeyScopeValidator)
+	validator.RegisterValidation("oauthscope", oauthScopeValidator)
+	validator.RegisterValidation("redirecturi", redirectURIValidator)
+	validator.RegisterValidation("role", roleValidator)
+	validator.RegisterValidation("pin", pinValidator)
+	validator.RegisterValidation("kycdecision", kycDecisionValidator)
 }
 
 func strongPasswordValidator(fl validator.FieldLevel) bool {
@@ -37,6 +59,20 @@ func cpfValidator(fl validator.FieldLevel) bool {
 	return cpf.IsValid()
 }
 
+func cnpjValidator(fl validator.FieldLevel) bool {
+	cnpj := cpfcnpj.NewCNPJ(fl.Field().String())
+
+	return cnpj.IsValid()
+}
+
+func documentTypeValidator(fl validator.FieldLevel) bool {
+	documentType, ok := fl.Field().Interface().(DocumentType)
+	if !ok {
+		return false
+	}
+	return documentType.IsValid()
+}
+
 func uuidValidator(fl validator.FieldLevel) bool {
 	_, err := uuid.Parse(fl.Field().String())
 
@@ -50,3 +86,94 @@ func walletTypeValidator(fl validator.FieldLevel) bool {
 	}
 	return walletType.IsValid()
 }
+
+func escrowResolutionValidator(fl validator.FieldLevel) bool {
+	resolution, ok := fl.Field().Interface().(EscrowResolution)
+	if !ok {
+		return false
+	}
+	return resolution.IsValid()
+}
+
+func disputeOutcomeValidator(fl validator.FieldLevel) bool {
+	outcome, ok := fl.Field().Interface().(DisputeOutcome)
+	if !ok {
+		return false
+	}
+	return outcome.IsValid()
+}
+
+func apiKeyScopeValidator(fl validator.FieldLevel) bool {
+	scope, ok := fl.Field().Interface().(APIKeyScope)
+	if !ok {
+		return false
+	}
+	return scope.IsValid()
+}
+
+func oauthScopeValidator(fl validator.FieldLevel) bool {
+	scope, ok := fl.Field().Interface().(OAuthScope)
+	if !ok {
+		return false
+	}
+	return scope.IsValid()
+}
+
+// redirectURIValidator accepts absolute https URIs without a fragment, and
+// plain http only on the loopback interface for native apps.
+func redirectURIValidator(fl validator.FieldLevel) bool {
+	parsed, err := url.Parse(fl.Field().String())
+	if err != nil || parsed.Host == "" || parsed.Fragment != "" {
+		return false
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := parsed.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+	return false
+}
+
+func roleValidator(fl validator.FieldLevel) bool {
+	role, ok := fl.Field().Interface().(Role)
+	if !ok {
+		return false
+	}
+	return role.IsValid()
+}
+
+func kycDecisionValidator(fl validator.FieldLevel) bool {
+	decision, ok := fl.Field().Interface().(KYCDecision)
+	if !ok {
+		return false
+	}
+	return decision.IsValid()
+}
+
+// pinValidator accepts 4 to 6 digits, rejecting repeated digits such as 0000
+// and runs such as 1234 or 654321, which are the first guesses.
+func pinValidator(fl validator.FieldLevel) bool {
+	pin := fl.Field().String()
+	if len(pin) < 4 || len(pin) > 6 {
+		return false
+	}
+
+	for _, digit := range pin {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+
+	repeated, ascending, descending := true, true, true
+	for i := 1; i < len(pin); i++ {
+		step := int(pin[i]) - int(pin[i-1])
+		repeated = repeated && step == 0
+		ascending = ascending && step == 1
+		descending = descending && step == -1
+	}
+
+	return !repeated && !ascending && !descending
+}
@@ -0,0 +1,233 @@
+package domain
+
+//go:generate mockgen -source=dispute.go -destination=../mocks/dispute_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDisputeNotFound          = errors.New("dispute not found")
+	ErrDisputeForbidden         = errors.New("user is not allowed to operate this dispute")
+	ErrDisputeAlreadyExists     = errors.New("there is already a dispute for this transfer")
+	ErrDisputeAlreadyResolved   = errors.New("dispute is already resolved")
+	ErrDisputeInvalidTransition = errors.New("dispute cannot move to the requested status")
+	ErrDisputeNotAllowed        = errors.New("this transfer cannot be disputed")
+	ErrEvidenceNotFound         = errors.New("dispute evidence not found")
+	ErrEvidenceTooLarge         = errors.New("evidence file is too large")
+	ErrCreateDispute            = errors.New("fail to create dispute")
+	ErrUpdateDispute            = errors.New("fail to update dispute")
+	ErrStoreEvidence            = errors.New("fail to store dispute evidence")
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpened              DisputeStatus = "opened"
+	DisputeStatusMerchantResponded   DisputeStatus = "merchant_responded"
+	DisputeStatusResolvedForPayer    DisputeStatus = "resolved_payer"
+	DisputeStatusResolvedForMerchant DisputeStatus = "resolved_merchant"
+)
+
+func (s DisputeStatus) IsResolved() bool {
+	return s == DisputeStatusResolvedForPayer || s == DisputeStatusResolvedForMerchant
+}
+
+type DisputeOutcome string
+
+const (
+	DisputeOutcomePayer    DisputeOutcome = "payer"
+	DisputeOutcomeMerchant DisputeOutcome = "merchant"
+)
+
+func (o DisputeOutcome) IsValid() bool {
+	switch o {
+	case DisputeOutcomePayer, DisputeOutcomeMerchant:
+		return true
+	}
+	return false
+}
+
+type Dispute struct {
+	ID                 uuid.UUID         `gorm:"column:id;type:char(36);primaryKey"`
+	TransferID         uuid.UUID         `gorm:"column:transferId;type:char(36);not null;index"`
+	Transfer           Transfer          `gorm:"foreignKey:TransferID"`
+	PayerID            uuid.UUID         `gorm:"column:payerId;type:char(36);not null;index"`
+	MerchantID         uuid.UUID         `gorm:"column:merchantId;type:char(36);not null;index"`
+	Reason             string            `gorm:"column:reason;type:varchar(1000);not null"`
+	MerchantResponse   string            `gorm:"column:merchantResponse;type:varchar(1000);default:NULL"`
+	Status             DisputeStatus     `gorm:"column:status;type:varchar(20);not null;index"`
+	ReversalTransferID *uuid.UUID        `gorm:"column:reversalTransferId;type:char(36);default:NULL"`
+	ResolvedAt         *time.Time        `gorm:"column:resolvedAt;default:NULL"`
+	Evidences          []DisputeEvidence `gorm:"foreignKey:DisputeID"`
+	CreatedAt          time.Time         `gorm:"column:createdAt;not null"`
+	UpdatedAt          time.Time         `gorm:"column:updatedAt;default:NULL"`
+	DeletedAt          gorm.DeletedAt    `gorm:"column:deletedAt;index"`
+}
+
+func (Dispute) TableName() string {
+	return "Dispute"
+}
+
+func (d *Dispute) BeforeUpdate(tx *gorm.DB) (err error) {
+	d.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+func (d *Dispute) IsParticipant(userID uuid.UUID) bool {
+	return d.PayerID == userID || d.MerchantID == userID
+}
+
+type DisputeEvidence struct {
+	ID          uuid.UUID `gorm:"column:id;type:char(36);primaryKey"`
+	DisputeID   uuid.UUID `gorm:"column:disputeId;type:char(36);not null;index"`
+	UploadedBy  uuid.UUID `gorm:"column:uploadedBy;type:char(36);not null"`
+	FileName    string    `gorm:"column:fileName;type:varchar(255);not null"`
+	ContentType string    `gorm:"column:contentType;type:varchar(100);not null"`
+	Size        int64     `gorm:"column:size;not null"`
+	StorageKey  string    `gorm:"column:storageKey;type:varchar(255);not null"`
+	CreatedAt   time.Time `gorm:"column:createdAt;not null"`
+}
+
+func (DisputeEvidence) TableName() string {
+	return "DisputeEvidence"
+}
+
+type OpenDisputePayload struct {
+	TransferID uuid.UUID `json:"transferId" validate:"required,uuid"`
+	Reason     string    `json:"reason" validate:"required,min=1,max=1000"`
+}
+
+type RespondDisputePayload struct {
+	Response string `json:"response" validate:"required,min=1,max=1000"`
+}
+
+type ResolveDisputePayload struct {
+	Outcome DisputeOutcome `json:"outcome" validate:"required,disputeoutcome"`
+}
+
+// EvidenceUpload carries an uploaded file from the handler to the service.
+type EvidenceUpload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+type DisputeEvidenceResponse struct {
+	ID          uuid.UUID `json:"id"`
+	UploadedBy  uuid.UUID `json:"uploadedBy"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type DisputeResponse struct {
+	ID                 uuid.UUID                 `json:"id"`
+	TransferID         uuid.UUID                 `json:"transferId"`
+	PayerID            uuid.UUID                 `json:"payerId"`
+	MerchantID         uuid.UUID                 `json:"merchantId"`
+	Reason             string                    `json:"reason"`
+	MerchantResponse   string                    `json:"merchantResponse,omitempty"`
+	Status             DisputeStatus             `json:"status"`
+	ReversalTransferID *uuid.UUID                `json:"reversalTransferId,omitempty"`
+	ResolvedAt         *time.Time                `json:"resolvedAt,omitempty"`
+	Evidences          []DisputeEvidenceResponse `json:"evidences"`
+	CreatedAt          time.Time                 `json:"createdAt"`
+}
+
+type DisputeHandler interface {
+	Open(ctx echo.Context) error
+	GetByID(ctx echo.Context) error
+	Respond(ctx echo.Context) error
+	UploadEvidence(ctx echo.Context) error
+	DownloadEvidence(ctx echo.Context) error
+	Resolve(ctx echo.Context) error
+}
+
+type DisputeService interface {
+	Open(ctx context.Context, payload *OpenDisputePayload) (*DisputeResponse, error)
+	GetByID(ctx context.Context, disputeID uuid.UUID) (*DisputeResponse, error)
+	Respond(ctx context.Context, disputeID uuid.UUID, payload *RespondDisputePayload) (*DisputeResponse, error)
+	UploadEvidence(ctx context.Context, disputeID uuid.UUID, upload *EvidenceUpload) (*DisputeEvidenceResponse, error)
+	DownloadEvidence(ctx context.Context, disputeID, evidenceID uuid.UUID) (*DisputeEvidence, io.ReadCloser, error)
+	Resolve(ctx context.Context, disputeID uuid.UUID, payload *ResolveDisputePayload) (*DisputeResponse, error)
+}
+
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *Dispute) error
+	GetByID(ctx context.Context, disputeID uuid.UUID) (*Dispute, error)
+	GetByTransferID(ctx context.Context, transferID uuid.UUID) (*Dispute, error)
+	Respond(ctx context.Context, disputeID uuid.UUID, response string) (*Dispute, error)
+	AddEvidence(ctx context.Context, evidence *DisputeEvidence) error
+	GetEvidence(ctx context.Context, disputeID, evidenceID uuid.UUID) (*DisputeEvidence, error)
+	ResolveForMerchant(ctx context.Context, disputeID uuid.UUID) (*Dispute, error)
+	ResolveForPayer(ctx context.Context, disputeID uuid.UUID) (*Dispute, error)
+}
+
+func (o *OpenDisputePayload) Validate() map[string]string {
+	o.Reason = strings.TrimSpace(o.Reason)
+	return ValidateStruct(o)
+}
+
+func (r *RespondDisputePayload) Validate() map[string]string {
+	r.Response = strings.TrimSpace(r.Response)
+	return ValidateStruct(r)
+}
+
+func (r *ResolveDisputePayload) Validate() map[string]string {
+	return ValidateStruct(r)
+}
+
+func (o *OpenDisputePayload) ToDispute(transfer *Transfer) *Dispute {
+	return &Dispute{
+		ID:         uuid.New(),
+		TransferID: transfer.ID,
+		PayerID:    transfer.PayerID,
+		MerchantID: transfer.PayeeID,
+		Reason:     o.Reason,
+		Status:     DisputeStatusOpened,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func (e *DisputeEvidence) ToResponse() *DisputeEvidenceResponse {
+	return &DisputeEvidenceResponse{
+		ID:          e.ID,
+		UploadedBy:  e.UploadedBy,
+		FileName:    e.FileName,
+		ContentType: e.ContentType,
+		Size:        e.Size,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+func (d *Dispute) ToResponse() *DisputeResponse {
+	evidences := make([]DisputeEvidenceResponse, 0, len(d.Evidences))
+	for _, evidence := range d.Evidences {
+		evidences = append(evidences, *evidence.ToResponse())
+	}
+
+	return &DisputeResponse{
+		ID:                 d.ID,
+		TransferID:         d.TransferID,
+		PayerID:            d.PayerID,
+		MerchantID:         d.MerchantID,
+		Reason:             d.Reason,
+		MerchantResponse:   d.MerchantResponse,
+		Status:             d.Status,
+		ReversalTransferID: d.ReversalTransferID,
+		ResolvedAt:         d.ResolvedAt,
+		Evidences:          evidences,
+		CreatedAt:          d.CreatedAt,
+	}
+}
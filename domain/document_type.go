@@ -0,0 +1,26 @@
+package domain
+
+// DocumentType tells whether a user registered as an individual, with a CPF,
+// or as a company, with a CNPJ. Only companies can be merchants.
+type DocumentType string
+
+const (
+	DocumentTypeCPF  DocumentType = "cpf"
+	DocumentTypeCNPJ DocumentType = "cnpj"
+)
+
+func (d DocumentType) IsValid() bool {
+	switch d {
+	case DocumentTypeCPF, DocumentTypeCNPJ:
+		return true
+	}
+	return false
+}
+
+// Tag returns the validation tag of the documents of this type.
+func (d DocumentType) Tag() string {
+	if d == DocumentTypeCNPJ {
+		return CNPJTag
+	}
+	return CPFTag
+}
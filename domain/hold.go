@@ -0,0 +1,165 @@
+package domain
+
+//go:generate mockgen -source=hold.go -destination=../mocks/hold_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHoldNotFound             = errors.New("hold not found")
+	ErrHoldNotActive            = errors.New("hold is not active")
+	ErrHoldExpired              = errors.New("hold has expired")
+	ErrHoldCaptureExceedsAmount = errors.New("capture value exceeds the remaining hold amount")
+	ErrHoldPayeeNotMerchant     = errors.New("holds can only be created in favour of merchant wallets")
+	ErrHoldForbidden            = errors.New("user is not allowed to operate this hold")
+	ErrCreateHold               = errors.New("fail to create hold")
+	ErrCaptureHold              = errors.New("fail to capture hold")
+	ErrVoidHold                 = errors.New("fail to void hold")
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusVoided   HoldStatus = "voided"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+type Hold struct {
+	ID             uuid.UUID      `gorm:"column:id;type:char(36);primaryKey"`
+	PayerID        uuid.UUID      `gorm:"column:payerId;type:char(36);not null;index"`
+	PayeeID        uuid.UUID      `gorm:"column:payeeId;type:char(36);not null;index"`
+	Payer          User           `gorm:"foreignKey:PayerID"`
+	Payee          User           `gorm:"foreignKey:PayeeID"`
+	Currency       string         `gorm:"column:currency;type:char(3);not null;default:'BRL'"`
+	Amount         float64        `gorm:"column:amount;type:decimal(15, 2);not null"`
+	CapturedAmount float64        `gorm:"column:capturedAmount;type:decimal(15, 2);not null;default:0"`
+	Status         HoldStatus     `gorm:"column:status;type:varchar(20);not null;index"`
+	ExpiresAt      time.Time      `gorm:"column:expiresAt;not null;index"`
+	CreatedAt      time.Time      `gorm:"column:createdAt;not null"`
+	UpdatedAt      time.Time      `gorm:"column:updatedAt;default:NULL"`
+	DeletedAt      gorm.DeletedAt `gorm:"column:deletedAt;index"`
+}
+
+func (Hold) TableName() string {
+	return "Hold"
+}
+
+func (h *Hold) BeforeUpdate(tx *gorm.DB) (err error) {
+	h.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Remaining returns the part of the hold that can still be captured.
+func (h *Hold) Remaining() float64 {
+	return h.Amount - h.CapturedAmount
+}
+
+func (h *Hold) IsExpired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+// HoldPayload reserves Value for a merchant to capture later. PIN is the
+// transaction PIN of the payer, as for a transfer.
+type HoldPayload struct {
+	PayeeID          uuid.UUID `json:"payeeId" validate:"required,uuid"`
+	Value            float64   `json:"value" validate:"required,gt=0"`
+	Currency         string    `json:"currency" validate:"required,iso4217"`
+	ExpiresInMinutes int       `json:"expiresInMinutes" validate:"omitempty,gt=0,max=43200"`
+	PIN              string    `json:"pin" validate:"required"`
+}
+
+// CaptureHoldPayload captures Value from the hold. When Value is zero the
+// whole remaining amount is captured. Final releases whatever is left after
+// this capture, closing the hold.
+type CaptureHoldPayload struct {
+	Value float64 `json:"value" validate:"omitempty,gt=0"`
+	Final bool    `json:"final"`
+}
+
+type HoldResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	PayerID        uuid.UUID  `json:"payerId"`
+	PayeeID        uuid.UUID  `json:"payeeId"`
+	Currency       string     `json:"currency"`
+	Amount         float64    `json:"amount"`
+	CapturedAmount float64    `json:"capturedAmount"`
+	Status         HoldStatus `json:"status"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type HoldHandler interface {
+	Create(ctx echo.Context) error
+	GetByID(ctx echo.Context) error
+	Capture(ctx echo.Context) error
+	Void(ctx echo.Context) error
+}
+
+type HoldService interface {
+	Create(ctx context.Context, payload *HoldPayload) (*HoldResponse, error)
+	GetByID(ctx context.Context, holdID uuid.UUID) (*HoldResponse, error)
+	Capture(ctx context.Context, holdID uuid.UUID, payload *CaptureHoldPayload) (*HoldResponse, error)
+	Void(ctx context.Context, holdID uuid.UUID) (*HoldResponse, error)
+	ReleaseExpired(ctx context.Context) (int64, error)
+}
+
+type HoldRepository interface {
+	Create(ctx context.Context, hold *Hold) error
+	GetByID(ctx context.Context, holdID uuid.UUID) (*Hold, error)
+	GetActiveAmountByPayerID(ctx context.Context, payerID uuid.UUID, currency string) (float64, error)
+	Capture(ctx context.Context, holdID uuid.UUID, value float64, final bool, payeeBalanceCap *KYCBalanceCap) (*Hold, *Transfer, error)
+	Void(ctx context.Context, holdID uuid.UUID) (*Hold, error)
+	ExpireActive(ctx context.Context, now time.Time) (int64, error)
+}
+
+func (h *HoldPayload) Validate() map[string]string {
+	h.Currency = NormalizeCurrency(h.Currency)
+	return ValidateStruct(h)
+}
+
+func (c *CaptureHoldPayload) Validate() map[string]string {
+	return ValidateStruct(c)
+}
+
+func (h *HoldPayload) ToHold(payerID uuid.UUID, defaultExpiration time.Duration) *Hold {
+	now := time.Now().UTC()
+
+	expiration := defaultExpiration
+	if h.ExpiresInMinutes > 0 {
+		expiration = time.Duration(h.ExpiresInMinutes) * time.Minute
+	}
+
+	return &Hold{
+		ID:        uuid.New(),
+		PayerID:   payerID,
+		PayeeID:   h.PayeeID,
+		Currency:  h.Currency,
+		Amount:    h.Value,
+		Status:    HoldStatusActive,
+		ExpiresAt: now.Add(expiration),
+		CreatedAt: now,
+	}
+}
+
+func (h *Hold) ToResponse() *HoldResponse {
+	return &HoldResponse{
+		ID:             h.ID,
+		PayerID:        h.PayerID,
+		PayeeID:        h.PayeeID,
+		Currency:       h.Currency,
+		Amount:         h.Amount,
+		CapturedAmount: h.CapturedAmount,
+		Status:         h.Status,
+		ExpiresAt:      h.ExpiresAt,
+		CreatedAt:      h.CreatedAt,
+	}
+}
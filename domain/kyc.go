@@ -0,0 +1,286 @@
+package domain
+
+//go:generate mockgen -source=kyc.go -destination=../mocks/kyc_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrKYCMaxTier                = errors.New("user already has the highest kyc tier")
+	ErrKYCSubmissionPending      = errors.New("there is already a kyc submission under review")
+	ErrKYCSubmissionNotFound     = errors.New("kyc submission not found")
+	ErrKYCSubmissionReviewed     = errors.New("kyc submission was already reviewed")
+	ErrKYCSelfReview             = errors.New("users cannot review their own kyc submission")
+	ErrKYCDocumentNotFound       = errors.New("kyc document not found")
+	ErrKYCDocumentMissing        = errors.New("kyc submission is missing required documents")
+	ErrKYCDocumentTooLarge       = errors.New("kyc document file is too large")
+	ErrKYCDocumentType           = errors.New("kyc document must be a jpeg, png or pdf file")
+	ErrKYCTransferLimitExceeded  = errors.New("transfer value exceeds the limit of the kyc tier")
+	ErrKYCBalanceLimitExceeded   = errors.New("payee balance would exceed the limit of the kyc tier")
+	ErrGetKYC                    = errors.New("get kyc fail")
+	ErrCreateKYCSubmission       = errors.New("create kyc submission fail")
+	ErrReviewKYCSubmission       = errors.New("review kyc submission fail")
+	ErrStoreKYCDocument          = errors.New("fail to store kyc document")
+	ErrKYCRejectionReasonMissing = errors.New("a reason is required to reject a kyc submission")
+)
+
+// KYCTier is how far a user proved their identity. Every user starts at
+// basic, which only needs the verified email, and moves up one tier at a
+// time through reviewed submissions.
+type KYCTier string
+
+const (
+	KYCTierBasic    KYCTier = "basic"
+	KYCTierVerified KYCTier = "verified"
+	KYCTierEnhanced KYCTier = "enhanced"
+)
+
+// Next returns the tier a user at t can apply for, and false at the top.
+func (t KYCTier) Next() (KYCTier, bool) {
+	switch t {
+	case KYCTierVerified:
+		return KYCTierEnhanced, true
+	case KYCTierEnhanced:
+		return "", false
+	}
+	return KYCTierVerified, true
+}
+
+// Requirements lists the documents a submission for t must carry.
+func (t KYCTier) Requirements() []KYCDocumentKind {
+	switch t {
+	case KYCTierVerified:
+		return []KYCDocumentKind{KYCDocumentIdentity, KYCDocumentSelfie}
+	case KYCTierEnhanced:
+		return []KYCDocumentKind{KYCDocumentProofOfAddress}
+	}
+	return nil
+}
+
+// KYCLimits caps the value of a single transfer and the balance a user holds
+// across all of their wallets, both in Currency. Amounts in other currencies
+// are converted before being compared. Zero means no limit.
+type KYCLimits struct {
+	Currency string  `json:"currency"`
+	Transfer float64 `json:"transfer,omitempty"`
+	Balance  float64 `json:"balance,omitempty"`
+}
+
+func (l *KYCLimits) AllowsTransfer(value float64) bool {
+	return l.Transfer <= 0 || value <= l.Transfer
+}
+
+func (l *KYCLimits) AllowsBalance(balance float64) bool {
+	return l.Balance <= 0 || balance <= l.Balance
+}
+
+// KYCBalanceCap is the balance limit of a payee together with the rate
+// converting each currency they hold into the currency of the limit.
+// Repositories crediting the payee check it while their wallets are locked,
+// so concurrent credits cannot both pass it.
+type KYCBalanceCap struct {
+	Limits *KYCLimits
+	Rates  map[string]float64
+}
+
+// Allows reports whether wallets, after crediting value in currency, stay
+// within the limit. A currency without a rate is refused, as its balance
+// cannot be accounted for.
+func (c *KYCBalanceCap) Allows(wallets []*Wallet, currency string, value float64) bool {
+	rate, ok := c.Rates[currency]
+	if !ok {
+		return false
+	}
+
+	total := value * rate
+	for _, wallet := range wallets {
+		rate, ok := c.Rates[wallet.Currency]
+		if !ok {
+			return false
+		}
+		total += wallet.Balance * rate
+	}
+
+	return c.Limits.AllowsBalance(total)
+}
+
+type KYCDocumentKind string
+
+const (
+	KYCDocumentIdentity       KYCDocumentKind = "identity"
+	KYCDocumentSelfie         KYCDocumentKind = "selfie"
+	KYCDocumentProofOfAddress KYCDocumentKind = "proofOfAddress"
+)
+
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusApproved KYCStatus = "approved"
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+type KYCDecision string
+
+const (
+	KYCDecisionApprove KYCDecision = "approve"
+	KYCDecisionReject  KYCDecision = "reject"
+)
+
+func (d KYCDecision) IsValid() bool {
+	switch d {
+	case KYCDecisionApprove, KYCDecisionReject:
+		return true
+	}
+	return false
+}
+
+// KYCSubmission asks for Tier with the documents it requires. A user has at
+// most one pending submission at a time.
+type KYCSubmission struct {
+	ID              uuid.UUID     `gorm:"column:id;type:char(36);primaryKey"`
+	UserID          uuid.UUID     `gorm:"column:userId;type:char(36);not null;index"`
+	Tier            KYCTier       `gorm:"column:tier;type:varchar(20);not null"`
+	Status          KYCStatus     `gorm:"column:status;type:varchar(20);not null;index"`
+	RejectionReason string        `gorm:"column:rejectionReason;type:varchar(500);default:NULL"`
+	ReviewedBy      *uuid.UUID    `gorm:"column:reviewedBy;type:char(36);default:NULL"`
+	ReviewedAt      *time.Time    `gorm:"column:reviewedAt;default:NULL"`
+	Documents       []KYCDocument `gorm:"foreignKey:SubmissionID"`
+	CreatedAt       time.Time     `gorm:"column:createdAt;not null"`
+}
+
+func (KYCSubmission) TableName() string {
+	return "KYCSubmission"
+}
+
+type KYCDocument struct {
+	ID           uuid.UUID       `gorm:"column:id;type:char(36);primaryKey"`
+	SubmissionID uuid.UUID       `gorm:"column:submissionId;type:char(36);not null;index"`
+	Kind         KYCDocumentKind `gorm:"column:kind;type:varchar(20);not null"`
+	FileName     string          `gorm:"column:fileName;type:varchar(255);not null"`
+	ContentType  string          `gorm:"column:contentType;type:varchar(100);not null"`
+	Size         int64           `gorm:"column:size;not null"`
+	StorageKey   string          `gorm:"column:storageKey;type:varchar(255);not null"`
+	CreatedAt    time.Time       `gorm:"column:createdAt;not null"`
+}
+
+func (KYCDocument) TableName() string {
+	return "KYCDocument"
+}
+
+// KYCDocumentUpload carries an uploaded document from the handler to the
+// service.
+type KYCDocumentUpload struct {
+	Kind        KYCDocumentKind
+	FileName    string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+type ReviewKYCPayload struct {
+	Decision KYCDecision `json:"decision" validate:"required,kycdecision"`
+	Reason   string      `json:"reason" validate:"max=500"`
+}
+
+type KYCDocumentResponse struct {
+	ID          uuid.UUID       `json:"id"`
+	Kind        KYCDocumentKind `json:"kind"`
+	FileName    string          `json:"fileName"`
+	ContentType string          `json:"contentType"`
+	Size        int64           `json:"size"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+type KYCSubmissionResponse struct {
+	ID              uuid.UUID             `json:"id"`
+	UserID          uuid.UUID             `json:"userId"`
+	Tier            KYCTier               `json:"tier"`
+	Status          KYCStatus             `json:"status"`
+	RejectionReason string                `json:"rejectionReason,omitempty"`
+	ReviewedAt      *time.Time            `json:"reviewedAt,omitempty"`
+	Documents       []KYCDocumentResponse `json:"documents"`
+	CreatedAt       time.Time             `json:"createdAt"`
+}
+
+// KYCUpgradeResponse describes the next tier: its limits and the documents
+// to send for it.
+type KYCUpgradeResponse struct {
+	Tier         KYCTier           `json:"tier"`
+	Limits       *KYCLimits        `json:"limits"`
+	Requirements []KYCDocumentKind `json:"requirements"`
+}
+
+type KYCStatusResponse struct {
+	Tier              KYCTier                `json:"tier"`
+	Limits            *KYCLimits             `json:"limits"`
+	Upgrade           *KYCUpgradeResponse    `json:"upgrade,omitempty"`
+	PendingSubmission *KYCSubmissionResponse `json:"pendingSubmission,omitempty"`
+}
+
+type KYCHandler interface {
+	GetStatus(ctx echo.Context) error
+	Submit(ctx echo.Context) error
+	GetPendingSubmissions(ctx echo.Context) error
+	DownloadDocument(ctx echo.Context) error
+	Review(ctx echo.Context) error
+}
+
+type KYCService interface {
+	GetStatus(ctx context.Context) (*KYCStatusResponse, error)
+	Submit(ctx context.Context, uploads []*KYCDocumentUpload) (*KYCSubmissionResponse, error)
+	GetPendingSubmissions(ctx context.Context) ([]*KYCSubmissionResponse, error)
+	DownloadDocument(ctx context.Context, submissionID, documentID uuid.UUID) (*KYCDocument, io.ReadCloser, error)
+	Review(ctx context.Context, submissionID uuid.UUID, payload *ReviewKYCPayload) (*KYCSubmissionResponse, error)
+	GetLimits(ctx context.Context, userID uuid.UUID) (*KYCLimits, error)
+}
+
+type KYCRepository interface {
+	CreateSubmission(ctx context.Context, submission *KYCSubmission) error
+	GetPendingByUserID(ctx context.Context, userID uuid.UUID) (*KYCSubmission, error)
+	GetPendingSubmissions(ctx context.Context) ([]*KYCSubmission, error)
+	GetDocument(ctx context.Context, submissionID, documentID uuid.UUID) (*KYCDocument, error)
+	Review(ctx context.Context, submissionID uuid.UUID, status KYCStatus, reason string, reviewerID uuid.UUID) (*KYCSubmission, error)
+}
+
+func (r *ReviewKYCPayload) Validate() map[string]string {
+	r.Reason = strings.TrimSpace(r.Reason)
+	return ValidateStruct(r)
+}
+
+func (d *KYCDocument) ToResponse() *KYCDocumentResponse {
+	return &KYCDocumentResponse{
+		ID:          d.ID,
+		Kind:        d.Kind,
+		FileName:    d.FileName,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+func (s *KYCSubmission) ToResponse() *KYCSubmissionResponse {
+	documents := make([]KYCDocumentResponse, 0, len(s.Documents))
+	for _, document := range s.Documents {
+		documents = append(documents, *document.ToResponse())
+	}
+
+	return &KYCSubmissionResponse{
+		ID:              s.ID,
+		UserID:          s.UserID,
+		Tier:            s.Tier,
+		Status:          s.Status,
+		RejectionReason: s.RejectionReason,
+		ReviewedAt:      s.ReviewedAt,
+		Documents:       documents,
+		CreatedAt:       s.CreatedAt,
+	}
+}
@@ -0,0 +1,445 @@
+package domain
+
+//go:generate mockgen -source=oauth.go -destination=../mocks/oauth_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	// OAuthAccessTokenType is the typ header of OAuth access tokens (RFC
+	// 9068), which tells them apart from session tokens signed with the same
+	// keys.
+	OAuthAccessTokenType = "at+jwt"
+
+	OAuthResponseTypeCode           = "code"
+	OAuthCodeChallengeMethodS256    = "S256"
+	OAuthGrantTypeAuthorizationCode = "authorization_code"
+	OAuthGrantTypeRefreshToken      = "refresh_token"
+	OAuthTokenTypeBearer            = "Bearer"
+)
+
+var (
+	ErrOAuthClientNotFound = errors.New("oauth client not found")
+	ErrCreateOAuthClient   = errors.New("create oauth client fail")
+	ErrGetOAuthClients     = errors.New("get oauth clients fail")
+	ErrDeleteOAuthClient   = errors.New("delete oauth client fail")
+	ErrOAuthAuthorize      = errors.New("oauth authorize fail")
+	ErrOAuthToken          = errors.New("oauth token fail")
+)
+
+// OAuthError is an error of the OAuth protocol, answered with the error
+// codes of RFC 6749. When RedirectURI is set the error has to be sent back
+// to the client through it instead of being shown to the user.
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
+
+var (
+	ErrOAuthInvalidRequest          = &OAuthError{Code: "invalid_request", Description: "The request is missing a parameter or has an invalid one."}
+	ErrOAuthInvalidClient           = &OAuthError{Code: "invalid_client", Description: "Client authentication failed."}
+	ErrOAuthInvalidGrant            = &OAuthError{Code: "invalid_grant", Description: "The grant is invalid, expired, revoked or was issued to another client."}
+	ErrOAuthInvalidScope            = &OAuthError{Code: "invalid_scope", Description: "The requested scope is invalid or not allowed for this client."}
+	ErrOAuthInvalidRedirectURI      = &OAuthError{Code: "invalid_request", Description: "The redirect_uri is not registered for this client."}
+	ErrOAuthUnknownClient           = &OAuthError{Code: "invalid_request", Description: "The client_id is unknown."}
+	ErrOAuthPKCERequired            = &OAuthError{Code: "invalid_request", Description: "PKCE with the S256 code_challenge_method is required."}
+	ErrOAuthAccessDenied            = &OAuthError{Code: "access_denied", Description: "The resource owner denied the request."}
+	ErrOAuthUnsupportedGrantType    = &OAuthError{Code: "unsupported_grant_type", Description: "The grant_type is not supported."}
+	ErrOAuthUnsupportedResponseType = &OAuthError{Code: "unsupported_response_type", Description: "Only the code response_type is supported."}
+)
+
+func (o *OAuthError) Error() string {
+	return o.Code + ": " + o.Description
+}
+
+// Is matches errors by code, so a copy carrying a redirect still matches
+// its sentinel.
+func (o *OAuthError) Is(target error) bool {
+	t, ok := target.(*OAuthError)
+	return ok && t.Code == o.Code
+}
+
+// WithRedirect returns a copy of the error to be delivered to redirectURI,
+// with the error and state appended to its query.
+func (o *OAuthError) WithRedirect(redirectURI, state string) *OAuthError {
+	params := url.Values{"error": {o.Code}}
+	if o.Description != "" {
+		params.Set("error_description", o.Description)
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	return &OAuthError{
+		Code:        o.Code,
+		Description: o.Description,
+		RedirectURI: AppendQuery(redirectURI, params),
+	}
+}
+
+type OAuthScope string
+
+const (
+	OAuthScopeProfile        OAuthScope = "profile"
+	OAuthScopeWalletsRead    OAuthScope = "wallets:read"
+	OAuthScopeStatementsRead OAuthScope = "statements:read"
+	OAuthScopeTransfersWrite OAuthScope = "transfers:write"
+)
+
+var oauthScopeDescriptions = map[OAuthScope]string{
+	OAuthScopeProfile:        "See your name and email",
+	OAuthScopeWalletsRead:    "See your wallets and balances",
+	OAuthScopeStatementsRead: "See the statements of your wallets",
+	OAuthScopeTransfersWrite: "Make transfers from your wallets",
+}
+
+func (s OAuthScope) IsValid() bool {
+	_, ok := oauthScopeDescriptions[s]
+	return ok
+}
+
+func (s OAuthScope) Description() string {
+	return oauthScopeDescriptions[s]
+}
+
+// ParseOAuthScopes reads a space separated scope parameter, dropping
+// repeated scopes. It returns false when any of them is unknown.
+func ParseOAuthScopes(value string) ([]OAuthScope, bool) {
+	var scopes []OAuthScope
+	for _, field := range strings.Fields(value) {
+		scope := OAuthScope(field)
+		if !scope.IsValid() {
+			return nil, false
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true
+}
+
+func JoinOAuthScopes(scopes []OAuthScope) string {
+	values := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		values = append(values, string(scope))
+	}
+	return strings.Join(values, " ")
+}
+
+// OAuthClient is a third-party app registered by one of our users. Public
+// clients, such as mobile and single page apps, have no secret and rely on
+// PKCE alone. RedirectURIs and Scopes are stored space separated.
+type OAuthClient struct {
+	ID           uuid.UUID      `gorm:"column:id;type:char(36);primaryKey"`
+	OwnerID      uuid.UUID      `gorm:"column:ownerId;type:char(36);not null;index"`
+	Name         string         `gorm:"column:name;type:varchar(100);not null"`
+	SecretHash   string         `gorm:"column:secretHash;type:char(64);default:NULL"`
+	RedirectURIs string         `gorm:"column:redirectUris;type:varchar(2000);not null"`
+	Scopes       string         `gorm:"column:scopes;type:varchar(255);not null"`
+	CreatedAt    time.Time      `gorm:"column:createdAt;not null"`
+	DeletedAt    gorm.DeletedAt `gorm:"column:deletedAt;index"`
+}
+
+func (OAuthClient) TableName() string {
+	return "OAuthClient"
+}
+
+type OAuthClientPayload struct {
+	Name         string       `json:"name" validate:"required,min=1,max=100"`
+	RedirectURIs []string     `json:"redirectUris" validate:"required,min=1,max=10,dive,redirecturi"`
+	Scopes       []OAuthScope `json:"scopes" validate:"required,min=1,dive,oauthscope"`
+	Confidential bool         `json:"confidential"`
+}
+
+type OAuthClientResponse struct {
+	ID           uuid.UUID    `json:"clientId"`
+	Name         string       `json:"name"`
+	RedirectURIs []string     `json:"redirectUris"`
+	Scopes       []OAuthScope `json:"scopes"`
+	Confidential bool         `json:"confidential"`
+	CreatedAt    time.Time    `json:"createdAt"`
+}
+
+// CreateOAuthClientResponse is the only time the client secret is returned.
+type CreateOAuthClientResponse struct {
+	OAuthClientResponse
+	ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+// OAuthAuthorizeRequest carries the parameters of the authorization
+// endpoint, read from the query when the consent screen is loaded and from
+// the body when the user answers it.
+type OAuthAuthorizeRequest struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Approved            bool   `json:"approved"`
+}
+
+type OAuthScopeResponse struct {
+	Scope       OAuthScope `json:"scope"`
+	Description string     `json:"description"`
+}
+
+// OAuthConsentResponse is what the consent screen shows the user before
+// they approve or deny the client.
+type OAuthConsentResponse struct {
+	ClientID    uuid.UUID             `json:"clientId"`
+	ClientName  string                `json:"clientName"`
+	RedirectURI string                `json:"redirectUri"`
+	Scopes      []*OAuthScopeResponse `json:"scopes"`
+	State       string                `json:"state,omitempty"`
+}
+
+// OAuthAuthorizeResponse tells the consent screen where to send the user
+// back to, with either the code or the error in the query.
+type OAuthAuthorizeResponse struct {
+	RedirectURI string `json:"redirectUri"`
+}
+
+// OAuthClientCredentials identifies the client calling the token,
+// introspection and revocation endpoints. Secret is empty for public
+// clients.
+type OAuthClientCredentials struct {
+	ClientID string
+	Secret   string
+}
+
+type OAuthTokenRequest struct {
+	OAuthClientCredentials
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthIntrospectionResponse follows RFC 7662. Inactive tokens only carry
+// Active.
+type OAuthIntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+type OAuthUserInfoResponse struct {
+	Subject uuid.UUID `json:"sub"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+}
+
+// OAuthAuthorizationCode is the stored side of a code: what the user
+// approved and the PKCE challenge the code exchange has to answer.
+type OAuthAuthorizationCode struct {
+	ClientID      uuid.UUID    `json:"clientId"`
+	UserID        uuid.UUID    `json:"userId"`
+	RedirectURI   string       `json:"redirectUri"`
+	Scopes        []OAuthScope `json:"scopes"`
+	CodeChallenge string       `json:"codeChallenge"`
+}
+
+// OAuthRefreshToken is the stored side of an OAuth refresh token. Each one
+// works once and is replaced by the next on every refresh.
+type OAuthRefreshToken struct {
+	ClientID uuid.UUID    `json:"clientId"`
+	UserID   uuid.UUID    `json:"userId"`
+	Scopes   []OAuthScope `json:"scopes"`
+}
+
+// OAuthIssuedGrant records the tokens issued together to a client for a
+// user, so they can be revoked when the client is deleted or the user loses
+// their sessions.
+type OAuthIssuedGrant struct {
+	ClientID         uuid.UUID `json:"clientId"`
+	UserID           uuid.UUID `json:"userId"`
+	AccessTokenID    string    `json:"accessTokenId"`
+	AccessExpiresAt  time.Time `json:"accessExpiresAt"`
+	RefreshTokenHash string    `json:"refreshTokenHash"`
+}
+
+// OAuthGrant is what a request authenticated by an OAuth access token was
+// granted: the client acting for the user and the scopes it may use.
+type OAuthGrant struct {
+	ClientID  uuid.UUID
+	Scopes    []OAuthScope
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+func (o *OAuthGrant) HasScope(scope OAuthScope) bool {
+	return slices.Contains(o.Scopes, scope)
+}
+
+type OAuthHandler interface {
+	CreateClient(ctx echo.Context) error
+	GetClients(ctx echo.Context) error
+	DeleteClient(ctx echo.Context) error
+	GetConsent(ctx echo.Context) error
+	Authorize(ctx echo.Context) error
+	Token(ctx echo.Context) error
+	Introspect(ctx echo.Context) error
+	Revoke(ctx echo.Context) error
+	UserInfo(ctx echo.Context) error
+}
+
+type OAuthService interface {
+	CreateClient(ctx context.Context, payload *OAuthClientPayload) (*CreateOAuthClientResponse, error)
+	GetClients(ctx context.Context) ([]*OAuthClientResponse, error)
+	DeleteClient(ctx context.Context, clientID uuid.UUID) error
+	GetConsent(ctx context.Context, request *OAuthAuthorizeRequest) (*OAuthConsentResponse, error)
+	Authorize(ctx context.Context, request *OAuthAuthorizeRequest) (*OAuthAuthorizeResponse, error)
+	Token(ctx context.Context, request *OAuthTokenRequest) (*OAuthTokenResponse, error)
+	Introspect(ctx context.Context, credentials *OAuthClientCredentials, token string) (*OAuthIntrospectionResponse, error)
+	Revoke(ctx context.Context, credentials *OAuthClientCredentials, token string) error
+	Authenticate(ctx context.Context, token string) (*Session, error)
+	UserInfo(ctx context.Context) (*OAuthUserInfoResponse, error)
+}
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *OAuthClient) error
+	GetByID(ctx context.Context, clientID uuid.UUID) (*OAuthClient, error)
+	GetAllByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*OAuthClient, error)
+	Delete(ctx context.Context, ownerID, clientID uuid.UUID) error
+}
+
+type OAuthTokenRepository interface {
+	CreateCode(ctx context.Context, codeHash string, code *OAuthAuthorizationCode) error
+	TakeCode(ctx context.Context, codeHash string) (*OAuthAuthorizationCode, error)
+	CreateRefreshToken(ctx context.Context, tokenHash string, token *OAuthRefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*OAuthRefreshToken, error)
+	TakeRefreshToken(ctx context.Context, tokenHash string) (*OAuthRefreshToken, error)
+	RevokeAccessToken(ctx context.Context, tokenID string, ttl time.Duration) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	RecordGrant(ctx context.Context, grant *OAuthIssuedGrant) error
+	RevokeUserGrants(ctx context.Context, userID uuid.UUID) (int, error)
+	RevokeClientGrants(ctx context.Context, clientID uuid.UUID) (int, error)
+}
+
+func (o *OAuthClientPayload) Validate() map[string]string {
+	o.Name = strings.TrimSpace(o.Name)
+	for i, redirectURI := range o.RedirectURIs {
+		o.RedirectURIs[i] = strings.TrimSpace(redirectURI)
+	}
+	return ValidateStruct(o)
+}
+
+func (o *OAuthClientPayload) ToOAuthClient(ownerID uuid.UUID, secretHash string) *OAuthClient {
+	var scopes []OAuthScope
+	for _, scope := range o.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return &OAuthClient{
+		ID:           uuid.New(),
+		OwnerID:      ownerID,
+		Name:         o.Name,
+		SecretHash:   secretHash,
+		RedirectURIs: strings.Join(o.RedirectURIs, " "),
+		Scopes:       JoinOAuthScopes(scopes),
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+func (o *OAuthClient) IsConfidential() bool {
+	return o.SecretHash != ""
+}
+
+func (o *OAuthClient) RedirectURIList() []string {
+	return strings.Fields(o.RedirectURIs)
+}
+
+func (o *OAuthClient) ScopeList() []OAuthScope {
+	scopes, _ := ParseOAuthScopes(o.Scopes)
+	return scopes
+}
+
+// ResolveRedirectURI returns the registered URI matching requested exactly.
+// Clients with a single URI may leave it out.
+func (o *OAuthClient) ResolveRedirectURI(requested string) (string, bool) {
+	registered := o.RedirectURIList()
+	if requested == "" {
+		if len(registered) == 1 {
+			return registered[0], true
+		}
+		return "", false
+	}
+	return requested, slices.Contains(registered, requested)
+}
+
+// AllowsScopes reports whether every scope was registered for the client.
+func (o *OAuthClient) AllowsScopes(scopes []OAuthScope) bool {
+	allowed := o.ScopeList()
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *OAuthClient) ToResponse() *OAuthClientResponse {
+	return &OAuthClientResponse{
+		ID:           o.ID,
+		Name:         o.Name,
+		RedirectURIs: o.RedirectURIList(),
+		Scopes:       o.ScopeList(),
+		Confidential: o.IsConfidential(),
+		CreatedAt:    o.CreatedAt,
+	}
+}
+
+func (o *OAuthAuthorizeRequest) Trim() {
+	o.ResponseType = strings.TrimSpace(o.ResponseType)
+	o.ClientID = strings.TrimSpace(o.ClientID)
+	o.RedirectURI = strings.TrimSpace(o.RedirectURI)
+	o.CodeChallenge = strings.TrimSpace(o.CodeChallenge)
+	o.CodeChallengeMethod = strings.TrimSpace(o.CodeChallengeMethod)
+}
+
+// AppendQuery adds params to the query of rawURL, keeping the ones it
+// already has.
+func AppendQuery(rawURL string, params url.Values) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := parsed.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,105 @@
+package domain
+
+//go:generate mockgen -source=quote.go -destination=../mocks/quote_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrQuoteNotFound       = errors.New("quote not found or expired")
+	ErrQuoteMismatch       = errors.New("quote does not match the transfer")
+	ErrQuoteRequired       = errors.New("cross-currency transfers require a quote")
+	ErrUnsupportedCurrency = errors.New("no exchange rate for the currency pair")
+	ErrCreateQuote         = errors.New("fail to create quote")
+)
+
+// Quote locks the rate converting SourceValue in From to TargetValue in To
+// until ExpiresAt. A quote can be used by a single transfer of its owner.
+type Quote struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"userId"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Rate        float64   `json:"rate"`
+	SourceValue float64   `json:"sourceValue"`
+	TargetValue float64   `json:"targetValue"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type QuotePayload struct {
+	From  string  `json:"from" validate:"required,iso4217"`
+	To    string  `json:"to" validate:"required,iso4217,nefield=From"`
+	Value float64 `json:"value" validate:"required,gt=0"`
+}
+
+type QuoteResponse struct {
+	ID          uuid.UUID `json:"id"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Rate        float64   `json:"rate"`
+	SourceValue float64   `json:"sourceValue"`
+	TargetValue float64   `json:"targetValue"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type QuoteHandler interface {
+	Create(ctx echo.Context) error
+}
+
+type QuoteService interface {
+	Create(ctx context.Context, payload *QuotePayload) (*QuoteResponse, error)
+}
+
+type QuoteRepository interface {
+	Create(ctx context.Context, quote *Quote) error
+	Take(ctx context.Context, quoteID uuid.UUID) (*Quote, error)
+	Restore(ctx context.Context, quote *Quote) error
+}
+
+func (q *QuotePayload) Validate() map[string]string {
+	q.From = NormalizeCurrency(q.From)
+	q.To = NormalizeCurrency(q.To)
+	return ValidateStruct(q)
+}
+
+func (q *QuotePayload) ToQuote(userID uuid.UUID, rate float64, ttl time.Duration) *Quote {
+	return &Quote{
+		ID:          uuid.New(),
+		UserID:      userID,
+		From:        q.From,
+		To:          q.To,
+		Rate:        rate,
+		SourceValue: q.Value,
+		TargetValue: ConvertAmount(q.Value, rate),
+		ExpiresAt:   time.Now().UTC().Add(ttl),
+	}
+}
+
+// Matches reports whether the quote covers a transfer of value in currency.
+func (q *Quote) Matches(currency string, value float64) bool {
+	return q.From == currency && q.SourceValue == value
+}
+
+func (q *Quote) ToResponse() *QuoteResponse {
+	return &QuoteResponse{
+		ID:          q.ID,
+		From:        q.From,
+		To:          q.To,
+		Rate:        q.Rate,
+		SourceValue: q.SourceValue,
+		TargetValue: q.TargetValue,
+		ExpiresAt:   q.ExpiresAt,
+	}
+}
+
+// ConvertAmount converts value at rate, rounding down to cents.
+func ConvertAmount(value, rate float64) float64 {
+	return math.Floor(math.Round(value*rate*1e6)/1e4) / 100
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidRole    = errors.New("invalid role")
+	ErrRoleSelfChange = errors.New("users cannot change their own role")
+	ErrUpdateRole     = errors.New("update role fail")
+)
+
+type Role string
+
+const (
+	RoleCustomer      Role = "customer"
+	RoleMerchantAdmin Role = "merchant_admin"
+	RoleSupport       Role = "support"
+	RoleAdmin         Role = "admin"
+)
+
+// Permission is what a route requires from the principal of a request.
+// Roles grant permissions through rolePermissions.
+type Permission string
+
+const (
+	PermissionAPIKeysManage   Permission = "api_keys:manage"
+	PermissionUsersUnlock     Permission = "users:unlock"
+	PermissionUsersRoles      Permission = "users:roles"
+	PermissionEscrowsResolve  Permission = "escrows:resolve"
+	PermissionDisputesResolve Permission = "disputes:resolve"
+	PermissionCampaignsRead   Permission = "campaigns:read"
+	PermissionCampaignsManage Permission = "campaigns:manage"
+	PermissionKYCReview       Permission = "kyc:review"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleCustomer:      {},
+	RoleMerchantAdmin: {PermissionAPIKeysManage},
+	RoleSupport:       {PermissionUsersUnlock, PermissionCampaignsRead},
+	RoleAdmin: {
+		PermissionAPIKeysManage,
+		PermissionUsersUnlock,
+		PermissionUsersRoles,
+		PermissionEscrowsResolve,
+		PermissionDisputesResolve,
+		PermissionCampaignsRead,
+		PermissionCampaignsManage,
+		PermissionKYCReview,
+	},
+}
+
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants permission. Unknown and empty roles,
+// such as those of sessions signed in before roles existed, grant nothing.
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+type RolePayload struct {
+	Role Role `json:"role" validate:"required,role"`
+}
+
+func (r *RolePayload) Validate() map[string]string {
+	r.Role = Role(strings.TrimSpace(string(r.Role)))
+	return ValidateStruct(r)
+}
@@ -5,8 +5,10 @@ package domain
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
 )
 
 var (
@@ -16,21 +18,129 @@ var (
 	ErrTokenNotFoundInContext = errors.New("token not found in context")
 	ErrSessionMismatch        = errors.New("session icompatible for user requested")
 	ErrCreateSession          = errors.New("create session fails")
+	ErrRefreshTokenInvalid    = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused     = errors.New("refresh token reused, token family revoked")
+	ErrUnknownSession         = errors.New("session not found for user")
+	ErrGetSessions            = errors.New("fail to get sessions")
+	ErrSignOut                = errors.New("fail to sign out")
 )
 
+// Session is one signed-in device of a user. Its ID travels in the access
+// token as the sid claim and is also the family of its refresh tokens.
 type Session struct {
-	Token  string    `json:"token"`
-	Name   string    `json:"name"`
-	UserID uuid.UUID `json:"picPayId"`
-	Email  string    `json:"email"`
+	ID         uuid.UUID `json:"sid"`
+	Token      string    `json:"token"`
+	Name       string    `json:"name"`
+	UserID     uuid.UUID `json:"picPayId"`
+	Email      string    `json:"email"`
+	Role       Role      `json:"role"`
+	DeviceName string    `json:"deviceName"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	// APIKey is set when the request was authenticated by a merchant API
+	// key instead of a session token.
+	APIKey *APIKey `json:"-"`
+	// OAuth is set when the request was authenticated by an OAuth access
+	// token issued to a third-party app acting for the user.
+	OAuth *OAuthGrant `json:"-"`
+}
+
+// Can reports whether the principal holds permission. Only users signed in
+// themselves carry a role: API keys and OAuth apps never act with the
+// back-office permissions of their user.
+func (s *Session) Can(permission Permission) bool {
+	if s.APIKey != nil || s.OAuth != nil {
+		return false
+	}
+	return s.Role.Can(permission)
+}
+
+// AllowsCurrency reports whether the principal may act on the wallet in
+// currency. API keys are bound to a single wallet; sessions to none.
+func (s *Session) AllowsCurrency(currency string) bool {
+	return s.APIKey == nil || s.APIKey.Currency == NormalizeCurrency(currency)
+}
+
+// Device describes where a sign-in comes from.
+type Device struct {
+	Name      string
+	IP        string
+	UserAgent string
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"deviceName"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Current    bool      `json:"current"`
+}
+
+type SignOutPayload struct {
+	All bool `json:"all"`
+}
+
+// RefreshToken is the stored side of an opaque refresh token: only its hash
+// is kept. Every sign-in starts a new family and each rotation issues the
+// next token of the same family, so replaying a rotated token revokes them
+// all. FamilyID is the ID of the session the token belongs to.
+type RefreshToken struct {
+	Hash      string
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	ExpiresAt time.Time
+}
+
+type SessionHandler interface {
+	GetAll(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+	SignOut(ctx echo.Context) error
+}
+
+type KeyHandler interface {
+	JWKS(ctx echo.Context) error
 }
 
 type SessionService interface {
-	Create(ctx context.Context, user *User) (string, error)
+	Create(ctx context.Context, user *User, device *Device) (*SignInResponse, error)
 	GetSession(ctx context.Context, token string) (*Session, error)
+	Refresh(ctx context.Context, refreshToken string) (*SignInResponse, error)
+	GetAll(ctx context.Context) ([]*SessionResponse, error)
+	Revoke(ctx context.Context, sessionID uuid.UUID) error
+	SignOut(ctx context.Context, payload *SignOutPayload) error
+	RevokeAll(ctx context.Context, userID uuid.UUID) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, role Role) error
+	UpdateProfile(ctx context.Context, user *User) error
 }
 
 type SessionRepository interface {
 	Create(ctx context.Context, session *Session) error
-	GetSession(ctx context.Context, userID uuid.UUID) (*Session, error)
+	GetSession(ctx context.Context, userID, sessionID uuid.UUID) (*Session, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*Session, error)
+	Touch(ctx context.Context, session *Session) error
+	Delete(ctx context.Context, userID, sessionID uuid.UUID) error
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	MarkUsed(ctx context.Context, hash string) (bool, error)
+	IsFamilyActive(ctx context.Context, familyID uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+func (s *Session) ToResponse(currentID uuid.UUID) *SessionResponse {
+	return &SessionResponse{
+		ID:         s.ID,
+		DeviceName: s.DeviceName,
+		IP:         s.IP,
+		UserAgent:  s.UserAgent,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		Current:    s.ID == currentID,
+	}
 }
@@ -0,0 +1,61 @@
+package domain
+
+//go:generate mockgen -source=sign_in_attempt.go -destination=../mocks/sign_in_attempt_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrAccountLocked         = errors.New("account locked after too many failed sign-in attempts")
+	ErrTooManySignInAttempts = errors.New("too many failed sign-in attempts")
+	ErrTooManyResetRequests  = errors.New("too many password reset requests")
+	ErrUnlockUser            = errors.New("unlock user fail")
+)
+
+// RetryAfterError tells the client how long to wait before trying again.
+// It unwraps to the sentinel describing why the request was refused.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (r *RetryAfterError) Error() string {
+	return r.Err.Error()
+}
+
+func (r *RetryAfterError) Unwrap() error {
+	return r.Err
+}
+
+// SignInAttemptStatus is what stands between an email and IP and the next
+// sign-in attempt. Zero durations mean no wait.
+type SignInAttemptStatus struct {
+	Lock         time.Duration
+	Delay        time.Duration
+	IPFailures   int64
+	IPRetryAfter time.Duration
+}
+
+// PasswordResetRequests is how many password resets an email and an IP
+// requested in their current windows and when those windows end.
+type PasswordResetRequests struct {
+	Email           int64
+	EmailRetryAfter time.Duration
+	IP              int64
+	IPRetryAfter    time.Duration
+}
+
+// SignInAttemptRepository keeps the failed sign-in counters per email and
+// per IP, together with the lock and delay windows derived from them, and
+// the password reset request counters.
+type SignInAttemptRepository interface {
+	GetStatus(ctx context.Context, email, ip string) (*SignInAttemptStatus, error)
+	RegisterFailure(ctx context.Context, email, ip string) (int64, error)
+	SetDelay(ctx context.Context, email string, delay time.Duration) error
+	Lock(ctx context.Context, email string, duration time.Duration) error
+	Reset(ctx context.Context, email string) error
+	RegisterPasswordReset(ctx context.Context, email, ip string) (*PasswordResetRequests, error)
+}
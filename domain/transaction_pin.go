@@ -0,0 +1,104 @@
+package domain
+
+//go:generate mockgen -source=transaction_pin.go -destination=../mocks/transaction_pin_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrPINNotSet     = errors.New("transaction pin not set")
+	ErrPINAlreadySet = errors.New("transaction pin already set")
+	ErrPINInvalid    = errors.New("invalid transaction pin")
+	ErrPINLocked     = errors.New("transaction pin locked after too many wrong attempts")
+	ErrSavePIN       = errors.New("save transaction pin fail")
+	ErrVerifyPIN     = errors.New("verify transaction pin fail")
+)
+
+// TransactionPIN is the 4 to 6 digit code, separate from the password, that
+// authorizes money leaving the wallets of a user. Only its hash is stored.
+type TransactionPIN struct {
+	UserID    uuid.UUID `gorm:"column:userId;type:char(36);primaryKey"`
+	PINHash   string    `gorm:"column:pinHash;type:varchar(255);not null"`
+	CreatedAt time.Time `gorm:"column:createdAt;not null"`
+	UpdatedAt time.Time `gorm:"column:updatedAt;not null"`
+}
+
+func (TransactionPIN) TableName() string {
+	return "TransactionPIN"
+}
+
+// SetPINPayload creates the first PIN of a user. The password is asked so a
+// stolen session alone cannot choose the PIN.
+type SetPINPayload struct {
+	Password   string `json:"password" validate:"required"`
+	PIN        string `json:"pin" validate:"required,pin"`
+	ConfirmPIN string `json:"confirmPin" validate:"required,eqfield=PIN"`
+	IP         string `json:"-"`
+}
+
+type ChangePINPayload struct {
+	CurrentPIN string `json:"currentPin" validate:"required"`
+	PIN        string `json:"pin" validate:"required,pin,nefield=CurrentPIN"`
+	ConfirmPIN string `json:"confirmPin" validate:"required,eqfield=PIN"`
+}
+
+// ResetPINPayload replaces a forgotten or locked PIN. Besides the password,
+// users with two-factor authentication enabled must send a TOTP code.
+type ResetPINPayload struct {
+	Password   string `json:"password" validate:"required"`
+	TOTPCode   string `json:"totpCode"`
+	PIN        string `json:"pin" validate:"required,pin"`
+	ConfirmPIN string `json:"confirmPin" validate:"required,eqfield=PIN"`
+	IP         string `json:"-"`
+}
+
+type TransactionPINHandler interface {
+	Set(ctx echo.Context) error
+	Change(ctx echo.Context) error
+	Reset(ctx echo.Context) error
+}
+
+type TransactionPINService interface {
+	Set(ctx context.Context, payload *SetPINPayload) error
+	Change(ctx context.Context, payload *ChangePINPayload) error
+	Reset(ctx context.Context, payload *ResetPINPayload) error
+	Verify(ctx context.Context, userID uuid.UUID, pin string) error
+}
+
+// TransactionPINRepository stores the PIN hashes and keeps the wrong entry
+// counters and locks of each user.
+type TransactionPINRepository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*TransactionPIN, error)
+	Save(ctx context.Context, pin *TransactionPIN) error
+	GetLock(ctx context.Context, userID uuid.UUID) (time.Duration, error)
+	RegisterFailure(ctx context.Context, userID uuid.UUID) (int64, error)
+	Lock(ctx context.Context, userID uuid.UUID, duration time.Duration) error
+	ResetAttempts(ctx context.Context, userID uuid.UUID) error
+}
+
+func (s *SetPINPayload) Validate() map[string]string {
+	s.PIN = strings.TrimSpace(s.PIN)
+	s.ConfirmPIN = strings.TrimSpace(s.ConfirmPIN)
+	return ValidateStruct(s)
+}
+
+func (c *ChangePINPayload) Validate() map[string]string {
+	c.CurrentPIN = strings.TrimSpace(c.CurrentPIN)
+	c.PIN = strings.TrimSpace(c.PIN)
+	c.ConfirmPIN = strings.TrimSpace(c.ConfirmPIN)
+	return ValidateStruct(c)
+}
+
+func (r *ResetPINPayload) Validate() map[string]string {
+	r.TOTPCode = strings.TrimSpace(r.TOTPCode)
+	r.PIN = strings.TrimSpace(r.PIN)
+	r.ConfirmPIN = strings.TrimSpace(r.ConfirmPIN)
+	return ValidateStruct(r)
+}
@@ -5,6 +5,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,18 +18,65 @@ var (
 	ErrTransferNotAuthorized           = errors.New("authorization service not authorized this transfer")
 	ErrTransferNotAllowedForWalletType = errors.New("this wallet type is not allowed to transfer")
 	ErrCreateTransfer                  = errors.New("fail to create transfer")
+	ErrTransferNotFound                = errors.New("transfer not found")
+	ErrTransferForbidden               = errors.New("user is not allowed to operate this transfer")
+	ErrEscrowNotHeld                   = errors.New("transfer funds are not held in escrow")
+	ErrEscrowNotDisputed               = errors.New("escrow transfer is not under dispute")
+	ErrSettleEscrow                    = errors.New("fail to settle escrow transfer")
+	ErrRefundNotAllowed                = errors.New("transfer cannot be refunded")
+	ErrRefundExceedsValue              = errors.New("refund value exceeds the refundable amount of the transfer")
+	ErrRefundTransfer                  = errors.New("fail to refund transfer")
 )
 
+// EscrowStatus is empty for regular transfers. Escrow transfers debit the
+// payer immediately but only credit the payee once released.
+type EscrowStatus string
+
+const (
+	EscrowStatusHeld     EscrowStatus = "held"
+	EscrowStatusDisputed EscrowStatus = "disputed"
+	EscrowStatusReleased EscrowStatus = "released"
+	EscrowStatusRefunded EscrowStatus = "refunded"
+)
+
+type EscrowResolution string
+
+const (
+	EscrowResolutionRelease EscrowResolution = "release"
+	EscrowResolutionRefund  EscrowResolution = "refund"
+)
+
+func (r EscrowResolution) IsValid() bool {
+	switch r {
+	case EscrowResolutionRelease, EscrowResolutionRefund:
+		return true
+	}
+	return false
+}
+
 type Transfer struct {
-	ID        uuid.UUID      `gorm:"column:id;type:char(36);primaryKey"`
-	PayerID   uuid.UUID      `gorm:"column:payerId;type:char(36);not null;index"`
-	PayeeID   uuid.UUID      `gorm:"column:payeeId;type:char(36);not null;index"`
-	Payer     User           `gorm:"foreignKey:PayerID"`
-	Payee     User           `gorm:"foreignKey:PayeeID"`
-	Value     float64        `gorm:"column:value;type:decimal(15, 2);not null"`
-	CreatedAt time.Time      `gorm:"column:createdAt;not null"`
-	UpdatedAt time.Time      `gorm:"column:updatedAt;default:NULL"`
-	DeletedAt gorm.DeletedAt `gorm:"column:deletedAt;index"`
+	ID                  uuid.UUID      `gorm:"column:id;type:char(36);primaryKey"`
+	PayerID             uuid.UUID      `gorm:"column:payerId;type:char(36);not null;index"`
+	PayeeID             uuid.UUID      `gorm:"column:payeeId;type:char(36);not null;index"`
+	Payer               User           `gorm:"foreignKey:PayerID"`
+	Payee               User           `gorm:"foreignKey:PayeeID"`
+	Currency            string         `gorm:"column:currency;type:char(3);not null;default:'BRL'"`
+	Value               float64        `gorm:"column:value;type:decimal(15, 2);not null"`
+	PayeeCurrency       string         `gorm:"column:payeeCurrency;type:char(3);default:NULL"`
+	PayeeValue          float64        `gorm:"column:payeeValue;type:decimal(15, 2);default:NULL"`
+	Rate                float64        `gorm:"column:rate;type:decimal(18, 8);default:NULL"`
+	HoldID              *uuid.UUID     `gorm:"column:holdId;type:char(36);index"`
+	EscrowStatus        EscrowStatus   `gorm:"column:escrowStatus;type:varchar(20);default:NULL;index"`
+	EscrowReleaseAt     *time.Time     `gorm:"column:escrowReleaseAt;default:NULL;index"`
+	EscrowDisputeReason string         `gorm:"column:escrowDisputeReason;type:varchar(500);default:NULL"`
+	ReversalOfID        *uuid.UUID     `gorm:"column:reversalOfId;type:char(36);index"`
+	CreatedAt           time.Time      `gorm:"column:createdAt;not null"`
+	UpdatedAt           time.Time      `gorm:"column:updatedAt;default:NULL"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deletedAt;index"`
+
+	// PayeeBalanceCap, when set, is checked against the wallets of the payee
+	// inside the transaction crediting them.
+	PayeeBalanceCap *KYCBalanceCap `gorm:"-"`
 }
 
 func (Transfer) TableName() string {
@@ -40,33 +88,200 @@ func (t *Transfer) BeforeUpdate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+// TransferPayload moves Value in Currency out of the payer's wallet. Without
+// a quote the payee is credited in the same currency; cross-currency
+// transfers must reference a quote locking the conversion rate. PIN is the
+// transaction PIN of the payer.
 type TransferPayload struct {
-	PayeeID uuid.UUID `json:"payeeId" validate:"required,uuid"`
-	Value   float64   `json:"value" validate:"required,gt=0"`
+	PayeeID  uuid.UUID  `json:"payeeId" validate:"required,uuid"`
+	Value    float64    `json:"value" validate:"required,gt=0"`
+	Currency string     `json:"currency" validate:"required,iso4217"`
+	QuoteID  *uuid.UUID `json:"quoteId"`
+	Escrow   bool       `json:"escrow"`
+	TOTPCode string     `json:"totpCode"`
+	PIN      string     `json:"pin" validate:"required"`
+}
+
+type DisputeEscrowPayload struct {
+	Reason string `json:"reason" validate:"required,min=1,max=500"`
+}
+
+type ResolveEscrowPayload struct {
+	Resolution EscrowResolution `json:"resolution" validate:"required,escrowresolution"`
+}
+
+// RefundPayload gives Value back to the payer, in the source currency of the
+// transfer. When Value is zero everything not yet refunded is returned.
+type RefundPayload struct {
+	Value float64 `json:"value" validate:"omitempty,gt=0"`
+}
+
+type TransferResponse struct {
+	ID              uuid.UUID    `json:"id"`
+	PayerID         uuid.UUID    `json:"payerId"`
+	PayeeID         uuid.UUID    `json:"payeeId"`
+	Currency        string       `json:"currency"`
+	Value           float64      `json:"value"`
+	PayeeCurrency   string       `json:"payeeCurrency"`
+	PayeeValue      float64      `json:"payeeValue"`
+	Rate            float64      `json:"rate"`
+	EscrowStatus    EscrowStatus `json:"escrowStatus,omitempty"`
+	EscrowReleaseAt *time.Time   `json:"escrowReleaseAt,omitempty"`
+	ReversalOfID    *uuid.UUID   `json:"reversalOfId,omitempty"`
+	CreatedAt       time.Time    `json:"createdAt"`
 }
 
 type TransferHandler interface {
 	Transfer(ctx echo.Context) error
+	ConfirmEscrow(ctx echo.Context) error
+	DisputeEscrow(ctx echo.Context) error
+	ResolveEscrow(ctx echo.Context) error
+	Refund(ctx echo.Context) error
 }
 
 type TransferService interface {
-	Transfer(ctx context.Context, payload *TransferPayload) error
+	Transfer(ctx context.Context, payload *TransferPayload) (*TransferResponse, error)
+	ConfirmEscrow(ctx context.Context, transferID uuid.UUID) (*TransferResponse, error)
+	DisputeEscrow(ctx context.Context, transferID uuid.UUID, payload *DisputeEscrowPayload) (*TransferResponse, error)
+	ResolveEscrow(ctx context.Context, transferID uuid.UUID, payload *ResolveEscrowPayload) (*TransferResponse, error)
+	ReleaseDueEscrows(ctx context.Context) (int, error)
+	Refund(ctx context.Context, transferID uuid.UUID, payload *RefundPayload) (*TransferResponse, error)
 }
 
 type TransferRepository interface {
 	Transfer(ctx context.Context, transfer *Transfer) error
+	GetByID(ctx context.Context, transferID uuid.UUID) (*Transfer, error)
+	GetDueEscrows(ctx context.Context, now time.Time, limit int) ([]*Transfer, error)
+	DisputeEscrow(ctx context.Context, transferID uuid.UUID, reason string) (*Transfer, error)
+	ReleaseEscrow(ctx context.Context, transferID uuid.UUID, payeeBalanceCap *KYCBalanceCap, from ...EscrowStatus) (*Transfer, error)
+	RefundEscrow(ctx context.Context, transferID uuid.UUID, from ...EscrowStatus) (*Transfer, error)
+	Refund(ctx context.Context, transferID uuid.UUID, value float64) (*Transfer, error)
+	GetStatement(ctx context.Context, userID uuid.UUID, currency string, query *StatementQuery) ([]*Transfer, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*Transfer, error)
 }
 
 func (t *TransferPayload) Validate() map[string]string {
+	t.Currency = NormalizeCurrency(t.Currency)
 	return ValidateStruct(t)
 }
 
-func (t *TransferPayload) ToTansaction(payerID uuid.UUID) *Transfer {
-	return &Transfer{
-		ID:        uuid.New(),
-		PayerID:   payerID,
-		PayeeID:   t.PayeeID,
-		Value:     t.Value,
-		CreatedAt: time.Now().UTC(),
+func (d *DisputeEscrowPayload) Validate() map[string]string {
+	d.Reason = strings.TrimSpace(d.Reason)
+	return ValidateStruct(d)
+}
+
+func (r *ResolveEscrowPayload) Validate() map[string]string {
+	return ValidateStruct(r)
+}
+
+func (r *RefundPayload) Validate() map[string]string {
+	return ValidateStruct(r)
+}
+
+// ToTansaction builds the transfer, converting the payee side with quote when
+// one is given.
+func (t *TransferPayload) ToTansaction(payerID uuid.UUID, quote *Quote, escrowTimeout time.Duration) *Transfer {
+	now := time.Now().UTC()
+
+	transfer := &Transfer{
+		ID:            uuid.New(),
+		PayerID:       payerID,
+		PayeeID:       t.PayeeID,
+		Currency:      t.Currency,
+		Value:         t.Value,
+		PayeeCurrency: t.Currency,
+		PayeeValue:    t.Value,
+		Rate:          1,
+		CreatedAt:     now,
+	}
+
+	if quote != nil {
+		transfer.PayeeCurrency = quote.To
+		transfer.PayeeValue = quote.TargetValue
+		transfer.Rate = quote.Rate
+	}
+
+	if t.Escrow {
+		releaseAt := now.Add(escrowTimeout)
+		transfer.EscrowStatus = EscrowStatusHeld
+		transfer.EscrowReleaseAt = &releaseAt
+	}
+
+	return transfer
+}
+
+// PayeeAmount returns the currency and amount credited to the payee for value
+// of the transfer in its source currency, at the rate the transfer was made.
+// Transfers recorded before multi-currency support credit the source amount.
+func (t *Transfer) PayeeAmount(value float64) (string, float64) {
+	currency := NormalizeCurrency(t.Currency)
+	if t.PayeeCurrency == "" || t.PayeeCurrency == currency {
+		return currency, value
+	}
+
+	if value == t.Value {
+		return t.PayeeCurrency, t.PayeeValue
+	}
+
+	return t.PayeeCurrency, ConvertAmount(value, t.Rate)
+}
+
+// IsRefundable reports whether the payee may give the transfer back. Held or
+// disputed escrows are settled through the escrow flow instead, and
+// reversals cannot themselves be reversed.
+func (t *Transfer) IsRefundable() bool {
+	if t.ReversalOfID != nil {
+		return false
+	}
+	return t.EscrowStatus == "" || t.EscrowStatus == EscrowStatusReleased
+}
+
+// ToStatementEntry describes the transfer from the side of userID's wallet
+// in currency.
+func (t *Transfer) ToStatementEntry(userID uuid.UUID, currency string) *StatementEntry {
+	entry := &StatementEntry{
+		TransferID:     t.ID,
+		Type:           StatementEntryDebit,
+		Value:          t.Value,
+		CounterpartyID: t.PayeeID,
+		EscrowStatus:   t.EscrowStatus,
+		ReversalOfID:   t.ReversalOfID,
+		CreatedAt:      t.CreatedAt,
+	}
+
+	if payeeCurrency, payeeValue := t.PayeeAmount(t.Value); t.PayeeID == userID && payeeCurrency == currency {
+		entry.Type = StatementEntryCredit
+		entry.Value = payeeValue
+		entry.CounterpartyID = t.PayerID
+	}
+
+	return entry
+}
+
+func (t *Transfer) IsParticipant(userID uuid.UUID) bool {
+	return t.PayerID == userID || t.PayeeID == userID
+}
+
+func (t *Transfer) ToResponse() *TransferResponse {
+	payeeCurrency, payeeValue := t.PayeeAmount(t.Value)
+
+	rate := t.Rate
+	if rate == 0 {
+		rate = 1
+	}
+
+	return &TransferResponse{
+		ID:              t.ID,
+		PayerID:         t.PayerID,
+		PayeeID:         t.PayeeID,
+		Currency:        t.Currency,
+		Value:           t.Value,
+		PayeeCurrency:   payeeCurrency,
+		PayeeValue:      payeeValue,
+		Rate:            rate,
+		EscrowStatus:    t.EscrowStatus,
+		EscrowReleaseAt: t.EscrowReleaseAt,
+		ReversalOfID:    t.ReversalOfID,
+		CreatedAt:       t.CreatedAt,
 	}
 }
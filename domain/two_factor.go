@@ -0,0 +1,134 @@
+package domain
+
+//go:generate mockgen -source=two_factor.go -destination=../mocks/two_factor_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication enrollment not started")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorCodeInvalid    = errors.New("invalid two-factor code")
+	ErrSignInChallengeInvalid  = errors.New("invalid or expired sign-in challenge")
+	ErrStepUpRequired          = errors.New("a fresh two-factor code is required for this operation")
+	ErrEnableTwoFactor         = errors.New("fail to enable two-factor authentication")
+	ErrTwoFactorLocked         = errors.New("two-factor codes locked after too many wrong attempts")
+)
+
+const (
+	RecoveryCodeCount = 10
+
+	// SignInChallengeMaxAttempts is how many wrong codes a sign-in challenge
+	// accepts before it is discarded and the password must be sent again.
+	SignInChallengeMaxAttempts = 5
+)
+
+// TwoFactor holds the TOTP secret of a user. It is pending until the first
+// code is confirmed, which sets EnabledAt. LastUsedStep is the last accepted
+// TOTP time step, so a code cannot be replayed.
+type TwoFactor struct {
+	UserID       uuid.UUID  `gorm:"column:userId;type:char(36);primaryKey"`
+	Secret       string     `gorm:"column:secret;type:varchar(64);not null"`
+	EnabledAt    *time.Time `gorm:"column:enabledAt;default:NULL"`
+	LastUsedStep int64      `gorm:"column:lastUsedStep;not null;default:0"`
+	CreatedAt    time.Time  `gorm:"column:createdAt;not null"`
+}
+
+func (TwoFactor) TableName() string {
+	return "TwoFactor"
+}
+
+func (t *TwoFactor) IsEnabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// RecoveryCode is a single-use replacement for a TOTP code. Only its hash is
+// stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"column:id;type:char(36);primaryKey"`
+	UserID    uuid.UUID  `gorm:"column:userId;type:char(36);not null;index"`
+	CodeHash  string     `gorm:"column:codeHash;type:char(64);not null;uniqueIndex"`
+	UsedAt    *time.Time `gorm:"column:usedAt;default:NULL"`
+	CreatedAt time.Time  `gorm:"column:createdAt;not null"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "RecoveryCode"
+}
+
+// SignInChallenge is the state between a password check and the second
+// factor of a sign-in.
+type SignInChallenge struct {
+	UserID uuid.UUID `json:"userId"`
+	Device Device    `json:"device"`
+}
+
+type TwoFactorCodePayload struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type SignInTwoFactorPayload struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type TwoFactorHandler interface {
+	Enroll(ctx echo.Context) error
+	Activate(ctx echo.Context) error
+	Disable(ctx echo.Context) error
+}
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context) (*TwoFactorEnrollResponse, error)
+	Activate(ctx context.Context, payload *TwoFactorCodePayload) (*RecoveryCodesResponse, error)
+	Disable(ctx context.Context, payload *TwoFactorCodePayload) error
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	CreateChallenge(ctx context.Context, userID uuid.UUID, device *Device) (string, error)
+	CompleteChallenge(ctx context.Context, payload *SignInTwoFactorPayload) (*SignInChallenge, error)
+	VerifyStepUp(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+type TwoFactorRepository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*TwoFactor, error)
+	Save(ctx context.Context, twoFactor *TwoFactor) error
+	Enable(ctx context.Context, userID uuid.UUID, step int64, codes []*RecoveryCode) error
+	Delete(ctx context.Context, userID uuid.UUID) error
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	CreateChallenge(ctx context.Context, tokenHash string, challenge *SignInChallenge) error
+	GetChallenge(ctx context.Context, tokenHash string) (*SignInChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, tokenHash string) (int64, error)
+	DeleteChallenge(ctx context.Context, tokenHash string) error
+	GetLock(ctx context.Context, userID uuid.UUID) (time.Duration, error)
+	RegisterFailure(ctx context.Context, userID uuid.UUID) (int64, error)
+	Lock(ctx context.Context, userID uuid.UUID, duration time.Duration) error
+	ResetAttempts(ctx context.Context, userID uuid.UUID) error
+}
+
+func (t *TwoFactorCodePayload) Validate() map[string]string {
+	t.Code = strings.TrimSpace(t.Code)
+	return ValidateStruct(t)
+}
+
+func (s *SignInTwoFactorPayload) Validate() map[string]string {
+	s.ChallengeToken = strings.TrimSpace(s.ChallengeToken)
+	s.Code = strings.TrimSpace(s.Code)
+	return ValidateStruct(s)
+}
@@ -5,6 +5,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -15,81 +16,255 @@ import (
 )
 
 var (
-	ErrUserNotFound          = errors.New("user not found")
-	ErrEmailAlreadyRegister  = errors.New("email already exists")
-	ErrCPFAlreadyRegister    = errors.New("cpf already exists")
-	ErrHashingPassword       = errors.New("failed to hash password")
-	ErrInvalidPassword       = errors.New("invalid password")
-	ErrUserNotFoundInContext = errors.New("user not found in context")
-	ErrCreateUser            = errors.New("create user fail")
-	ErrGetUserByEmail        = errors.New("get user by email fail")
-	ErrGetUserByCPF          = errors.New("get user by cpf fail")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrEmailAlreadyRegister    = errors.New("email already exists")
+	ErrDocumentAlreadyRegister = errors.New("document already exists")
+	ErrHashingPassword         = errors.New("failed to hash password")
+	ErrInvalidPassword         = errors.New("invalid password")
+	ErrUserNotFoundInContext   = errors.New("user not found in context")
+	ErrCreateUser              = errors.New("create user fail")
+	ErrGetUserByEmail          = errors.New("get user by email fail")
+	ErrGetUserByDocument       = errors.New("get user by document fail")
+	ErrResetTokenInvalid       = errors.New("invalid or expired password reset token")
+	ErrUpdatePassword          = errors.New("update password fail")
+	ErrEmailNotVerified        = errors.New("email not verified")
+	ErrEmailAlreadyVerified    = errors.New("email already verified")
+	ErrVerifyTokenInvalid      = errors.New("invalid or expired email verification token")
+	ErrVerificationTooSoon     = errors.New("email verification sent too recently")
+	ErrVerifyEmail             = errors.New("verify email fail")
+	ErrProfileModified         = errors.New("profile was modified since it was read")
+	ErrGetProfile              = errors.New("get profile fail")
+	ErrUpdateProfile           = errors.New("update profile fail")
+	ErrVerifyPassword          = errors.New("verify password fail")
 )
 
 type User struct {
-	ID           uuid.UUID      `gorm:"column:id;type:char(36);primaryKey"`
-	Name         string         `gorm:"column:name;type:varchar(255);not null"`
-	CPF          string         `gorm:"column:cpf;type:char(11);uniqueIndex;not null"`
-	Email        string         `gorm:"column:email;type:varchar(255);uniqueIndex;not null"`
-	PasswordHash string         `gorm:"column:passwordHash;type:varchar(255);not null"`
-	CreatedAt    time.Time      `gorm:"column:createdAt;not null"`
-	UpdatedAt    time.Time      `gorm:"column:updatedAt;default:NULL"`
-	DeletedAt    gorm.DeletedAt `gorm:"column:deletedAt;index"`
+	ID              uuid.UUID      `gorm:"column:id;type:char(36);primaryKey"`
+	Name            string         `gorm:"column:name;type:varchar(255);not null"`
+	DocumentType    DocumentType   `gorm:"column:documentType;type:varchar(4);not null;default:'cpf'"`
+	Document        string         `gorm:"column:document;type:varchar(255);not null;serializer:encrypted"`
+	DocumentHash    *string        `gorm:"column:documentHash;type:char(64);uniqueIndex;default:NULL"`
+	LegalName       string         `gorm:"column:legalName;type:varchar(255);not null;default:''"`
+	TradeName       string         `gorm:"column:tradeName;type:varchar(255);not null;default:''"`
+	Email           string         `gorm:"column:email;type:varchar(512);not null;serializer:encrypted"`
+	EmailHash       *string        `gorm:"column:emailHash;type:char(64);uniqueIndex;default:NULL"`
+	Phone           string         `gorm:"column:phone;type:varchar(255);not null;default:'';serializer:encrypted"`
+	PasswordHash    string         `gorm:"column:passwordHash;type:varchar(255);not null"`
+	EmailVerifiedAt *time.Time     `gorm:"column:emailVerifiedAt;default:NULL"`
+	Role            Role           `gorm:"column:role;type:varchar(20);not null;default:'customer'"`
+	KYCTier         KYCTier        `gorm:"column:kycTier;type:varchar(20);not null;default:'basic'"`
+	CreatedAt       time.Time      `gorm:"column:createdAt;not null"`
+	UpdatedAt       time.Time      `gorm:"column:updatedAt;default:NULL"`
+	DeletedAt       gorm.DeletedAt `gorm:"column:deletedAt;index"`
 }
 
 func (User) TableName() string {
 	return "User"
 }
 
+// IsCompany reports whether the user registered with a CNPJ.
+func (u *User) IsCompany() bool {
+	return u.DocumentType == DocumentTypeCNPJ
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// ETag identifies the version of the profile. UpdatedAt is stored with
+// millisecond precision, so that is the precision of the tag.
+func (u *User) ETag() string {
+	return fmt.Sprintf(`"%x"`, u.UpdatedAt.UnixMilli())
+}
+
+// UserPayload registers an individual, with a CPF, or a company, with a
+// CNPJ. Companies must also send their legal name and may send the trade
+// name they do business as. Clients written before companies could sign up
+// send only the CPF, in the cpf field, and are still registered as
+// individuals.
 type UserPayload struct {
-	Name            string `json:"name" validate:"required,min=1,max=75"`
-	CPF             string `json:"cpf" validate:"required,cpf"`
-	Email           string `json:"email" validate:"required,email"`
-	ConfirmEmail    string `json:"confirmEmail" validate:"required,eqfield=Email"`
-	Password        string `json:"password,omitempty" validate:"required,max=255,strongpassword"`
-	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=Password"`
+	Name            string       `json:"name" validate:"required,min=1,max=75"`
+	DocumentType    DocumentType `json:"documentType" validate:"required,documenttype"`
+	Document        string       `json:"document" validate:"required"`
+	LegalName       string       `json:"legalName,omitempty" validate:"required_if=DocumentType cnpj,excluded_unless=DocumentType cnpj,max=255"`
+	TradeName       string       `json:"tradeName,omitempty" validate:"excluded_unless=DocumentType cnpj,max=255"`
+	Email           string       `json:"email" validate:"required,email"`
+	ConfirmEmail    string       `json:"confirmEmail" validate:"required,eqfield=Email"`
+	Password        string       `json:"password,omitempty" validate:"required,max=255,strongpassword"`
+	ConfirmPassword string       `json:"confirmPassword" validate:"required,eqfield=Password"`
+	CPF             string       `json:"cpf,omitempty"`
 }
 
 type SignInPayload struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password,omitempty" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password,omitempty" validate:"required"`
+	DeviceName string `json:"deviceName" validate:"omitempty,max=100"`
+	IP         string `json:"-"`
+	UserAgent  string `json:"-"`
 }
 
+// SignInResponse carries a short-lived access token and the opaque refresh
+// token used to obtain the next one. ExpiresIn is the access token lifetime
+// in seconds. When the user has two-factor authentication enabled, the
+// password step only returns ChallengeToken, to be sent along with a code
+// to complete the sign-in.
 type SignInResponse struct {
-	Token string `json:"token"`
+	Token             string `json:"token,omitempty"`
+	RefreshToken      string `json:"refreshToken,omitempty"`
+	ExpiresIn         int64  `json:"expiresIn,omitempty"`
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	ChallengeToken    string `json:"challengeToken,omitempty"`
+}
+
+type RefreshPayload struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type ForgotPasswordPayload struct {
+	Email string `json:"email" validate:"required,email"`
+	IP    string `json:"-"`
+}
+
+type ResetPasswordPayload struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password,omitempty" validate:"required,max=255,strongpassword"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=Password"`
+}
+
+type ChangePasswordPayload struct {
+	CurrentPassword string `json:"currentPassword,omitempty" validate:"required"`
+	Password        string `json:"password,omitempty" validate:"required,max=255,strongpassword,nefield=CurrentPassword"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=Password"`
+}
+
+// UpdateProfilePayload changes only the fields that are sent. Name and email
+// cannot be blanked, while an empty phone removes the phone number.
+type UpdateProfilePayload struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=75"`
+	Email *string `json:"email" validate:"omitempty,email"`
+	Phone *string `json:"phone" validate:"omitempty,e164|len=0"`
+}
+
+type UserProfileResponse struct {
+	ID            uuid.UUID    `json:"id"`
+	Name          string       `json:"name"`
+	DocumentType  DocumentType `json:"documentType"`
+	Document      string       `json:"document"`
+	LegalName     string       `json:"legalName,omitempty"`
+	TradeName     string       `json:"tradeName,omitempty"`
+	Email         string       `json:"email"`
+	EmailVerified bool         `json:"emailVerified"`
+	Phone         string       `json:"phone,omitempty"`
+	Role          Role         `json:"role"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
+	// ETag is sent in the header of the same name and expected back in
+	// If-Match to update the profile.
+	ETag string `json:"-"`
+}
+
+type VerifyEmailPayload struct {
+	Token string `json:"token" validate:"required"`
 }
 
 type UserHandler interface {
 	Create(ctx echo.Context) error
 	SignIn(ctx echo.Context) error
+	SignInTwoFactor(ctx echo.Context) error
+	Refresh(ctx echo.Context) error
+	ForgotPassword(ctx echo.Context) error
+	ResetPassword(ctx echo.Context) error
+	ChangePassword(ctx echo.Context) error
+	VerifyEmail(ctx echo.Context) error
+	ResendEmailVerification(ctx echo.Context) error
+	Unlock(ctx echo.Context) error
+	UpdateRole(ctx echo.Context) error
+	GetProfile(ctx echo.Context) error
+	UpdateProfile(ctx echo.Context) error
 }
 
 type UserService interface {
 	Create(ctx context.Context, payload *UserPayload) error
 	SignIn(ctx context.Context, payload *SignInPayload) (*SignInResponse, error)
+	SignInTwoFactor(ctx context.Context, payload *SignInTwoFactorPayload) (*SignInResponse, error)
+	Refresh(ctx context.Context, payload *RefreshPayload) (*SignInResponse, error)
+	ForgotPassword(ctx context.Context, payload *ForgotPasswordPayload) error
+	ResetPassword(ctx context.Context, payload *ResetPasswordPayload) error
+	ChangePassword(ctx context.Context, payload *ChangePasswordPayload) error
+	VerifyEmail(ctx context.Context, payload *VerifyEmailPayload) error
+	ResendEmailVerification(ctx context.Context) error
+	EnsureEmailVerified(ctx context.Context, userID uuid.UUID) error
+	EnsureCompany(ctx context.Context, userID uuid.UUID) error
+	Unlock(ctx context.Context, userID uuid.UUID) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, payload *RolePayload) error
+	GrantMerchantAdmin(ctx context.Context, userID uuid.UUID) error
+	GetProfile(ctx context.Context) (*UserProfileResponse, error)
+	UpdateProfile(ctx context.Context, payload *UpdateProfilePayload, ifMatch string) (*UserProfileResponse, error)
+	VerifyPassword(ctx context.Context, userID uuid.UUID, password, ip string) error
 }
 
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, ID uuid.UUID) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
-	GetByCPF(ctx context.Context, CPF string) (*User, error)
+	GetByDocument(ctx context.Context, document string) (*User, error)
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	ReplacePasswordHash(ctx context.Context, userID uuid.UUID, currentHash, passwordHash string) (bool, error)
+	CreatePasswordReset(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	TakePasswordReset(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
+	CreateEmailVerification(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	TakeEmailVerification(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	HoldEmailVerificationResend(ctx context.Context, userID uuid.UUID) (bool, error)
+	UpdateRole(ctx context.Context, userID uuid.UUID, role Role) error
+	UpdateProfile(ctx context.Context, user *User, version time.Time) (bool, error)
 }
 
 func (u *UserPayload) trim() {
+	if u.Document == "" && u.CPF != "" {
+		u.Document = u.CPF
+	}
+	u.CPF = ""
+
+	if strings.TrimSpace(string(u.DocumentType)) == "" {
+		u.DocumentType = DocumentTypeCPF
+	}
+
 	u.Name = strings.TrimSpace(u.Name)
-	u.CPF = strings.TrimSpace(u.CPF)
+	u.DocumentType = DocumentType(strings.TrimSpace(strings.ToLower(string(u.DocumentType))))
+	u.Document = strings.TrimSpace(u.Document)
+	u.LegalName = strings.TrimSpace(u.LegalName)
+	u.TradeName = strings.TrimSpace(u.TradeName)
 	u.Email = strings.TrimSpace(strings.ToLower(u.Email))
 	u.ConfirmEmail = strings.TrimSpace(strings.ToLower(u.ConfirmEmail))
 }
 
 func (s *SignInPayload) trim() {
 	s.Email = strings.TrimSpace(strings.ToLower(s.Email))
+	s.DeviceName = strings.TrimSpace(s.DeviceName)
 }
 
+// Validate checks the document against the rules of its type once the type
+// itself is valid.
 func (u *UserPayload) Validate() map[string]string {
 	u.trim()
-	return ValidateStruct(u)
+	validationErrors := ValidateStruct(u)
+
+	if u.DocumentType.IsValid() && u.Document != "" {
+		if message := ValidateVar(u.Document, u.DocumentType.Tag()); message != "" {
+			if validationErrors == nil {
+				validationErrors = make(map[string]string)
+			}
+			validationErrors["document"] = message
+		}
+	}
+
+	return validationErrors
+}
+
+// CleanDocument returns the document with only its digits, as it is stored.
+func (u *UserPayload) CleanDocument() string {
+	return cpfcnpj.Clean(u.Document)
 }
 
 func (s *SignInPayload) Validate() map[string]string {
@@ -97,13 +272,82 @@ func (s *SignInPayload) Validate() map[string]string {
 	return ValidateStruct(s)
 }
 
+func (r *RefreshPayload) Validate() map[string]string {
+	r.RefreshToken = strings.TrimSpace(r.RefreshToken)
+	return ValidateStruct(r)
+}
+
 func (u *UserPayload) ToUser(passwordHash string) *User {
 	return &User{
 		ID:           uuid.New(),
 		Name:         u.Name,
-		CPF:          string(cpfcnpj.NewCPF(u.CPF)),
+		DocumentType: u.DocumentType,
+		Document:     u.CleanDocument(),
+		LegalName:    u.LegalName,
+		TradeName:    u.TradeName,
 		Email:        u.Email,
 		PasswordHash: passwordHash,
+		Role:         RoleCustomer,
+		KYCTier:      KYCTierBasic,
 		CreatedAt:    time.Now().UTC(),
 	}
 }
+
+func (s *SignInPayload) ToDevice() *Device {
+	return &Device{
+		Name:      s.DeviceName,
+		IP:        s.IP,
+		UserAgent: s.UserAgent,
+	}
+}
+
+func (f *ForgotPasswordPayload) Validate() map[string]string {
+	f.Email = strings.TrimSpace(strings.ToLower(f.Email))
+	return ValidateStruct(f)
+}
+
+func (r *ResetPasswordPayload) Validate() map[string]string {
+	r.Token = strings.TrimSpace(r.Token)
+	return ValidateStruct(r)
+}
+
+func (c *ChangePasswordPayload) Validate() map[string]string {
+	return ValidateStruct(c)
+}
+
+func (u *UpdateProfilePayload) Validate() map[string]string {
+	if u.Name != nil {
+		*u.Name = strings.TrimSpace(*u.Name)
+	}
+	if u.Email != nil {
+		*u.Email = strings.TrimSpace(strings.ToLower(*u.Email))
+	}
+	if u.Phone != nil {
+		*u.Phone = strings.TrimSpace(*u.Phone)
+	}
+
+	return ValidateStruct(u)
+}
+
+func (u *User) ToProfileResponse() *UserProfileResponse {
+	return &UserProfileResponse{
+		ID:            u.ID,
+		Name:          u.Name,
+		DocumentType:  u.DocumentType,
+		Document:      u.Document,
+		LegalName:     u.LegalName,
+		TradeName:     u.TradeName,
+		Email:         u.Email,
+		EmailVerified: u.IsEmailVerified(),
+		Phone:         u.Phone,
+		Role:          u.Role,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+		ETag:          u.ETag(),
+	}
+}
+
+func (v *VerifyEmailPayload) Validate() map[string]string {
+	v.Token = strings.TrimSpace(v.Token)
+	return ValidateStruct(v)
+}
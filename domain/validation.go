@@ -7,16 +7,35 @@ import (
 )
 
 var validationMessages = map[string]string{
-	"required":        "This field is required",
-	"email":           "Invalid email format",
-	"min":             "Value is too short",
-	"max":             "Value is too long",
-	"eqfield":         "Fields do not match",
-	"gt":              "The value must be greater than zero",
-	CPFTag:            "Invalid CPF format",
-	StrongPasswordTag: "Password must be at least 8 characters long, contain an uppercase letter, a number, and a special character",
-	UUIDTag:           "Invalid uuid format",
-	WalletTypeTag:     "Invalid wallet type",
+	"required":          "This field is required",
+	"required_if":       "This field is required",
+	"excluded_unless":   "This field is only allowed for companies",
+	"required_without":  "Send either this field or its alternative",
+	"excluded_with":     "Send only one of this field and its alternative",
+	"email":             "Invalid email format",
+	"min":               "Value is too short",
+	"max":               "Value is too long",
+	"eqfield":           "Fields do not match",
+	"nefield":           "Value must be different",
+	"gt":                "The value must be greater than zero",
+	"iso4217":           "Invalid ISO 4217 currency code",
+	"ip|cidr":           "Invalid IP address or CIDR range",
+	"e164|len=0":        "Phone number must be in E.164 format, like +5511999999999",
+	CPFTag:              "Invalid CPF format",
+	CNPJTag:             "Invalid CNPJ format",
+	"cpf|cnpj":          "Invalid CPF or CNPJ format",
+	DocumentTypeTag:     "Invalid document type",
+	StrongPasswordTag:   "Password must be at least 8 characters long, contain an uppercase letter, a number, and a special character",
+	UUIDTag:             "Invalid uuid format",
+	WalletTypeTag:       "Invalid wallet type",
+	EscrowResolutionTag: "Invalid escrow resolution",
+	DisputeOutcomeTag:   "Invalid dispute outcome",
+	APIKeyScopeTag:      "Invalid api key scope",
+	OAuthScopeTag:       "Invalid oauth scope",
+	RedirectURITag:      "Redirect URI must be an absolute https URL without fragment, or http on localhost",
+	RoleTag:             "Invalid role",
+	PINTag:              "PIN must have 4 to 6 digits and cannot be a repeated digit or a sequence",
+	KYCDecisionTag:      "Invalid kyc decision",
 }
 
 func ValidateStruct(s any) map[string]string {
@@ -41,6 +60,25 @@ func ValidateStruct(s any) map[string]string {
 	return validationErrors
 }
 
+// ValidateVar checks a single value against tag and returns the message of
+// the failed rule, or an empty string when the value is valid.
+func ValidateVar(field any, tag string) string {
+	validate := validator.New()
+
+	SetupCustomValidations(validate)
+
+	err := validate.Var(field, tag)
+	if err == nil {
+		return ""
+	}
+
+	for _, err := range err.(validator.ValidationErrors) {
+		return getErrorMessage(err)
+	}
+
+	return "Invalid value"
+}
+
 func getErrorMessage(err validator.FieldError) string {
 	if msg, exists := validationMessages[err.Tag()]; exists {
 		return msg
@@ -5,6 +5,8 @@ package domain
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,12 +20,35 @@ var (
 	ErrPayerWalletNotFound       = errors.New("payer's wallet not found")
 	ErrSelfTransactionNotAllowed = errors.New("payer cannot perform transfers to themselves")
 	ErrWalletAlredyRegister      = errors.New("the user already has a wallet")
+	ErrWalletTypeMismatch        = errors.New("all wallets of a user must have the same type")
+	ErrMerchantRequiresCNPJ      = errors.New("only users registered with a cnpj can open merchant wallets")
 	ErrDebitWallet               = errors.New("failed to debit the wallet")
 	ErrCreditWallet              = errors.New("failed to credit the wallet")
+	ErrWalletNotFound            = errors.New("wallet not found")
+	ErrGetStatement              = errors.New("get statement fail")
 )
 
+const (
+	DefaultStatementLimit = 50
+	MaxStatementLimit     = 200
+)
+
+type StatementEntryType string
+
+const (
+	StatementEntryCredit StatementEntryType = "credit"
+	StatementEntryDebit  StatementEntryType = "debit"
+)
+
+// DefaultCurrency is the currency of wallets, holds and transfers created
+// without an explicit one, and of every record predating multi-currency.
+const DefaultCurrency = "BRL"
+
+// Wallet holds the balance of a user in one currency. A user has at most one
+// wallet per ISO 4217 currency code.
 type Wallet struct {
 	UserID    uuid.UUID      `gorm:"column:userId;type:char(36);primaryKey"`
+	Currency  string         `gorm:"column:currency;type:char(3);primaryKey;default:'BRL'"`
 	User      User           `gorm:"foreignKey:UserID"`
 	Type      WalletType     `gorm:"column:type;type:tinyint;not null;index"`
 	Balance   float64        `gorm:"column:balance;type:decimal(15, 2);not null"`
@@ -37,30 +62,123 @@ func (Wallet) TableName() string {
 }
 
 type WalletPayload struct {
-	Type WalletType `json:"type" validate:"required,wallettype"`
+	Type     WalletType `json:"type" validate:"required,wallettype"`
+	Currency string     `json:"currency" validate:"required,iso4217"`
+}
+
+type WalletResponse struct {
+	Currency string     `json:"currency"`
+	Type     WalletType `json:"type"`
+	Balance  float64    `json:"balance"`
+}
+
+// StatementQuery filters the statement of a wallet to transfers created in
+// [From, To), newest first.
+type StatementQuery struct {
+	From  *time.Time
+	To    *time.Time
+	Limit int
+}
+
+type StatementEntry struct {
+	TransferID     uuid.UUID          `json:"transferId"`
+	Type           StatementEntryType `json:"type"`
+	Value          float64            `json:"value"`
+	CounterpartyID uuid.UUID          `json:"counterpartyId"`
+	EscrowStatus   EscrowStatus       `json:"escrowStatus,omitempty"`
+	ReversalOfID   *uuid.UUID         `json:"reversalOfId,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt"`
+}
+
+type StatementResponse struct {
+	Currency string            `json:"currency"`
+	Balance  float64           `json:"balance"`
+	Entries  []*StatementEntry `json:"entries"`
 }
 
 type WalletHandler interface {
 	Create(echo.Context) error
+	GetAll(echo.Context) error
+	GetStatement(echo.Context) error
 }
 
 type WalletService interface {
 	Create(ctx context.Context, payload *WalletPayload) error
+	GetAll(ctx context.Context) ([]*WalletResponse, error)
+	GetStatement(ctx context.Context, currency string, query *StatementQuery) (*StatementResponse, error)
 }
 
 type WalletRepository interface {
 	Create(ctx context.Context, wallet *Wallet) error
-	GetByUserID(ctx context.Context, userID uuid.UUID) (*Wallet, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, currency string) (*Wallet, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*Wallet, error)
 }
 
 func (w *WalletPayload) Validate() map[string]string {
+	w.Currency = NormalizeCurrency(w.Currency)
 	return ValidateStruct(w)
 }
 
 func (w *WalletPayload) ToWallet(userID uuid.UUID) *Wallet {
 	return &Wallet{
 		UserID:    userID,
+		Currency:  w.Currency,
 		Type:      w.Type,
 		CreatedAt: time.Now().UTC(),
 	}
 }
+
+func (w *Wallet) ToResponse() *WalletResponse {
+	return &WalletResponse{
+		Currency: w.Currency,
+		Type:     w.Type,
+		Balance:  w.Balance,
+	}
+}
+
+// NewStatementQuery parses the from and to RFC 3339 timestamps and the
+// limit of the statement query string. Empty values keep their defaults.
+func NewStatementQuery(from, to, limit string) (*StatementQuery, map[string]string) {
+	query := &StatementQuery{Limit: DefaultStatementLimit}
+	validationErrors := make(map[string]string)
+
+	if from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			validationErrors["from"] = "Invalid RFC 3339 timestamp"
+		}
+		query.From = &parsed
+	}
+
+	if to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			validationErrors["to"] = "Invalid RFC 3339 timestamp"
+		}
+		query.To = &parsed
+	}
+
+	if limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > MaxStatementLimit {
+			validationErrors["limit"] = "Limit must be between 1 and " + strconv.Itoa(MaxStatementLimit)
+		}
+		query.Limit = parsed
+	}
+
+	if len(validationErrors) == 0 {
+		return query, nil
+	}
+
+	return nil, validationErrors
+}
+
+// NormalizeCurrency upper-cases a currency code and falls back to
+// DefaultCurrency when it is empty.
+func NormalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
@@ -0,0 +1,125 @@
+package encryption
+
+//go:generate mockgen -source=encryption.go -destination=../mocks/encryption_mock.go -package=mocks
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/samber/do"
+)
+
+const (
+	DriverLocal = "local"
+
+	// prefix marks an encrypted value as enc:v1:<keyID>:<nonce|ciphertext>.
+	// Values without it are plaintext written before the encryption existed.
+	prefix = "enc:v1:"
+)
+
+var (
+	ErrUnsupportedDriver   = errors.New("unsupported kms driver")
+	ErrMasterKeyExists     = errors.New("master key already exists")
+	ErrMasterKeyNotExists  = errors.New("master key does not exist, generate it first")
+	ErrInvalidMasterKey    = errors.New("master key must be 32 bytes")
+	ErrKeyringExists       = errors.New("pii keyring already exists")
+	ErrKeyringNotExists    = errors.New("pii keyring does not exist, generate it first")
+	ErrUnknownKey          = errors.New("value encrypted with an unknown data key")
+	ErrMalformedCiphertext = errors.New("malformed ciphertext")
+	ErrCipherNotConfigured = errors.New("pii cipher not configured")
+)
+
+// Cipher encrypts personal data before it is stored. The field is the column
+// name and is bound to the ciphertext, so a value copied to another column
+// does not decrypt.
+type Cipher interface {
+	Encrypt(plaintext, field string) (string, error)
+	Decrypt(value, field string) (string, error)
+
+	// BlindIndex is a keyed hash of the value, used to look up and enforce
+	// uniqueness on encrypted columns without decrypting them.
+	BlindIndex(value, field string) string
+
+	// NeedsReencrypt reports whether the value is plaintext or encrypted
+	// with a data key other than the active one.
+	NeedsReencrypt(value string) bool
+}
+
+func NewCipher(i *do.Injector) (Cipher, error) {
+	kms, err := NewKMS()
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(context.Background(), kms, config.Env.PIIKeyringFile)
+}
+
+type aesCipher struct {
+	active   string
+	keys     map[string]cipher.AEAD
+	indexKey []byte
+}
+
+func (a *aesCipher) Encrypt(plaintext, field string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	sealed, err := seal(a.keys[a.active], []byte(plaintext), []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + a.active + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (a *aesCipher) Decrypt(value, field string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrMalformedCiphertext
+	}
+
+	aead, ok := a.keys[keyID]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrMalformedCiphertext
+	}
+
+	plaintext, err := open(aead, sealed, []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func (a *aesCipher) BlindIndex(value, field string) string {
+	mac := hmac.New(sha256.New, a.indexKey)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (a *aesCipher) NeedsReencrypt(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	return !strings.HasPrefix(value, prefix+a.active+":")
+}
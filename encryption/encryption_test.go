@@ -0,0 +1,158 @@
+package encryption
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestKeyring generates a local master key and a keyring in a temporary
+// directory, returning the KMS and the keyring path.
+func newTestKeyring(t *testing.T) (KMS, string) {
+	dir := t.TempDir()
+	masterKeyFile := filepath.Join(dir, "pii-master.key")
+	keyringFile := filepath.Join(dir, "pii-keyring.json")
+
+	assert.NoError(t, GenerateMasterKey(masterKeyFile))
+
+	kms, err := newLocalKMS(masterKeyFile)
+	assert.NoError(t, err)
+
+	_, err = Generate(context.Background(), kms, keyringFile)
+	assert.NoError(t, err)
+
+	return kms, keyringFile
+}
+
+func newTestCipher(t *testing.T) Cipher {
+	kms, keyringFile := newTestKeyring(t)
+
+	cipher, err := Load(context.Background(), kms, keyringFile)
+	assert.NoError(t, err)
+
+	return cipher
+}
+
+func TestCipher_EncryptDecrypt_ShouldRoundTrip(t *testing.T) {
+	cipher := newTestCipher(t)
+
+	encrypted, err := cipher.Encrypt("maria@example.com", "email")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, prefix))
+	assert.NotContains(t, encrypted, "maria")
+
+	decrypted, err := cipher.Decrypt(encrypted, "email")
+	assert.NoError(t, err)
+	assert.Equal(t, "maria@example.com", decrypted)
+}
+
+func TestCipher_Encrypt_WhenValueIsEmpty_ShouldKeepItEmpty(t *testing.T) {
+	cipher := newTestCipher(t)
+
+	encrypted, err := cipher.Encrypt("", "phone")
+	assert.NoError(t, err)
+	assert.Empty(t, encrypted)
+}
+
+func TestCipher_Decrypt_WhenFieldDoesNotMatch_ShouldFail(t *testing.T) {
+	cipher := newTestCipher(t)
+
+	encrypted, err := cipher.Encrypt("52998224725", "document")
+	assert.NoError(t, err)
+
+	_, err = cipher.Decrypt(encrypted, "email")
+	assert.Error(t, err)
+}
+
+func TestCipher_Decrypt_WhenKeyIDIsUnknown_ShouldReturnErrUnknownKey(t *testing.T) {
+	cipher := newTestCipher(t)
+	other := newTestCipher(t)
+
+	encrypted, err := other.Encrypt("maria@example.com", "email")
+	assert.NoError(t, err)
+
+	_, err = cipher.Decrypt(encrypted, "email")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestCipher_Decrypt_WhenValueIsLegacyPlaintext_ShouldReturnItAsIs(t *testing.T) {
+	cipher := newTestCipher(t)
+
+	decrypted, err := cipher.Decrypt("maria@example.com", "email")
+	assert.NoError(t, err)
+	assert.Equal(t, "maria@example.com", decrypted)
+}
+
+func TestCipher_Decrypt_WhenCiphertextIsMalformed_ShouldReturnErrMalformedCiphertext(t *testing.T) {
+	cipher := newTestCipher(t)
+
+	_, err := cipher.Decrypt(prefix+"no-separator", "email")
+	assert.ErrorIs(t, err, ErrMalformedCiphertext)
+}
+
+func TestCipher_NeedsReencrypt_AfterRotation_ShouldFlagValuesUnderOlderKeys(t *testing.T) {
+	kms, keyringFile := newTestKeyring(t)
+	ctx := context.Background()
+
+	before, err := Load(ctx, kms, keyringFile)
+	assert.NoError(t, err)
+
+	encrypted, err := before.Encrypt("maria@example.com", "email")
+	assert.NoError(t, err)
+	assert.False(t, before.NeedsReencrypt(encrypted))
+	assert.True(t, before.NeedsReencrypt("maria@example.com"))
+	assert.False(t, before.NeedsReencrypt(""))
+
+	_, err = Rotate(ctx, kms, keyringFile)
+	assert.NoError(t, err)
+
+	after, err := Load(ctx, kms, keyringFile)
+	assert.NoError(t, err)
+	assert.True(t, after.NeedsReencrypt(encrypted))
+
+	decrypted, err := after.Decrypt(encrypted, "email")
+	assert.NoError(t, err)
+	assert.Equal(t, "maria@example.com", decrypted)
+
+	reencrypted, err := after.Encrypt(decrypted, "email")
+	assert.NoError(t, err)
+	assert.False(t, after.NeedsReencrypt(reencrypted))
+}
+
+func TestCipher_BlindIndex_ShouldBeDeterministicPerField(t *testing.T) {
+	kms, keyringFile := newTestKeyring(t)
+	ctx := context.Background()
+
+	cipher, err := Load(ctx, kms, keyringFile)
+	assert.NoError(t, err)
+
+	index := cipher.BlindIndex("maria@example.com", "email")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, cipher.BlindIndex("maria@example.com", "email"))
+	assert.NotEqual(t, index, cipher.BlindIndex("joao@example.com", "email"))
+	assert.NotEqual(t, index, cipher.BlindIndex("maria@example.com", "document"))
+
+	_, err = Rotate(ctx, kms, keyringFile)
+	assert.NoError(t, err)
+
+	rotated, err := Load(ctx, kms, keyringFile)
+	assert.NoError(t, err)
+	assert.Equal(t, index, rotated.BlindIndex("maria@example.com", "email"))
+}
+
+func TestGenerate_WhenKeyringExists_ShouldReturnErrKeyringExists(t *testing.T) {
+	kms, keyringFile := newTestKeyring(t)
+
+	_, err := Generate(context.Background(), kms, keyringFile)
+	assert.ErrorIs(t, err, ErrKeyringExists)
+}
+
+func TestGenerateMasterKey_WhenKeyExists_ShouldReturnErrMasterKeyExists(t *testing.T) {
+	masterKeyFile := filepath.Join(t.TempDir(), "pii-master.key")
+
+	assert.NoError(t, GenerateMasterKey(masterKeyFile))
+	assert.ErrorIs(t, GenerateMasterKey(masterKeyFile), ErrMasterKeyExists)
+}
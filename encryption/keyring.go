@@ -0,0 +1,190 @@
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const dataKeySize = 32
+
+// keyring is the file holding the data keys, each wrapped by the KMS. Old
+// keys are never dropped as rows encrypted with them may still exist.
+type keyring struct {
+	Active   string       `json:"active"`
+	IndexKey []byte       `json:"indexKey"`
+	Keys     []keyringKey `json:"keys"`
+}
+
+type keyringKey struct {
+	ID        string    `json:"id"`
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Generate creates the keyring at path with one active data key and the blind
+// index key. The index key is never rotated, as every stored index would
+// have to be recomputed.
+func Generate(ctx context.Context, kms KMS, path string) (string, error) {
+	if _, err := readKeyring(path); !errors.Is(err, ErrKeyringNotExists) {
+		if err == nil {
+			return "", ErrKeyringExists
+		}
+		return "", err
+	}
+
+	indexKey, err := newWrappedKey(ctx, kms)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := newKeyringKey(ctx, kms)
+	if err != nil {
+		return "", err
+	}
+
+	k := &keyring{
+		Active:   key.ID,
+		IndexKey: indexKey,
+		Keys:     []keyringKey{*key},
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+
+	if err := writeKeyring(path, k); err != nil {
+		return "", err
+	}
+
+	return key.ID, nil
+}
+
+// Rotate makes a new data key the active one. Values encrypted with the
+// previous keys keep decrypting until the encrypt command rewrites them.
+func Rotate(ctx context.Context, kms KMS, path string) (string, error) {
+	k, err := readKeyring(path)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := newKeyringKey(ctx, kms)
+	if err != nil {
+		return "", err
+	}
+
+	k.Active = key.ID
+	k.Keys = append(k.Keys, *key)
+
+	if err := writeKeyring(path, k); err != nil {
+		return "", err
+	}
+
+	return key.ID, nil
+}
+
+// Load unwraps every key of the keyring at path into a Cipher.
+func Load(ctx context.Context, kms KMS, path string) (Cipher, error) {
+	k, err := readKeyring(path)
+	if err != nil {
+		return nil, err
+	}
+
+	indexKey, err := kms.Unwrap(ctx, k.IndexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]cipher.AEAD, len(k.Keys))
+	for _, entry := range k.Keys {
+		key, err := kms.Unwrap(ctx, entry.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[entry.ID] = aead
+	}
+
+	if _, ok := keys[k.Active]; !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return &aesCipher{
+		active:   k.Active,
+		keys:     keys,
+		indexKey: indexKey,
+	}, nil
+}
+
+func newKeyringKey(ctx context.Context, kms KMS) (*keyringKey, error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := newWrappedKey(ctx, kms)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyringKey{
+		ID:        hex.EncodeToString(id),
+		Key:       wrapped,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func newWrappedKey(ctx context.Context, kms KMS) ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return kms.Wrap(ctx, key)
+}
+
+func readKeyring(path string) (*keyring, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrKeyringNotExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var k keyring
+	if err := jsoniter.Unmarshal(content, &k); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// writeKeyring replaces the keyring atomically so a starting server never
+// reads it half written.
+func writeKeyring(path string, k *keyring) error {
+	content, err := jsoniter.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
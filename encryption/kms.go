@@ -0,0 +1,126 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+)
+
+const masterKeySize = 32
+
+// KMS holds the key encryption key. Data keys are only stored wrapped by it,
+// so the keyring file alone reveals no personal data. A cloud KMS can
+// implement it by calling its encrypt and decrypt APIs.
+type KMS interface {
+	Wrap(ctx context.Context, key []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+func NewKMS() (KMS, error) {
+	switch config.Env.PIIKMSDriver {
+	case DriverLocal:
+		return newLocalKMS(config.Env.PIIMasterKeyFile)
+	}
+
+	return nil, ErrUnsupportedDriver
+}
+
+// localKMS wraps data keys with a master key read from a file, for
+// development and single-host deployments.
+type localKMS struct {
+	aead cipher.AEAD
+}
+
+func newLocalKMS(path string) (KMS, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrMasterKeyNotExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != masterKeySize {
+		return nil, ErrInvalidMasterKey
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localKMS{aead: aead}, nil
+}
+
+func (l *localKMS) Wrap(ctx context.Context, key []byte) ([]byte, error) {
+	return seal(l.aead, key, nil)
+}
+
+func (l *localKMS) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	return open(l.aead, wrapped, nil)
+}
+
+// GenerateMasterKey writes a new random master key for the local KMS to path.
+// It never overwrites an existing one, as that would make every data key
+// wrapped by it unreadable.
+func GenerateMasterKey(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return ErrMasterKeyExists
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	key := make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext under a random nonce, which it prepends to the
+// result.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformedCiphertext
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,81 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+var active atomic.Value
+
+func init() {
+	schema.RegisterSerializer("encrypted", serializer{})
+}
+
+// UseCipher sets the cipher of the "encrypted" gorm serializer. It must be
+// called before any model with an encrypted field is read or written.
+func UseCipher(c Cipher) {
+	active.Store(c)
+}
+
+func currentCipher() (Cipher, error) {
+	c, ok := active.Load().(Cipher)
+	if !ok {
+		return nil, ErrCipherNotConfigured
+	}
+
+	return c, nil
+}
+
+// serializer encrypts string fields tagged with serializer:encrypted, bound
+// to their column name.
+type serializer struct{}
+
+func (serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	if value != "" {
+		c, err := currentCipher()
+		if err != nil {
+			return err
+		}
+
+		value, err = c.Decrypt(value, field.DBName)
+		if err != nil {
+			return err
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+func (serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %T for encrypted field %s", fieldValue, field.Name)
+	}
+
+	if value == "" {
+		return "", nil
+	}
+
+	c, err := currentCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Encrypt(value, field.DBName)
+}
@@ -0,0 +1,91 @@
+package encryption
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+)
+
+type serializerTestModel struct {
+	Email string `gorm:"column:email;serializer:encrypted"`
+}
+
+func newSerializerTestField(t *testing.T) *schema.Field {
+	s, err := schema.Parse(&serializerTestModel{}, &sync.Map{}, schema.NamingStrategy{})
+	assert.NoError(t, err)
+
+	field := s.LookUpField("email")
+	assert.NotNil(t, field)
+
+	return field
+}
+
+func TestSerializer_ValueThenScan_ShouldStoreCiphertextAndReadPlaintext(t *testing.T) {
+	cipher := newTestCipher(t)
+	UseCipher(cipher)
+
+	field := newSerializerTestField(t)
+	ctx := context.Background()
+	model := &serializerTestModel{}
+	dst := reflect.ValueOf(model).Elem()
+
+	stored, err := serializer{}.Value(ctx, field, dst, "maria@example.com")
+	assert.NoError(t, err)
+	assert.NotContains(t, stored, "maria")
+	assert.False(t, cipher.NeedsReencrypt(stored.(string)))
+
+	err = serializer{}.Scan(ctx, field, dst, []byte(stored.(string)))
+	assert.NoError(t, err)
+	assert.Equal(t, "maria@example.com", model.Email)
+}
+
+func TestSerializer_Scan_WhenRowIsLegacyPlaintext_ShouldReadItAsIs(t *testing.T) {
+	UseCipher(newTestCipher(t))
+
+	field := newSerializerTestField(t)
+	model := &serializerTestModel{}
+
+	err := serializer{}.Scan(context.Background(), field, reflect.ValueOf(model).Elem(), "maria@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "maria@example.com", model.Email)
+}
+
+func TestSerializer_Scan_WhenValueIsNull_ShouldSetEmptyString(t *testing.T) {
+	UseCipher(newTestCipher(t))
+
+	field := newSerializerTestField(t)
+	model := &serializerTestModel{Email: "stale"}
+
+	err := serializer{}.Scan(context.Background(), field, reflect.ValueOf(model).Elem(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, model.Email)
+}
+
+func TestSerializer_Value_WhenValueIsEmpty_ShouldStoreEmptyString(t *testing.T) {
+	UseCipher(newTestCipher(t))
+
+	field := newSerializerTestField(t)
+	model := &serializerTestModel{}
+
+	stored, err := serializer{}.Value(context.Background(), field, reflect.ValueOf(model).Elem(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", stored)
+}
+
+func TestSerializer_Scan_WhenCiphertextBelongsToAnotherColumn_ShouldFail(t *testing.T) {
+	cipher := newTestCipher(t)
+	UseCipher(cipher)
+
+	encrypted, err := cipher.Encrypt("52998224725", "document")
+	assert.NoError(t, err)
+
+	field := newSerializerTestField(t)
+	model := &serializerTestModel{}
+
+	err = serializer{}.Scan(context.Background(), field, reflect.ValueOf(model).Elem(), encrypted)
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package exchange
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// rateTable is the content of the rates file: how many units of each
+// currency are worth one unit of Base.
+type rateTable struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// fileRateProvider serves rates from a local JSON file, reloading it whenever
+// the file is modified so rates can be updated without a restart.
+type fileRateProvider struct {
+	path    string
+	mu      sync.RWMutex
+	table   rateTable
+	modTime time.Time
+}
+
+func newFileRateProvider(path string) (RateProvider, error) {
+	provider := &fileRateProvider{
+		path: path,
+	}
+
+	if err := provider.reload(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func (f *fileRateProvider) Rate(ctx context.Context, from, to string) (float64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("exchange", "file"),
+		slog.String("func", "Rate"),
+	)
+
+	if err := f.reload(); err != nil {
+		log.Warn("Failed to reload rates file, using last loaded rates", slog.String("error", err.Error()))
+	}
+
+	if from == to {
+		return 1, nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	fromRate, ok := f.unitsPerBase(from)
+	if !ok {
+		return 0, ErrRateNotFound
+	}
+
+	toRate, ok := f.unitsPerBase(to)
+	if !ok {
+		return 0, ErrRateNotFound
+	}
+
+	return toRate / fromRate, nil
+}
+
+func (f *fileRateProvider) unitsPerBase(currency string) (float64, bool) {
+	if currency == f.table.Base {
+		return 1, true
+	}
+
+	rate, ok := f.table.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+func (f *fileRateProvider) reload() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.mu.RLock()
+	unchanged := info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	content, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	var table rateTable
+	if err := jsoniter.Unmarshal(content, &table); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.table = table
+	f.modTime = info.ModTime()
+	f.mu.Unlock()
+
+	slog.Info("Exchange rates loaded", slog.String("path", f.path), slog.String("base", table.Base), slog.Int("currencies", len(table.Rates)))
+	return nil
+}
@@ -0,0 +1,22 @@
+package exchange
+
+//go:generate mockgen -source=rate.go -destination=../mocks/rate_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/samber/do"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// RateProvider returns how many units of to are worth one unit of from.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+func NewRateProvider(i *do.Injector) (RateProvider, error) {
+	return newFileRateProvider(config.Env.RatesFilePath)
+}
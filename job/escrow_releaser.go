@@ -0,0 +1,56 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/samber/do"
+)
+
+// EscrowReleaser periodically credits payees of escrow transfers whose
+// confirmation timeout has passed without a dispute.
+type EscrowReleaser struct {
+	i               *do.Injector
+	transferService domain.TransferService
+	interval        time.Duration
+}
+
+func NewEscrowReleaser(i *do.Injector) (*EscrowReleaser, error) {
+	transferService, err := do.Invoke[domain.TransferService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EscrowReleaser{
+		i:               i,
+		transferService: transferService,
+		interval:        config.Env.EscrowReleaseInterval,
+	}, nil
+}
+
+func (e *EscrowReleaser) Start(ctx context.Context) {
+	log := slog.With(
+		slog.String("job", "escrowReleaser"),
+		slog.String("func", "Start"),
+	)
+
+	log.Info("Starting escrow releaser", slog.String("interval", e.interval.String()))
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Escrow releaser stopped")
+			return
+		case <-ticker.C:
+			if _, err := e.transferService.ReleaseDueEscrows(ctx); err != nil {
+				log.Error("Failed to release due escrow transfers", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
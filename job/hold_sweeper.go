@@ -0,0 +1,56 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/samber/do"
+)
+
+// HoldSweeper periodically releases holds whose expiration has passed so
+// the reserved amount becomes available again.
+type HoldSweeper struct {
+	i           *do.Injector
+	holdService domain.HoldService
+	interval    time.Duration
+}
+
+func NewHoldSweeper(i *do.Injector) (*HoldSweeper, error) {
+	holdService, err := do.Invoke[domain.HoldService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HoldSweeper{
+		i:           i,
+		holdService: holdService,
+		interval:    config.Env.HoldSweepInterval,
+	}, nil
+}
+
+func (h *HoldSweeper) Start(ctx context.Context) {
+	log := slog.With(
+		slog.String("job", "holdSweeper"),
+		slog.String("func", "Start"),
+	)
+
+	log.Info("Starting hold sweeper", slog.String("interval", h.interval.String()))
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Hold sweeper stopped")
+			return
+		case <-ticker.C:
+			if _, err := h.holdService.ReleaseExpired(ctx); err != nil {
+				log.Error("Failed to release expired holds", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	manifestFile = "keyring.json"
+
+	// reloadInterval bounds how often the manifest is checked for a rotation,
+	// so signing and verifying tokens does not stat it on every request.
+	reloadInterval = 10 * time.Second
+)
+
+// legacyPrivateKeyFile is the single key used before the keyring existed.
+// It is still served as the active key while no keyring was generated, and
+// Generate keeps it for verification so the tokens it signed stay valid.
+var legacyPrivateKeyFile = "ec_private_key.pem"
+
+// manifest lists the keys of a keyring directory. Every key has a
+// <kid>.pub.pem file and the active one also has <kid>.pem.
+type manifest struct {
+	Active string        `json:"active"`
+	Keys   []manifestKey `json:"keys"`
+}
+
+type manifestKey struct {
+	ID        string     `json:"kid"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RetiredAt *time.Time `json:"retiredAt,omitempty"`
+}
+
+// fileKeyring serves the keys of a keyring directory, reloading them whenever
+// the manifest changes so a rotation is picked up without a restart.
+type fileKeyring struct {
+	dir            string
+	reloadInterval time.Duration
+	checkedAt      atomic.Int64
+	mu             sync.RWMutex
+	active         *Key
+	keys           map[string]*Key
+	modTime        time.Time
+}
+
+func newFileKeyring(dir string) (*fileKeyring, error) {
+	keyring := &fileKeyring{
+		dir:            dir,
+		reloadInterval: reloadInterval,
+	}
+
+	if err := keyring.reload(); err != nil {
+		return nil, err
+	}
+
+	return keyring, nil
+}
+
+func (f *fileKeyring) SigningKey() (*Key, error) {
+	f.refresh()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.active == nil {
+		return nil, ErrNoActiveKey
+	}
+
+	return f.active, nil
+}
+
+func (f *fileKeyring) VerificationKey(kid string) (*ecdsa.PublicKey, error) {
+	f.refresh()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	key, ok := f.keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	return key.PublicKey, nil
+}
+
+func (f *fileKeyring) JWKS() (*JWKSet, error) {
+	f.refresh()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	set := &JWKSet{Keys: make([]JWK, 0, len(f.keys))}
+	set.Keys = append(set.Keys, f.active.ToJWK())
+	for _, key := range f.keys {
+		if key.ID != f.active.ID {
+			set.Keys = append(set.Keys, key.ToJWK())
+		}
+	}
+
+	return set, nil
+}
+
+// refresh reloads the keyring when the manifest changed, checking it at most
+// once per reloadInterval.
+func (f *fileKeyring) refresh() {
+	log := slog.With(
+		slog.String("keyring", "file"),
+		slog.String("func", "refresh"),
+	)
+
+	now := time.Now().UnixNano()
+	checkedAt := f.checkedAt.Load()
+	if now-checkedAt < int64(f.reloadInterval) || !f.checkedAt.CompareAndSwap(checkedAt, now) {
+		return
+	}
+
+	if err := f.reload(); err != nil {
+		log.Warn("Failed to reload keyring, using last loaded keys", slog.String("error", err.Error()))
+	}
+}
+
+func (f *fileKeyring) reload() error {
+	log := slog.With(
+		slog.String("keyring", "file"),
+		slog.String("func", "reload"),
+	)
+
+	info, err := os.Stat(filepath.Join(f.dir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return f.loadLegacy()
+	}
+	if err != nil {
+		return err
+	}
+
+	f.mu.RLock()
+	unchanged := info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	keys, err := Read(f.dir)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 || keys[0].PrivateKey == nil {
+		return ErrNoActiveKey
+	}
+
+	active := keys[0]
+	byID := make(map[string]*Key, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key
+	}
+
+	f.mu.Lock()
+	f.active = active
+	f.keys = byID
+	f.modTime = info.ModTime()
+	f.mu.Unlock()
+
+	log.Info("Keyring loaded", slog.String("dir", f.dir), slog.String("activeKid", active.ID), slog.Int("keys", len(keys)))
+	return nil
+}
+
+func (f *fileKeyring) loadLegacy() error {
+	log := slog.With(
+		slog.String("keyring", "file"),
+		slog.String("func", "loadLegacy"),
+	)
+
+	f.mu.RLock()
+	loaded := f.active != nil
+	f.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	privateKey, err := readPrivateKey(legacyPrivateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	key := &Key{
+		ID:         Thumbprint(&privateKey.PublicKey),
+		PrivateKey: privateKey,
+		PublicKey:  &privateKey.PublicKey,
+	}
+
+	f.mu.Lock()
+	f.active = key
+	f.keys = map[string]*Key{key.ID: key}
+	f.mu.Unlock()
+
+	log.Warn("Keyring not found, using legacy signing key", slog.String("dir", f.dir), slog.String("activeKid", key.ID))
+	return nil
+}
+
+func readManifest(dir string) (*manifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrKeyringNotExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := jsoniter.Unmarshal(content, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// writeManifest replaces the manifest atomically so a reloading server never
+// reads it half written.
+func writeManifest(dir string, m *manifest) error {
+	content, err := jsoniter.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(dir, manifestFile+".tmp")
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(dir, manifestFile))
+}
+
+func privateKeyPath(dir, kid string) string {
+	return filepath.Join(dir, kid+".pem")
+}
+
+func publicKeyPath(dir, kid string) string {
+	return filepath.Join(dir, kid+".pub.pem")
+}
+
+func readPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	keyData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(keyData)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, errors.New("failed to decode PEM block containing private key")
+	}
+
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func readPublicKey(path string) (*ecdsa.PublicKey, error) {
+	keyData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(keyData)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("failed to decode PEM block containing public key")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaPubKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not ECDSA public key")
+	}
+
+	return ecdsaPubKey, nil
+}
+
+func writePrivateKey(path string, privateKey *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600)
+}
+
+func writePublicKey(path string, publicKey *ecdsa.PublicKey) error {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644)
+}
@@ -0,0 +1,52 @@
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// JWK is the public half of a key in the JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *Key) ToJWK() JWK {
+	x, y := encodeCoordinates(k.PublicKey)
+	return JWK{
+		KeyType:   "EC",
+		Curve:     k.PublicKey.Curve.Params().Name,
+		X:         x,
+		Y:         y,
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: "ES256",
+	}
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the public key, used as its
+// kid so the same key always gets the same ID.
+func Thumbprint(publicKey *ecdsa.PublicKey) string {
+	x, y := encodeCoordinates(publicKey)
+	canonical := fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`, publicKey.Curve.Params().Name, x, y)
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func encodeCoordinates(publicKey *ecdsa.PublicKey) (string, string) {
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+	x := publicKey.X.FillBytes(make([]byte, size))
+	y := publicKey.Y.FillBytes(make([]byte, size))
+	return base64.RawURLEncoding.EncodeToString(x), base64.RawURLEncoding.EncodeToString(y)
+}
@@ -0,0 +1,45 @@
+package keyring
+
+//go:generate mockgen -source=keyring.go -destination=../mocks/keyring_mock.go -package=mocks
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/samber/do"
+)
+
+var (
+	ErrKeyNotFound      = errors.New("signing key not found")
+	ErrNoActiveKey      = errors.New("keyring has no active signing key")
+	ErrKeyringExists    = errors.New("keyring already exists")
+	ErrKeyringNotExists = errors.New("keyring does not exist, generate it first")
+)
+
+// Key is an ES256 key identified by its kid. Only the active key keeps its
+// private half; retired keys stay in the keyring to verify the tokens they
+// signed before the rotation.
+type Key struct {
+	ID         string
+	PrivateKey *ecdsa.PrivateKey
+	PublicKey  *ecdsa.PublicKey
+	CreatedAt  time.Time
+	RetiredAt  *time.Time
+}
+
+type Keyring interface {
+	SigningKey() (*Key, error)
+	VerificationKey(kid string) (*ecdsa.PublicKey, error)
+	JWKS() (*JWKSet, error)
+}
+
+func NewKeyring(i *do.Injector) (Keyring, error) {
+	keyring, err := newFileKeyring(config.Env.KeysDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyring, nil
+}
@@ -0,0 +1,229 @@
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useLegacyKey points the legacy key path at a file in a temporary directory
+// for the duration of the test, writing a key there when write is true.
+func useLegacyKey(t *testing.T, write bool) *ecdsa.PrivateKey {
+	path := filepath.Join(t.TempDir(), "ec_private_key.pem")
+	previous := legacyPrivateKeyFile
+	legacyPrivateKeyFile = path
+	t.Cleanup(func() { legacyPrivateKeyFile = previous })
+
+	if !write {
+		return nil
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, writePrivateKey(path, privateKey))
+
+	return privateKey
+}
+
+// touchManifest moves the modification time of the manifest forward, so a
+// reload notices a change even on filesystems with coarse timestamps.
+func touchManifest(t *testing.T, dir string) {
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, manifestFile), later, later))
+}
+
+func TestRotate_ShouldKeepOnlyTheKeepMostRecentRetiredKeys(t *testing.T) {
+	useLegacyKey(t, false)
+	dir := t.TempDir()
+
+	first, err := Generate(dir)
+	assert.NoError(t, err)
+
+	second, err := Rotate(dir, 1)
+	assert.NoError(t, err)
+
+	third, err := Rotate(dir, 1)
+	assert.NoError(t, err)
+
+	keys, err := Read(dir)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, third.ID, keys[0].ID)
+	assert.NotNil(t, keys[0].PrivateKey)
+	assert.Nil(t, keys[0].RetiredAt)
+	assert.Equal(t, second.ID, keys[1].ID)
+	assert.Nil(t, keys[1].PrivateKey)
+	assert.NotNil(t, keys[1].RetiredAt)
+
+	assert.NoFileExists(t, publicKeyPath(dir, first.ID))
+	assert.NoFileExists(t, privateKeyPath(dir, first.ID))
+	assert.NoFileExists(t, privateKeyPath(dir, second.ID))
+	assert.FileExists(t, publicKeyPath(dir, second.ID))
+}
+
+func TestRotate_WhenKeepIsZero_ShouldDropEveryRetiredKey(t *testing.T) {
+	useLegacyKey(t, false)
+	dir := t.TempDir()
+
+	first, err := Generate(dir)
+	assert.NoError(t, err)
+
+	second, err := Rotate(dir, 0)
+	assert.NoError(t, err)
+
+	keys, err := Read(dir)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, second.ID, keys[0].ID)
+	assert.NoFileExists(t, publicKeyPath(dir, first.ID))
+	assert.NoFileExists(t, privateKeyPath(dir, first.ID))
+}
+
+func TestFileKeyring_JWKS_ShouldListTheActiveKeyFirstAndTheRetiredOnes(t *testing.T) {
+	useLegacyKey(t, false)
+	dir := t.TempDir()
+
+	retired, err := Generate(dir)
+	assert.NoError(t, err)
+
+	active, err := Rotate(dir, 2)
+	assert.NoError(t, err)
+
+	keyring, err := newFileKeyring(dir)
+	assert.NoError(t, err)
+
+	set, err := keyring.JWKS()
+	assert.NoError(t, err)
+	assert.Len(t, set.Keys, 2)
+	assert.Equal(t, active.ID, set.Keys[0].KeyID)
+	assert.Equal(t, retired.ID, set.Keys[1].KeyID)
+
+	for _, jwk := range set.Keys {
+		assert.Equal(t, "EC", jwk.KeyType)
+		assert.Equal(t, "P-256", jwk.Curve)
+		assert.Equal(t, "ES256", jwk.Algorithm)
+		assert.Equal(t, "sig", jwk.Use)
+	}
+
+	signingKey, err := keyring.SigningKey()
+	assert.NoError(t, err)
+	assert.Equal(t, active.ID, signingKey.ID)
+
+	_, err = keyring.VerificationKey(retired.ID)
+	assert.NoError(t, err)
+
+	_, err = keyring.VerificationKey("unknown")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestThumbprint_ShouldHashTheRequiredMembersInLexicographicOrder(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	key := &Key{ID: Thumbprint(&privateKey.PublicKey), PublicKey: &privateKey.PublicKey}
+	jwk := key.ToJWK()
+
+	// RFC 7638: only crv, kty, x and y, with sorted keys and no whitespace,
+	// which is how encoding/json marshals a map.
+	canonical, err := json.Marshal(map[string]string{"crv": jwk.Curve, "kty": jwk.KeyType, "x": jwk.X, "y": jwk.Y})
+	assert.NoError(t, err)
+	sum := sha256.Sum256(canonical)
+
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), key.ID)
+	assert.Equal(t, key.ID, jwk.KeyID)
+	assert.Len(t, jwk.X, 43)
+	assert.Len(t, jwk.Y, 43)
+}
+
+func TestFileKeyring_WhenManifestChanges_ShouldReloadAfterTheInterval(t *testing.T) {
+	useLegacyKey(t, false)
+	dir := t.TempDir()
+
+	first, err := Generate(dir)
+	assert.NoError(t, err)
+
+	keyring, err := newFileKeyring(dir)
+	assert.NoError(t, err)
+
+	second, err := Rotate(dir, 1)
+	assert.NoError(t, err)
+	touchManifest(t, dir)
+
+	keyring.checkedAt.Store(time.Now().UnixNano())
+	signingKey, err := keyring.SigningKey()
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, signingKey.ID)
+
+	keyring.checkedAt.Store(time.Now().Add(-reloadInterval).UnixNano())
+	signingKey, err = keyring.SigningKey()
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, signingKey.ID)
+
+	_, err = keyring.VerificationKey(first.ID)
+	assert.NoError(t, err)
+}
+
+func TestFileKeyring_WhenNoManifestExists_ShouldFallBackToTheLegacyKey(t *testing.T) {
+	legacy := useLegacyKey(t, true)
+
+	keyring, err := newFileKeyring(t.TempDir())
+	assert.NoError(t, err)
+
+	signingKey, err := keyring.SigningKey()
+	assert.NoError(t, err)
+	assert.Equal(t, Thumbprint(&legacy.PublicKey), signingKey.ID)
+	assert.True(t, legacy.Equal(signingKey.PrivateKey))
+
+	set, err := keyring.JWKS()
+	assert.NoError(t, err)
+	assert.Len(t, set.Keys, 1)
+}
+
+func TestGenerate_WhenLegacyKeyExists_ShouldKeepItAsARetiredKey(t *testing.T) {
+	legacy := useLegacyKey(t, true)
+	dir := t.TempDir()
+	legacyKid := Thumbprint(&legacy.PublicKey)
+
+	active, err := Generate(dir)
+	assert.NoError(t, err)
+	assert.NotEqual(t, legacyKid, active.ID)
+
+	keys, err := Read(dir)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, active.ID, keys[0].ID)
+	assert.Equal(t, legacyKid, keys[1].ID)
+	assert.NotNil(t, keys[1].RetiredAt)
+	assert.Nil(t, keys[1].PrivateKey)
+
+	keyring, err := newFileKeyring(dir)
+	assert.NoError(t, err)
+
+	publicKey, err := keyring.VerificationKey(legacyKid)
+	assert.NoError(t, err)
+	assert.True(t, legacy.PublicKey.Equal(publicKey))
+
+	signingKey, err := keyring.SigningKey()
+	assert.NoError(t, err)
+	assert.Equal(t, active.ID, signingKey.ID)
+}
+
+func TestGenerate_WhenKeyringExists_ShouldReturnErrKeyringExists(t *testing.T) {
+	useLegacyKey(t, false)
+	dir := t.TempDir()
+
+	_, err := Generate(dir)
+	assert.NoError(t, err)
+
+	_, err = Generate(dir)
+	assert.ErrorIs(t, err, ErrKeyringExists)
+}
@@ -0,0 +1,189 @@
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Generate creates a keyring in dir with a new active key. The legacy signing
+// key, when present, is kept as a retired key so the tokens it signed keep
+// verifying until they expire.
+func Generate(dir string) (*Key, error) {
+	if _, err := readManifest(dir); !errors.Is(err, ErrKeyringNotExists) {
+		if err == nil {
+			return nil, ErrKeyringExists
+		}
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	key, err := newKey(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &manifest{
+		Active: key.ID,
+		Keys:   []manifestKey{{ID: key.ID, CreatedAt: key.CreatedAt}},
+	}
+
+	legacy, err := importLegacyKey(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if legacy != nil {
+		m.Keys = append(m.Keys, *legacy)
+	}
+
+	if err := writeManifest(dir, m); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Rotate makes a new key the active one. The previous active key loses its
+// private half and is kept for verification along with the keep most recent
+// retired keys; older ones are removed.
+func Rotate(dir string, keep int) (*Key, error) {
+	m, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newKey(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	keys := []manifestKey{{ID: key.ID, CreatedAt: key.CreatedAt}}
+	var dropped []string
+	for _, previous := range m.Keys {
+		if previous.RetiredAt == nil {
+			previous.RetiredAt = &now
+		}
+
+		if len(keys) > keep {
+			dropped = append(dropped, previous.ID)
+			continue
+		}
+		keys = append(keys, previous)
+	}
+
+	retired := m.Active
+	m.Active = key.ID
+	m.Keys = keys
+
+	if err := writeManifest(dir, m); err != nil {
+		return nil, err
+	}
+
+	if err := removeFile(privateKeyPath(dir, retired)); err != nil {
+		return nil, err
+	}
+
+	for _, kid := range dropped {
+		if err := removeFile(publicKeyPath(dir, kid)); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// Read loads every key of the keyring in dir, active key first.
+func Read(dir string) ([]*Key, error) {
+	m, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(m.Keys))
+	for _, entry := range m.Keys {
+		publicKey, err := readPublicKey(publicKeyPath(dir, entry.ID))
+		if err != nil {
+			return nil, err
+		}
+
+		key := &Key{
+			ID:        entry.ID,
+			PublicKey: publicKey,
+			CreatedAt: entry.CreatedAt,
+			RetiredAt: entry.RetiredAt,
+		}
+
+		if entry.ID == m.Active {
+			key.PrivateKey, err = readPrivateKey(privateKeyPath(dir, entry.ID))
+			if err != nil {
+				return nil, err
+			}
+			keys = append([]*Key{key}, keys...)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// importLegacyKey copies the public half of the legacy signing key into dir
+// as a retired key. It returns nil when there is no legacy key.
+func importLegacyKey(dir string) (*manifestKey, error) {
+	privateKey, err := readPrivateKey(legacyPrivateKeyFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kid := Thumbprint(&privateKey.PublicKey)
+	if err := writePublicKey(publicKeyPath(dir, kid), &privateKey.PublicKey); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &manifestKey{ID: kid, CreatedAt: now, RetiredAt: &now}, nil
+}
+
+func newKey(dir string) (*Key, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		ID:         Thumbprint(&privateKey.PublicKey),
+		PrivateKey: privateKey,
+		PublicKey:  &privateKey.PublicKey,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := writePublicKey(publicKeyPath(dir, key.ID), key.PublicKey); err != nil {
+		return nil, err
+	}
+
+	if err := writePrivateKey(privateKeyPath(dir, key.ID), key.PrivateKey); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -11,8 +11,15 @@ import (
 	"github.com/GSVillas/pic-pay-desafio/client"
 	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/config/database"
+	"github.com/GSVillas/pic-pay-desafio/encryption"
+	"github.com/GSVillas/pic-pay-desafio/exchange"
+	"github.com/GSVillas/pic-pay-desafio/job"
+	"github.com/GSVillas/pic-pay-desafio/keyring"
+	"github.com/GSVillas/pic-pay-desafio/middleware"
 	"github.com/GSVillas/pic-pay-desafio/repository"
+	"github.com/GSVillas/pic-pay-desafio/secure"
 	"github.com/GSVillas/pic-pay-desafio/service"
+	"github.com/GSVillas/pic-pay-desafio/storage"
 	"github.com/go-redis/redis/v8"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/do"
@@ -26,6 +33,12 @@ func main() {
 	e := echo.New()
 	i := do.New()
 
+	ipExtractor, err := middleware.IPExtractor(config.Env.TrustedProxies)
+	if err != nil {
+		log.Fatal("Fail to parse trusted proxies: ", err)
+	}
+	e.IPExtractor = ipExtractor
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -39,7 +52,16 @@ func main() {
 		log.Fatal("Fail to connect to redis: ", err)
 	}
 
-	httpClient := &http.Client{Timeout: 10 * time.Second}
+	piiCipher, err := encryption.NewCipher(i)
+	if err != nil {
+		log.Fatal("Fail to load pii keyring: ", err)
+	}
+	encryption.UseCipher(piiCipher)
+
+	httpClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: client.NewRequestIDTransport(http.DefaultTransport),
+	}
 
 	do.Provide(i, func(i *do.Injector) (*gorm.DB, error) {
 		return db, nil
@@ -49,26 +71,85 @@ func main() {
 		return redisClient, nil
 	})
 
+	do.Provide(i, func(i *do.Injector) (encryption.Cipher, error) {
+		return piiCipher, nil
+	})
+
 	do.Provide(i, func(i *do.Injector) (*http.Client, error) {
 		return httpClient, nil
 	})
 
 	do.Provide(i, client.NewAuthorizationService)
+	do.Provide(i, client.NewNotificationService)
+	do.Provide(i, client.NewEmailService)
+
+	do.Provide(i, storage.NewFileStorage)
+	do.Provide(i, exchange.NewRateProvider)
+	do.Provide(i, keyring.NewKeyring)
+	do.Provide(i, secure.NewPasswordHasher)
 
 	do.Provide(i, handler.NewTransferHandler)
 	do.Provide(i, handler.NewUserHandler)
+	do.Provide(i, handler.NewAccountHandler)
+	do.Provide(i, handler.NewSessionHandler)
+	do.Provide(i, handler.NewKeyHandler)
+	do.Provide(i, handler.NewTwoFactorHandler)
+	do.Provide(i, handler.NewTransactionPINHandler)
 	do.Provide(i, handler.NewWalletHandler)
+	do.Provide(i, handler.NewHoldHandler)
+	do.Provide(i, handler.NewDisputeHandler)
+	do.Provide(i, handler.NewKYCHandler)
+	do.Provide(i, handler.NewContactHandler)
+	do.Provide(i, handler.NewCampaignHandler)
+	do.Provide(i, handler.NewQuoteHandler)
+	do.Provide(i, handler.NewAPIKeyHandler)
+	do.Provide(i, handler.NewOAuthHandler)
 
 	do.Provide(i, service.NewTransferService)
 	do.Provide(i, service.NewUserService)
+	do.Provide(i, service.NewAccountService)
 	do.Provide(i, service.NewSessionService)
+	do.Provide(i, service.NewTwoFactorService)
+	do.Provide(i, service.NewTransactionPINService)
 	do.Provide(i, service.NewWalletService)
+	do.Provide(i, service.NewHoldService)
+	do.Provide(i, service.NewDisputeService)
+	do.Provide(i, service.NewKYCService)
+	do.Provide(i, service.NewContactService)
+	do.Provide(i, service.NewCampaignService)
+	do.Provide(i, service.NewQuoteService)
+	do.Provide(i, service.NewAPIKeyService)
+	do.Provide(i, service.NewOAuthService)
 
 	do.Provide(i, repository.NewTransferRepository)
 	do.Provide(i, repository.NewUserRepository)
+	do.Provide(i, repository.NewAccountRepository)
 	do.Provide(i, repository.NewSessionRepository)
+	do.Provide(i, repository.NewRefreshTokenRepository)
+	do.Provide(i, repository.NewTwoFactorRepository)
+	do.Provide(i, repository.NewTransactionPINRepository)
+	do.Provide(i, repository.NewSignInAttemptRepository)
 	do.Provide(i, repository.NewWalletRepository)
+	do.Provide(i, repository.NewHoldRepository)
+	do.Provide(i, repository.NewDisputeRepository)
+	do.Provide(i, repository.NewKYCRepository)
+	do.Provide(i, repository.NewContactRepository)
+	do.Provide(i, repository.NewCampaignRepository)
+	do.Provide(i, repository.NewQuoteRepository)
+	do.Provide(i, repository.NewAPIKeyRepository)
+	do.Provide(i, repository.NewOAuthClientRepository)
+	do.Provide(i, repository.NewOAuthTokenRepository)
+
+	do.Provide(i, job.NewHoldSweeper)
+	do.Provide(i, job.NewEscrowReleaser)
 
 	handler.SetupRoutes(e, i)
+
+	holdSweeper := do.MustInvoke[*job.HoldSweeper](i)
+	go holdSweeper.Start(context.Background())
+
+	escrowReleaser := do.MustInvoke[*job.EscrowReleaser](i)
+	go escrowReleaser.Start(context.Background())
+
 	e.Logger.Fatal(e.Start(fmt.Sprintf(":%s", config.Env.APIPort)))
 }
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+// CheckAPIKeyOrLoggedIn accepts either a merchant API key holding scope or a
+// session token, both sent as "Authorization: Bearer <token>". API keys put
+// an equivalent session in the context, so handlers behind it work the same
+// for both. Session tokens are checked by CheckLoggedIn.
+func CheckAPIKeyOrLoggedIn(i *do.Injector, scope domain.APIKeyScope) echo.MiddlewareFunc {
+	checkLoggedIn := CheckLoggedIn(i)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		sessionNext := checkLoggedIn(next)
+
+		return func(ctx echo.Context) error {
+			content := strings.Split(ctx.Request().Header.Get("Authorization"), " ")
+			if len(content) != 2 || !strings.HasPrefix(content[1], domain.APIKeyTokenPrefix) {
+				return sessionNext(ctx)
+			}
+
+			apiKeyService, err := do.Invoke[domain.APIKeyService](i)
+			if err != nil {
+				return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+			}
+
+			session, err := apiKeyService.Authenticate(ctx.Request().Context(), content[1], ctx.RealIP())
+			if err != nil {
+				if errors.Is(err, domain.ErrAPIKeyInvalid) {
+					apiError := domain.NewAPIError(http.StatusUnauthorized, "Access Denied", "Invalid or revoked api key.")
+					return ctx.JSON(http.StatusUnauthorized, apiError)
+				}
+
+				if errors.Is(err, domain.ErrAPIKeyIPNotAllowed) {
+					apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "This api key is not allowed from your ip address.")
+					return ctx.JSON(http.StatusForbidden, apiError)
+				}
+
+				return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+			}
+
+			if !session.APIKey.HasScope(scope) {
+				apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "This api key does not have the "+string(scope)+" scope.")
+				return ctx.JSON(http.StatusForbidden, apiError)
+			}
+
+			withSession(ctx, session)
+
+			return next(ctx)
+		}
+	}
+}
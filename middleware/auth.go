@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
 	"strings"
 
@@ -42,8 +41,7 @@ func CheckLoggedIn(i *do.Injector) echo.MiddlewareFunc {
 				return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
 			}
 
-			newCtx := context.WithValue(ctx.Request().Context(), domain.SessionKey, session)
-			ctx.SetRequest(ctx.Request().WithContext(newCtx))
+			withSession(ctx, session)
 
 			return next(ctx)
 		}
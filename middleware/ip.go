@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor tells ctx.RealIP where to find the client address. Forwarded
+// headers are only trusted when the request comes from one of trustedProxies,
+// a comma separated list of CIDRs. Without any, the address of the
+// connection is used and those headers are ignored, since clients can send
+// whatever they like in them.
+func IPExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	trusted := 0
+	for _, proxy := range strings.Split(trustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy == "" {
+			continue
+		}
+
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, echo.TrustIPRange(ipRange))
+		trusted++
+	}
+
+	if trusted == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
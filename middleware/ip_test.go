@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveRealIP runs a request from remoteAddr carrying spoofed forwarding
+// headers and returns the address the handler saw.
+func serveRealIP(t *testing.T, trustedProxies, remoteAddr, forwardedFor string) string {
+	ipExtractor, err := IPExtractor(trustedProxies)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.IPExtractor = ipExtractor
+
+	var realIP string
+	e.GET("/", func(ctx echo.Context) error {
+		realIP = ctx.RealIP()
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+	req.Header.Set(echo.HeaderXRealIP, "198.51.100.7")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	return realIP
+}
+
+func TestIPExtractor_WhenNoProxyIsTrusted_ShouldIgnoreForwardedHeaders(t *testing.T) {
+	realIP := serveRealIP(t, "", "192.0.2.10:5123", "203.0.113.5")
+
+	assert.Equal(t, "192.0.2.10", realIP)
+}
+
+func TestIPExtractor_WhenRequestComesFromTrustedProxy_ShouldUseTheForwardedAddress(t *testing.T) {
+	realIP := serveRealIP(t, "10.0.0.0/8", "10.0.0.2:5123", "192.0.2.10")
+
+	assert.Equal(t, "192.0.2.10", realIP)
+}
+
+func TestIPExtractor_WhenClientPrependsAnAddress_ShouldUseTheOneAddedByTheProxy(t *testing.T) {
+	realIP := serveRealIP(t, "10.0.0.0/8", "10.0.0.2:5123", "203.0.113.5, 192.0.2.10")
+
+	assert.Equal(t, "192.0.2.10", realIP)
+}
+
+func TestIPExtractor_WhenRequestComesFromUntrustedAddress_ShouldIgnoreForwardedHeaders(t *testing.T) {
+	realIP := serveRealIP(t, "10.0.0.0/8", "192.168.0.3:5123", "203.0.113.5")
+
+	assert.Equal(t, "192.168.0.3", realIP)
+}
+
+func TestIPExtractor_WhenProxyIsNotACIDR_ShouldReturnError(t *testing.T) {
+	_, err := IPExtractor("10.0.0.1")
+
+	assert.Error(t, err)
+}
+
+func TestCheckAPIKeyOrLoggedIn_WhenForwardedHeaderIsSpoofed_ShouldCheckTheConnectionAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	apiKeyServiceMock := mocks.NewMockAPIKeyService(ctrl)
+
+	i := do.New()
+	do.ProvideValue[domain.APIKeyService](i, apiKeyServiceMock)
+
+	ipExtractor, err := IPExtractor("")
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.IPExtractor = ipExtractor
+	e.GET("/", func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	}, CheckAPIKeyOrLoggedIn(i, domain.APIKeyScopeChargesWrite))
+
+	key := domain.APIKeyTokenPrefix + strings.Repeat("a", 32)
+	apiKeyServiceMock.EXPECT().Authenticate(gomock.Any(), key, "192.0.2.10").Return(nil, domain.ErrAPIKeyIPNotAllowed)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.10:5123"
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+key)
+	req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.5")
+	req.Header.Set(echo.HeaderXRealIP, "203.0.113.5")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
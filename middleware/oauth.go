@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+// CheckOAuthOr accepts OAuth access tokens holding scope and hands every
+// other bearer token to fallback, so a route can serve third-party apps
+// alongside the principals it already accepts.
+func CheckOAuthOr(i *do.Injector, scope domain.OAuthScope, fallback echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		fallbackNext := fallback(next)
+
+		return func(ctx echo.Context) error {
+			content := strings.Split(ctx.Request().Header.Get("Authorization"), " ")
+			if len(content) != 2 || !isOAuthAccessToken(content[1]) {
+				return fallbackNext(ctx)
+			}
+
+			oauthService, err := do.Invoke[domain.OAuthService](i)
+			if err != nil {
+				return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+			}
+
+			session, err := oauthService.Authenticate(ctx.Request().Context(), content[1])
+			if err != nil {
+				if errors.Is(err, domain.ErrTokenInvalid) {
+					apiError := domain.NewAPIError(http.StatusUnauthorized, "Access Denied", "Invalid, expired or revoked access token.")
+					return ctx.JSON(http.StatusUnauthorized, apiError)
+				}
+
+				return ctx.JSON(http.StatusInternalServerError, domain.InternalServerAPIError)
+			}
+
+			if !session.OAuth.HasScope(scope) {
+				apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "This access token does not have the "+string(scope)+" scope.")
+				return ctx.JSON(http.StatusForbidden, apiError)
+			}
+
+			withSession(ctx, session)
+
+			return next(ctx)
+		}
+	}
+}
+
+// isOAuthAccessToken only peeks at the unverified typ header; the token is
+// verified by the OAuth service.
+func isOAuthAccessToken(token string) bool {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+
+	typ, _ := parsed.Header["typ"].(string)
+	return typ == domain.OAuthAccessTokenType
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// RequirePermission only lets through principals whose role grants every
+// one of permissions. It must run after the middleware that authenticates
+// the request.
+func RequirePermission(permissions ...domain.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			session, ok := ctx.Request().Context().Value(domain.SessionKey).(*domain.Session)
+			if !ok || session == nil {
+				return ctx.JSON(http.StatusForbidden, domain.SessionNotFoundAPIError)
+			}
+
+			for _, permission := range permissions {
+				if !session.Can(permission) {
+					apiError := domain.NewAPIError(http.StatusForbidden, "Forbidden", "You do not have permission to access this resource.")
+					return ctx.JSON(http.StatusForbidden, apiError)
+				}
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// requestIDPattern bounds the request IDs accepted from clients, so one
+// cannot inject arbitrary content into the logs or outbound headers.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID tags the request with the X-Request-ID sent by the client, or a
+// new one, and echoes it in the response. The logger stored in the request
+// context carries it, as do outbound calls made while handling the request.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			requestID := ctx.Request().Header.Get(echo.HeaderXRequestID)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID = uuid.NewString()
+			}
+
+			ctx.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			logger := slog.Default().With(slog.String("requestID", requestID))
+			newCtx := context.WithValue(ctx.Request().Context(), domain.RequestIDKey, requestID)
+			newCtx = context.WithValue(newCtx, domain.LoggerKey, logger)
+			ctx.SetRequest(ctx.Request().WithContext(newCtx))
+
+			return next(ctx)
+		}
+	}
+}
+
+// withSession stores the session of the signed in user in the request
+// context and adds the user ID to its logger.
+func withSession(ctx echo.Context, session *domain.Session) {
+	newCtx := context.WithValue(ctx.Request().Context(), domain.SessionKey, session)
+	logger := domain.LoggerFromContext(newCtx).With(slog.String("userID", session.UserID.String()))
+	newCtx = context.WithValue(newCtx, domain.LoggerKey, logger)
+	ctx.SetRequest(ctx.Request().WithContext(newCtx))
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveRequestID runs a request with the given X-Request-ID through the
+// middleware and returns the response and the ID the handler saw in its
+// context.
+func serveRequestID(t *testing.T, requestID string) (*httptest.ResponseRecorder, string) {
+	e := echo.New()
+	e.Use(RequestID())
+
+	var contextID string
+	e.GET("/", func(ctx echo.Context) error {
+		contextID = domain.RequestIDFromContext(ctx.Request().Context())
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if requestID != "" {
+		req.Header.Set(echo.HeaderXRequestID, requestID)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	return rec, contextID
+}
+
+func TestRequestID_WhenHeaderIsValid_ShouldKeepIt(t *testing.T) {
+	rec, contextID := serveRequestID(t, "checkout-42.retry:1")
+
+	assert.Equal(t, "checkout-42.retry:1", contextID)
+	assert.Equal(t, "checkout-42.retry:1", rec.Header().Get(echo.HeaderXRequestID))
+}
+
+func TestRequestID_WhenHeaderIsMissing_ShouldGenerateOne(t *testing.T) {
+	rec, contextID := serveRequestID(t, "")
+
+	_, err := uuid.Parse(contextID)
+	assert.NoError(t, err)
+	assert.Equal(t, contextID, rec.Header().Get(echo.HeaderXRequestID))
+}
+
+func TestRequestID_WhenHeaderIsInvalid_ShouldReplaceIt(t *testing.T) {
+	for _, requestID := range []string{"id with spaces", "id\"}{injected", string(bytes.Repeat([]byte("a"), 129))} {
+		rec, contextID := serveRequestID(t, requestID)
+
+		_, err := uuid.Parse(contextID)
+		assert.NoError(t, err, requestID)
+		assert.NotEqual(t, requestID, contextID)
+		assert.Equal(t, contextID, rec.Header().Get(echo.HeaderXRequestID))
+	}
+}
+
+func TestRequestID_ShouldTagTheContextLoggerAndAddTheUserOnceSignedIn(t *testing.T) {
+	var output bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&output, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	userID := uuid.New()
+
+	e := echo.New()
+	e.Use(RequestID())
+	e.GET("/", func(ctx echo.Context) error {
+		withSession(ctx, &domain.Session{UserID: userID})
+		domain.LoggerFromContext(ctx.Request().Context()).Info("Handled")
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRequestID, "abc-123")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, output.String(), `"requestID":"abc-123"`)
+	assert.Contains(t, output.String(), `"userID":"`+userID.String()+`"`)
+}
@@ -0,0 +1,260 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: campaign.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockCampaignHandler is a mock of CampaignHandler interface.
+type MockCampaignHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockCampaignHandlerMockRecorder
+}
+
+// MockCampaignHandlerMockRecorder is the mock recorder for MockCampaignHandler.
+type MockCampaignHandlerMockRecorder struct {
+	mock *MockCampaignHandler
+}
+
+// NewMockCampaignHandler creates a new mock instance.
+func NewMockCampaignHandler(ctrl *gomock.Controller) *MockCampaignHandler {
+	mock := &MockCampaignHandler{ctrl: ctrl}
+	mock.recorder = &MockCampaignHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCampaignHandler) EXPECT() *MockCampaignHandlerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCampaignHandler) Create(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCampaignHandlerMockRecorder) Create(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCampaignHandler)(nil).Create), ctx)
+}
+
+// GetAll mocks base method.
+func (m *MockCampaignHandler) GetAll(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCampaignHandlerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCampaignHandler)(nil).GetAll), ctx)
+}
+
+// GetRewards mocks base method.
+func (m *MockCampaignHandler) GetRewards(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewards", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetRewards indicates an expected call of GetRewards.
+func (mr *MockCampaignHandlerMockRecorder) GetRewards(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewards", reflect.TypeOf((*MockCampaignHandler)(nil).GetRewards), ctx)
+}
+
+// MockCampaignService is a mock of CampaignService interface.
+type MockCampaignService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCampaignServiceMockRecorder
+}
+
+// MockCampaignServiceMockRecorder is the mock recorder for MockCampaignService.
+type MockCampaignServiceMockRecorder struct {
+	mock *MockCampaignService
+}
+
+// NewMockCampaignService creates a new mock instance.
+func NewMockCampaignService(ctrl *gomock.Controller) *MockCampaignService {
+	mock := &MockCampaignService{ctrl: ctrl}
+	mock.recorder = &MockCampaignServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCampaignService) EXPECT() *MockCampaignServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCampaignService) Create(ctx context.Context, payload *domain.CampaignPayload) (*domain.CampaignResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, payload)
+	ret0, _ := ret[0].(*domain.CampaignResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCampaignServiceMockRecorder) Create(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCampaignService)(nil).Create), ctx, payload)
+}
+
+// EvaluateTransfer mocks base method.
+func (m *MockCampaignService) EvaluateTransfer(ctx context.Context, transfer *domain.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateTransfer", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EvaluateTransfer indicates an expected call of EvaluateTransfer.
+func (mr *MockCampaignServiceMockRecorder) EvaluateTransfer(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateTransfer", reflect.TypeOf((*MockCampaignService)(nil).EvaluateTransfer), ctx, transfer)
+}
+
+// GetAll mocks base method.
+func (m *MockCampaignService) GetAll(ctx context.Context) ([]*domain.CampaignResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*domain.CampaignResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCampaignServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCampaignService)(nil).GetAll), ctx)
+}
+
+// GetRewards mocks base method.
+func (m *MockCampaignService) GetRewards(ctx context.Context) ([]*domain.RewardResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewards", ctx)
+	ret0, _ := ret[0].([]*domain.RewardResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewards indicates an expected call of GetRewards.
+func (mr *MockCampaignServiceMockRecorder) GetRewards(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewards", reflect.TypeOf((*MockCampaignService)(nil).GetRewards), ctx)
+}
+
+// MockCampaignRepository is a mock of CampaignRepository interface.
+type MockCampaignRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCampaignRepositoryMockRecorder
+}
+
+// MockCampaignRepositoryMockRecorder is the mock recorder for MockCampaignRepository.
+type MockCampaignRepositoryMockRecorder struct {
+	mock *MockCampaignRepository
+}
+
+// NewMockCampaignRepository creates a new mock instance.
+func NewMockCampaignRepository(ctrl *gomock.Controller) *MockCampaignRepository {
+	mock := &MockCampaignRepository{ctrl: ctrl}
+	mock.recorder = &MockCampaignRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCampaignRepository) EXPECT() *MockCampaignRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCampaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCampaignRepositoryMockRecorder) Create(ctx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCampaignRepository)(nil).Create), ctx, campaign)
+}
+
+// GetActiveByMerchantID mocks base method.
+func (m *MockCampaignRepository) GetActiveByMerchantID(ctx context.Context, merchantID uuid.UUID, currency string, now time.Time) ([]*domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByMerchantID", ctx, merchantID, currency, now)
+	ret0, _ := ret[0].([]*domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByMerchantID indicates an expected call of GetActiveByMerchantID.
+func (mr *MockCampaignRepositoryMockRecorder) GetActiveByMerchantID(ctx, merchantID, currency, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByMerchantID", reflect.TypeOf((*MockCampaignRepository)(nil).GetActiveByMerchantID), ctx, merchantID, currency, now)
+}
+
+// GetAll mocks base method.
+func (m *MockCampaignRepository) GetAll(ctx context.Context) ([]*domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCampaignRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCampaignRepository)(nil).GetAll), ctx)
+}
+
+// GetRewardsByUserID mocks base method.
+func (m *MockCampaignRepository) GetRewardsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Reward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Reward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardsByUserID indicates an expected call of GetRewardsByUserID.
+func (mr *MockCampaignRepositoryMockRecorder) GetRewardsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardsByUserID", reflect.TypeOf((*MockCampaignRepository)(nil).GetRewardsByUserID), ctx, userID)
+}
+
+// GrantReward mocks base method.
+func (m *MockCampaignRepository) GrantReward(ctx context.Context, campaignID uuid.UUID, transfer *domain.Transfer) (*domain.Reward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantReward", ctx, campaignID, transfer)
+	ret0, _ := ret[0].(*domain.Reward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantReward indicates an expected call of GrantReward.
+func (mr *MockCampaignRepositoryMockRecorder) GrantReward(ctx, campaignID, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantReward", reflect.TypeOf((*MockCampaignRepository)(nil).GrantReward), ctx, campaignID, transfer)
+}
@@ -0,0 +1,345 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contact.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockContactHandler is a mock of ContactHandler interface.
+type MockContactHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactHandlerMockRecorder
+}
+
+// MockContactHandlerMockRecorder is the mock recorder for MockContactHandler.
+type MockContactHandlerMockRecorder struct {
+	mock *MockContactHandler
+}
+
+// NewMockContactHandler creates a new mock instance.
+func NewMockContactHandler(ctrl *gomock.Controller) *MockContactHandler {
+	mock := &MockContactHandler{ctrl: ctrl}
+	mock.recorder = &MockContactHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactHandler) EXPECT() *MockContactHandlerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockContactHandler) Create(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockContactHandlerMockRecorder) Create(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockContactHandler)(nil).Create), ctx)
+}
+
+// Delete mocks base method.
+func (m *MockContactHandler) Delete(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockContactHandlerMockRecorder) Delete(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContactHandler)(nil).Delete), ctx)
+}
+
+// GetAll mocks base method.
+func (m *MockContactHandler) GetAll(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockContactHandlerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockContactHandler)(nil).GetAll), ctx)
+}
+
+// GetRecentPayees mocks base method.
+func (m *MockContactHandler) GetRecentPayees(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentPayees", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetRecentPayees indicates an expected call of GetRecentPayees.
+func (mr *MockContactHandlerMockRecorder) GetRecentPayees(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentPayees", reflect.TypeOf((*MockContactHandler)(nil).GetRecentPayees), ctx)
+}
+
+// Update mocks base method.
+func (m *MockContactHandler) Update(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockContactHandlerMockRecorder) Update(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockContactHandler)(nil).Update), ctx)
+}
+
+// MockContactService is a mock of ContactService interface.
+type MockContactService struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactServiceMockRecorder
+}
+
+// MockContactServiceMockRecorder is the mock recorder for MockContactService.
+type MockContactServiceMockRecorder struct {
+	mock *MockContactService
+}
+
+// NewMockContactService creates a new mock instance.
+func NewMockContactService(ctrl *gomock.Controller) *MockContactService {
+	mock := &MockContactService{ctrl: ctrl}
+	mock.recorder = &MockContactServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactService) EXPECT() *MockContactServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockContactService) Create(ctx context.Context, payload *domain.ContactPayload) (*domain.ContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, payload)
+	ret0, _ := ret[0].(*domain.ContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockContactServiceMockRecorder) Create(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockContactService)(nil).Create), ctx, payload)
+}
+
+// Delete mocks base method.
+func (m *MockContactService) Delete(ctx context.Context, contactID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, contactID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockContactServiceMockRecorder) Delete(ctx, contactID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContactService)(nil).Delete), ctx, contactID)
+}
+
+// GetAll mocks base method.
+func (m *MockContactService) GetAll(ctx context.Context) ([]*domain.ContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*domain.ContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockContactServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockContactService)(nil).GetAll), ctx)
+}
+
+// GetRecentPayees mocks base method.
+func (m *MockContactService) GetRecentPayees(ctx context.Context, query *domain.RecentPayeesQuery) ([]*domain.ContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentPayees", ctx, query)
+	ret0, _ := ret[0].([]*domain.ContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecentPayees indicates an expected call of GetRecentPayees.
+func (mr *MockContactServiceMockRecorder) GetRecentPayees(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentPayees", reflect.TypeOf((*MockContactService)(nil).GetRecentPayees), ctx, query)
+}
+
+// RecordTransfer mocks base method.
+func (m *MockContactService) RecordTransfer(ctx context.Context, transfer *domain.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTransfer", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordTransfer indicates an expected call of RecordTransfer.
+func (mr *MockContactServiceMockRecorder) RecordTransfer(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTransfer", reflect.TypeOf((*MockContactService)(nil).RecordTransfer), ctx, transfer)
+}
+
+// Update mocks base method.
+func (m *MockContactService) Update(ctx context.Context, contactID uuid.UUID, payload *domain.UpdateContactPayload) (*domain.ContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, contactID, payload)
+	ret0, _ := ret[0].(*domain.ContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockContactServiceMockRecorder) Update(ctx, contactID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockContactService)(nil).Update), ctx, contactID, payload)
+}
+
+// MockContactRepository is a mock of ContactRepository interface.
+type MockContactRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactRepositoryMockRecorder
+}
+
+// MockContactRepositoryMockRecorder is the mock recorder for MockContactRepository.
+type MockContactRepositoryMockRecorder struct {
+	mock *MockContactRepository
+}
+
+// NewMockContactRepository creates a new mock instance.
+func NewMockContactRepository(ctrl *gomock.Controller) *MockContactRepository {
+	mock := &MockContactRepository{ctrl: ctrl}
+	mock.recorder = &MockContactRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactRepository) EXPECT() *MockContactRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockContactRepository) Create(ctx context.Context, contact *domain.Contact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockContactRepositoryMockRecorder) Create(ctx, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockContactRepository)(nil).Create), ctx, contact)
+}
+
+// Delete mocks base method.
+func (m *MockContactRepository) Delete(ctx context.Context, userID, contactID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, contactID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockContactRepositoryMockRecorder) Delete(ctx, userID, contactID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContactRepository)(nil).Delete), ctx, userID, contactID)
+}
+
+// Get mocks base method.
+func (m *MockContactRepository) Get(ctx context.Context, userID, contactID uuid.UUID) (*domain.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, contactID)
+	ret0, _ := ret[0].(*domain.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockContactRepositoryMockRecorder) Get(ctx, userID, contactID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockContactRepository)(nil).Get), ctx, userID, contactID)
+}
+
+// GetAllByUserID mocks base method.
+func (m *MockContactRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID.
+func (mr *MockContactRepositoryMockRecorder) GetAllByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockContactRepository)(nil).GetAllByUserID), ctx, userID)
+}
+
+// GetPaidByUserID mocks base method.
+func (m *MockContactRepository) GetPaidByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaidByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaidByUserID indicates an expected call of GetPaidByUserID.
+func (mr *MockContactRepositoryMockRecorder) GetPaidByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaidByUserID", reflect.TypeOf((*MockContactRepository)(nil).GetPaidByUserID), ctx, userID)
+}
+
+// RecordTransfer mocks base method.
+func (m *MockContactRepository) RecordTransfer(ctx context.Context, userID, contactID uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTransfer", ctx, userID, contactID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordTransfer indicates an expected call of RecordTransfer.
+func (mr *MockContactRepositoryMockRecorder) RecordTransfer(ctx, userID, contactID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTransfer", reflect.TypeOf((*MockContactRepository)(nil).RecordTransfer), ctx, userID, contactID, at)
+}
+
+// Update mocks base method.
+func (m *MockContactRepository) Update(ctx context.Context, contact *domain.Contact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockContactRepositoryMockRecorder) Update(ctx, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockContactRepository)(nil).Update), ctx, contact)
+}
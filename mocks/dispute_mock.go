@@ -0,0 +1,378 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dispute.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockDisputeHandler is a mock of DisputeHandler interface.
+type MockDisputeHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisputeHandlerMockRecorder
+}
+
+// MockDisputeHandlerMockRecorder is the mock recorder for MockDisputeHandler.
+type MockDisputeHandlerMockRecorder struct {
+	mock *MockDisputeHandler
+}
+
+// NewMockDisputeHandler creates a new mock instance.
+func NewMockDisputeHandler(ctrl *gomock.Controller) *MockDisputeHandler {
+	mock := &MockDisputeHandler{ctrl: ctrl}
+	mock.recorder = &MockDisputeHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDisputeHandler) EXPECT() *MockDisputeHandlerMockRecorder {
+	return m.recorder
+}
+
+// DownloadEvidence mocks base method.
+func (m *MockDisputeHandler) DownloadEvidence(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadEvidence", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadEvidence indicates an expected call of DownloadEvidence.
+func (mr *MockDisputeHandlerMockRecorder) DownloadEvidence(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadEvidence", reflect.TypeOf((*MockDisputeHandler)(nil).DownloadEvidence), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockDisputeHandler) GetByID(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDisputeHandlerMockRecorder) GetByID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDisputeHandler)(nil).GetByID), ctx)
+}
+
+// Open mocks base method.
+func (m *MockDisputeHandler) Open(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockDisputeHandlerMockRecorder) Open(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockDisputeHandler)(nil).Open), ctx)
+}
+
+// Resolve mocks base method.
+func (m *MockDisputeHandler) Resolve(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockDisputeHandlerMockRecorder) Resolve(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockDisputeHandler)(nil).Resolve), ctx)
+}
+
+// Respond mocks base method.
+func (m *MockDisputeHandler) Respond(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Respond", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Respond indicates an expected call of Respond.
+func (mr *MockDisputeHandlerMockRecorder) Respond(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Respond", reflect.TypeOf((*MockDisputeHandler)(nil).Respond), ctx)
+}
+
+// UploadEvidence mocks base method.
+func (m *MockDisputeHandler) UploadEvidence(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadEvidence", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadEvidence indicates an expected call of UploadEvidence.
+func (mr *MockDisputeHandlerMockRecorder) UploadEvidence(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadEvidence", reflect.TypeOf((*MockDisputeHandler)(nil).UploadEvidence), ctx)
+}
+
+// MockDisputeService is a mock of DisputeService interface.
+type MockDisputeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisputeServiceMockRecorder
+}
+
+// MockDisputeServiceMockRecorder is the mock recorder for MockDisputeService.
+type MockDisputeServiceMockRecorder struct {
+	mock *MockDisputeService
+}
+
+// NewMockDisputeService creates a new mock instance.
+func NewMockDisputeService(ctrl *gomock.Controller) *MockDisputeService {
+	mock := &MockDisputeService{ctrl: ctrl}
+	mock.recorder = &MockDisputeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDisputeService) EXPECT() *MockDisputeServiceMockRecorder {
+	return m.recorder
+}
+
+// DownloadEvidence mocks base method.
+func (m *MockDisputeService) DownloadEvidence(ctx context.Context, disputeID, evidenceID uuid.UUID) (*domain.DisputeEvidence, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadEvidence", ctx, disputeID, evidenceID)
+	ret0, _ := ret[0].(*domain.DisputeEvidence)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DownloadEvidence indicates an expected call of DownloadEvidence.
+func (mr *MockDisputeServiceMockRecorder) DownloadEvidence(ctx, disputeID, evidenceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadEvidence", reflect.TypeOf((*MockDisputeService)(nil).DownloadEvidence), ctx, disputeID, evidenceID)
+}
+
+// GetByID mocks base method.
+func (m *MockDisputeService) GetByID(ctx context.Context, disputeID uuid.UUID) (*domain.DisputeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, disputeID)
+	ret0, _ := ret[0].(*domain.DisputeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDisputeServiceMockRecorder) GetByID(ctx, disputeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDisputeService)(nil).GetByID), ctx, disputeID)
+}
+
+// Open mocks base method.
+func (m *MockDisputeService) Open(ctx context.Context, payload *domain.OpenDisputePayload) (*domain.DisputeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, payload)
+	ret0, _ := ret[0].(*domain.DisputeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockDisputeServiceMockRecorder) Open(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockDisputeService)(nil).Open), ctx, payload)
+}
+
+// Resolve mocks base method.
+func (m *MockDisputeService) Resolve(ctx context.Context, disputeID uuid.UUID, payload *domain.ResolveDisputePayload) (*domain.DisputeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, disputeID, payload)
+	ret0, _ := ret[0].(*domain.DisputeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockDisputeServiceMockRecorder) Resolve(ctx, disputeID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockDisputeService)(nil).Resolve), ctx, disputeID, payload)
+}
+
+// Respond mocks base method.
+func (m *MockDisputeService) Respond(ctx context.Context, disputeID uuid.UUID, payload *domain.RespondDisputePayload) (*domain.DisputeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Respond", ctx, disputeID, payload)
+	ret0, _ := ret[0].(*domain.DisputeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Respond indicates an expected call of Respond.
+func (mr *MockDisputeServiceMockRecorder) Respond(ctx, disputeID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Respond", reflect.TypeOf((*MockDisputeService)(nil).Respond), ctx, disputeID, payload)
+}
+
+// UploadEvidence mocks base method.
+func (m *MockDisputeService) UploadEvidence(ctx context.Context, disputeID uuid.UUID, upload *domain.EvidenceUpload) (*domain.DisputeEvidenceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadEvidence", ctx, disputeID, upload)
+	ret0, _ := ret[0].(*domain.DisputeEvidenceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadEvidence indicates an expected call of UploadEvidence.
+func (mr *MockDisputeServiceMockRecorder) UploadEvidence(ctx, disputeID, upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadEvidence", reflect.TypeOf((*MockDisputeService)(nil).UploadEvidence), ctx, disputeID, upload)
+}
+
+// MockDisputeRepository is a mock of DisputeRepository interface.
+type MockDisputeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisputeRepositoryMockRecorder
+}
+
+// MockDisputeRepositoryMockRecorder is the mock recorder for MockDisputeRepository.
+type MockDisputeRepositoryMockRecorder struct {
+	mock *MockDisputeRepository
+}
+
+// NewMockDisputeRepository creates a new mock instance.
+func NewMockDisputeRepository(ctrl *gomock.Controller) *MockDisputeRepository {
+	mock := &MockDisputeRepository{ctrl: ctrl}
+	mock.recorder = &MockDisputeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDisputeRepository) EXPECT() *MockDisputeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddEvidence mocks base method.
+func (m *MockDisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvidence", ctx, evidence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvidence indicates an expected call of AddEvidence.
+func (mr *MockDisputeRepositoryMockRecorder) AddEvidence(ctx, evidence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvidence", reflect.TypeOf((*MockDisputeRepository)(nil).AddEvidence), ctx, evidence)
+}
+
+// Create mocks base method.
+func (m *MockDisputeRepository) Create(ctx context.Context, dispute *domain.Dispute) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, dispute)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDisputeRepositoryMockRecorder) Create(ctx, dispute interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDisputeRepository)(nil).Create), ctx, dispute)
+}
+
+// GetByID mocks base method.
+func (m *MockDisputeRepository) GetByID(ctx context.Context, disputeID uuid.UUID) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, disputeID)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDisputeRepositoryMockRecorder) GetByID(ctx, disputeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDisputeRepository)(nil).GetByID), ctx, disputeID)
+}
+
+// GetByTransferID mocks base method.
+func (m *MockDisputeRepository) GetByTransferID(ctx context.Context, transferID uuid.UUID) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTransferID", ctx, transferID)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTransferID indicates an expected call of GetByTransferID.
+func (mr *MockDisputeRepositoryMockRecorder) GetByTransferID(ctx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTransferID", reflect.TypeOf((*MockDisputeRepository)(nil).GetByTransferID), ctx, transferID)
+}
+
+// GetEvidence mocks base method.
+func (m *MockDisputeRepository) GetEvidence(ctx context.Context, disputeID, evidenceID uuid.UUID) (*domain.DisputeEvidence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvidence", ctx, disputeID, evidenceID)
+	ret0, _ := ret[0].(*domain.DisputeEvidence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvidence indicates an expected call of GetEvidence.
+func (mr *MockDisputeRepositoryMockRecorder) GetEvidence(ctx, disputeID, evidenceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvidence", reflect.TypeOf((*MockDisputeRepository)(nil).GetEvidence), ctx, disputeID, evidenceID)
+}
+
+// ResolveForMerchant mocks base method.
+func (m *MockDisputeRepository) ResolveForMerchant(ctx context.Context, disputeID uuid.UUID) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveForMerchant", ctx, disputeID)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveForMerchant indicates an expected call of ResolveForMerchant.
+func (mr *MockDisputeRepositoryMockRecorder) ResolveForMerchant(ctx, disputeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveForMerchant", reflect.TypeOf((*MockDisputeRepository)(nil).ResolveForMerchant), ctx, disputeID)
+}
+
+// ResolveForPayer mocks base method.
+func (m *MockDisputeRepository) ResolveForPayer(ctx context.Context, disputeID uuid.UUID) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveForPayer", ctx, disputeID)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveForPayer indicates an expected call of ResolveForPayer.
+func (mr *MockDisputeRepositoryMockRecorder) ResolveForPayer(ctx, disputeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveForPayer", reflect.TypeOf((*MockDisputeRepository)(nil).ResolveForPayer), ctx, disputeID)
+}
+
+// Respond mocks base method.
+func (m *MockDisputeRepository) Respond(ctx context.Context, disputeID uuid.UUID, response string) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Respond", ctx, disputeID, response)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Respond indicates an expected call of Respond.
+func (mr *MockDisputeRepositoryMockRecorder) Respond(ctx, disputeID, response interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Respond", reflect.TypeOf((*MockDisputeRepository)(nil).Respond), ctx, disputeID, response)
+}
@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: encryption.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCipher is a mock of Cipher interface.
+type MockCipher struct {
+	ctrl     *gomock.Controller
+	recorder *MockCipherMockRecorder
+}
+
+// MockCipherMockRecorder is the mock recorder for MockCipher.
+type MockCipherMockRecorder struct {
+	mock *MockCipher
+}
+
+// NewMockCipher creates a new mock instance.
+func NewMockCipher(ctrl *gomock.Controller) *MockCipher {
+	mock := &MockCipher{ctrl: ctrl}
+	mock.recorder = &MockCipherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCipher) EXPECT() *MockCipherMockRecorder {
+	return m.recorder
+}
+
+// BlindIndex mocks base method.
+func (m *MockCipher) BlindIndex(value, field string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlindIndex", value, field)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BlindIndex indicates an expected call of BlindIndex.
+func (mr *MockCipherMockRecorder) BlindIndex(value, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlindIndex", reflect.TypeOf((*MockCipher)(nil).BlindIndex), value, field)
+}
+
+// Decrypt mocks base method.
+func (m *MockCipher) Decrypt(value, field string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrypt", value, field)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrypt indicates an expected call of Decrypt.
+func (mr *MockCipherMockRecorder) Decrypt(value, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockCipher)(nil).Decrypt), value, field)
+}
+
+// Encrypt mocks base method.
+func (m *MockCipher) Encrypt(plaintext, field string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encrypt", plaintext, field)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encrypt indicates an expected call of Encrypt.
+func (mr *MockCipherMockRecorder) Encrypt(plaintext, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockCipher)(nil).Encrypt), plaintext, field)
+}
+
+// NeedsReencrypt mocks base method.
+func (m *MockCipher) NeedsReencrypt(value string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsReencrypt", value)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsReencrypt indicates an expected call of NeedsReencrypt.
+func (mr *MockCipherMockRecorder) NeedsReencrypt(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsReencrypt", reflect.TypeOf((*MockCipher)(nil).NeedsReencrypt), value)
+}
@@ -0,0 +1,306 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: hold.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockHoldHandler is a mock of HoldHandler interface.
+type MockHoldHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldHandlerMockRecorder
+}
+
+// MockHoldHandlerMockRecorder is the mock recorder for MockHoldHandler.
+type MockHoldHandlerMockRecorder struct {
+	mock *MockHoldHandler
+}
+
+// NewMockHoldHandler creates a new mock instance.
+func NewMockHoldHandler(ctrl *gomock.Controller) *MockHoldHandler {
+	mock := &MockHoldHandler{ctrl: ctrl}
+	mock.recorder = &MockHoldHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldHandler) EXPECT() *MockHoldHandlerMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockHoldHandler) Capture(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockHoldHandlerMockRecorder) Capture(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockHoldHandler)(nil).Capture), ctx)
+}
+
+// Create mocks base method.
+func (m *MockHoldHandler) Create(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHoldHandlerMockRecorder) Create(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHoldHandler)(nil).Create), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockHoldHandler) GetByID(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockHoldHandlerMockRecorder) GetByID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHoldHandler)(nil).GetByID), ctx)
+}
+
+// Void mocks base method.
+func (m *MockHoldHandler) Void(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockHoldHandlerMockRecorder) Void(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockHoldHandler)(nil).Void), ctx)
+}
+
+// MockHoldService is a mock of HoldService interface.
+type MockHoldService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldServiceMockRecorder
+}
+
+// MockHoldServiceMockRecorder is the mock recorder for MockHoldService.
+type MockHoldServiceMockRecorder struct {
+	mock *MockHoldService
+}
+
+// NewMockHoldService creates a new mock instance.
+func NewMockHoldService(ctrl *gomock.Controller) *MockHoldService {
+	mock := &MockHoldService{ctrl: ctrl}
+	mock.recorder = &MockHoldServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldService) EXPECT() *MockHoldServiceMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockHoldService) Capture(ctx context.Context, holdID uuid.UUID, payload *domain.CaptureHoldPayload) (*domain.HoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, holdID, payload)
+	ret0, _ := ret[0].(*domain.HoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockHoldServiceMockRecorder) Capture(ctx, holdID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockHoldService)(nil).Capture), ctx, holdID, payload)
+}
+
+// Create mocks base method.
+func (m *MockHoldService) Create(ctx context.Context, payload *domain.HoldPayload) (*domain.HoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, payload)
+	ret0, _ := ret[0].(*domain.HoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHoldServiceMockRecorder) Create(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHoldService)(nil).Create), ctx, payload)
+}
+
+// GetByID mocks base method.
+func (m *MockHoldService) GetByID(ctx context.Context, holdID uuid.UUID) (*domain.HoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, holdID)
+	ret0, _ := ret[0].(*domain.HoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockHoldServiceMockRecorder) GetByID(ctx, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHoldService)(nil).GetByID), ctx, holdID)
+}
+
+// ReleaseExpired mocks base method.
+func (m *MockHoldService) ReleaseExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpired indicates an expected call of ReleaseExpired.
+func (mr *MockHoldServiceMockRecorder) ReleaseExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpired", reflect.TypeOf((*MockHoldService)(nil).ReleaseExpired), ctx)
+}
+
+// Void mocks base method.
+func (m *MockHoldService) Void(ctx context.Context, holdID uuid.UUID) (*domain.HoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, holdID)
+	ret0, _ := ret[0].(*domain.HoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockHoldServiceMockRecorder) Void(ctx, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockHoldService)(nil).Void), ctx, holdID)
+}
+
+// MockHoldRepository is a mock of HoldRepository interface.
+type MockHoldRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldRepositoryMockRecorder
+}
+
+// MockHoldRepositoryMockRecorder is the mock recorder for MockHoldRepository.
+type MockHoldRepositoryMockRecorder struct {
+	mock *MockHoldRepository
+}
+
+// NewMockHoldRepository creates a new mock instance.
+func NewMockHoldRepository(ctrl *gomock.Controller) *MockHoldRepository {
+	mock := &MockHoldRepository{ctrl: ctrl}
+	mock.recorder = &MockHoldRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldRepository) EXPECT() *MockHoldRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockHoldRepository) Capture(ctx context.Context, holdID uuid.UUID, value float64, final bool, payeeBalanceCap *domain.KYCBalanceCap) (*domain.Hold, *domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, holdID, value, final, payeeBalanceCap)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(*domain.Transfer)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockHoldRepositoryMockRecorder) Capture(ctx, holdID, value, final, payeeBalanceCap interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockHoldRepository)(nil).Capture), ctx, holdID, value, final, payeeBalanceCap)
+}
+
+// Create mocks base method.
+func (m *MockHoldRepository) Create(ctx context.Context, hold *domain.Hold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHoldRepositoryMockRecorder) Create(ctx, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHoldRepository)(nil).Create), ctx, hold)
+}
+
+// ExpireActive mocks base method.
+func (m *MockHoldRepository) ExpireActive(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireActive", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireActive indicates an expected call of ExpireActive.
+func (mr *MockHoldRepositoryMockRecorder) ExpireActive(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireActive", reflect.TypeOf((*MockHoldRepository)(nil).ExpireActive), ctx, now)
+}
+
+// GetActiveAmountByPayerID mocks base method.
+func (m *MockHoldRepository) GetActiveAmountByPayerID(ctx context.Context, payerID uuid.UUID, currency string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveAmountByPayerID", ctx, payerID, currency)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveAmountByPayerID indicates an expected call of GetActiveAmountByPayerID.
+func (mr *MockHoldRepositoryMockRecorder) GetActiveAmountByPayerID(ctx, payerID, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAmountByPayerID", reflect.TypeOf((*MockHoldRepository)(nil).GetActiveAmountByPayerID), ctx, payerID, currency)
+}
+
+// GetByID mocks base method.
+func (m *MockHoldRepository) GetByID(ctx context.Context, holdID uuid.UUID) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, holdID)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockHoldRepositoryMockRecorder) GetByID(ctx, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHoldRepository)(nil).GetByID), ctx, holdID)
+}
+
+// Void mocks base method.
+func (m *MockHoldRepository) Void(ctx context.Context, holdID uuid.UUID) (*domain.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, holdID)
+	ret0, _ := ret[0].(*domain.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockHoldRepositoryMockRecorder) Void(ctx, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockHoldRepository)(nil).Void), ctx, holdID)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: keyring.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	ecdsa "crypto/ecdsa"
+	reflect "reflect"
+
+	keyring "github.com/GSVillas/pic-pay-desafio/keyring"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockKeyring is a mock of Keyring interface.
+type MockKeyring struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyringMockRecorder
+}
+
+// MockKeyringMockRecorder is the mock recorder for MockKeyring.
+type MockKeyringMockRecorder struct {
+	mock *MockKeyring
+}
+
+// NewMockKeyring creates a new mock instance.
+func NewMockKeyring(ctrl *gomock.Controller) *MockKeyring {
+	mock := &MockKeyring{ctrl: ctrl}
+	mock.recorder = &MockKeyringMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyring) EXPECT() *MockKeyringMockRecorder {
+	return m.recorder
+}
+
+// JWKS mocks base method.
+func (m *MockKeyring) JWKS() (*keyring.JWKSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(*keyring.JWKSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockKeyringMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockKeyring)(nil).JWKS))
+}
+
+// SigningKey mocks base method.
+func (m *MockKeyring) SigningKey() (*keyring.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SigningKey")
+	ret0, _ := ret[0].(*keyring.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SigningKey indicates an expected call of SigningKey.
+func (mr *MockKeyringMockRecorder) SigningKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SigningKey", reflect.TypeOf((*MockKeyring)(nil).SigningKey))
+}
+
+// VerificationKey mocks base method.
+func (m *MockKeyring) VerificationKey(kid string) (*ecdsa.PublicKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerificationKey", kid)
+	ret0, _ := ret[0].(*ecdsa.PublicKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerificationKey indicates an expected call of VerificationKey.
+func (mr *MockKeyringMockRecorder) VerificationKey(kid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerificationKey", reflect.TypeOf((*MockKeyring)(nil).VerificationKey), kid)
+}
@@ -0,0 +1,320 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: kyc.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockKYCHandler is a mock of KYCHandler interface.
+type MockKYCHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockKYCHandlerMockRecorder
+}
+
+// MockKYCHandlerMockRecorder is the mock recorder for MockKYCHandler.
+type MockKYCHandlerMockRecorder struct {
+	mock *MockKYCHandler
+}
+
+// NewMockKYCHandler creates a new mock instance.
+func NewMockKYCHandler(ctrl *gomock.Controller) *MockKYCHandler {
+	mock := &MockKYCHandler{ctrl: ctrl}
+	mock.recorder = &MockKYCHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKYCHandler) EXPECT() *MockKYCHandlerMockRecorder {
+	return m.recorder
+}
+
+// DownloadDocument mocks base method.
+func (m *MockKYCHandler) DownloadDocument(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadDocument", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadDocument indicates an expected call of DownloadDocument.
+func (mr *MockKYCHandlerMockRecorder) DownloadDocument(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadDocument", reflect.TypeOf((*MockKYCHandler)(nil).DownloadDocument), ctx)
+}
+
+// GetPendingSubmissions mocks base method.
+func (m *MockKYCHandler) GetPendingSubmissions(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingSubmissions", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetPendingSubmissions indicates an expected call of GetPendingSubmissions.
+func (mr *MockKYCHandlerMockRecorder) GetPendingSubmissions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingSubmissions", reflect.TypeOf((*MockKYCHandler)(nil).GetPendingSubmissions), ctx)
+}
+
+// GetStatus mocks base method.
+func (m *MockKYCHandler) GetStatus(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockKYCHandlerMockRecorder) GetStatus(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockKYCHandler)(nil).GetStatus), ctx)
+}
+
+// Review mocks base method.
+func (m *MockKYCHandler) Review(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockKYCHandlerMockRecorder) Review(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockKYCHandler)(nil).Review), ctx)
+}
+
+// Submit mocks base method.
+func (m *MockKYCHandler) Submit(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockKYCHandlerMockRecorder) Submit(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockKYCHandler)(nil).Submit), ctx)
+}
+
+// MockKYCService is a mock of KYCService interface.
+type MockKYCService struct {
+	ctrl     *gomock.Controller
+	recorder *MockKYCServiceMockRecorder
+}
+
+// MockKYCServiceMockRecorder is the mock recorder for MockKYCService.
+type MockKYCServiceMockRecorder struct {
+	mock *MockKYCService
+}
+
+// NewMockKYCService creates a new mock instance.
+func NewMockKYCService(ctrl *gomock.Controller) *MockKYCService {
+	mock := &MockKYCService{ctrl: ctrl}
+	mock.recorder = &MockKYCServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKYCService) EXPECT() *MockKYCServiceMockRecorder {
+	return m.recorder
+}
+
+// DownloadDocument mocks base method.
+func (m *MockKYCService) DownloadDocument(ctx context.Context, submissionID, documentID uuid.UUID) (*domain.KYCDocument, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadDocument", ctx, submissionID, documentID)
+	ret0, _ := ret[0].(*domain.KYCDocument)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DownloadDocument indicates an expected call of DownloadDocument.
+func (mr *MockKYCServiceMockRecorder) DownloadDocument(ctx, submissionID, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadDocument", reflect.TypeOf((*MockKYCService)(nil).DownloadDocument), ctx, submissionID, documentID)
+}
+
+// GetLimits mocks base method.
+func (m *MockKYCService) GetLimits(ctx context.Context, userID uuid.UUID) (*domain.KYCLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", ctx, userID)
+	ret0, _ := ret[0].(*domain.KYCLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockKYCServiceMockRecorder) GetLimits(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockKYCService)(nil).GetLimits), ctx, userID)
+}
+
+// GetPendingSubmissions mocks base method.
+func (m *MockKYCService) GetPendingSubmissions(ctx context.Context) ([]*domain.KYCSubmissionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingSubmissions", ctx)
+	ret0, _ := ret[0].([]*domain.KYCSubmissionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingSubmissions indicates an expected call of GetPendingSubmissions.
+func (mr *MockKYCServiceMockRecorder) GetPendingSubmissions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingSubmissions", reflect.TypeOf((*MockKYCService)(nil).GetPendingSubmissions), ctx)
+}
+
+// GetStatus mocks base method.
+func (m *MockKYCService) GetStatus(ctx context.Context) (*domain.KYCStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx)
+	ret0, _ := ret[0].(*domain.KYCStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockKYCServiceMockRecorder) GetStatus(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockKYCService)(nil).GetStatus), ctx)
+}
+
+// Review mocks base method.
+func (m *MockKYCService) Review(ctx context.Context, submissionID uuid.UUID, payload *domain.ReviewKYCPayload) (*domain.KYCSubmissionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx, submissionID, payload)
+	ret0, _ := ret[0].(*domain.KYCSubmissionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockKYCServiceMockRecorder) Review(ctx, submissionID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockKYCService)(nil).Review), ctx, submissionID, payload)
+}
+
+// Submit mocks base method.
+func (m *MockKYCService) Submit(ctx context.Context, uploads []*domain.KYCDocumentUpload) (*domain.KYCSubmissionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, uploads)
+	ret0, _ := ret[0].(*domain.KYCSubmissionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockKYCServiceMockRecorder) Submit(ctx, uploads interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockKYCService)(nil).Submit), ctx, uploads)
+}
+
+// MockKYCRepository is a mock of KYCRepository interface.
+type MockKYCRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockKYCRepositoryMockRecorder
+}
+
+// MockKYCRepositoryMockRecorder is the mock recorder for MockKYCRepository.
+type MockKYCRepositoryMockRecorder struct {
+	mock *MockKYCRepository
+}
+
+// NewMockKYCRepository creates a new mock instance.
+func NewMockKYCRepository(ctrl *gomock.Controller) *MockKYCRepository {
+	mock := &MockKYCRepository{ctrl: ctrl}
+	mock.recorder = &MockKYCRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKYCRepository) EXPECT() *MockKYCRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSubmission mocks base method.
+func (m *MockKYCRepository) CreateSubmission(ctx context.Context, submission *domain.KYCSubmission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubmission", ctx, submission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSubmission indicates an expected call of CreateSubmission.
+func (mr *MockKYCRepositoryMockRecorder) CreateSubmission(ctx, submission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubmission", reflect.TypeOf((*MockKYCRepository)(nil).CreateSubmission), ctx, submission)
+}
+
+// GetDocument mocks base method.
+func (m *MockKYCRepository) GetDocument(ctx context.Context, submissionID, documentID uuid.UUID) (*domain.KYCDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocument", ctx, submissionID, documentID)
+	ret0, _ := ret[0].(*domain.KYCDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDocument indicates an expected call of GetDocument.
+func (mr *MockKYCRepositoryMockRecorder) GetDocument(ctx, submissionID, documentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocument", reflect.TypeOf((*MockKYCRepository)(nil).GetDocument), ctx, submissionID, documentID)
+}
+
+// GetPendingByUserID mocks base method.
+func (m *MockKYCRepository) GetPendingByUserID(ctx context.Context, userID uuid.UUID) (*domain.KYCSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingByUserID", ctx, userID)
+	ret0, _ := ret[0].(*domain.KYCSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingByUserID indicates an expected call of GetPendingByUserID.
+func (mr *MockKYCRepositoryMockRecorder) GetPendingByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingByUserID", reflect.TypeOf((*MockKYCRepository)(nil).GetPendingByUserID), ctx, userID)
+}
+
+// GetPendingSubmissions mocks base method.
+func (m *MockKYCRepository) GetPendingSubmissions(ctx context.Context) ([]*domain.KYCSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingSubmissions", ctx)
+	ret0, _ := ret[0].([]*domain.KYCSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingSubmissions indicates an expected call of GetPendingSubmissions.
+func (mr *MockKYCRepositoryMockRecorder) GetPendingSubmissions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingSubmissions", reflect.TypeOf((*MockKYCRepository)(nil).GetPendingSubmissions), ctx)
+}
+
+// Review mocks base method.
+func (m *MockKYCRepository) Review(ctx context.Context, submissionID uuid.UUID, status domain.KYCStatus, reason string, reviewerID uuid.UUID) (*domain.KYCSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx, submissionID, status, reason, reviewerID)
+	ret0, _ := ret[0].(*domain.KYCSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockKYCRepositoryMockRecorder) Review(ctx, submissionID, status, reason, reviewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockKYCRepository)(nil).Review), ctx, submissionID, status, reason, reviewerID)
+}
@@ -0,0 +1,586 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: oauth.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockOAuthHandler is a mock of OAuthHandler interface.
+type MockOAuthHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthHandlerMockRecorder
+}
+
+// MockOAuthHandlerMockRecorder is the mock recorder for MockOAuthHandler.
+type MockOAuthHandlerMockRecorder struct {
+	mock *MockOAuthHandler
+}
+
+// NewMockOAuthHandler creates a new mock instance.
+func NewMockOAuthHandler(ctrl *gomock.Controller) *MockOAuthHandler {
+	mock := &MockOAuthHandler{ctrl: ctrl}
+	mock.recorder = &MockOAuthHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthHandler) EXPECT() *MockOAuthHandlerMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockOAuthHandler) Authorize(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOAuthHandlerMockRecorder) Authorize(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOAuthHandler)(nil).Authorize), ctx)
+}
+
+// CreateClient mocks base method.
+func (m *MockOAuthHandler) CreateClient(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockOAuthHandlerMockRecorder) CreateClient(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockOAuthHandler)(nil).CreateClient), ctx)
+}
+
+// DeleteClient mocks base method.
+func (m *MockOAuthHandler) DeleteClient(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockOAuthHandlerMockRecorder) DeleteClient(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockOAuthHandler)(nil).DeleteClient), ctx)
+}
+
+// GetClients mocks base method.
+func (m *MockOAuthHandler) GetClients(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClients", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetClients indicates an expected call of GetClients.
+func (mr *MockOAuthHandlerMockRecorder) GetClients(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClients", reflect.TypeOf((*MockOAuthHandler)(nil).GetClients), ctx)
+}
+
+// GetConsent mocks base method.
+func (m *MockOAuthHandler) GetConsent(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConsent", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetConsent indicates an expected call of GetConsent.
+func (mr *MockOAuthHandlerMockRecorder) GetConsent(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsent", reflect.TypeOf((*MockOAuthHandler)(nil).GetConsent), ctx)
+}
+
+// Introspect mocks base method.
+func (m *MockOAuthHandler) Introspect(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockOAuthHandlerMockRecorder) Introspect(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockOAuthHandler)(nil).Introspect), ctx)
+}
+
+// Revoke mocks base method.
+func (m *MockOAuthHandler) Revoke(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockOAuthHandlerMockRecorder) Revoke(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockOAuthHandler)(nil).Revoke), ctx)
+}
+
+// Token mocks base method.
+func (m *MockOAuthHandler) Token(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOAuthHandlerMockRecorder) Token(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOAuthHandler)(nil).Token), ctx)
+}
+
+// UserInfo mocks base method.
+func (m *MockOAuthHandler) UserInfo(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockOAuthHandlerMockRecorder) UserInfo(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockOAuthHandler)(nil).UserInfo), ctx)
+}
+
+// MockOAuthService is a mock of OAuthService interface.
+type MockOAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthServiceMockRecorder
+}
+
+// MockOAuthServiceMockRecorder is the mock recorder for MockOAuthService.
+type MockOAuthServiceMockRecorder struct {
+	mock *MockOAuthService
+}
+
+// NewMockOAuthService creates a new mock instance.
+func NewMockOAuthService(ctrl *gomock.Controller) *MockOAuthService {
+	mock := &MockOAuthService{ctrl: ctrl}
+	mock.recorder = &MockOAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthService) EXPECT() *MockOAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockOAuthService) Authenticate(ctx context.Context, token string) (*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockOAuthServiceMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockOAuthService)(nil).Authenticate), ctx, token)
+}
+
+// Authorize mocks base method.
+func (m *MockOAuthService) Authorize(ctx context.Context, request *domain.OAuthAuthorizeRequest) (*domain.OAuthAuthorizeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, request)
+	ret0, _ := ret[0].(*domain.OAuthAuthorizeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOAuthServiceMockRecorder) Authorize(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOAuthService)(nil).Authorize), ctx, request)
+}
+
+// CreateClient mocks base method.
+func (m *MockOAuthService) CreateClient(ctx context.Context, payload *domain.OAuthClientPayload) (*domain.CreateOAuthClientResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx, payload)
+	ret0, _ := ret[0].(*domain.CreateOAuthClientResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockOAuthServiceMockRecorder) CreateClient(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockOAuthService)(nil).CreateClient), ctx, payload)
+}
+
+// DeleteClient mocks base method.
+func (m *MockOAuthService) DeleteClient(ctx context.Context, clientID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, clientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockOAuthServiceMockRecorder) DeleteClient(ctx, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockOAuthService)(nil).DeleteClient), ctx, clientID)
+}
+
+// GetClients mocks base method.
+func (m *MockOAuthService) GetClients(ctx context.Context) ([]*domain.OAuthClientResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClients", ctx)
+	ret0, _ := ret[0].([]*domain.OAuthClientResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClients indicates an expected call of GetClients.
+func (mr *MockOAuthServiceMockRecorder) GetClients(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClients", reflect.TypeOf((*MockOAuthService)(nil).GetClients), ctx)
+}
+
+// GetConsent mocks base method.
+func (m *MockOAuthService) GetConsent(ctx context.Context, request *domain.OAuthAuthorizeRequest) (*domain.OAuthConsentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConsent", ctx, request)
+	ret0, _ := ret[0].(*domain.OAuthConsentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConsent indicates an expected call of GetConsent.
+func (mr *MockOAuthServiceMockRecorder) GetConsent(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsent", reflect.TypeOf((*MockOAuthService)(nil).GetConsent), ctx, request)
+}
+
+// Introspect mocks base method.
+func (m *MockOAuthService) Introspect(ctx context.Context, credentials *domain.OAuthClientCredentials, token string) (*domain.OAuthIntrospectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx, credentials, token)
+	ret0, _ := ret[0].(*domain.OAuthIntrospectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockOAuthServiceMockRecorder) Introspect(ctx, credentials, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockOAuthService)(nil).Introspect), ctx, credentials, token)
+}
+
+// Revoke mocks base method.
+func (m *MockOAuthService) Revoke(ctx context.Context, credentials *domain.OAuthClientCredentials, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, credentials, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockOAuthServiceMockRecorder) Revoke(ctx, credentials, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockOAuthService)(nil).Revoke), ctx, credentials, token)
+}
+
+// Token mocks base method.
+func (m *MockOAuthService) Token(ctx context.Context, request *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx, request)
+	ret0, _ := ret[0].(*domain.OAuthTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOAuthServiceMockRecorder) Token(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOAuthService)(nil).Token), ctx, request)
+}
+
+// UserInfo mocks base method.
+func (m *MockOAuthService) UserInfo(ctx context.Context) (*domain.OAuthUserInfoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", ctx)
+	ret0, _ := ret[0].(*domain.OAuthUserInfoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockOAuthServiceMockRecorder) UserInfo(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockOAuthService)(nil).UserInfo), ctx)
+}
+
+// MockOAuthClientRepository is a mock of OAuthClientRepository interface.
+type MockOAuthClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthClientRepositoryMockRecorder
+}
+
+// MockOAuthClientRepositoryMockRecorder is the mock recorder for MockOAuthClientRepository.
+type MockOAuthClientRepositoryMockRecorder struct {
+	mock *MockOAuthClientRepository
+}
+
+// NewMockOAuthClientRepository creates a new mock instance.
+func NewMockOAuthClientRepository(ctrl *gomock.Controller) *MockOAuthClientRepository {
+	mock := &MockOAuthClientRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthClientRepository) EXPECT() *MockOAuthClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOAuthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOAuthClientRepositoryMockRecorder) Create(ctx, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOAuthClientRepository)(nil).Create), ctx, client)
+}
+
+// Delete mocks base method.
+func (m *MockOAuthClientRepository) Delete(ctx context.Context, ownerID, clientID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ownerID, clientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOAuthClientRepositoryMockRecorder) Delete(ctx, ownerID, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOAuthClientRepository)(nil).Delete), ctx, ownerID, clientID)
+}
+
+// GetAllByOwnerID mocks base method.
+func (m *MockOAuthClientRepository) GetAllByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*domain.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByOwnerID", ctx, ownerID)
+	ret0, _ := ret[0].([]*domain.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByOwnerID indicates an expected call of GetAllByOwnerID.
+func (mr *MockOAuthClientRepositoryMockRecorder) GetAllByOwnerID(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByOwnerID", reflect.TypeOf((*MockOAuthClientRepository)(nil).GetAllByOwnerID), ctx, ownerID)
+}
+
+// GetByID mocks base method.
+func (m *MockOAuthClientRepository) GetByID(ctx context.Context, clientID uuid.UUID) (*domain.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, clientID)
+	ret0, _ := ret[0].(*domain.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOAuthClientRepositoryMockRecorder) GetByID(ctx, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOAuthClientRepository)(nil).GetByID), ctx, clientID)
+}
+
+// MockOAuthTokenRepository is a mock of OAuthTokenRepository interface.
+type MockOAuthTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthTokenRepositoryMockRecorder
+}
+
+// MockOAuthTokenRepositoryMockRecorder is the mock recorder for MockOAuthTokenRepository.
+type MockOAuthTokenRepositoryMockRecorder struct {
+	mock *MockOAuthTokenRepository
+}
+
+// NewMockOAuthTokenRepository creates a new mock instance.
+func NewMockOAuthTokenRepository(ctrl *gomock.Controller) *MockOAuthTokenRepository {
+	mock := &MockOAuthTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthTokenRepository) EXPECT() *MockOAuthTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCode mocks base method.
+func (m *MockOAuthTokenRepository) CreateCode(ctx context.Context, codeHash string, code *domain.OAuthAuthorizationCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCode", ctx, codeHash, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCode indicates an expected call of CreateCode.
+func (mr *MockOAuthTokenRepositoryMockRecorder) CreateCode(ctx, codeHash, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCode", reflect.TypeOf((*MockOAuthTokenRepository)(nil).CreateCode), ctx, codeHash, code)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockOAuthTokenRepository) CreateRefreshToken(ctx context.Context, tokenHash string, token *domain.OAuthRefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, tokenHash, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockOAuthTokenRepositoryMockRecorder) CreateRefreshToken(ctx, tokenHash, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockOAuthTokenRepository)(nil).CreateRefreshToken), ctx, tokenHash, token)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockOAuthTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.OAuthRefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*domain.OAuthRefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockOAuthTokenRepositoryMockRecorder) GetRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockOAuthTokenRepository)(nil).GetRefreshToken), ctx, tokenHash)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockOAuthTokenRepository) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, tokenID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockOAuthTokenRepositoryMockRecorder) IsAccessTokenRevoked(ctx, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockOAuthTokenRepository)(nil).IsAccessTokenRevoked), ctx, tokenID)
+}
+
+// RecordGrant mocks base method.
+func (m *MockOAuthTokenRepository) RecordGrant(ctx context.Context, grant *domain.OAuthIssuedGrant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordGrant", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordGrant indicates an expected call of RecordGrant.
+func (mr *MockOAuthTokenRepositoryMockRecorder) RecordGrant(ctx, grant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGrant", reflect.TypeOf((*MockOAuthTokenRepository)(nil).RecordGrant), ctx, grant)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockOAuthTokenRepository) RevokeAccessToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, tokenID, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockOAuthTokenRepositoryMockRecorder) RevokeAccessToken(ctx, tokenID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockOAuthTokenRepository)(nil).RevokeAccessToken), ctx, tokenID, ttl)
+}
+
+// RevokeClientGrants mocks base method.
+func (m *MockOAuthTokenRepository) RevokeClientGrants(ctx context.Context, clientID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeClientGrants", ctx, clientID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeClientGrants indicates an expected call of RevokeClientGrants.
+func (mr *MockOAuthTokenRepositoryMockRecorder) RevokeClientGrants(ctx, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeClientGrants", reflect.TypeOf((*MockOAuthTokenRepository)(nil).RevokeClientGrants), ctx, clientID)
+}
+
+// RevokeUserGrants mocks base method.
+func (m *MockOAuthTokenRepository) RevokeUserGrants(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserGrants", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeUserGrants indicates an expected call of RevokeUserGrants.
+func (mr *MockOAuthTokenRepositoryMockRecorder) RevokeUserGrants(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserGrants", reflect.TypeOf((*MockOAuthTokenRepository)(nil).RevokeUserGrants), ctx, userID)
+}
+
+// TakeCode mocks base method.
+func (m *MockOAuthTokenRepository) TakeCode(ctx context.Context, codeHash string) (*domain.OAuthAuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeCode", ctx, codeHash)
+	ret0, _ := ret[0].(*domain.OAuthAuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeCode indicates an expected call of TakeCode.
+func (mr *MockOAuthTokenRepositoryMockRecorder) TakeCode(ctx, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeCode", reflect.TypeOf((*MockOAuthTokenRepository)(nil).TakeCode), ctx, codeHash)
+}
+
+// TakeRefreshToken mocks base method.
+func (m *MockOAuthTokenRepository) TakeRefreshToken(ctx context.Context, tokenHash string) (*domain.OAuthRefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*domain.OAuthRefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRefreshToken indicates an expected call of TakeRefreshToken.
+func (mr *MockOAuthTokenRepositoryMockRecorder) TakeRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRefreshToken", reflect.TypeOf((*MockOAuthTokenRepository)(nil).TakeRefreshToken), ctx, tokenHash)
+}
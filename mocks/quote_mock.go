@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: quote.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockQuoteHandler is a mock of QuoteHandler interface.
+type MockQuoteHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteHandlerMockRecorder
+}
+
+// MockQuoteHandlerMockRecorder is the mock recorder for MockQuoteHandler.
+type MockQuoteHandlerMockRecorder struct {
+	mock *MockQuoteHandler
+}
+
+// NewMockQuoteHandler creates a new mock instance.
+func NewMockQuoteHandler(ctrl *gomock.Controller) *MockQuoteHandler {
+	mock := &MockQuoteHandler{ctrl: ctrl}
+	mock.recorder = &MockQuoteHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteHandler) EXPECT() *MockQuoteHandlerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockQuoteHandler) Create(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuoteHandlerMockRecorder) Create(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuoteHandler)(nil).Create), ctx)
+}
+
+// MockQuoteService is a mock of QuoteService interface.
+type MockQuoteService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteServiceMockRecorder
+}
+
+// MockQuoteServiceMockRecorder is the mock recorder for MockQuoteService.
+type MockQuoteServiceMockRecorder struct {
+	mock *MockQuoteService
+}
+
+// NewMockQuoteService creates a new mock instance.
+func NewMockQuoteService(ctrl *gomock.Controller) *MockQuoteService {
+	mock := &MockQuoteService{ctrl: ctrl}
+	mock.recorder = &MockQuoteServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteService) EXPECT() *MockQuoteServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockQuoteService) Create(ctx context.Context, payload *domain.QuotePayload) (*domain.QuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, payload)
+	ret0, _ := ret[0].(*domain.QuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuoteServiceMockRecorder) Create(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuoteService)(nil).Create), ctx, payload)
+}
+
+// MockQuoteRepository is a mock of QuoteRepository interface.
+type MockQuoteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteRepositoryMockRecorder
+}
+
+// MockQuoteRepositoryMockRecorder is the mock recorder for MockQuoteRepository.
+type MockQuoteRepositoryMockRecorder struct {
+	mock *MockQuoteRepository
+}
+
+// NewMockQuoteRepository creates a new mock instance.
+func NewMockQuoteRepository(ctrl *gomock.Controller) *MockQuoteRepository {
+	mock := &MockQuoteRepository{ctrl: ctrl}
+	mock.recorder = &MockQuoteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteRepository) EXPECT() *MockQuoteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockQuoteRepository) Create(ctx context.Context, quote *domain.Quote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, quote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuoteRepositoryMockRecorder) Create(ctx, quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuoteRepository)(nil).Create), ctx, quote)
+}
+
+// Restore mocks base method.
+func (m *MockQuoteRepository) Restore(ctx context.Context, quote *domain.Quote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, quote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockQuoteRepositoryMockRecorder) Restore(ctx, quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockQuoteRepository)(nil).Restore), ctx, quote)
+}
+
+// Take mocks base method.
+func (m *MockQuoteRepository) Take(ctx context.Context, quoteID uuid.UUID) (*domain.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, quoteID)
+	ret0, _ := ret[0].(*domain.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockQuoteRepositoryMockRecorder) Take(ctx, quoteID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockQuoteRepository)(nil).Take), ctx, quoteID)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: sign_in_attempt.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSignInAttemptRepository is a mock of SignInAttemptRepository interface.
+type MockSignInAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignInAttemptRepositoryMockRecorder
+}
+
+// MockSignInAttemptRepositoryMockRecorder is the mock recorder for MockSignInAttemptRepository.
+type MockSignInAttemptRepositoryMockRecorder struct {
+	mock *MockSignInAttemptRepository
+}
+
+// NewMockSignInAttemptRepository creates a new mock instance.
+func NewMockSignInAttemptRepository(ctrl *gomock.Controller) *MockSignInAttemptRepository {
+	mock := &MockSignInAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockSignInAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignInAttemptRepository) EXPECT() *MockSignInAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetStatus mocks base method.
+func (m *MockSignInAttemptRepository) GetStatus(ctx context.Context, email, ip string) (*domain.SignInAttemptStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, email, ip)
+	ret0, _ := ret[0].(*domain.SignInAttemptStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockSignInAttemptRepositoryMockRecorder) GetStatus(ctx, email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockSignInAttemptRepository)(nil).GetStatus), ctx, email, ip)
+}
+
+// Lock mocks base method.
+func (m *MockSignInAttemptRepository) Lock(ctx context.Context, email string, duration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, email, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockSignInAttemptRepositoryMockRecorder) Lock(ctx, email, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockSignInAttemptRepository)(nil).Lock), ctx, email, duration)
+}
+
+// RegisterFailure mocks base method.
+func (m *MockSignInAttemptRepository) RegisterFailure(ctx context.Context, email, ip string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterFailure", ctx, email, ip)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterFailure indicates an expected call of RegisterFailure.
+func (mr *MockSignInAttemptRepositoryMockRecorder) RegisterFailure(ctx, email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFailure", reflect.TypeOf((*MockSignInAttemptRepository)(nil).RegisterFailure), ctx, email, ip)
+}
+
+// RegisterPasswordReset mocks base method.
+func (m *MockSignInAttemptRepository) RegisterPasswordReset(ctx context.Context, email, ip string) (*domain.PasswordResetRequests, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPasswordReset", ctx, email, ip)
+	ret0, _ := ret[0].(*domain.PasswordResetRequests)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterPasswordReset indicates an expected call of RegisterPasswordReset.
+func (mr *MockSignInAttemptRepositoryMockRecorder) RegisterPasswordReset(ctx, email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPasswordReset", reflect.TypeOf((*MockSignInAttemptRepository)(nil).RegisterPasswordReset), ctx, email, ip)
+}
+
+// Reset mocks base method.
+func (m *MockSignInAttemptRepository) Reset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockSignInAttemptRepositoryMockRecorder) Reset(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockSignInAttemptRepository)(nil).Reset), ctx, email)
+}
+
+// SetDelay mocks base method.
+func (m *MockSignInAttemptRepository) SetDelay(ctx context.Context, email string, delay time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDelay", ctx, email, delay)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDelay indicates an expected call of SetDelay.
+func (mr *MockSignInAttemptRepositoryMockRecorder) SetDelay(ctx, email, delay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDelay", reflect.TypeOf((*MockSignInAttemptRepository)(nil).SetDelay), ctx, email, delay)
+}
@@ -0,0 +1,270 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: transaction_pin.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/GSVillas/pic-pay-desafio/domain"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	echo "github.com/labstack/echo/v4"
+)
+
+// MockTransactionPINHandler is a mock of TransactionPINHandler interface.
+type MockTransactionPINHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionPINHandlerMockRecorder
+}
+
+// MockTransactionPINHandlerMockRecorder is the mock recorder for MockTransactionPINHandler.
+type MockTransactionPINHandlerMockRecorder struct {
+	mock *MockTransactionPINHandler
+}
+
+// NewMockTransactionPINHandler creates a new mock instance.
+func NewMockTransactionPINHandler(ctrl *gomock.Controller) *MockTransactionPINHandler {
+	mock := &MockTransactionPINHandler{ctrl: ctrl}
+	mock.recorder = &MockTransactionPINHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionPINHandler) EXPECT() *MockTransactionPINHandlerMockRecorder {
+	return m.recorder
+}
+
+// Change mocks base method.
+func (m *MockTransactionPINHandler) Change(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Change", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Change indicates an expected call of Change.
+func (mr *MockTransactionPINHandlerMockRecorder) Change(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Change", reflect.TypeOf((*MockTransactionPINHandler)(nil).Change), ctx)
+}
+
+// Reset mocks base method.
+func (m *MockTransactionPINHandler) Reset(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockTransactionPINHandlerMockRecorder) Reset(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockTransactionPINHandler)(nil).Reset), ctx)
+}
+
+// Set mocks base method.
+func (m *MockTransactionPINHandler) Set(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockTransactionPINHandlerMockRecorder) Set(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockTransactionPINHandler)(nil).Set), ctx)
+}
+
+// MockTransactionPINService is a mock of TransactionPINService interface.
+type MockTransactionPINService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionPINServiceMockRecorder
+}
+
+// MockTransactionPINServiceMockRecorder is the mock recorder for MockTransactionPINService.
+type MockTransactionPINServiceMockRecorder struct {
+	mock *MockTransactionPINService
+}
+
+// NewMockTransactionPINService creates a new mock instance.
+func NewMockTransactionPINService(ctrl *gomock.Controller) *MockTransactionPINService {
+	mock := &MockTransactionPINService{ctrl: ctrl}
+	mock.recorder = &MockTransactionPINServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionPINService) EXPECT() *MockTransactionPINServiceMockRecorder {
+	return m.recorder
+}
+
+// Change mocks base method.
+func (m *MockTransactionPINService) Change(ctx context.Context, payload *domain.ChangePINPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Change", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Change indicates an expected call of Change.
+func (mr *MockTransactionPINServiceMockRecorder) Change(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Change", reflect.TypeOf((*MockTransactionPINService)(nil).Change), ctx, payload)
+}
+
+// Reset mocks base method.
+func (m *MockTransactionPINService) Reset(ctx context.Context, payload *domain.ResetPINPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockTransactionPINServiceMockRecorder) Reset(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockTransactionPINService)(nil).Reset), ctx, payload)
+}
+
+// Set mocks base method.
+func (m *MockTransactionPINService) Set(ctx context.Context, payload *domain.SetPINPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockTransactionPINServiceMockRecorder) Set(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockTransactionPINService)(nil).Set), ctx, payload)
+}
+
+// Verify mocks base method.
+func (m *MockTransactionPINService) Verify(ctx context.Context, userID uuid.UUID, pin string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, userID, pin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockTransactionPINServiceMockRecorder) Verify(ctx, userID, pin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockTransactionPINService)(nil).Verify), ctx, userID, pin)
+}
+
+// MockTransactionPINRepository is a mock of TransactionPINRepository interface.
+type MockTransactionPINRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionPINRepositoryMockRecorder
+}
+
+// MockTransactionPINRepositoryMockRecorder is the mock recorder for MockTransactionPINRepository.
+type MockTransactionPINRepositoryMockRecorder struct {
+	mock *MockTransactionPINRepository
+}
+
+// NewMockTransactionPINRepository creates a new mock instance.
+func NewMockTransactionPINRepository(ctrl *gomock.Controller) *MockTransactionPINRepository {
+	mock := &MockTransactionPINRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionPINRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionPINRepository) EXPECT() *MockTransactionPINRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByUserID mocks base method.
+func (m *MockTransactionPINRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.TransactionPIN, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].(*domain.TransactionPIN)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockTransactionPINRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockTransactionPINRepository)(nil).GetByUserID), ctx, userID)
+}
+
+// GetLock mocks base method.
+func (m *MockTransactionPINRepository) GetLock(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLock", ctx, userID)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLock indicates an expected call of GetLock.
+func (mr *MockTransactionPINRepositoryMockRecorder) GetLock(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLock", reflect.TypeOf((*MockTransactionPINRepository)(nil).GetLock), ctx, userID)
+}
+
+// Lock mocks base method.
+func (m *MockTransactionPINRepository) Lock(ctx context.Context, userID uuid.UUID, duration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, userID, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockTransactionPINRepositoryMockRecorder) Lock(ctx, userID, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockTransactionPINRepository)(nil).Lock), ctx, userID, duration)
+}
+
+// RegisterFailure mocks base method.
+func (m *MockTransactionPINRepository) RegisterFailure(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterFailure", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterFailure indicates an expected call of RegisterFailure.
+func (mr *MockTransactionPINRepositoryMockRecorder) RegisterFailure(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFailure", reflect.TypeOf((*MockTransactionPINRepository)(nil).RegisterFailure), ctx, userID)
+}
+
+// ResetAttempts mocks base method.
+func (m *MockTransactionPINRepository) ResetAttempts(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAttempts", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAttempts indicates an expected call of ResetAttempts.
+func (mr *MockTransactionPINRepositoryMockRecorder) ResetAttempts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAttempts", reflect.TypeOf((*MockTransactionPINRepository)(nil).ResetAttempts), ctx, userID)
+}
+
+// Save mocks base method.
+func (m *MockTransactionPINRepository) Save(ctx context.Context, pin *domain.TransactionPIN) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, pin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTransactionPINRepositoryMockRecorder) Save(ctx, pin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTransactionPINRepository)(nil).Save), ctx, pin)
+}
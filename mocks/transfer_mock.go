@@ -7,9 +7,11 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/GSVillas/pic-pay-desafio/domain"
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 	echo "github.com/labstack/echo/v4"
 )
 
@@ -36,6 +38,62 @@ func (m *MockTransferHandler) EXPECT() *MockTransferHandlerMockRecorder {
 	return m.recorder
 }
 
+// ConfirmEscrow mocks base method.
+func (m *MockTransferHandler) ConfirmEscrow(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEscrow", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmEscrow indicates an expected call of ConfirmEscrow.
+func (mr *MockTransferHandlerMockRecorder) ConfirmEscrow(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEscrow", reflect.TypeOf((*MockTransferHandler)(nil).ConfirmEscrow), ctx)
+}
+
+// DisputeEscrow mocks base method.
+func (m *MockTransferHandler) DisputeEscrow(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrow", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisputeEscrow indicates an expected call of DisputeEscrow.
+func (mr *MockTransferHandlerMockRecorder) DisputeEscrow(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrow", reflect.TypeOf((*MockTransferHandler)(nil).DisputeEscrow), ctx)
+}
+
+// Refund mocks base method.
+func (m *MockTransferHandler) Refund(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockTransferHandlerMockRecorder) Refund(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockTransferHandler)(nil).Refund), ctx)
+}
+
+// ResolveEscrow mocks base method.
+func (m *MockTransferHandler) ResolveEscrow(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveEscrow", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveEscrow indicates an expected call of ResolveEscrow.
+func (mr *MockTransferHandlerMockRecorder) ResolveEscrow(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveEscrow", reflect.TypeOf((*MockTransferHandler)(nil).ResolveEscrow), ctx)
+}
+
 // Transfer mocks base method.
 func (m *MockTransferHandler) Transfer(ctx echo.Context) error {
 	m.ctrl.T.Helper()
@@ -73,12 +131,88 @@ func (m *MockTransferService) EXPECT() *MockTransferServiceMockRecorder {
 	return m.recorder
 }
 
+// ConfirmEscrow mocks base method.
+func (m *MockTransferService) ConfirmEscrow(ctx context.Context, transferID uuid.UUID) (*domain.TransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEscrow", ctx, transferID)
+	ret0, _ := ret[0].(*domain.TransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEscrow indicates an expected call of ConfirmEscrow.
+func (mr *MockTransferServiceMockRecorder) ConfirmEscrow(ctx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEscrow", reflect.TypeOf((*MockTransferService)(nil).ConfirmEscrow), ctx, transferID)
+}
+
+// DisputeEscrow mocks base method.
+func (m *MockTransferService) DisputeEscrow(ctx context.Context, transferID uuid.UUID, payload *domain.DisputeEscrowPayload) (*domain.TransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrow", ctx, transferID, payload)
+	ret0, _ := ret[0].(*domain.TransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputeEscrow indicates an expected call of DisputeEscrow.
+func (mr *MockTransferServiceMockRecorder) DisputeEscrow(ctx, transferID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrow", reflect.TypeOf((*MockTransferService)(nil).DisputeEscrow), ctx, transferID, payload)
+}
+
+// Refund mocks base method.
+func (m *MockTransferService) Refund(ctx context.Context, transferID uuid.UUID, payload *domain.RefundPayload) (*domain.TransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, transferID, payload)
+	ret0, _ := ret[0].(*domain.TransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockTransferServiceMockRecorder) Refund(ctx, transferID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockTransferService)(nil).Refund), ctx, transferID, payload)
+}
+
+// ReleaseDueEscrows mocks base method.
+func (m *MockTransferService) ReleaseDueEscrows(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseDueEscrows", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseDueEscrows indicates an expected call of ReleaseDueEscrows.
+func (mr *MockTransferServiceMockRecorder) ReleaseDueEscrows(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseDueEscrows", reflect.TypeOf((*MockTransferService)(nil).ReleaseDueEscrows), ctx)
+}
+
+// ResolveEscrow mocks base method.
+func (m *MockTransferService) ResolveEscrow(ctx context.Context, transferID uuid.UUID, payload *domain.ResolveEscrowPayload) (*domain.TransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveEscrow", ctx, transferID, payload)
+	ret0, _ := ret[0].(*domain.TransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveEscrow indicates an expected call of ResolveEscrow.
+func (mr *MockTransferServiceMockRecorder) ResolveEscrow(ctx, transferID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveEscrow", reflect.TypeOf((*MockTransferService)(nil).ResolveEscrow), ctx, transferID, payload)
+}
+
 // Transfer mocks base method.
-func (m *MockTransferService) Transfer(ctx context.Context, payload *domain.TransferPayload) error {
+func (m *MockTransferService) Transfer(ctx context.Context, payload *domain.TransferPayload) (*domain.TransferResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Transfer", ctx, payload)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*domain.TransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Transfer indicates an expected call of Transfer.
@@ -110,6 +244,136 @@ func (m *MockTransferRepository) EXPECT() *MockTransferRepositoryMockRecorder {
 	return m.recorder
 }
 
+// DisputeEscrow mocks base method.
+func (m *MockTransferRepository) DisputeEscrow(ctx context.Context, transferID uuid.UUID, reason string) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrow", ctx, transferID, reason)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputeEscrow indicates an expected call of DisputeEscrow.
+func (mr *MockTransferRepositoryMockRecorder) DisputeEscrow(ctx, transferID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrow", reflect.TypeOf((*MockTransferRepository)(nil).DisputeEscrow), ctx, transferID, reason)
+}
+
+// GetAllByUserID mocks base method.
+func (m *MockTransferRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID.
+func (mr *MockTransferRepositoryMockRecorder) GetAllByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockTransferRepository)(nil).GetAllByUserID), ctx, userID)
+}
+
+// GetByID mocks base method.
+func (m *MockTransferRepository) GetByID(ctx context.Context, transferID uuid.UUID) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, transferID)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTransferRepositoryMockRecorder) GetByID(ctx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransferRepository)(nil).GetByID), ctx, transferID)
+}
+
+// GetDueEscrows mocks base method.
+func (m *MockTransferRepository) GetDueEscrows(ctx context.Context, now time.Time, limit int) ([]*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueEscrows", ctx, now, limit)
+	ret0, _ := ret[0].([]*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueEscrows indicates an expected call of GetDueEscrows.
+func (mr *MockTransferRepositoryMockRecorder) GetDueEscrows(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueEscrows", reflect.TypeOf((*MockTransferRepository)(nil).GetDueEscrows), ctx, now, limit)
+}
+
+// GetStatement mocks base method.
+func (m *MockTransferRepository) GetStatement(ctx context.Context, userID uuid.UUID, currency string, query *domain.StatementQuery) ([]*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, userID, currency, query)
+	ret0, _ := ret[0].([]*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockTransferRepositoryMockRecorder) GetStatement(ctx, userID, currency, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockTransferRepository)(nil).GetStatement), ctx, userID, currency, query)
+}
+
+// Refund mocks base method.
+func (m *MockTransferRepository) Refund(ctx context.Context, transferID uuid.UUID, value float64) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, transferID, value)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockTransferRepositoryMockRecorder) Refund(ctx, transferID, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockTransferRepository)(nil).Refund), ctx, transferID, value)
+}
+
+// RefundEscrow mocks base method.
+func (m *MockTransferRepository) RefundEscrow(ctx context.Context, transferID uuid.UUID, from ...domain.EscrowStatus) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, transferID}
+	for _, a := range from {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RefundEscrow", varargs...)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundEscrow indicates an expected call of RefundEscrow.
+func (mr *MockTransferRepositoryMockRecorder) RefundEscrow(ctx, transferID interface{}, from ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, transferID}, from...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundEscrow", reflect.TypeOf((*MockTransferRepository)(nil).RefundEscrow), varargs...)
+}
+
+// ReleaseEscrow mocks base method.
+func (m *MockTransferRepository) ReleaseEscrow(ctx context.Context, transferID uuid.UUID, payeeBalanceCap *domain.KYCBalanceCap, from ...domain.EscrowStatus) (*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, transferID, payeeBalanceCap}
+	for _, a := range from {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReleaseEscrow", varargs...)
+	ret0, _ := ret[0].(*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseEscrow indicates an expected call of ReleaseEscrow.
+func (mr *MockTransferRepositoryMockRecorder) ReleaseEscrow(ctx, transferID, payeeBalanceCap interface{}, from ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, transferID, payeeBalanceCap}, from...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseEscrow", reflect.TypeOf((*MockTransferRepository)(nil).ReleaseEscrow), varargs...)
+}
+
 // Transfer mocks base method.
 func (m *MockTransferRepository) Transfer(ctx context.Context, transfer *domain.Transfer) error {
 	m.ctrl.T.Helper()
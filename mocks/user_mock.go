@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/GSVillas/pic-pay-desafio/domain"
 	gomock "github.com/golang/mock/gomock"
@@ -37,6 +38,20 @@ func (m *MockUserHandler) EXPECT() *MockUserHandlerMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserHandler) ChangePassword(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserHandlerMockRecorder) ChangePassword(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserHandler)(nil).ChangePassword), ctx)
+}
+
 // Create mocks base method.
 func (m *MockUserHandler) Create(ctx echo.Context) error {
 	m.ctrl.T.Helper()
@@ -51,6 +66,76 @@ func (mr *MockUserHandlerMockRecorder) Create(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserHandler)(nil).Create), ctx)
 }
 
+// ForgotPassword mocks base method.
+func (m *MockUserHandler) ForgotPassword(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserHandlerMockRecorder) ForgotPassword(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserHandler)(nil).ForgotPassword), ctx)
+}
+
+// GetProfile mocks base method.
+func (m *MockUserHandler) GetProfile(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockUserHandlerMockRecorder) GetProfile(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockUserHandler)(nil).GetProfile), ctx)
+}
+
+// Refresh mocks base method.
+func (m *MockUserHandler) Refresh(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserHandlerMockRecorder) Refresh(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserHandler)(nil).Refresh), ctx)
+}
+
+// ResendEmailVerification mocks base method.
+func (m *MockUserHandler) ResendEmailVerification(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendEmailVerification", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendEmailVerification indicates an expected call of ResendEmailVerification.
+func (mr *MockUserHandlerMockRecorder) ResendEmailVerification(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendEmailVerification", reflect.TypeOf((*MockUserHandler)(nil).ResendEmailVerification), ctx)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserHandler) ResetPassword(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserHandlerMockRecorder) ResetPassword(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserHandler)(nil).ResetPassword), ctx)
+}
+
 // SignIn mocks base method.
 func (m *MockUserHandler) SignIn(ctx echo.Context) error {
 	m.ctrl.T.Helper()
@@ -65,6 +150,76 @@ func (mr *MockUserHandlerMockRecorder) SignIn(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockUserHandler)(nil).SignIn), ctx)
 }
 
+// SignInTwoFactor mocks base method.
+func (m *MockUserHandler) SignInTwoFactor(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInTwoFactor", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SignInTwoFactor indicates an expected call of SignInTwoFactor.
+func (mr *MockUserHandlerMockRecorder) SignInTwoFactor(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInTwoFactor", reflect.TypeOf((*MockUserHandler)(nil).SignInTwoFactor), ctx)
+}
+
+// Unlock mocks base method.
+func (m *MockUserHandler) Unlock(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockUserHandlerMockRecorder) Unlock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockUserHandler)(nil).Unlock), ctx)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserHandler) UpdateProfile(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserHandlerMockRecorder) UpdateProfile(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserHandler)(nil).UpdateProfile), ctx)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserHandler) UpdateRole(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserHandlerMockRecorder) UpdateRole(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserHandler)(nil).UpdateRole), ctx)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserHandler) VerifyEmail(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserHandlerMockRecorder) VerifyEmail(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserHandler)(nil).VerifyEmail), ctx)
+}
+
 // MockUserService is a mock of UserService interface.
 type MockUserService struct {
 	ctrl     *gomock.Controller
@@ -88,6 +243,20 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserService) ChangePassword(ctx context.Context, payload *domain.ChangePasswordPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserServiceMockRecorder) ChangePassword(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserService)(nil).ChangePassword), ctx, payload)
+}
+
 // Create mocks base method.
 func (m *MockUserService) Create(ctx context.Context, payload *domain.UserPayload) error {
 	m.ctrl.T.Helper()
@@ -102,6 +271,120 @@ func (mr *MockUserServiceMockRecorder) Create(ctx, payload interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserService)(nil).Create), ctx, payload)
 }
 
+// EnsureCompany mocks base method.
+func (m *MockUserService) EnsureCompany(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureCompany", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureCompany indicates an expected call of EnsureCompany.
+func (mr *MockUserServiceMockRecorder) EnsureCompany(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureCompany", reflect.TypeOf((*MockUserService)(nil).EnsureCompany), ctx, userID)
+}
+
+// EnsureEmailVerified mocks base method.
+func (m *MockUserService) EnsureEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureEmailVerified", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureEmailVerified indicates an expected call of EnsureEmailVerified.
+func (mr *MockUserServiceMockRecorder) EnsureEmailVerified(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureEmailVerified", reflect.TypeOf((*MockUserService)(nil).EnsureEmailVerified), ctx, userID)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockUserService) ForgotPassword(ctx context.Context, payload *domain.ForgotPasswordPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserServiceMockRecorder) ForgotPassword(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserService)(nil).ForgotPassword), ctx, payload)
+}
+
+// GetProfile mocks base method.
+func (m *MockUserService) GetProfile(ctx context.Context) (*domain.UserProfileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx)
+	ret0, _ := ret[0].(*domain.UserProfileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockUserServiceMockRecorder) GetProfile(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockUserService)(nil).GetProfile), ctx)
+}
+
+// GrantMerchantAdmin mocks base method.
+func (m *MockUserService) GrantMerchantAdmin(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantMerchantAdmin", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantMerchantAdmin indicates an expected call of GrantMerchantAdmin.
+func (mr *MockUserServiceMockRecorder) GrantMerchantAdmin(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantMerchantAdmin", reflect.TypeOf((*MockUserService)(nil).GrantMerchantAdmin), ctx, userID)
+}
+
+// Refresh mocks base method.
+func (m *MockUserService) Refresh(ctx context.Context, payload *domain.RefreshPayload) (*domain.SignInResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, payload)
+	ret0, _ := ret[0].(*domain.SignInResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserServiceMockRecorder) Refresh(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserService)(nil).Refresh), ctx, payload)
+}
+
+// ResendEmailVerification mocks base method.
+func (m *MockUserService) ResendEmailVerification(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendEmailVerification", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendEmailVerification indicates an expected call of ResendEmailVerification.
+func (mr *MockUserServiceMockRecorder) ResendEmailVerification(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendEmailVerification", reflect.TypeOf((*MockUserService)(nil).ResendEmailVerification), ctx)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserService) ResetPassword(ctx context.Context, payload *domain.ResetPasswordPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserServiceMockRecorder) ResetPassword(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserService)(nil).ResetPassword), ctx, payload)
+}
+
 // SignIn mocks base method.
 func (m *MockUserService) SignIn(ctx context.Context, payload *domain.SignInPayload) (*domain.SignInResponse, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +400,92 @@ func (mr *MockUserServiceMockRecorder) SignIn(ctx, payload interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockUserService)(nil).SignIn), ctx, payload)
 }
 
+// SignInTwoFactor mocks base method.
+func (m *MockUserService) SignInTwoFactor(ctx context.Context, payload *domain.SignInTwoFactorPayload) (*domain.SignInResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInTwoFactor", ctx, payload)
+	ret0, _ := ret[0].(*domain.SignInResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignInTwoFactor indicates an expected call of SignInTwoFactor.
+func (mr *MockUserServiceMockRecorder) SignInTwoFactor(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInTwoFactor", reflect.TypeOf((*MockUserService)(nil).SignInTwoFactor), ctx, payload)
+}
+
+// Unlock mocks base method.
+func (m *MockUserService) Unlock(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockUserServiceMockRecorder) Unlock(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockUserService)(nil).Unlock), ctx, userID)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserService) UpdateProfile(ctx context.Context, payload *domain.UpdateProfilePayload, ifMatch string) (*domain.UserProfileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, payload, ifMatch)
+	ret0, _ := ret[0].(*domain.UserProfileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserServiceMockRecorder) UpdateProfile(ctx, payload, ifMatch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserService)(nil).UpdateProfile), ctx, payload, ifMatch)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserService) UpdateRole(ctx context.Context, userID uuid.UUID, payload *domain.RolePayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserServiceMockRecorder) UpdateRole(ctx, userID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserService)(nil).UpdateRole), ctx, userID, payload)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserService) VerifyEmail(ctx context.Context, payload *domain.VerifyEmailPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserServiceMockRecorder) VerifyEmail(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserService)(nil).VerifyEmail), ctx, payload)
+}
+
+// VerifyPassword mocks base method.
+func (m *MockUserService) VerifyPassword(ctx context.Context, userID uuid.UUID, password, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", ctx, userID, password, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *MockUserServiceMockRecorder) VerifyPassword(ctx, userID, password, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*MockUserService)(nil).VerifyPassword), ctx, userID, password, ip)
+}
+
 // MockUserRepository is a mock of UserRepository interface.
 type MockUserRepository struct {
 	ctrl     *gomock.Controller
@@ -154,19 +523,47 @@ func (mr *MockUserRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), ctx, user)
 }
 
-// GetByCPF mocks base method.
-func (m *MockUserRepository) GetByCPF(ctx context.Context, CPF string) (*domain.User, error) {
+// CreateEmailVerification mocks base method.
+func (m *MockUserRepository) CreateEmailVerification(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailVerification", ctx, userID, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEmailVerification indicates an expected call of CreateEmailVerification.
+func (mr *MockUserRepositoryMockRecorder) CreateEmailVerification(ctx, userID, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailVerification", reflect.TypeOf((*MockUserRepository)(nil).CreateEmailVerification), ctx, userID, tokenHash)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockUserRepository) CreatePasswordReset(ctx context.Context, userID uuid.UUID, tokenHash string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCPF", ctx, CPF)
+	ret := m.ctrl.Call(m, "CreatePasswordReset", ctx, userID, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockUserRepositoryMockRecorder) CreatePasswordReset(ctx, userID, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockUserRepository)(nil).CreatePasswordReset), ctx, userID, tokenHash)
+}
+
+// GetByDocument mocks base method.
+func (m *MockUserRepository) GetByDocument(ctx context.Context, document string) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDocument", ctx, document)
 	ret0, _ := ret[0].(*domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByCPF indicates an expected call of GetByCPF.
-func (mr *MockUserRepositoryMockRecorder) GetByCPF(ctx, CPF interface{}) *gomock.Call {
+// GetByDocument indicates an expected call of GetByDocument.
+func (mr *MockUserRepositoryMockRecorder) GetByDocument(ctx, document interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCPF", reflect.TypeOf((*MockUserRepository)(nil).GetByCPF), ctx, CPF)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDocument", reflect.TypeOf((*MockUserRepository)(nil).GetByDocument), ctx, document)
 }
 
 // GetByEmail mocks base method.
@@ -198,3 +595,120 @@ func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, ID interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, ID)
 }
+
+// HoldEmailVerificationResend mocks base method.
+func (m *MockUserRepository) HoldEmailVerificationResend(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldEmailVerificationResend", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HoldEmailVerificationResend indicates an expected call of HoldEmailVerificationResend.
+func (mr *MockUserRepositoryMockRecorder) HoldEmailVerificationResend(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldEmailVerificationResend", reflect.TypeOf((*MockUserRepository)(nil).HoldEmailVerificationResend), ctx, userID)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepositoryMockRecorder) MarkEmailVerified(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepository)(nil).MarkEmailVerified), ctx, userID)
+}
+
+// ReplacePasswordHash mocks base method.
+func (m *MockUserRepository) ReplacePasswordHash(ctx context.Context, userID uuid.UUID, currentHash, passwordHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePasswordHash", ctx, userID, currentHash, passwordHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplacePasswordHash indicates an expected call of ReplacePasswordHash.
+func (mr *MockUserRepositoryMockRecorder) ReplacePasswordHash(ctx, userID, currentHash, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePasswordHash", reflect.TypeOf((*MockUserRepository)(nil).ReplacePasswordHash), ctx, userID, currentHash, passwordHash)
+}
+
+// TakeEmailVerification mocks base method.
+func (m *MockUserRepository) TakeEmailVerification(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeEmailVerification", ctx, tokenHash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeEmailVerification indicates an expected call of TakeEmailVerification.
+func (mr *MockUserRepositoryMockRecorder) TakeEmailVerification(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeEmailVerification", reflect.TypeOf((*MockUserRepository)(nil).TakeEmailVerification), ctx, tokenHash)
+}
+
+// TakePasswordReset mocks base method.
+func (m *MockUserRepository) TakePasswordReset(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakePasswordReset", ctx, tokenHash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakePasswordReset indicates an expected call of TakePasswordReset.
+func (mr *MockUserRepositoryMockRecorder) TakePasswordReset(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakePasswordReset", reflect.TypeOf((*MockUserRepository)(nil).TakePasswordReset), ctx, tokenHash)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, userID, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, userID, passwordHash)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, user *domain.User, version time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, user, version)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserRepositoryMockRecorder) UpdateProfile(ctx, user, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserRepository)(nil).UpdateProfile), ctx, user, version)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role domain.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(ctx, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), ctx, userID, role)
+}
@@ -51,6 +51,34 @@ func (mr *MockWalletHandlerMockRecorder) Create(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletHandler)(nil).Create), arg0)
 }
 
+// GetAll mocks base method.
+func (m *MockWalletHandler) GetAll(arg0 echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWalletHandlerMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWalletHandler)(nil).GetAll), arg0)
+}
+
+// GetStatement mocks base method.
+func (m *MockWalletHandler) GetStatement(arg0 echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockWalletHandlerMockRecorder) GetStatement(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockWalletHandler)(nil).GetStatement), arg0)
+}
+
 // MockWalletService is a mock of WalletService interface.
 type MockWalletService struct {
 	ctrl     *gomock.Controller
@@ -88,6 +116,36 @@ func (mr *MockWalletServiceMockRecorder) Create(ctx, payload interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletService)(nil).Create), ctx, payload)
 }
 
+// GetAll mocks base method.
+func (m *MockWalletService) GetAll(ctx context.Context) ([]*domain.WalletResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*domain.WalletResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWalletServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWalletService)(nil).GetAll), ctx)
+}
+
+// GetStatement mocks base method.
+func (m *MockWalletService) GetStatement(ctx context.Context, currency string, query *domain.StatementQuery) (*domain.StatementResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, currency, query)
+	ret0, _ := ret[0].(*domain.StatementResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockWalletServiceMockRecorder) GetStatement(ctx, currency, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockWalletService)(nil).GetStatement), ctx, currency, query)
+}
+
 // MockWalletRepository is a mock of WalletRepository interface.
 type MockWalletRepository struct {
 	ctrl     *gomock.Controller
@@ -125,17 +183,32 @@ func (mr *MockWalletRepositoryMockRecorder) Create(ctx, wallet interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletRepository)(nil).Create), ctx, wallet)
 }
 
+// GetAllByUserID mocks base method.
+func (m *MockWalletRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID.
+func (mr *MockWalletRepositoryMockRecorder) GetAllByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockWalletRepository)(nil).GetAllByUserID), ctx, userID)
+}
+
 // GetByUserID mocks base method.
-func (m *MockWalletRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.Wallet, error) {
+func (m *MockWalletRepository) GetByUserID(ctx context.Context, userID uuid.UUID, currency string) (*domain.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID, currency)
 	ret0, _ := ret[0].(*domain.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserID indicates an expected call of GetByUserID.
-func (mr *MockWalletRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+func (mr *MockWalletRepositoryMockRecorder) GetByUserID(ctx, userID, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockWalletRepository)(nil).GetByUserID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockWalletRepository)(nil).GetByUserID), ctx, userID, currency)
 }
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/encryption"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type accountRepository struct {
+	i      *do.Injector
+	db     *gorm.DB
+	cipher encryption.Cipher
+}
+
+func NewAccountRepository(i *do.Injector) (domain.AccountRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher, err := do.Invoke[encryption.Cipher](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountRepository{
+		i:      i,
+		db:     db,
+		cipher: cipher,
+	}, nil
+}
+
+// Close stores the pseudonymized user and soft-deletes it along with its
+// wallets and API keys, dropping its contacts, PIN and two-factor secrets.
+// The wallets are locked first so no transfer can credit them while the
+// account closes. Transfers, holds and KYC submissions are kept for
+// regulatory retention.
+func (a *accountRepository) Close(ctx context.Context, user *domain.User) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "account"),
+		slog.String("func", "Close"),
+	)
+
+	log.Info("Initializing close account process", slog.String("userID", user.ID.String()))
+
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallets []*domain.Wallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("userId = ?", user.ID).Find(&wallets).Error; err != nil {
+			return err
+		}
+
+		for _, wallet := range wallets {
+			if wallet.Balance != 0 {
+				return domain.ErrAccountHasBalance
+			}
+		}
+
+		var holds int64
+		if err := tx.Model(&domain.Hold{}).Where("(payerId = ? OR payeeId = ?) AND status = ?", user.ID, user.ID, domain.HoldStatusActive).Count(&holds).Error; err != nil {
+			return err
+		}
+
+		var escrows int64
+		if err := tx.Model(&domain.Transfer{}).Where("(payerId = ? OR payeeId = ?) AND escrowStatus IN ?", user.ID, user.ID, []domain.EscrowStatus{domain.EscrowStatusHeld, domain.EscrowStatusDisputed}).Count(&escrows).Error; err != nil {
+			return err
+		}
+
+		if holds > 0 || escrows > 0 {
+			return domain.ErrAccountHasOpenFunds
+		}
+
+		indexUser(a.cipher, user)
+		if err := tx.Model(user).Select("name", "document", "documentHash", "legalName", "tradeName", "email", "emailHash", "phone", "passwordHash", "emailVerifiedAt").Updates(user).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", user.ID).Delete(&domain.TransactionPIN{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", user.ID).Delete(&domain.TwoFactor{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", user.ID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", user.ID).Delete(&domain.Contact{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", user.ID).Delete(&domain.APIKey{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", user.ID).Delete(&domain.Wallet{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		log.Error("Failed to close account", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Close account process executed successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+// apiKeyTouchInterval bounds how often the last use of a key is written.
+const apiKeyTouchInterval = time.Minute
+
+type apiKeyRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(i *do.Injector) (domain.APIKeyRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKeyRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (a *apiKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create api key process")
+
+	if err := a.db.WithContext(ctx).Create(apiKey).Error; err != nil {
+		log.Error("Failed to create api key", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create api key process executed successfully", slog.String("apiKeyID", apiKey.ID.String()))
+	return nil
+}
+
+func (a *apiKeyRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "GetAllByUserID"),
+	)
+
+	log.Info("Initializing get api keys by userId process")
+
+	var apiKeys []*domain.APIKey
+	if err := a.db.WithContext(ctx).Where("userId = ?", userID).Order("createdAt DESC").Find(&apiKeys).Error; err != nil {
+		log.Error("Failed to get api keys by userId", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get api keys by userId process executed successfully", slog.Int("count", len(apiKeys)))
+	return apiKeys, nil
+}
+
+// GetByPrefix returns the live key with prefix along with its user, or nil
+// when there is none.
+func (a *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "GetByPrefix"),
+	)
+
+	var apiKey domain.APIKey
+	if err := a.db.WithContext(ctx).Preload("User").Where("prefix = ?", prefix).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Api key not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get api key by prefix", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// Touch records the last use of the key, skipping the write when it was
+// already recorded within apiKeyTouchInterval.
+func (a *apiKeyRepository) Touch(ctx context.Context, apiKeyID uuid.UUID, usedAt time.Time) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "Touch"),
+	)
+
+	err := a.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ? AND (lastUsedAt IS NULL OR lastUsedAt < ?)", apiKeyID, usedAt.Add(-apiKeyTouchInterval)).
+		UpdateColumn("lastUsedAt", usedAt).Error
+	if err != nil {
+		log.Error("Failed to touch api key", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (a *apiKeyRepository) Delete(ctx context.Context, userID, apiKeyID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing delete api key process")
+
+	result := a.db.WithContext(ctx).Where("id = ? AND userId = ?", apiKeyID, userID).Delete(&domain.APIKey{})
+	if result.Error != nil {
+		log.Error("Failed to delete api key", slog.String("error", result.Error.Error()))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Warn("Api key not found", slog.String("apiKeyID", apiKeyID.String()))
+		return domain.ErrAPIKeyNotFound
+	}
+
+	log.Info("Delete api key process executed successfully", slog.String("apiKeyID", apiKeyID.String()))
+	return nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type campaignRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewCampaignRepository(i *do.Injector) (domain.CampaignRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (c *campaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "campaign"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing campaign creation process")
+	if err := c.db.WithContext(ctx).Create(campaign).Error; err != nil {
+		log.Error("Failed to create campaign", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create campaign process executed successfully", slog.String("campaignID", campaign.ID.String()))
+	return nil
+}
+
+func (c *campaignRepository) GetAll(ctx context.Context) ([]*domain.Campaign, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "campaign"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing process of obtaining campaigns")
+
+	var campaigns []*domain.Campaign
+	if err := c.db.WithContext(ctx).Preload("Merchants").Order("createdAt DESC").Find(&campaigns).Error; err != nil {
+		log.Error("Failed to get campaigns", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining campaigns executed successfully", slog.Int("count", len(campaigns)))
+	return campaigns, nil
+}
+
+func (c *campaignRepository) GetActiveByMerchantID(ctx context.Context, merchantID uuid.UUID, currency string, now time.Time) ([]*domain.Campaign, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "campaign"),
+		slog.String("func", "GetActiveByMerchantID"),
+	)
+
+	log.Info("Initializing process of obtaining active campaigns by merchant")
+
+	var campaigns []*domain.Campaign
+	err := c.db.WithContext(ctx).
+		Joins("JOIN CampaignMerchant ON CampaignMerchant.campaignId = Campaign.id").
+		Where("CampaignMerchant.merchantId = ? AND Campaign.currency = ? AND Campaign.startsAt <= ? AND Campaign.endsAt > ? AND Campaign.remainingBudget > 0", merchantID, currency, now, now).
+		Find(&campaigns).Error
+	if err != nil {
+		log.Error("Failed to get active campaigns by merchant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining active campaigns by merchant executed successfully", slog.Int("count", len(campaigns)))
+	return campaigns, nil
+}
+
+// GrantReward pays the cashback of transfer under the campaign. The campaign
+// row stays locked until the reward is booked and the budget is only
+// decremented while it still covers the reward, so concurrent transfers can
+// never overspend it. It returns nil when the transfer earns nothing.
+func (c *campaignRepository) GrantReward(ctx context.Context, campaignID uuid.UUID, transfer *domain.Transfer) (*domain.Reward, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "campaign"),
+		slog.String("func", "GrantReward"),
+	)
+
+	log.Info("Initializing grant reward process", slog.String("campaignID", campaignID.String()), slog.String("transferID", transfer.ID.String()))
+
+	var reward *domain.Reward
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var campaign domain.Campaign
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", campaignID).First(&campaign).Error; err != nil {
+			return err
+		}
+
+		if !campaign.IsActive(time.Now().UTC()) {
+			return nil
+		}
+
+		var rewarded float64
+		err := tx.Model(&domain.Reward{}).
+			Where("campaignId = ? AND userId = ?", campaignID, transfer.PayerID).
+			Select("COALESCE(SUM(value - clawedBack), 0)").
+			Scan(&rewarded).Error
+		if err != nil {
+			return err
+		}
+
+		value := campaign.RewardFor(transfer.Value, rewarded)
+		if value <= 0 {
+			return nil
+		}
+
+		available, err := lockAvailableBalance(ctx, tx, campaign.FundingWalletID, campaign.Currency)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrFundingWalletNotFound
+			}
+			return err
+		}
+
+		if available < value {
+			return domain.ErrInsufficientBalance
+		}
+
+		result := tx.Model(&domain.Campaign{}).
+			Where("id = ? AND remainingBudget >= ?", campaignID, value).
+			UpdateColumn("remainingBudget", gorm.Expr("remainingBudget - ?", value))
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected != 1 {
+			return domain.ErrCampaignBudgetExhausted
+		}
+
+		if err := debit(ctx, tx, campaign.FundingWalletID, campaign.Currency, value); err != nil {
+			return err
+		}
+
+		if err := credit(ctx, tx, transfer.PayerID, campaign.Currency, value); err != nil {
+			return err
+		}
+
+		reward = &domain.Reward{
+			ID:         uuid.New(),
+			CampaignID: campaignID,
+			UserID:     transfer.PayerID,
+			TransferID: transfer.ID,
+			Value:      value,
+			CreatedAt:  time.Now().UTC(),
+		}
+
+		return tx.Create(reward).Error
+	})
+	if err != nil {
+		log.Error("Failed to grant reward", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Grant reward process executed successfully", slog.String("campaignID", campaignID.String()))
+	return reward, nil
+}
+
+// clawbackRewards takes back, inside tx, the share of the cashback paid on
+// original that matches what its payer has been given back once value is
+// reversed, and returns it to the funding wallet and to the campaign budget.
+// Like the reversal itself, the payer is debited even if that leaves the
+// wallet negative.
+func clawbackRewards(ctx context.Context, tx *gorm.DB, original *domain.Transfer, value float64) error {
+	var rewards []*domain.Reward
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("transferId = ?", original.ID).Find(&rewards).Error; err != nil {
+		return err
+	}
+
+	if len(rewards) == 0 {
+		return nil
+	}
+
+	reversed, err := reversedValue(ctx, tx, original.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, reward := range rewards {
+		clawback := reward.ClawbackFor(original.Value, reversed+value)
+		if clawback <= 0 {
+			continue
+		}
+
+		var campaign domain.Campaign
+		if err := tx.WithContext(ctx).Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", reward.CampaignID).First(&campaign).Error; err != nil {
+			return err
+		}
+
+		if err := debit(ctx, tx, reward.UserID, campaign.Currency, clawback); err != nil {
+			return err
+		}
+
+		if err := credit(ctx, tx, campaign.FundingWalletID, campaign.Currency, clawback); err != nil {
+			return err
+		}
+
+		if err := tx.WithContext(ctx).Model(&domain.Campaign{}).Unscoped().Where("id = ?", campaign.ID).UpdateColumn("remainingBudget", gorm.Expr("remainingBudget + ?", clawback)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.WithContext(ctx).Model(reward).UpdateColumn("clawedBack", gorm.Expr("clawedBack + ?", clawback)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *campaignRepository) GetRewardsByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Reward, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "campaign"),
+		slog.String("func", "GetRewardsByUserID"),
+	)
+
+	log.Info("Initializing process of obtaining rewards by user ID")
+
+	var rewards []*domain.Reward
+	if err := c.db.WithContext(ctx).Where("userId = ?", userID).Order("createdAt DESC").Find(&rewards).Error; err != nil {
+		log.Error("Failed to get rewards by user id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining rewards by user ID executed successfully", slog.Int("count", len(rewards)))
+	return rewards, nil
+}
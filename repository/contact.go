@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type contactRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewContactRepository(i *do.Injector) (domain.ContactRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contactRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (c *contactRepository) Create(ctx context.Context, contact *domain.Contact) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "contact"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing contact creation process")
+	if err := c.db.WithContext(ctx).Omit("Payee").Create(contact).Error; err != nil {
+		log.Error("Failed to create contact", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create contact process executed successfully")
+	return nil
+}
+
+// Get returns the contact with its user. Contacts of closed accounts are
+// treated as not found.
+func (c *contactRepository) Get(ctx context.Context, userID, contactID uuid.UUID) (*domain.Contact, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "contact"),
+		slog.String("func", "Get"),
+	)
+
+	log.Info("Initializing process of obtaining contact")
+
+	var contact *domain.Contact
+	if err := c.db.WithContext(ctx).InnerJoins("Payee").Where("Contact.userId = ? AND Contact.contactId = ?", userID, contactID).First(&contact).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info("Contact not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get contact", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining contact executed successfully")
+	return contact, nil
+}
+
+func (c *contactRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contact, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "contact"),
+		slog.String("func", "GetAllByUserID"),
+	)
+
+	log.Info("Initializing get contacts by userId process")
+
+	var contacts []*domain.Contact
+	if err := c.db.WithContext(ctx).InnerJoins("Payee").Where("Contact.userId = ?", userID).Find(&contacts).Error; err != nil {
+		log.Error("Failed to get contacts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get contacts by userId process executed successfully", slog.Int("count", len(contacts)))
+	return contacts, nil
+}
+
+// GetPaidByUserID returns the contacts the user transferred to at least once.
+func (c *contactRepository) GetPaidByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Contact, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "contact"),
+		slog.String("func", "GetPaidByUserID"),
+	)
+
+	log.Info("Initializing get paid contacts by userId process")
+
+	var contacts []*domain.Contact
+	if err := c.db.WithContext(ctx).InnerJoins("Payee").Where("Contact.userId = ? AND Contact.transferCount > 0", userID).Find(&contacts).Error; err != nil {
+		log.Error("Failed to get paid contacts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get paid contacts by userId process executed successfully", slog.Int("count", len(contacts)))
+	return contacts, nil
+}
+
+// Update saves the nickname and favorite flag of the contact.
+func (c *contactRepository) Update(ctx context.Context, contact *domain.Contact) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "contact"),
+		slog.String("func", "Update"),
+	)
+
+	log.Info("Initializing update contact process")
+
+	err := c.db.WithContext(ctx).Model(contact).Updates(map[string]any{
+		"nickname":  contact.Nickname,
+		"favorite":  contact.Favorite,
+		"updatedAt": time.Now().UTC(),
+	}).Error
+	if err != nil {
+		log.Error("Failed to update contact", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Update contact process executed successfully")
+	return nil
+}
+
+// Delete removes the contact and reports whether it existed.
+func (c *contactRepository) Delete(ctx context.Context, userID, contactID uuid.UUID) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "contact"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing delete contact process")
+
+	result := c.db.WithContext(ctx).Where("userId = ? AND contactId = ?", userID, contactID).Delete(&domain.Contact{})
+	if result.Error != nil {
+		log.Error("Failed to delete contact", slog.String("error", result.Error.Error()))
+		return false, result.Error
+	}
+
+	log.Info("Delete contact process executed successfully", slog.Int64("rows", result.RowsAffected))
+	return result.RowsAffected > 0, nil
+}
+
+// RecordTransfer counts a transfer from userID to contactID, adding the
+// contact on the first one.
+func (c *contactRepository) RecordTransfer(ctx context.Context, userID, contactID uuid.UUID, at time.Time) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "contact"),
+		slog.String("func", "RecordTransfer"),
+	)
+
+	contact := &domain.Contact{
+		UserID:         userID,
+		ContactID:      contactID,
+		TransferCount:  1,
+		LastTransferAt: &at,
+		CreatedAt:      at,
+		UpdatedAt:      at,
+	}
+
+	err := c.db.WithContext(ctx).Omit("Payee").Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"transferCount":  gorm.Expr("transferCount + 1"),
+			"lastTransferAt": at,
+			"updatedAt":      at,
+		}),
+	}).Create(contact).Error
+	if err != nil {
+		log.Error("Failed to record contact transfer", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Record contact transfer process executed successfully")
+	return nil
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type disputeRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewDisputeRepository(i *do.Injector) (domain.DisputeRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &disputeRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (d *disputeRepository) Create(ctx context.Context, dispute *domain.Dispute) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing dispute creation process")
+	if err := d.db.WithContext(ctx).Omit(clause.Associations).Create(dispute).Error; err != nil {
+		log.Error("Failed to create dispute", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create dispute process executed successfully", slog.String("disputeID", dispute.ID.String()))
+	return nil
+}
+
+func (d *disputeRepository) GetByID(ctx context.Context, disputeID uuid.UUID) (*domain.Dispute, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing process of obtaining dispute by ID")
+
+	var dispute *domain.Dispute
+	if err := d.db.WithContext(ctx).Preload("Evidences").Where("id = ?", disputeID).First(&dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Dispute not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get dispute by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining dispute by id executed successfully")
+	return dispute, nil
+}
+
+func (d *disputeRepository) GetByTransferID(ctx context.Context, transferID uuid.UUID) (*domain.Dispute, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "GetByTransferID"),
+	)
+
+	log.Info("Initializing process of obtaining dispute by transfer ID")
+
+	var dispute *domain.Dispute
+	if err := d.db.WithContext(ctx).Where("transferId = ?", transferID).First(&dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info("No dispute for transfer")
+			return nil, nil
+		}
+
+		log.Error("Failed to get dispute by transfer id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining dispute by transfer ID executed successfully")
+	return dispute, nil
+}
+
+func (d *disputeRepository) Respond(ctx context.Context, disputeID uuid.UUID, response string) (*domain.Dispute, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "Respond"),
+	)
+
+	log.Info("Initializing dispute response process", slog.String("disputeID", disputeID.String()))
+
+	var dispute domain.Dispute
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDispute(tx, disputeID, &dispute); err != nil {
+			return err
+		}
+
+		if dispute.Status != domain.DisputeStatusOpened {
+			return domain.ErrDisputeInvalidTransition
+		}
+
+		dispute.MerchantResponse = response
+		dispute.Status = domain.DisputeStatusMerchantResponded
+
+		return tx.Model(&dispute).Updates(map[string]any{
+			"merchantResponse": dispute.MerchantResponse,
+			"status":           dispute.Status,
+		}).Error
+	})
+	if err != nil {
+		log.Error("Failed to respond dispute", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Dispute response process executed successfully", slog.String("disputeID", disputeID.String()))
+	return &dispute, nil
+}
+
+func (d *disputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "AddEvidence"),
+	)
+
+	log.Info("Initializing add evidence process", slog.String("disputeID", evidence.DisputeID.String()))
+	if err := d.db.WithContext(ctx).Create(evidence).Error; err != nil {
+		log.Error("Failed to add evidence", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Add evidence process executed successfully", slog.String("evidenceID", evidence.ID.String()))
+	return nil
+}
+
+func (d *disputeRepository) GetEvidence(ctx context.Context, disputeID, evidenceID uuid.UUID) (*domain.DisputeEvidence, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "GetEvidence"),
+	)
+
+	log.Info("Initializing process of obtaining evidence")
+
+	var evidence *domain.DisputeEvidence
+	if err := d.db.WithContext(ctx).Where("id = ? AND disputeId = ?", evidenceID, disputeID).First(&evidence).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Evidence not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get evidence", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining evidence executed successfully")
+	return evidence, nil
+}
+
+func (d *disputeRepository) ResolveForMerchant(ctx context.Context, disputeID uuid.UUID) (*domain.Dispute, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "ResolveForMerchant"),
+	)
+
+	log.Info("Initializing resolve dispute for merchant process", slog.String("disputeID", disputeID.String()))
+
+	var dispute domain.Dispute
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDispute(tx, disputeID, &dispute); err != nil {
+			return err
+		}
+
+		if dispute.Status.IsResolved() {
+			return domain.ErrDisputeAlreadyResolved
+		}
+
+		now := time.Now().UTC()
+		dispute.Status = domain.DisputeStatusResolvedForMerchant
+		dispute.ResolvedAt = &now
+
+		return tx.Model(&dispute).Updates(map[string]any{
+			"status":     dispute.Status,
+			"resolvedAt": dispute.ResolvedAt,
+		}).Error
+	})
+	if err != nil {
+		log.Error("Failed to resolve dispute for merchant", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Resolve dispute for merchant process executed successfully", slog.String("disputeID", disputeID.String()))
+	return &dispute, nil
+}
+
+func (d *disputeRepository) ResolveForPayer(ctx context.Context, disputeID uuid.UUID) (*domain.Dispute, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "dispute"),
+		slog.String("func", "ResolveForPayer"),
+	)
+
+	log.Info("Initializing resolve dispute for payer process", slog.String("disputeID", disputeID.String()))
+
+	var dispute domain.Dispute
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDispute(tx, disputeID, &dispute); err != nil {
+			return err
+		}
+
+		if dispute.Status.IsResolved() {
+			return domain.ErrDisputeAlreadyResolved
+		}
+
+		var transfer domain.Transfer
+		if err := lockTransfer(tx, dispute.TransferID, &transfer); err != nil {
+			return err
+		}
+
+		reversed, err := reversedValue(ctx, tx, transfer.ID)
+		if err != nil {
+			return err
+		}
+
+		if remaining := transfer.Value - reversed; remaining > 0 {
+			reversal, err := postReversal(ctx, tx, &transfer, remaining)
+			if err != nil {
+				return err
+			}
+			dispute.ReversalTransferID = &reversal.ID
+		}
+
+		now := time.Now().UTC()
+		dispute.Status = domain.DisputeStatusResolvedForPayer
+		dispute.ResolvedAt = &now
+
+		return tx.Model(&dispute).Updates(map[string]any{
+			"status":             dispute.Status,
+			"reversalTransferId": dispute.ReversalTransferID,
+			"resolvedAt":         dispute.ResolvedAt,
+		}).Error
+	})
+	if err != nil {
+		log.Error("Failed to resolve dispute for payer", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Resolve dispute for payer process executed successfully", slog.String("disputeID", disputeID.String()))
+	return &dispute, nil
+}
+
+func lockDispute(tx *gorm.DB, disputeID uuid.UUID, dispute *domain.Dispute) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", disputeID).First(dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrDisputeNotFound
+		}
+		return err
+	}
+
+	return nil
+}
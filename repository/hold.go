@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type holdRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewHoldRepository(i *do.Injector) (domain.HoldRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &holdRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (h *holdRepository) Create(ctx context.Context, hold *domain.Hold) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "hold"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing hold creation process", slog.String("payerID", hold.PayerID.String()), slog.Float64("amount", hold.Amount))
+
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		available, err := lockAvailableBalance(ctx, tx, hold.PayerID, hold.Currency)
+		if err != nil {
+			return err
+		}
+
+		if available < hold.Amount {
+			return domain.ErrInsufficientBalance
+		}
+
+		return tx.Create(hold).Error
+	})
+	if err != nil {
+		log.Error("Failed to create hold", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create hold process executed successfully", slog.String("holdID", hold.ID.String()))
+	return nil
+}
+
+func (h *holdRepository) GetByID(ctx context.Context, holdID uuid.UUID) (*domain.Hold, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "hold"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing process of obtaining hold by ID")
+
+	var hold *domain.Hold
+	if err := h.db.WithContext(ctx).Where("id = ?", holdID).First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Hold not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get hold by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining hold by id executed successfully")
+	return hold, nil
+}
+
+func (h *holdRepository) GetActiveAmountByPayerID(ctx context.Context, payerID uuid.UUID, currency string) (float64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "hold"),
+		slog.String("func", "GetActiveAmountByPayerID"),
+	)
+
+	log.Info("Initializing process of obtaining active hold amount", slog.String("payerID", payerID.String()))
+
+	held, err := activeHoldAmount(ctx, h.db, payerID, currency)
+	if err != nil {
+		log.Error("Failed to sum active holds", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("Process of obtaining active hold amount executed successfully", slog.Float64("held", held))
+	return held, nil
+}
+
+// Capture moves value of the hold from the payer to the payee, refusing it
+// when it would take the payee over payeeBalanceCap.
+func (h *holdRepository) Capture(ctx context.Context, holdID uuid.UUID, value float64, final bool, payeeBalanceCap *domain.KYCBalanceCap) (*domain.Hold, *domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "hold"),
+		slog.String("func", "Capture"),
+	)
+
+	log.Info("Initializing hold capture process", slog.String("holdID", holdID.String()), slog.Float64("value", value))
+
+	var hold domain.Hold
+	var transfer *domain.Transfer
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", holdID).First(&hold).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrHoldNotFound
+			}
+			return err
+		}
+
+		if hold.Status != domain.HoldStatusActive {
+			return domain.ErrHoldNotActive
+		}
+
+		now := time.Now().UTC()
+		if hold.IsExpired(now) {
+			return domain.ErrHoldExpired
+		}
+
+		if value == 0 {
+			value = hold.Remaining()
+		}
+
+		if value > hold.Remaining() {
+			return domain.ErrHoldCaptureExceedsAmount
+		}
+
+		var payer domain.Wallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("userId = ? AND currency = ?", hold.PayerID, hold.Currency).First(&payer).Error; err != nil {
+			return err
+		}
+
+		if payer.Balance < value {
+			return domain.ErrInsufficientBalance
+		}
+
+		if err := debit(ctx, tx, hold.PayerID, hold.Currency, value); err != nil {
+			return err
+		}
+
+		if err := checkPayeeBalanceCap(ctx, tx, hold.PayeeID, payeeBalanceCap, hold.Currency, value); err != nil {
+			return err
+		}
+
+		if err := credit(ctx, tx, hold.PayeeID, hold.Currency, value); err != nil {
+			return err
+		}
+
+		transfer = &domain.Transfer{
+			ID:            uuid.New(),
+			PayerID:       hold.PayerID,
+			PayeeID:       hold.PayeeID,
+			Currency:      hold.Currency,
+			Value:         value,
+			PayeeCurrency: hold.Currency,
+			PayeeValue:    value,
+			Rate:          1,
+			HoldID:        &hold.ID,
+			CreatedAt:     now,
+		}
+
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+
+		hold.CapturedAmount += value
+		if final || hold.Remaining() <= 0 {
+			hold.Status = domain.HoldStatusCaptured
+		}
+
+		return tx.Model(&hold).Updates(map[string]any{
+			"capturedAmount": hold.CapturedAmount,
+			"status":         hold.Status,
+		}).Error
+	})
+	if err != nil {
+		log.Error("Failed to capture hold", slog.String("holdID", holdID.String()), slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	log.Info("Hold capture process executed successfully", slog.String("holdID", holdID.String()), slog.String("status", string(hold.Status)))
+	return &hold, transfer, nil
+}
+
+func (h *holdRepository) Void(ctx context.Context, holdID uuid.UUID) (*domain.Hold, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "hold"),
+		slog.String("func", "Void"),
+	)
+
+	log.Info("Initializing hold void process", slog.String("holdID", holdID.String()))
+
+	var hold domain.Hold
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", holdID).First(&hold).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrHoldNotFound
+			}
+			return err
+		}
+
+		if hold.Status != domain.HoldStatusActive {
+			return domain.ErrHoldNotActive
+		}
+
+		hold.Status = domain.HoldStatusVoided
+		return tx.Model(&hold).Update("status", hold.Status).Error
+	})
+	if err != nil {
+		log.Error("Failed to void hold", slog.String("holdID", holdID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Hold void process executed successfully", slog.String("holdID", holdID.String()))
+	return &hold, nil
+}
+
+func (h *holdRepository) ExpireActive(ctx context.Context, now time.Time) (int64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "hold"),
+		slog.String("func", "ExpireActive"),
+	)
+
+	log.Info("Initializing expired holds release process")
+
+	result := h.db.WithContext(ctx).
+		Model(&domain.Hold{}).
+		Where("status = ? AND expiresAt <= ?", domain.HoldStatusActive, now).
+		Updates(map[string]any{
+			"status":    domain.HoldStatusExpired,
+			"updatedAt": now,
+		})
+	if result.Error != nil {
+		log.Error("Failed to release expired holds", slog.String("error", result.Error.Error()))
+		return 0, result.Error
+	}
+
+	log.Info("Expired holds release process executed successfully", slog.Int64("released", result.RowsAffected))
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type kycRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewKYCRepository(i *do.Injector) (domain.KYCRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kycRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+// CreateSubmission stores the submission together with its documents.
+func (k *kycRepository) CreateSubmission(ctx context.Context, submission *domain.KYCSubmission) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "kyc"),
+		slog.String("func", "CreateSubmission"),
+	)
+
+	log.Info("Initializing kyc submission creation process")
+	if err := k.db.WithContext(ctx).Create(submission).Error; err != nil {
+		log.Error("Failed to create kyc submission", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create kyc submission process executed successfully", slog.String("submissionID", submission.ID.String()))
+	return nil
+}
+
+func (k *kycRepository) GetPendingByUserID(ctx context.Context, userID uuid.UUID) (*domain.KYCSubmission, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "kyc"),
+		slog.String("func", "GetPendingByUserID"),
+	)
+
+	log.Info("Initializing process of obtaining pending kyc submission")
+
+	var submission *domain.KYCSubmission
+	if err := k.db.WithContext(ctx).Preload("Documents").Where("userId = ? AND status = ?", userID, domain.KYCStatusPending).First(&submission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info("No pending kyc submission")
+			return nil, nil
+		}
+
+		log.Error("Failed to get pending kyc submission", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining pending kyc submission executed successfully")
+	return submission, nil
+}
+
+// GetPendingSubmissions returns the review queue, oldest first.
+func (k *kycRepository) GetPendingSubmissions(ctx context.Context) ([]*domain.KYCSubmission, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "kyc"),
+		slog.String("func", "GetPendingSubmissions"),
+	)
+
+	log.Info("Initializing process of obtaining pending kyc submissions")
+
+	var submissions []*domain.KYCSubmission
+	if err := k.db.WithContext(ctx).Preload("Documents").Where("status = ?", domain.KYCStatusPending).Order("createdAt ASC").Find(&submissions).Error; err != nil {
+		log.Error("Failed to get pending kyc submissions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining pending kyc submissions executed successfully", slog.Int("count", len(submissions)))
+	return submissions, nil
+}
+
+func (k *kycRepository) GetDocument(ctx context.Context, submissionID, documentID uuid.UUID) (*domain.KYCDocument, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "kyc"),
+		slog.String("func", "GetDocument"),
+	)
+
+	log.Info("Initializing process of obtaining kyc document")
+
+	var document *domain.KYCDocument
+	if err := k.db.WithContext(ctx).Where("id = ? AND submissionId = ?", documentID, submissionID).First(&document).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Kyc document not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get kyc document", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining kyc document executed successfully")
+	return document, nil
+}
+
+// Review closes a pending submission and, when it is approved, moves the user
+// to the tier it asked for in the same transaction.
+func (k *kycRepository) Review(ctx context.Context, submissionID uuid.UUID, status domain.KYCStatus, reason string, reviewerID uuid.UUID) (*domain.KYCSubmission, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "kyc"),
+		slog.String("func", "Review"),
+	)
+
+	log.Info("Initializing review kyc submission process", slog.String("submissionID", submissionID.String()))
+
+	var submission domain.KYCSubmission
+	err := k.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", submissionID).First(&submission).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrKYCSubmissionNotFound
+			}
+			return err
+		}
+
+		if submission.UserID == reviewerID {
+			return domain.ErrKYCSelfReview
+		}
+
+		if submission.Status != domain.KYCStatusPending {
+			return domain.ErrKYCSubmissionReviewed
+		}
+
+		now := time.Now().UTC()
+		submission.Status = status
+		submission.RejectionReason = reason
+		submission.ReviewedBy = &reviewerID
+		submission.ReviewedAt = &now
+
+		if err := tx.Model(&submission).Updates(map[string]any{
+			"status":          submission.Status,
+			"rejectionReason": submission.RejectionReason,
+			"reviewedBy":      submission.ReviewedBy,
+			"reviewedAt":      submission.ReviewedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		if status != domain.KYCStatusApproved {
+			return nil
+		}
+
+		return tx.Model(&domain.User{}).Where("id = ?", submission.UserID).Update("kycTier", submission.Tier).Error
+	})
+	if err != nil {
+		log.Error("Failed to review kyc submission", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := k.db.WithContext(ctx).Where("submissionId = ?", submission.ID).Find(&submission.Documents).Error; err != nil {
+		log.Error("Failed to get kyc documents", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Review kyc submission process executed successfully", slog.String("submissionID", submissionID.String()), slog.String("status", string(status)))
+	return &submission, nil
+}
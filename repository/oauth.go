@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type oauthClientRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(i *do.Injector) (domain.OAuthClientRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauthClientRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (o *oauthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthClient"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create oauth client process")
+
+	if err := o.db.WithContext(ctx).Create(client).Error; err != nil {
+		log.Error("Failed to create oauth client", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create oauth client process executed successfully", slog.String("clientID", client.ID.String()))
+	return nil
+}
+
+func (o *oauthClientRepository) GetByID(ctx context.Context, clientID uuid.UUID) (*domain.OAuthClient, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthClient"),
+		slog.String("func", "GetByID"),
+	)
+
+	var client domain.OAuthClient
+	if err := o.db.WithContext(ctx).Where("id = ?", clientID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Oauth client not found", slog.String("clientID", clientID.String()))
+			return nil, nil
+		}
+
+		log.Error("Failed to get oauth client", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+func (o *oauthClientRepository) GetAllByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*domain.OAuthClient, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthClient"),
+		slog.String("func", "GetAllByOwnerID"),
+	)
+
+	log.Info("Initializing get oauth clients by ownerId process")
+
+	var clients []*domain.OAuthClient
+	if err := o.db.WithContext(ctx).Where("ownerId = ?", ownerID).Order("createdAt DESC").Find(&clients).Error; err != nil {
+		log.Error("Failed to get oauth clients by ownerId", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get oauth clients by ownerId process executed successfully", slog.Int("count", len(clients)))
+	return clients, nil
+}
+
+func (o *oauthClientRepository) Delete(ctx context.Context, ownerID, clientID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthClient"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing delete oauth client process")
+
+	result := o.db.WithContext(ctx).Where("id = ? AND ownerId = ?", clientID, ownerID).Delete(&domain.OAuthClient{})
+	if result.Error != nil {
+		log.Error("Failed to delete oauth client", slog.String("error", result.Error.Error()))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Warn("Oauth client not found", slog.String("clientID", clientID.String()))
+		return domain.ErrOAuthClientNotFound
+	}
+
+	log.Info("Delete oauth client process executed successfully", slog.String("clientID", clientID.String()))
+	return nil
+}
+
+type oauthTokenRepository struct {
+	i           *do.Injector
+	redisClient *redis.Client
+}
+
+func NewOAuthTokenRepository(i *do.Injector) (domain.OAuthTokenRepository, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauthTokenRepository{
+		i:           i,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (o *oauthTokenRepository) CreateCode(ctx context.Context, codeHash string, code *domain.OAuthAuthorizationCode) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "CreateCode"),
+	)
+
+	if err := o.set(ctx, o.getCodeKey(codeHash), code, config.Env.OAuthCodeTTL); err != nil {
+		log.Error("Failed to save authorization code", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// TakeCode consumes an authorization code, or returns nil when it is
+// unknown, expired or was already exchanged.
+func (o *oauthTokenRepository) TakeCode(ctx context.Context, codeHash string) (*domain.OAuthAuthorizationCode, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "TakeCode"),
+	)
+
+	var code domain.OAuthAuthorizationCode
+	found, err := o.take(ctx, o.getCodeKey(codeHash), &code)
+	if err != nil {
+		log.Error("Failed to take authorization code", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !found {
+		log.Warn("Authorization code not found")
+		return nil, nil
+	}
+
+	return &code, nil
+}
+
+func (o *oauthTokenRepository) CreateRefreshToken(ctx context.Context, tokenHash string, token *domain.OAuthRefreshToken) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "CreateRefreshToken"),
+	)
+
+	if err := o.set(ctx, o.getRefreshTokenKey(tokenHash), token, config.Env.OAuthRefreshTokenTTL); err != nil {
+		log.Error("Failed to save refresh token", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (o *oauthTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.OAuthRefreshToken, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "GetRefreshToken"),
+	)
+
+	value, err := o.redisClient.Get(ctx, o.getRefreshTokenKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		log.Error("Failed to get refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var token domain.OAuthRefreshToken
+	if err := jsoniter.UnmarshalFromString(value, &token); err != nil {
+		log.Error("Failed to unmarshal refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// TakeRefreshToken consumes a refresh token, or returns nil when it is
+// unknown, expired or was already used.
+func (o *oauthTokenRepository) TakeRefreshToken(ctx context.Context, tokenHash string) (*domain.OAuthRefreshToken, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "TakeRefreshToken"),
+	)
+
+	var token domain.OAuthRefreshToken
+	found, err := o.take(ctx, o.getRefreshTokenKey(tokenHash), &token)
+	if err != nil {
+		log.Error("Failed to take refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !found {
+		log.Warn("Refresh token not found")
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+// RevokeAccessToken denies an access token by its jti until it would have
+// expired anyway.
+func (o *oauthTokenRepository) RevokeAccessToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "RevokeAccessToken"),
+	)
+
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := o.redisClient.Set(ctx, o.getRevokedAccessTokenKey(tokenID), 1, ttl).Err(); err != nil {
+		log.Error("Failed to revoke access token", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (o *oauthTokenRepository) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "IsAccessTokenRevoked"),
+	)
+
+	count, err := o.redisClient.Exists(ctx, o.getRevokedAccessTokenKey(tokenID)).Result()
+	if err != nil {
+		log.Error("Failed to check revoked access token", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RecordGrant indexes the tokens of grant by user and by client until its
+// refresh token would expire.
+func (o *oauthTokenRepository) RecordGrant(ctx context.Context, grant *domain.OAuthIssuedGrant) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "RecordGrant"),
+	)
+
+	data, err := jsoniter.MarshalToString(grant)
+	if err != nil {
+		log.Error("Failed to marshal oauth grant", slog.String("error", err.Error()))
+		return err
+	}
+
+	ttl := config.Env.OAuthRefreshTokenTTL
+	member := &redis.Z{Score: float64(time.Now().Add(ttl).Unix()), Member: data}
+
+	_, err = o.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range []string{o.getUserGrantsKey(grant.UserID), o.getClientGrantsKey(grant.ClientID)} {
+			pipe.ZAdd(ctx, key, member)
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to record oauth grant", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// RevokeUserGrants revokes every token issued to any client for the user
+// and returns how many grants were revoked.
+func (o *oauthTokenRepository) RevokeUserGrants(ctx context.Context, userID uuid.UUID) (int, error) {
+	return o.revokeGrants(ctx, o.getUserGrantsKey(userID))
+}
+
+// RevokeClientGrants revokes every token issued to the client for any user
+// and returns how many grants were revoked.
+func (o *oauthTokenRepository) RevokeClientGrants(ctx context.Context, clientID uuid.UUID) (int, error) {
+	return o.revokeGrants(ctx, o.getClientGrantsKey(clientID))
+}
+
+// revokeGrants denies the access tokens and deletes the refresh tokens of
+// the grants indexed at key, then drops the index. Grants whose refresh
+// token expired are skipped.
+func (o *oauthTokenRepository) revokeGrants(ctx context.Context, key string) (int, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "oauthToken"),
+		slog.String("func", "revokeGrants"),
+	)
+
+	now := time.Now()
+	members, err := o.redisClient.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(now.Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		log.Error("Failed to get oauth grants", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	_, err = o.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, member := range members {
+			var grant domain.OAuthIssuedGrant
+			if err := jsoniter.UnmarshalFromString(member, &grant); err != nil {
+				return err
+			}
+
+			if ttl := grant.AccessExpiresAt.Sub(now); ttl > 0 {
+				pipe.Set(ctx, o.getRevokedAccessTokenKey(grant.AccessTokenID), 1, ttl)
+			}
+			pipe.Del(ctx, o.getRefreshTokenKey(grant.RefreshTokenHash))
+		}
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to revoke oauth grants", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return len(members), nil
+}
+
+func (o *oauthTokenRepository) set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := jsoniter.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return o.redisClient.Set(ctx, key, data, ttl).Err()
+}
+
+func (o *oauthTokenRepository) take(ctx context.Context, key string, value any) (bool, error) {
+	data, err := o.redisClient.GetDel(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := jsoniter.UnmarshalFromString(data, value); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (o *oauthTokenRepository) getCodeKey(codeHash string) string {
+	return fmt.Sprintf("oauth_code_%s", codeHash)
+}
+
+func (o *oauthTokenRepository) getRefreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("oauth_refresh_token_%s", tokenHash)
+}
+
+func (o *oauthTokenRepository) getRevokedAccessTokenKey(tokenID string) string {
+	return fmt.Sprintf("oauth_revoked_access_token_%s", tokenID)
+}
+
+func (o *oauthTokenRepository) getUserGrantsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("oauth_user_grants_%s", userID)
+}
+
+func (o *oauthTokenRepository) getClientGrantsKey(clientID uuid.UUID) string {
+	return fmt.Sprintf("oauth_client_grants_%s", clientID)
+}
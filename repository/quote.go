@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+)
+
+type quoteRepository struct {
+	i           *do.Injector
+	redisClient *redis.Client
+}
+
+func NewQuoteRepository(i *do.Injector) (domain.QuoteRepository, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quoteRepository{
+		i:           i,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (q *quoteRepository) Create(ctx context.Context, quote *domain.Quote) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "quote"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing quote creation process")
+
+	quoteJSON, err := jsoniter.Marshal(quote)
+	if err != nil {
+		log.Error("Failed to marshal quote", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := q.redisClient.Set(ctx, q.getQuoteKey(quote.ID), quoteJSON, time.Until(quote.ExpiresAt)).Err(); err != nil {
+		log.Error("Failed to save quote", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create quote process executed successfully", slog.String("quoteID", quote.ID.String()))
+	return nil
+}
+
+// Take returns the quote and deletes it in the same command, so a quote can
+// only be used once even by concurrent transfers.
+func (q *quoteRepository) Take(ctx context.Context, quoteID uuid.UUID) (*domain.Quote, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "quote"),
+		slog.String("func", "Take"),
+	)
+
+	log.Info("Initializing take quote process", slog.String("quoteID", quoteID.String()))
+
+	quoteJSON, err := q.redisClient.GetDel(ctx, q.getQuoteKey(quoteID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Quote not found or expired")
+			return nil, nil
+		}
+
+		log.Error("Failed to take quote", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var quote domain.Quote
+	if err := jsoniter.UnmarshalFromString(quoteJSON, &quote); err != nil {
+		log.Error("Failed to unmarshal quote", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Take quote process executed successfully")
+	return &quote, nil
+}
+
+// Restore puts back a taken quote that was not used, for the rest of its
+// validity. An expired quote is not restored.
+func (q *quoteRepository) Restore(ctx context.Context, quote *domain.Quote) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "quote"),
+		slog.String("func", "Restore"),
+	)
+
+	ttl := time.Until(quote.ExpiresAt)
+	if ttl <= 0 {
+		log.Info("Quote expired, not restoring it", slog.String("quoteID", quote.ID.String()))
+		return nil
+	}
+
+	quoteJSON, err := jsoniter.Marshal(quote)
+	if err != nil {
+		log.Error("Failed to marshal quote", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := q.redisClient.SetNX(ctx, q.getQuoteKey(quote.ID), quoteJSON, ttl).Err(); err != nil {
+		log.Error("Failed to restore quote", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Restore quote process executed successfully", slog.String("quoteID", quote.ID.String()))
+	return nil
+}
+
+func (q *quoteRepository) getQuoteKey(quoteID uuid.UUID) string {
+	return fmt.Sprintf("quote_%s", quoteID)
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const (
+	refreshTokenUserField   = "userId"
+	refreshTokenFamilyField = "familyId"
+	refreshTokenUsedField   = "usedAt"
+)
+
+type refreshTokenRepository struct {
+	i           *do.Injector
+	redisClient *redis.Client
+}
+
+func NewRefreshTokenRepository(i *do.Injector) (domain.RefreshTokenRepository, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &refreshTokenRepository{
+		i:           i,
+		redisClient: redisClient,
+	}, nil
+}
+
+// Create stores the token and keeps its family alive for as long as the
+// token itself.
+func (r *refreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "refreshToken"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing refresh token creation process", slog.String("familyID", token.FamilyID.String()))
+
+	ttl := time.Until(token.ExpiresAt)
+	tokenKey := r.getTokenKey(token.Hash)
+
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, tokenKey, refreshTokenUserField, token.UserID.String(), refreshTokenFamilyField, token.FamilyID.String())
+		pipe.Expire(ctx, tokenKey, ttl)
+		pipe.Set(ctx, r.getFamilyKey(token.FamilyID), token.UserID.String(), ttl)
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to save refresh token", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create refresh token process executed successfully")
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "refreshToken"),
+		slog.String("func", "GetByHash"),
+	)
+
+	log.Info("Initializing get refresh token process")
+
+	fields, err := r.redisClient.HGetAll(ctx, r.getTokenKey(hash)).Result()
+	if err != nil {
+		log.Error("Failed to get refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		log.Warn("Refresh token not found")
+		return nil, nil
+	}
+
+	userID, err := uuid.Parse(fields[refreshTokenUserField])
+	if err != nil {
+		log.Error("Failed to parse refresh token user", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	familyID, err := uuid.Parse(fields[refreshTokenFamilyField])
+	if err != nil {
+		log.Error("Failed to parse refresh token family", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get refresh token process executed successfully")
+	return &domain.RefreshToken{
+		Hash:     hash,
+		UserID:   userID,
+		FamilyID: familyID,
+	}, nil
+}
+
+// MarkUsed flags the token as rotated. It reports false when the token had
+// already been used, which means it is being replayed.
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, hash string) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "refreshToken"),
+		slog.String("func", "MarkUsed"),
+	)
+
+	first, err := r.redisClient.HSetNX(ctx, r.getTokenKey(hash), refreshTokenUsedField, time.Now().UTC().Unix()).Result()
+	if err != nil {
+		log.Error("Failed to mark refresh token as used", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return first, nil
+}
+
+func (r *refreshTokenRepository) IsFamilyActive(ctx context.Context, familyID uuid.UUID) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "refreshToken"),
+		slog.String("func", "IsFamilyActive"),
+	)
+
+	if err := r.redisClient.Get(ctx, r.getFamilyKey(familyID)).Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+
+		log.Error("Failed to get refresh token family", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "refreshToken"),
+		slog.String("func", "RevokeFamily"),
+	)
+
+	log.Info("Initializing revoke refresh token family process", slog.String("familyID", familyID.String()))
+
+	if err := r.redisClient.Del(ctx, r.getFamilyKey(familyID)).Err(); err != nil {
+		log.Error("Failed to revoke refresh token family", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Revoke refresh token family process executed successfully")
+	return nil
+}
+
+func (r *refreshTokenRepository) getTokenKey(hash string) string {
+	return fmt.Sprintf("refresh_token_%s", hash)
+}
+
+func (r *refreshTokenRepository) getFamilyKey(familyID uuid.UUID) string {
+	return fmt.Sprintf("refresh_family_%s", familyID)
+}
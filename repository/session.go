@@ -40,8 +40,10 @@ func NewSessionRepository(i *do.Injector) (domain.SessionRepository, error) {
 	}, nil
 }
 
+// Create stores the session under its own key and indexes it in the set of
+// sessions of its user. Both expire together after SessionExp hours.
 func (s *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "session"),
 		slog.String("func", "Create"),
 	)
@@ -53,7 +55,16 @@ func (s *sessionRepository) Create(ctx context.Context, session *domain.Session)
 		return err
 	}
 
-	if err := s.redisClient.Set(ctx, s.getSessionKey(session.UserID.String()), sessionJSON, time.Duration(config.Env.SessionExp)*time.Hour).Err(); err != nil {
+	ttl := time.Duration(config.Env.SessionExp) * time.Hour
+	indexKey := s.getUserSessionsKey(session.UserID)
+
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.getSessionKey(session.UserID, session.ID.String()), sessionJSON, ttl)
+		pipe.SAdd(ctx, indexKey, session.ID.String())
+		pipe.Expire(ctx, indexKey, ttl)
+		return nil
+	})
+	if err != nil {
 		log.Error("Failed to save token", slog.String("error", err.Error()))
 		return err
 	}
@@ -61,15 +72,15 @@ func (s *sessionRepository) Create(ctx context.Context, session *domain.Session)
 	return nil
 }
 
-func (s *sessionRepository) GetSession(ctx context.Context, userID uuid.UUID) (*domain.Session, error) {
-	log := slog.With(
+func (s *sessionRepository) GetSession(ctx context.Context, userID, sessionID uuid.UUID) (*domain.Session, error) {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "session"),
 		slog.String("func", "GetSession"),
 	)
 
 	log.Info("Initializing get session process")
 
-	sessionJSON, err := s.redisClient.Get(ctx, s.getSessionKey(userID.String())).Result()
+	sessionJSON, err := s.redisClient.Get(ctx, s.getSessionKey(userID, sessionID.String())).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			log.Warn("session not found")
@@ -90,7 +101,112 @@ func (s *sessionRepository) GetSession(ctx context.Context, userID uuid.UUID) (*
 	return &session, nil
 }
 
-func (s *sessionRepository) getSessionKey(userID string) string {
-	tokenKey := fmt.Sprintf("session_%s", userID)
-	return tokenKey
+// GetAllByUserID returns the live sessions of the user and drops the index
+// entries of the ones that already expired.
+func (s *sessionRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "session"),
+		slog.String("func", "GetAllByUserID"),
+	)
+
+	log.Info("Initializing get sessions by user ID process")
+
+	indexKey := s.getUserSessionsKey(userID)
+	sessionIDs, err := s.redisClient.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		log.Error("Failed to get user sessions index", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	sessions := make([]*domain.Session, 0, len(sessionIDs))
+	if len(sessionIDs) == 0 {
+		return sessions, nil
+	}
+
+	keys := make([]string, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, s.getSessionKey(userID, sessionID))
+	}
+
+	values, err := s.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Error("Failed to get user sessions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var expired []any
+	for index, value := range values {
+		sessionJSON, ok := value.(string)
+		if !ok {
+			expired = append(expired, sessionIDs[index])
+			continue
+		}
+
+		var session domain.Session
+		if err := jsoniter.UnmarshalFromString(sessionJSON, &session); err != nil {
+			log.Error("Failed to unmarshal session data", slog.String("error", err.Error()))
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if len(expired) > 0 {
+		if err := s.redisClient.SRem(ctx, indexKey, expired...).Err(); err != nil {
+			log.Warn("Failed to prune expired sessions", slog.String("error", err.Error()))
+		}
+	}
+
+	log.Info("Get sessions by user ID process executed successfully", slog.Int("count", len(sessions)))
+	return sessions, nil
+}
+
+// Touch saves the session without extending its expiration.
+func (s *sessionRepository) Touch(ctx context.Context, session *domain.Session) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "session"),
+		slog.String("func", "Touch"),
+	)
+
+	sessionJSON, err := jsoniter.Marshal(session)
+	if err != nil {
+		log.Error("Failed to marshal session data", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.redisClient.SetXX(ctx, s.getSessionKey(session.UserID, session.ID.String()), sessionJSON, redis.KeepTTL).Err(); err != nil {
+		log.Error("Failed to touch session", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (s *sessionRepository) Delete(ctx context.Context, userID, sessionID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "session"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing delete session process")
+
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.getSessionKey(userID, sessionID.String()))
+		pipe.SRem(ctx, s.getUserSessionsKey(userID), sessionID.String())
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to delete session", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Delete session process executed successfully")
+	return nil
+}
+
+func (s *sessionRepository) getSessionKey(userID uuid.UUID, sessionID string) string {
+	return fmt.Sprintf("session_%s_%s", userID, sessionID)
+}
+
+func (s *sessionRepository) getUserSessionsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_sessions_%s", userID)
 }
@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/encryption"
+	"github.com/go-redis/redis/v8"
+	"github.com/samber/do"
+)
+
+type signInAttemptRepository struct {
+	i           *do.Injector
+	redisClient *redis.Client
+	cipher      encryption.Cipher
+}
+
+func NewSignInAttemptRepository(i *do.Injector) (domain.SignInAttemptRepository, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher, err := do.Invoke[encryption.Cipher](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signInAttemptRepository{
+		i:           i,
+		redisClient: redisClient,
+		cipher:      cipher,
+	}, nil
+}
+
+// GetStatus reads the lock and delay of the email and the failures of the
+// IP in a single round trip.
+func (s *signInAttemptRepository) GetStatus(ctx context.Context, email, ip string) (*domain.SignInAttemptStatus, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "signInAttempt"),
+		slog.String("func", "GetStatus"),
+	)
+
+	var lock, delay, ipTTL *redis.DurationCmd
+	var ipFailures *redis.StringCmd
+	_, err := s.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		lock = pipe.PTTL(ctx, s.getLockKey(email))
+		delay = pipe.PTTL(ctx, s.getDelayKey(email))
+		ipFailures = pipe.Get(ctx, s.getIPKey(ip))
+		ipTTL = pipe.PTTL(ctx, s.getIPKey(ip))
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Error("Failed to get sign-in attempt status", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	status := &domain.SignInAttemptStatus{
+		Lock:         remainingTTL(lock.Val()),
+		Delay:        remainingTTL(delay.Val()),
+		IPRetryAfter: remainingTTL(ipTTL.Val()),
+	}
+
+	if ipFailures.Err() == nil {
+		status.IPFailures, err = ipFailures.Int64()
+		if err != nil {
+			log.Error("Failed to parse ip failures", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// RegisterFailure counts a failed attempt for both the email and the IP and
+// returns the failures of the email in the current window. Every failure
+// extends the window, so counters only reset after a quiet period.
+func (s *signInAttemptRepository) RegisterFailure(ctx context.Context, email, ip string) (int64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "signInAttempt"),
+		slog.String("func", "RegisterFailure"),
+	)
+
+	log.Info("Initializing register sign-in failure process")
+
+	window := config.Env.SignInAttemptWindow
+	emailKey := s.getFailuresKey(email)
+	ipKey := s.getIPKey(ip)
+
+	var emailFailures *redis.IntCmd
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		emailFailures = pipe.Incr(ctx, emailKey)
+		pipe.Expire(ctx, emailKey, window)
+		if ip != "" {
+			pipe.Incr(ctx, ipKey)
+			pipe.Expire(ctx, ipKey, window)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to register sign-in failure", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	log.Info("Register sign-in failure process executed successfully")
+	return emailFailures.Val(), nil
+}
+
+func (s *signInAttemptRepository) SetDelay(ctx context.Context, email string, delay time.Duration) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "signInAttempt"),
+		slog.String("func", "SetDelay"),
+	)
+
+	if err := s.redisClient.Set(ctx, s.getDelayKey(email), 1, delay).Err(); err != nil {
+		log.Error("Failed to set sign-in delay", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// Lock blocks the email for the duration and starts a fresh failure count
+// for when the lock expires.
+func (s *signInAttemptRepository) Lock(ctx context.Context, email string, duration time.Duration) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "signInAttempt"),
+		slog.String("func", "Lock"),
+	)
+
+	log.Info("Initializing lock sign-in process")
+
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.getLockKey(email), 1, duration)
+		pipe.Del(ctx, s.getFailuresKey(email), s.getDelayKey(email))
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to lock sign-in", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Lock sign-in process executed successfully")
+	return nil
+}
+
+// Reset clears the failures, delay and lock of the email. The IP counter is
+// left alone so one valid account cannot launder guesses against others.
+func (s *signInAttemptRepository) Reset(ctx context.Context, email string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "signInAttempt"),
+		slog.String("func", "Reset"),
+	)
+
+	if err := s.redisClient.Del(ctx, s.getFailuresKey(email), s.getDelayKey(email), s.getLockKey(email)).Err(); err != nil {
+		log.Error("Failed to reset sign-in attempts", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// RegisterPasswordReset counts a password reset request for both the email
+// and the IP. Windows are fixed: they start with the first request and are
+// not extended by later ones. The email is keyed by its blind index so the
+// address never appears in redis.
+func (s *signInAttemptRepository) RegisterPasswordReset(ctx context.Context, email, ip string) (*domain.PasswordResetRequests, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "signInAttempt"),
+		slog.String("func", "RegisterPasswordReset"),
+	)
+
+	log.Info("Initializing register password reset request process")
+
+	window := config.Env.PasswordResetRequestWindow
+	emailKey := s.getPasswordResetKey(email)
+	ipKey := s.getPasswordResetIPKey(ip)
+
+	var emailRequests, ipRequests *redis.IntCmd
+	var emailTTL, ipTTL *redis.DurationCmd
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, emailKey, 0, window)
+		emailRequests = pipe.Incr(ctx, emailKey)
+		emailTTL = pipe.PTTL(ctx, emailKey)
+		if ip != "" {
+			pipe.SetNX(ctx, ipKey, 0, window)
+			ipRequests = pipe.Incr(ctx, ipKey)
+			ipTTL = pipe.PTTL(ctx, ipKey)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to register password reset request", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	requests := &domain.PasswordResetRequests{
+		Email:           emailRequests.Val(),
+		EmailRetryAfter: remainingTTL(emailTTL.Val()),
+	}
+
+	if ip != "" {
+		requests.IP = ipRequests.Val()
+		requests.IPRetryAfter = remainingTTL(ipTTL.Val())
+	}
+
+	log.Info("Register password reset request process executed successfully")
+	return requests, nil
+}
+
+// remainingTTL maps the negative PTTL replies for missing keys or keys without
+// expiration to zero.
+func remainingTTL(ttl time.Duration) time.Duration {
+	if ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+func (s *signInAttemptRepository) getFailuresKey(email string) string {
+	return fmt.Sprintf("sign_in_failures_%s", email)
+}
+
+func (s *signInAttemptRepository) getDelayKey(email string) string {
+	return fmt.Sprintf("sign_in_delay_%s", email)
+}
+
+func (s *signInAttemptRepository) getLockKey(email string) string {
+	return fmt.Sprintf("sign_in_lock_%s", email)
+}
+
+func (s *signInAttemptRepository) getIPKey(ip string) string {
+	return fmt.Sprintf("sign_in_failures_ip_%s", ipBucket(ip))
+}
+
+func (s *signInAttemptRepository) getPasswordResetKey(email string) string {
+	return fmt.Sprintf("password_reset_requests_%s", s.cipher.BlindIndex(email, "email"))
+}
+
+func (s *signInAttemptRepository) getPasswordResetIPKey(ip string) string {
+	return fmt.Sprintf("password_reset_requests_ip_%s", ipBucket(ip))
+}
+
+// ipBucket counts IPv6 clients by their /64, as a single host usually gets
+// the whole prefix and could otherwise pick a new address for every attempt.
+func ipBucket(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return parsed.Mask(net.CIDRMask(64, 128)).String() + "/64"
+	}
+	return ip
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type transactionPINRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewTransactionPINRepository(i *do.Injector) (domain.TransactionPINRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionPINRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (t *transactionPINRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.TransactionPIN, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transactionPIN"),
+		slog.String("func", "GetByUserID"),
+	)
+
+	var pin domain.TransactionPIN
+	if err := t.db.WithContext(ctx).Where("userId = ?", userID).First(&pin).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		log.Error("Failed to get transaction pin", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &pin, nil
+}
+
+// Save creates the PIN of the user or replaces its hash.
+func (t *transactionPINRepository) Save(ctx context.Context, pin *domain.TransactionPIN) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transactionPIN"),
+		slog.String("func", "Save"),
+	)
+
+	log.Info("Initializing save transaction pin process")
+
+	err := t.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "userId"}},
+		DoUpdates: clause.AssignmentColumns([]string{"pinHash", "updatedAt"}),
+	}).Create(pin).Error
+	if err != nil {
+		log.Error("Failed to save transaction pin", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Save transaction pin process executed successfully")
+	return nil
+}
+
+// GetLock returns how long the PIN of the user stays locked, zero when it is
+// not locked.
+func (t *transactionPINRepository) GetLock(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transactionPIN"),
+		slog.String("func", "GetLock"),
+	)
+
+	ttl, err := t.redisClient.PTTL(ctx, t.getLockKey(userID)).Result()
+	if err != nil {
+		log.Error("Failed to get transaction pin lock", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return remainingTTL(ttl), nil
+}
+
+// RegisterFailure counts a wrong PIN and returns the wrong entries in the
+// current window, which lasts as long as a lock.
+func (t *transactionPINRepository) RegisterFailure(ctx context.Context, userID uuid.UUID) (int64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transactionPIN"),
+		slog.String("func", "RegisterFailure"),
+	)
+
+	key := t.getFailuresKey(userID)
+
+	var failures *redis.IntCmd
+	_, err := t.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, config.Env.TransactionPINLockoutDuration)
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to register transaction pin failure", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return failures.Val(), nil
+}
+
+// Lock blocks the PIN for the duration and starts a fresh failure count for
+// when the lock expires.
+func (t *transactionPINRepository) Lock(ctx context.Context, userID uuid.UUID, duration time.Duration) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transactionPIN"),
+		slog.String("func", "Lock"),
+	)
+
+	log.Info("Initializing lock transaction pin process")
+
+	_, err := t.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, t.getLockKey(userID), 1, duration)
+		pipe.Del(ctx, t.getFailuresKey(userID))
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to lock transaction pin", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Lock transaction pin process executed successfully")
+	return nil
+}
+
+// ResetAttempts clears the wrong entry counter and the lock of the user.
+func (t *transactionPINRepository) ResetAttempts(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transactionPIN"),
+		slog.String("func", "ResetAttempts"),
+	)
+
+	if err := t.redisClient.Del(ctx, t.getFailuresKey(userID), t.getLockKey(userID)).Err(); err != nil {
+		log.Error("Failed to reset transaction pin attempts", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (t *transactionPINRepository) getFailuresKey(userID uuid.UUID) string {
+	return fmt.Sprintf("transaction_pin_failures_%s", userID.String())
+}
+
+func (t *transactionPINRepository) getLockKey(userID uuid.UUID) string {
+	return fmt.Sprintf("transaction_pin_lock_%s", userID.String())
+}
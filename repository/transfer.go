@@ -2,13 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/GSVillas/pic-pay-desafio/domain"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/samber/do"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type transferRepository struct {
@@ -36,7 +40,7 @@ func NewTransferRepository(i *do.Injector) (domain.TransferRepository, error) {
 }
 
 func (t *transferRepository) Transfer(ctx context.Context, transfer *domain.Transfer) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "transfer"),
 		slog.String("func", "Transfer"),
 	)
@@ -57,18 +61,39 @@ func (t *transferRepository) Transfer(ctx context.Context, transfer *domain.Tran
 
 	log.Info("Starting to process transfer", slog.String("payerID", transfer.PayerID.String()), slog.String("payeeID", transfer.PayeeID.String()), slog.Float64("value", transfer.Value))
 
-	if err := t.debit(ctx, transfer.PayerID, transfer.Value); err != nil {
+	available, err := lockAvailableBalance(ctx, tx, transfer.PayerID, transfer.Currency)
+	if err != nil {
+		tx.Rollback()
+		log.Error("Failed to lock payer's wallet, transaction rolled back", slog.String("payerID", transfer.PayerID.String()), slog.String("error", err.Error()))
+		return err
+	}
+
+	if available < transfer.Value {
+		tx.Rollback()
+		log.Warn("Insufficient available balance, transaction rolled back", slog.String("payerID", transfer.PayerID.String()), slog.Float64("available", available))
+		return domain.ErrInsufficientBalance
+	}
+
+	if err := debit(ctx, tx, transfer.PayerID, transfer.Currency, transfer.Value); err != nil {
 		tx.Rollback()
 		log.Error("Failed to debit payer's wallet, transaction rolled back", slog.String("payerID", transfer.PayerID.String()), slog.Float64("value", transfer.Value), slog.String("error", err.Error()))
 		return err
 	}
 
-	if err := t.credit(ctx, transfer.PayeeID, transfer.Value); err != nil {
+	if err := checkPayeeBalanceCap(ctx, tx, transfer.PayeeID, transfer.PayeeBalanceCap, transfer.PayeeCurrency, transfer.PayeeValue); err != nil {
 		tx.Rollback()
-		log.Error("Failed to credit payee's wallet, transaction rolled back", slog.String("payeeID", transfer.PayeeID.String()), slog.Float64("value", transfer.Value), slog.String("error", err.Error()))
+		log.Warn("Payee balance limit check failed, transaction rolled back", slog.String("payeeID", transfer.PayeeID.String()), slog.String("error", err.Error()))
 		return err
 	}
 
+	if transfer.EscrowStatus != domain.EscrowStatusHeld {
+		if err := credit(ctx, tx, transfer.PayeeID, transfer.PayeeCurrency, transfer.PayeeValue); err != nil {
+			tx.Rollback()
+			log.Error("Failed to credit payee's wallet, transaction rolled back", slog.String("payeeID", transfer.PayeeID.String()), slog.Float64("value", transfer.PayeeValue), slog.String("error", err.Error()))
+			return err
+		}
+	}
+
 	if err := tx.WithContext(ctx).Create(transfer).Error; err != nil {
 		tx.Rollback()
 		log.Error("Failed to record transfer, transaction rolled back", slog.String("transferID", transfer.ID.String()), slog.String("error", err.Error()))
@@ -84,36 +109,362 @@ func (t *transferRepository) Transfer(ctx context.Context, transfer *domain.Tran
 	return nil
 }
 
-func (t *transferRepository) credit(ctx context.Context, userID uuid.UUID, value float64) error {
-	log := slog.With(
-		slog.String("repository", "wallet"),
-		slog.String("func", "Credit"),
+func (t *transferRepository) GetByID(ctx context.Context, transferID uuid.UUID) (*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing process of obtaining transfer by ID")
+
+	var transfer *domain.Transfer
+	if err := t.db.WithContext(ctx).Where("id = ?", transferID).First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Transfer not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get transfer by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining transfer by id executed successfully")
+	return transfer, nil
+}
+
+func (t *transferRepository) GetDueEscrows(ctx context.Context, now time.Time, limit int) ([]*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "GetDueEscrows"),
+	)
+
+	log.Info("Initializing process of obtaining due escrow transfers")
+
+	var transfers []*domain.Transfer
+	if err := t.db.WithContext(ctx).
+		Where("escrowStatus = ? AND escrowReleaseAt <= ?", domain.EscrowStatusHeld, now).
+		Order("escrowReleaseAt").
+		Limit(limit).
+		Find(&transfers).Error; err != nil {
+		log.Error("Failed to get due escrow transfers", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining due escrow transfers executed successfully", slog.Int("count", len(transfers)))
+	return transfers, nil
+}
+
+func (t *transferRepository) DisputeEscrow(ctx context.Context, transferID uuid.UUID, reason string) (*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "DisputeEscrow"),
+	)
+
+	log.Info("Initializing escrow dispute process", slog.String("transferID", transferID.String()))
+
+	var transfer domain.Transfer
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockTransfer(tx, transferID, &transfer); err != nil {
+			return err
+		}
+
+		if transfer.EscrowStatus != domain.EscrowStatusHeld {
+			return domain.ErrEscrowNotHeld
+		}
+
+		transfer.EscrowStatus = domain.EscrowStatusDisputed
+		transfer.EscrowDisputeReason = reason
+
+		return tx.Model(&transfer).Updates(map[string]any{
+			"escrowStatus":        transfer.EscrowStatus,
+			"escrowDisputeReason": transfer.EscrowDisputeReason,
+		}).Error
+	})
+	if err != nil {
+		log.Error("Failed to dispute escrow transfer", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Escrow dispute process executed successfully", slog.String("transferID", transferID.String()))
+	return &transfer, nil
+}
+
+// ReleaseEscrow credits the escrowed value to the payee, refusing it when it
+// would take them over payeeBalanceCap. The funds then stay in escrow.
+func (t *transferRepository) ReleaseEscrow(ctx context.Context, transferID uuid.UUID, payeeBalanceCap *domain.KYCBalanceCap, from ...domain.EscrowStatus) (*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "ReleaseEscrow"),
+	)
+
+	log.Info("Initializing escrow release process", slog.String("transferID", transferID.String()))
+
+	transfer, err := t.settleEscrow(ctx, transferID, domain.EscrowStatusReleased, from, payeeBalanceCap)
+	if err != nil {
+		log.Error("Failed to release escrow transfer", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Escrow release process executed successfully", slog.String("transferID", transferID.String()))
+	return transfer, nil
+}
+
+func (t *transferRepository) RefundEscrow(ctx context.Context, transferID uuid.UUID, from ...domain.EscrowStatus) (*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "RefundEscrow"),
+	)
+
+	log.Info("Initializing escrow refund process", slog.String("transferID", transferID.String()))
+
+	transfer, err := t.settleEscrow(ctx, transferID, domain.EscrowStatusRefunded, from, nil)
+	if err != nil {
+		log.Error("Failed to refund escrow transfer", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Escrow refund process executed successfully", slog.String("transferID", transferID.String()))
+	return transfer, nil
+}
+
+// settleEscrow moves the escrowed value to the payee (released) or back to
+// the payer (refunded), provided the transfer is still in one of the from
+// statuses once its row is locked. Releases are checked against
+// payeeBalanceCap, as the payee may have received other funds since the
+// transfer was made.
+func (t *transferRepository) settleEscrow(ctx context.Context, transferID uuid.UUID, to domain.EscrowStatus, from []domain.EscrowStatus, payeeBalanceCap *domain.KYCBalanceCap) (*domain.Transfer, error) {
+	var transfer domain.Transfer
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockTransfer(tx, transferID, &transfer); err != nil {
+			return err
+		}
+
+		if !slices.Contains(from, transfer.EscrowStatus) {
+			if transfer.EscrowStatus == domain.EscrowStatusHeld {
+				return domain.ErrEscrowNotDisputed
+			}
+			return domain.ErrEscrowNotHeld
+		}
+
+		beneficiary := transfer.PayeeID
+		currency, value := transfer.PayeeAmount(transfer.Value)
+		if to == domain.EscrowStatusRefunded {
+			beneficiary = transfer.PayerID
+			currency, value = transfer.Currency, transfer.Value
+		} else if err := checkPayeeBalanceCap(ctx, tx, beneficiary, payeeBalanceCap, currency, value); err != nil {
+			return err
+		}
+
+		if err := credit(ctx, tx, beneficiary, currency, value); err != nil {
+			return err
+		}
+
+		transfer.EscrowStatus = to
+		return tx.Model(&transfer).Update("escrowStatus", transfer.EscrowStatus).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// Refund gives value of the transfer, in its source currency, back from the
+// payee to the payer. A zero value refunds whatever is left after previous
+// refunds and reversals. Unlike dispute reversals, the payee must have the
+// funds available.
+func (t *transferRepository) Refund(ctx context.Context, transferID uuid.UUID, value float64) (*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "Refund"),
 	)
 
-	log.Info("Starting to credit value to user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
+	log.Info("Initializing refund process", slog.String("transferID", transferID.String()))
+
+	var reversal *domain.Transfer
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var transfer domain.Transfer
+		if err := lockTransfer(tx, transferID, &transfer); err != nil {
+			return err
+		}
+
+		if !transfer.IsRefundable() {
+			return domain.ErrRefundNotAllowed
+		}
+
+		reversed, err := reversedValue(ctx, tx, transfer.ID)
+		if err != nil {
+			return err
+		}
+
+		remaining := transfer.Value - reversed
+		if value == 0 {
+			value = remaining
+		}
+
+		if value <= 0 || value > remaining {
+			return domain.ErrRefundExceedsValue
+		}
+
+		payeeCurrency, payeeValue := transfer.PayeeAmount(value)
+		available, err := lockAvailableBalance(ctx, tx, transfer.PayeeID, payeeCurrency)
+		if err != nil {
+			return err
+		}
+
+		if available < payeeValue {
+			return domain.ErrInsufficientBalance
+		}
 
-	if err := t.db.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ?", userID).UpdateColumn("balance", gorm.Expr("balance + ?", value)).Error; err != nil {
-		log.Error("Failed to credit value to wallet", slog.String("error", err.Error()))
+		reversal, err = postReversal(ctx, tx, &transfer, value)
 		return err
+	})
+	if err != nil {
+		log.Error("Failed to refund transfer", slog.String("error", err.Error()))
+		return nil, err
 	}
 
-	log.Info("Successfully credited value to user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
-	return nil
+	log.Info("Refund process executed successfully", slog.String("transferID", transferID.String()), slog.String("reversalID", reversal.ID.String()))
+	return reversal, nil
 }
 
-func (t *transferRepository) debit(ctx context.Context, userID uuid.UUID, value float64) error {
-	log := slog.With(
-		slog.String("repository", "wallet"),
-		slog.String("func", "Debit"),
+// GetStatement returns the transfers that moved money in or out of the
+// wallet of userID in currency, newest first.
+func (t *transferRepository) GetStatement(ctx context.Context, userID uuid.UUID, currency string, query *domain.StatementQuery) ([]*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "GetStatement"),
+	)
+
+	log.Info("Initializing get statement process", slog.String("currency", currency))
+
+	db := t.db.WithContext(ctx).
+		Where("(payerId = ? AND currency = ?) OR (payeeId = ? AND COALESCE(NULLIF(payeeCurrency, ''), currency) = ?)", userID, currency, userID, currency)
+
+	if query.From != nil {
+		db = db.Where("createdAt >= ?", query.From.UTC())
+	}
+
+	if query.To != nil {
+		db = db.Where("createdAt < ?", query.To.UTC())
+	}
+
+	var transfers []*domain.Transfer
+	if err := db.Order("createdAt DESC").Limit(query.Limit).Find(&transfers).Error; err != nil {
+		log.Error("Failed to get statement", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get statement process executed successfully", slog.Int("count", len(transfers)))
+	return transfers, nil
+}
+
+// GetAllByUserID returns every transfer the user sent or received, in any
+// currency, oldest first.
+func (t *transferRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Transfer, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "transfer"),
+		slog.String("func", "GetAllByUserID"),
 	)
 
-	log.Info("Starting to debit value from user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
+	log.Info("Initializing get transfers by userId process")
 
-	if err := t.db.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ?", userID).UpdateColumn("balance", gorm.Expr("balance - ?", value)).Error; err != nil {
-		log.Error("Failed to debit value from wallet", slog.String("error", err.Error()))
+	var transfers []*domain.Transfer
+	if err := t.db.WithContext(ctx).Where("payerId = ? OR payeeId = ?", userID, userID).Order("createdAt ASC").Find(&transfers).Error; err != nil {
+		log.Error("Failed to get transfers", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get transfers by userId process executed successfully", slog.Int("count", len(transfers)))
+	return transfers, nil
+}
+
+// checkPayeeBalanceCap locks every wallet of payeeID and refuses the credit
+// of value in currency when it would take them over balanceCap, so two
+// concurrent credits cannot both pass the check.
+func checkPayeeBalanceCap(ctx context.Context, tx *gorm.DB, payeeID uuid.UUID, balanceCap *domain.KYCBalanceCap, currency string, value float64) error {
+	if balanceCap == nil {
+		return nil
+	}
+
+	var wallets []*domain.Wallet
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("userId = ?", payeeID).Find(&wallets).Error; err != nil {
 		return err
 	}
 
-	log.Info("Successfully debited value from user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
+	if !balanceCap.Allows(wallets, currency, value) {
+		return domain.ErrKYCBalanceLimitExceeded
+	}
+
 	return nil
 }
+
+func lockTransfer(tx *gorm.DB, transferID uuid.UUID, transfer *domain.Transfer) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", transferID).First(transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrTransferNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// postReversal moves value, in the original source currency, from the
+// original payee back to the original payer inside tx and records it as a
+// transfer pointing to the original one. The payee is debited at the rate of
+// the original transfer, even if that leaves the wallet negative, since the
+// funds were already received. The cashback paid on the original transfer is
+// clawed back in the same proportion.
+func postReversal(ctx context.Context, tx *gorm.DB, original *domain.Transfer, value float64) (*domain.Transfer, error) {
+	if err := clawbackRewards(ctx, tx, original, value); err != nil {
+		return nil, err
+	}
+
+	payeeCurrency, payeeValue := original.PayeeAmount(value)
+
+	if err := debit(ctx, tx, original.PayeeID, payeeCurrency, payeeValue); err != nil {
+		return nil, err
+	}
+
+	if err := credit(ctx, tx, original.PayerID, original.Currency, value); err != nil {
+		return nil, err
+	}
+
+	rate := 1.0
+	if payeeValue > 0 {
+		rate = value / payeeValue
+	}
+
+	reversal := &domain.Transfer{
+		ID:            uuid.New(),
+		PayerID:       original.PayeeID,
+		PayeeID:       original.PayerID,
+		Currency:      payeeCurrency,
+		Value:         payeeValue,
+		PayeeCurrency: original.Currency,
+		PayeeValue:    value,
+		Rate:          rate,
+		ReversalOfID:  &original.ID,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if err := tx.WithContext(ctx).Create(reversal).Error; err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// reversedValue sums, in the source currency of the original transfer, what
+// refunds and dispute reversals already gave back to its payer.
+func reversedValue(ctx context.Context, tx *gorm.DB, transferID uuid.UUID) (float64, error) {
+	var reversed float64
+	err := tx.WithContext(ctx).
+		Model(&domain.Transfer{}).
+		Where("reversalOfId = ?", transferID).
+		Select("COALESCE(SUM(payeeValue), 0)").
+		Scan(&reversed).Error
+
+	return reversed, err
+}
@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type twoFactorRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewTwoFactorRepository(i *do.Injector) (domain.TwoFactorRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactorRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (t *twoFactorRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.TwoFactor, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "GetByUserID"),
+	)
+
+	var twoFactor domain.TwoFactor
+	if err := t.db.WithContext(ctx).Where("userId = ?", userID).First(&twoFactor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		log.Error("Failed to get two-factor settings", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &twoFactor, nil
+}
+
+// Save stores a pending enrollment, replacing any previous pending secret.
+func (t *twoFactorRepository) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "Save"),
+	)
+
+	log.Info("Initializing save two-factor enrollment process")
+
+	err := t.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "userId"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabledAt", "lastUsedStep", "createdAt"}),
+	}).Create(twoFactor).Error
+	if err != nil {
+		log.Error("Failed to save two-factor enrollment", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Save two-factor enrollment process executed successfully")
+	return nil
+}
+
+// Enable confirms a pending enrollment at the time step of its first code
+// and replaces the recovery codes of the user.
+func (t *twoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, codes []*domain.RecoveryCode) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "Enable"),
+	)
+
+	log.Info("Initializing enable two-factor process")
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.TwoFactor{}).
+			Where("userId = ? AND enabledAt IS NULL", userID).
+			Updates(map[string]any{"enabledAt": time.Now().UTC(), "lastUsedStep": step})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected != 1 {
+			return domain.ErrTwoFactorAlreadyEnabled
+		}
+
+		if err := tx.Where("userId = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(codes).Error
+	})
+	if err != nil {
+		log.Error("Failed to enable two-factor", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Enable two-factor process executed successfully")
+	return nil
+}
+
+func (t *twoFactorRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing delete two-factor process")
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("userId = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("userId = ?", userID).Delete(&domain.TwoFactor{}).Error
+	})
+	if err != nil {
+		log.Error("Failed to delete two-factor", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Delete two-factor process executed successfully")
+	return nil
+}
+
+// UseStep records step as the last accepted TOTP step. It reports false when
+// that step or a later one was already used.
+func (t *twoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "UseStep"),
+	)
+
+	result := t.db.WithContext(ctx).Model(&domain.TwoFactor{}).
+		Where("userId = ? AND lastUsedStep < ?", userID, step).
+		UpdateColumn("lastUsedStep", step)
+	if result.Error != nil {
+		log.Error("Failed to use two-factor step", slog.String("error", result.Error.Error()))
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (t *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "UseRecoveryCode"),
+	)
+
+	result := t.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("userId = ? AND codeHash = ? AND usedAt IS NULL", userID, codeHash).
+		UpdateColumn("usedAt", time.Now().UTC())
+	if result.Error != nil {
+		log.Error("Failed to use recovery code", slog.String("error", result.Error.Error()))
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (t *twoFactorRepository) CreateChallenge(ctx context.Context, tokenHash string, challenge *domain.SignInChallenge) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "CreateChallenge"),
+	)
+
+	challengeJSON, err := jsoniter.Marshal(challenge)
+	if err != nil {
+		log.Error("Failed to marshal sign-in challenge", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.redisClient.Set(ctx, t.getChallengeKey(tokenHash), challengeJSON, config.Env.SignInChallengeTTL).Err(); err != nil {
+		log.Error("Failed to save sign-in challenge", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (t *twoFactorRepository) GetChallenge(ctx context.Context, tokenHash string) (*domain.SignInChallenge, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "GetChallenge"),
+	)
+
+	challengeJSON, err := t.redisClient.Get(ctx, t.getChallengeKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		log.Error("Failed to get sign-in challenge", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var challenge domain.SignInChallenge
+	if err := jsoniter.UnmarshalFromString(challengeJSON, &challenge); err != nil {
+		log.Error("Failed to unmarshal sign-in challenge", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// IncrementChallengeAttempts counts one more code tried against the
+// challenge and returns the total so far.
+func (t *twoFactorRepository) IncrementChallengeAttempts(ctx context.Context, tokenHash string) (int64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "IncrementChallengeAttempts"),
+	)
+
+	attemptsKey := t.getChallengeAttemptsKey(tokenHash)
+
+	var incr *redis.IntCmd
+	_, err := t.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, attemptsKey)
+		pipe.Expire(ctx, attemptsKey, config.Env.SignInChallengeTTL)
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to count sign-in challenge attempt", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (t *twoFactorRepository) DeleteChallenge(ctx context.Context, tokenHash string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "DeleteChallenge"),
+	)
+
+	if err := t.redisClient.Del(ctx, t.getChallengeKey(tokenHash), t.getChallengeAttemptsKey(tokenHash)).Err(); err != nil {
+		log.Error("Failed to delete sign-in challenge", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetLock returns how long the two-factor codes of the user stay locked,
+// zero when they are not locked.
+func (t *twoFactorRepository) GetLock(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "GetLock"),
+	)
+
+	ttl, err := t.redisClient.PTTL(ctx, t.getLockKey(userID)).Result()
+	if err != nil {
+		log.Error("Failed to get two-factor lock", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return remainingTTL(ttl), nil
+}
+
+// RegisterFailure counts a wrong code sent outside a sign-in challenge and
+// returns the wrong codes in the current window, which lasts as long as a
+// lock.
+func (t *twoFactorRepository) RegisterFailure(ctx context.Context, userID uuid.UUID) (int64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "RegisterFailure"),
+	)
+
+	key := t.getFailuresKey(userID)
+
+	var failures *redis.IntCmd
+	_, err := t.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, config.Env.TwoFactorLockoutDuration)
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to register two-factor failure", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	return failures.Val(), nil
+}
+
+// Lock blocks the two-factor codes of the user for the duration and starts a
+// fresh failure count for when the lock expires.
+func (t *twoFactorRepository) Lock(ctx context.Context, userID uuid.UUID, duration time.Duration) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "Lock"),
+	)
+
+	log.Info("Initializing lock two-factor process")
+
+	_, err := t.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, t.getLockKey(userID), 1, duration)
+		pipe.Del(ctx, t.getFailuresKey(userID))
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to lock two-factor", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Lock two-factor process executed successfully")
+	return nil
+}
+
+// ResetAttempts clears the wrong code counter and the lock of the user.
+func (t *twoFactorRepository) ResetAttempts(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "ResetAttempts"),
+	)
+
+	if err := t.redisClient.Del(ctx, t.getFailuresKey(userID), t.getLockKey(userID)).Err(); err != nil {
+		log.Error("Failed to reset two-factor attempts", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (t *twoFactorRepository) getChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("sign_in_challenge_%s", tokenHash)
+}
+
+func (t *twoFactorRepository) getChallengeAttemptsKey(tokenHash string) string {
+	return fmt.Sprintf("sign_in_challenge_attempts_%s", tokenHash)
+}
+
+func (t *twoFactorRepository) getFailuresKey(userID uuid.UUID) string {
+	return fmt.Sprintf("two_factor_failures_%s", userID.String())
+}
+
+func (t *twoFactorRepository) getLockKey(userID uuid.UUID) string {
+	return fmt.Sprintf("two_factor_lock_%s", userID.String())
+}
@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/encryption"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/samber/do"
@@ -16,6 +20,7 @@ type userRepository struct {
 	i           *do.Injector
 	db          *gorm.DB
 	redisCLient *redis.Client
+	cipher      encryption.Cipher
 }
 
 func NewUserRepository(i *do.Injector) (domain.UserRepository, error) {
@@ -29,20 +34,27 @@ func NewUserRepository(i *do.Injector) (domain.UserRepository, error) {
 		return nil, err
 	}
 
+	cipher, err := do.Invoke[encryption.Cipher](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &userRepository{
 		i:           i,
 		db:          db,
 		redisCLient: redisClient,
+		cipher:      cipher,
 	}, nil
 }
 
 func (u *userRepository) Create(ctx context.Context, user *domain.User) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "user"),
 		slog.String("func", "Create"),
 	)
 
 	log.Info("Initializing user creation process")
+	indexUser(u.cipher, user)
 	if err := u.db.WithContext(ctx).Create(&user).Error; err != nil {
 		log.Error("Failed to create user", slog.String("error", err.Error()))
 		return err
@@ -53,7 +65,7 @@ func (u *userRepository) Create(ctx context.Context, user *domain.User) error {
 }
 
 func (u *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "user"),
 		slog.String("func", "GetByEmail"),
 	)
@@ -61,7 +73,10 @@ func (u *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	log.Info("Initializing process of obtaining user by email")
 
 	var user *domain.User
-	if err := u.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+	err := u.db.WithContext(ctx).
+		Where("emailHash = ? OR (emailHash IS NULL AND email = ?)", u.cipher.BlindIndex(email, "email"), email).
+		First(&user).Error
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Warn("User not found")
 			return nil, nil
@@ -75,31 +90,34 @@ func (u *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return user, nil
 }
 
-func (u *userRepository) GetByCPF(ctx context.Context, CPF string) (*domain.User, error) {
-	log := slog.With(
+func (u *userRepository) GetByDocument(ctx context.Context, document string) (*domain.User, error) {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "user"),
-		slog.String("func", "GetByEmail"),
+		slog.String("func", "GetByDocument"),
 	)
 
-	log.Info("Initializing process of obtaining user by CPF")
+	log.Info("Initializing process of obtaining user by document")
 
 	var user *domain.User
-	if err := u.db.WithContext(ctx).Where("cpf = ?", CPF).First(&user).Error; err != nil {
+	err := u.db.WithContext(ctx).
+		Where("documentHash = ? OR (documentHash IS NULL AND document = ?)", u.cipher.BlindIndex(document, "document"), document).
+		First(&user).Error
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Warn("User not found")
 			return nil, nil
 		}
 
-		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		log.Error("Failed to get user by document", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	log.Info("Process of obtaining user by cpf executed successfully")
+	log.Info("Process of obtaining user by document executed successfully")
 	return user, nil
 }
 
 func (u *userRepository) GetByID(ctx context.Context, ID uuid.UUID) (*domain.User, error) {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "user"),
 		slog.String("func", "GetByID"),
 	)
@@ -120,3 +138,316 @@ func (u *userRepository) GetByID(ctx context.Context, ID uuid.UUID) (*domain.Use
 	log.Info("Process of obtaining user by id executed successfully")
 	return user, nil
 }
+
+func (u *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdatePassword"),
+	)
+
+	log.Info("Initializing update password process")
+
+	result := u.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]any{"passwordHash": passwordHash, "updatedAt": time.Now().UTC()})
+	if result.Error != nil {
+		log.Error("Failed to update password", slog.String("error", result.Error.Error()))
+		return result.Error
+	}
+
+	if result.RowsAffected != 1 {
+		log.Warn("User not found to update password")
+		return domain.ErrUserNotFound
+	}
+
+	log.Info("Update password process executed successfully")
+	return nil
+}
+
+// ReplacePasswordHash swaps the hash of the same password for a new one,
+// only while the stored hash is still currentHash so a password changed in
+// the meantime is not overwritten.
+func (u *userRepository) ReplacePasswordHash(ctx context.Context, userID uuid.UUID, currentHash, passwordHash string) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "ReplacePasswordHash"),
+	)
+
+	result := u.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND passwordHash = ?", userID, currentHash).
+		Update("passwordHash", passwordHash)
+	if result.Error != nil {
+		log.Error("Failed to replace password hash", slog.String("error", result.Error.Error()))
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// CreatePasswordReset stores the hash of a reset token for the user. A user
+// has at most one live token: issuing a new one discards the previous.
+func (u *userRepository) CreatePasswordReset(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "CreatePasswordReset"),
+	)
+
+	log.Info("Initializing create password reset process")
+
+	userKey := u.getPasswordResetUserKey(userID)
+	previous, err := u.redisCLient.Get(ctx, userKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Error("Failed to get previous password reset", slog.String("error", err.Error()))
+		return err
+	}
+
+	ttl := config.Env.PasswordResetTTL
+	_, err = u.redisCLient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, u.getPasswordResetKey(previous))
+		}
+		pipe.Set(ctx, u.getPasswordResetKey(tokenHash), userID.String(), ttl)
+		pipe.Set(ctx, userKey, tokenHash, ttl)
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to save password reset", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create password reset process executed successfully")
+	return nil
+}
+
+// TakePasswordReset consumes a reset token and returns its user, or
+// uuid.Nil when the token is unknown, expired or already used.
+func (u *userRepository) TakePasswordReset(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "TakePasswordReset"),
+	)
+
+	log.Info("Initializing take password reset process")
+
+	value, err := u.redisCLient.GetDel(ctx, u.getPasswordResetKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Password reset not found")
+			return uuid.Nil, nil
+		}
+
+		log.Error("Failed to take password reset", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		log.Error("Failed to parse password reset user", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+
+	if err := u.redisCLient.Del(ctx, u.getPasswordResetUserKey(userID)).Err(); err != nil {
+		log.Warn("Failed to clear password reset of user", slog.String("error", err.Error()))
+	}
+
+	log.Info("Take password reset process executed successfully")
+	return userID, nil
+}
+
+// MarkEmailVerified records that the user proved ownership of the email.
+// Users already verified keep their original verification time.
+func (u *userRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "MarkEmailVerified"),
+	)
+
+	log.Info("Initializing mark email verified process")
+
+	now := time.Now().UTC()
+	err := u.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND emailVerifiedAt IS NULL", userID).
+		Updates(map[string]any{"emailVerifiedAt": now, "updatedAt": now}).Error
+	if err != nil {
+		log.Error("Failed to mark email verified", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Mark email verified process executed successfully")
+	return nil
+}
+
+func (u *userRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role domain.Role) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	log.Info("Initializing update role process")
+
+	result := u.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]any{"role": role, "updatedAt": time.Now().UTC()})
+	if result.Error != nil {
+		log.Error("Failed to update role", slog.String("error", result.Error.Error()))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Warn("User not found", slog.String("userID", userID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	log.Info("Update role process executed successfully", slog.String("role", string(role)))
+	return nil
+}
+
+// UpdateProfile saves the name, email, phone and email verification of the
+// user only if the profile is still at version, its previous UpdatedAt. It
+// reports false when another update got there first.
+func (u *userRepository) UpdateProfile(ctx context.Context, user *domain.User, version time.Time) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdateProfile"),
+	)
+
+	log.Info("Initializing update profile process")
+
+	query := u.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", user.ID)
+	if version.IsZero() {
+		query = query.Where("updatedAt IS NULL")
+	} else {
+		query = query.Where("updatedAt = ?", version)
+	}
+
+	// A struct update, unlike a map, goes through the field serializers so
+	// the email and phone are stored encrypted.
+	indexUser(u.cipher, user)
+	result := query.Select("name", "email", "emailHash", "phone", "emailVerifiedAt", "updatedAt").UpdateColumns(user)
+	if result.Error != nil {
+		log.Error("Failed to update profile", slog.String("error", result.Error.Error()))
+		return false, result.Error
+	}
+
+	log.Info("Update profile process executed successfully", slog.Int64("rowsAffected", result.RowsAffected))
+	return result.RowsAffected == 1, nil
+}
+
+// CreateEmailVerification stores the hash of a verification token for the
+// user. As with password resets, issuing a new token discards the previous.
+func (u *userRepository) CreateEmailVerification(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "CreateEmailVerification"),
+	)
+
+	log.Info("Initializing create email verification process")
+
+	userKey := u.getEmailVerificationUserKey(userID)
+	previous, err := u.redisCLient.Get(ctx, userKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Error("Failed to get previous email verification", slog.String("error", err.Error()))
+		return err
+	}
+
+	ttl := config.Env.EmailVerificationTTL
+	_, err = u.redisCLient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, u.getEmailVerificationKey(previous))
+		}
+		pipe.Set(ctx, u.getEmailVerificationKey(tokenHash), userID.String(), ttl)
+		pipe.Set(ctx, userKey, tokenHash, ttl)
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to save email verification", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Create email verification process executed successfully")
+	return nil
+}
+
+// TakeEmailVerification consumes a verification token and returns its user,
+// or uuid.Nil when the token is unknown, expired or already used.
+func (u *userRepository) TakeEmailVerification(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "TakeEmailVerification"),
+	)
+
+	log.Info("Initializing take email verification process")
+
+	value, err := u.redisCLient.GetDel(ctx, u.getEmailVerificationKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Email verification not found")
+			return uuid.Nil, nil
+		}
+
+		log.Error("Failed to take email verification", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		log.Error("Failed to parse email verification user", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+
+	if err := u.redisCLient.Del(ctx, u.getEmailVerificationUserKey(userID)).Err(); err != nil {
+		log.Warn("Failed to clear email verification of user", slog.String("error", err.Error()))
+	}
+
+	log.Info("Take email verification process executed successfully")
+	return userID, nil
+}
+
+// HoldEmailVerificationResend reserves the resend slot of the user for the
+// configured interval. It returns false while a previous email still holds it.
+func (u *userRepository) HoldEmailVerificationResend(ctx context.Context, userID uuid.UUID) (bool, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "user"),
+		slog.String("func", "HoldEmailVerificationResend"),
+	)
+
+	log.Info("Initializing hold email verification resend process")
+
+	held, err := u.redisCLient.SetNX(ctx, u.getEmailVerificationResendKey(userID), 1, config.Env.EmailVerificationResendInterval).Result()
+	if err != nil {
+		log.Error("Failed to hold email verification resend", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	log.Info("Hold email verification resend process executed successfully")
+	return held, nil
+}
+
+// indexUser sets the blind indexes of the email and document of the user,
+// which lookups and the unique indexes use as both are stored encrypted.
+func indexUser(cipher encryption.Cipher, user *domain.User) {
+	emailHash := cipher.BlindIndex(user.Email, "email")
+	documentHash := cipher.BlindIndex(user.Document, "document")
+	user.EmailHash = &emailHash
+	user.DocumentHash = &documentHash
+}
+
+func (u *userRepository) getPasswordResetKey(tokenHash string) string {
+	return fmt.Sprintf("password_reset_%s", tokenHash)
+}
+
+func (u *userRepository) getPasswordResetUserKey(userID uuid.UUID) string {
+	return fmt.Sprintf("password_reset_user_%s", userID)
+}
+
+func (u *userRepository) getEmailVerificationKey(tokenHash string) string {
+	return fmt.Sprintf("email_verification_%s", tokenHash)
+}
+
+func (u *userRepository) getEmailVerificationUserKey(userID uuid.UUID) string {
+	return fmt.Sprintf("email_verification_user_%s", userID)
+}
+
+func (u *userRepository) getEmailVerificationResendKey(userID uuid.UUID) string {
+	return fmt.Sprintf("email_verification_resend_%s", userID)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/GSVillas/pic-pay-desafio/domain"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/samber/do"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type walletRepository struct {
@@ -37,7 +39,7 @@ func NewWalletRepository(i *do.Injector) (domain.WalletRepository, error) {
 }
 
 func (w *walletRepository) Create(ctx context.Context, wallet *domain.Wallet) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "wallet"),
 		slog.String("func", "Create"),
 	)
@@ -52,16 +54,16 @@ func (w *walletRepository) Create(ctx context.Context, wallet *domain.Wallet) er
 	return nil
 }
 
-func (w *walletRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.Wallet, error) {
-	log := slog.With(
+func (w *walletRepository) GetByUserID(ctx context.Context, userID uuid.UUID, currency string) (*domain.Wallet, error) {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "wallet"),
 		slog.String("func", "GetByUserID"),
 	)
 
-	log.Info("Initializing get wallet by userId process")
+	log.Info("Initializing get wallet by userId process", slog.String("currency", currency))
 
 	var wallet *domain.Wallet
-	if err := w.db.WithContext(ctx).Where("userId = ?", userID.String()).First(&wallet).Error; err != nil {
+	if err := w.db.WithContext(ctx).Where("userId = ? AND currency = ?", userID.String(), currency).First(&wallet).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Warn("Wallet not found")
 			return nil, nil
@@ -75,36 +77,115 @@ func (w *walletRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*
 	return wallet, nil
 }
 
-func (w *walletRepository) Credit(ctx context.Context, userID uuid.UUID, value float64) error {
-	log := slog.With(
+func (w *walletRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Wallet, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "wallet"),
+		slog.String("func", "GetAllByUserID"),
+	)
+
+	log.Info("Initializing get wallets by userId process")
+
+	var wallets []*domain.Wallet
+	if err := w.db.WithContext(ctx).Where("userId = ?", userID.String()).Order("currency").Find(&wallets).Error; err != nil {
+		log.Error("Failed to get wallets by userId", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Process of obtaining wallets by userID executed successfully", slog.Int("count", len(wallets)))
+	return wallets, nil
+}
+
+func (w *walletRepository) Credit(ctx context.Context, userID uuid.UUID, currency string, value float64) error {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "wallet"),
 		slog.String("func", "Credit"),
 	)
 
-	log.Info("Starting to credit value to user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
+	log.Info("Starting to credit value to user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
 
-	if err := w.db.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ?", userID).UpdateColumn("balance", gorm.Expr("balance + ?", value)).Error; err != nil {
+	if err := w.db.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ? AND currency = ?", userID, currency).UpdateColumn("balance", gorm.Expr("balance + ?", value)).Error; err != nil {
 		log.Error("Failed to credit value to wallet", slog.String("error", err.Error()))
 		return err
 	}
 
-	log.Info("Successfully credited value to user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
+	log.Info("Successfully credited value to user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
 	return nil
 }
 
-func (w *walletRepository) Debit(ctx context.Context, userID uuid.UUID, value float64) error {
-	log := slog.With(
+func (w *walletRepository) Debit(ctx context.Context, userID uuid.UUID, currency string, value float64) error {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("repository", "wallet"),
 		slog.String("func", "Debit"),
 	)
 
-	log.Info("Starting to debit value from user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
+	log.Info("Starting to debit value from user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
+
+	if err := w.db.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ? AND currency = ?", userID, currency).UpdateColumn("balance", gorm.Expr("balance - ?", value)).Error; err != nil {
+		log.Error("Failed to debit value from wallet", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Successfully debited value from user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
+	return nil
+}
+
+// lockAvailableBalance locks the user's wallet row in currency for the rest
+// of tx and returns its balance minus the amount reserved by active holds.
+func lockAvailableBalance(ctx context.Context, tx *gorm.DB, userID uuid.UUID, currency string) (float64, error) {
+	var wallet domain.Wallet
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("userId = ? AND currency = ?", userID, currency).First(&wallet).Error; err != nil {
+		return 0, err
+	}
+
+	held, err := activeHoldAmount(ctx, tx, userID, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	return wallet.Balance - held, nil
+}
+
+func activeHoldAmount(ctx context.Context, db *gorm.DB, payerID uuid.UUID, currency string) (float64, error) {
+	var held float64
+	err := db.WithContext(ctx).
+		Model(&domain.Hold{}).
+		Where("payerId = ? AND currency = ? AND status = ? AND expiresAt > ?", payerID, currency, domain.HoldStatusActive, time.Now().UTC()).
+		Select("COALESCE(SUM(amount - capturedAmount), 0)").
+		Scan(&held).Error
+
+	return held, err
+}
+
+func credit(ctx context.Context, tx *gorm.DB, userID uuid.UUID, currency string, value float64) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "wallet"),
+		slog.String("func", "credit"),
+	)
+
+	log.Info("Starting to credit value to user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
+
+	if err := tx.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ? AND currency = ?", userID, currency).UpdateColumn("balance", gorm.Expr("balance + ?", value)).Error; err != nil {
+		log.Error("Failed to credit value to wallet", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Successfully credited value to user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
+	return nil
+}
+
+func debit(ctx context.Context, tx *gorm.DB, userID uuid.UUID, currency string, value float64) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("repository", "wallet"),
+		slog.String("func", "debit"),
+	)
+
+	log.Info("Starting to debit value from user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
 
-	if err := w.db.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ?", userID).UpdateColumn("balance", gorm.Expr("balance - ?", value)).Error; err != nil {
+	if err := tx.WithContext(ctx).Model(&domain.Wallet{}).Where("userId = ? AND currency = ?", userID, currency).UpdateColumn("balance", gorm.Expr("balance - ?", value)).Error; err != nil {
 		log.Error("Failed to debit value from wallet", slog.String("error", err.Error()))
 		return err
 	}
 
-	log.Info("Successfully debited value from user's wallet", slog.String("userID", userID.String()), slog.Float64("value", value))
+	log.Info("Successfully debited value from user's wallet", slog.String("userID", userID.String()), slog.String("currency", currency), slog.Float64("value", value))
 	return nil
 }
@@ -1,11 +1,201 @@
 package secure
 
-import "golang.org/x/crypto/bcrypt"
+//go:generate mockgen -source=password.go -destination=../mocks/password_mock.go -package=mocks
 
-func Hash(password string) ([]byte, error) {
-	return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/samber/do"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	ErrPasswordMismatch          = errors.New("password does not match the hash")
+	ErrUnknownPasswordHash       = errors.New("unknown password hash format")
+	ErrUnsupportedHashAlgorithm  = errors.New("unsupported password hash algorithm")
+	ErrInvalidPasswordHashParams = errors.New("invalid password hash parameters")
+)
+
+// PasswordHasher hashes new passwords with the configured algorithm and
+// verifies any stored hash it recognizes, so the algorithm and its cost can
+// be raised while older hashes keep working until NeedsRehash replaces them.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) error
+	NeedsRehash(hash string) bool
+}
+
+// PasswordHashParams selects the algorithm of new hashes. Argon2Memory is in
+// KiB.
+type PasswordHashParams struct {
+	Algorithm         string
+	BcryptCost        int
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+type passwordHasher struct {
+	params PasswordHashParams
+}
+
+func NewPasswordHasher(i *do.Injector) (PasswordHasher, error) {
+	return NewPasswordHasherWithParams(PasswordHashParams{
+		Algorithm:         config.Env.PasswordHashAlgorithm,
+		BcryptCost:        config.Env.BcryptCost,
+		Argon2Memory:      config.Env.Argon2Memory,
+		Argon2Iterations:  config.Env.Argon2Iterations,
+		Argon2Parallelism: config.Env.Argon2Parallelism,
+	})
+}
+
+func NewPasswordHasherWithParams(params PasswordHashParams) (PasswordHasher, error) {
+	switch params.Algorithm {
+	case PasswordHashBcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, ErrInvalidPasswordHashParams
+		}
+	case PasswordHashArgon2id:
+		if params.Argon2Memory == 0 || params.Argon2Iterations == 0 || params.Argon2Parallelism == 0 {
+			return nil, ErrInvalidPasswordHashParams
+		}
+	default:
+		return nil, ErrUnsupportedHashAlgorithm
+	}
+
+	return &passwordHasher{params: params}, nil
+}
+
+func (p *passwordHasher) Hash(password string) (string, error) {
+	if p.params.Algorithm == PasswordHashArgon2id {
+		return p.hashArgon2id(password)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), p.params.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Verify identifies the algorithm from the format of hash: the modular crypt
+// prefixes $2a$, $2b$ and $2y$ for bcrypt and the PHC string $argon2id$.
+func (p *passwordHasher) Verify(hash, password string) error {
+	if isBcryptHash(hash) {
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return ErrPasswordMismatch
+			}
+			return err
+		}
+		return nil
+	}
+
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Argon2Iterations, params.Argon2Memory, params.Argon2Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether hash was made with another algorithm or other
+// parameters than the current ones.
+func (p *passwordHasher) NeedsRehash(hash string) bool {
+	if isBcryptHash(hash) {
+		if p.params.Algorithm != PasswordHashBcrypt {
+			return true
+		}
+
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != p.params.BcryptCost
+	}
+
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil || p.params.Algorithm != PasswordHashArgon2id {
+		return true
+	}
+
+	return params.Argon2Memory != p.params.Argon2Memory ||
+		params.Argon2Iterations != p.params.Argon2Iterations ||
+		params.Argon2Parallelism != p.params.Argon2Parallelism ||
+		len(salt) != argon2SaltLength ||
+		len(key) != argon2KeyLength
+}
+
+// hashArgon2id encodes the hash as a PHC string, for example
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>, so it carries the parameters
+// needed to verify it after they change.
+func (p *passwordHasher) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, p.params.Argon2Iterations, p.params.Argon2Memory, p.params.Argon2Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.params.Argon2Memory, p.params.Argon2Iterations, p.params.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-func CheckPassword(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func decodeArgon2id(hash string) (*PasswordHashParams, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != PasswordHashArgon2id {
+		return nil, nil, nil, ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrUnknownPasswordHash
+	}
+
+	params := &PasswordHashParams{Algorithm: PasswordHashArgon2id}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Iterations, &params.Argon2Parallelism); err != nil {
+		return nil, nil, nil, ErrUnknownPasswordHash
+	}
+
+	// argon2.IDKey panics with no iterations or no parallelism.
+	if params.Argon2Memory == 0 || params.Argon2Iterations == 0 || params.Argon2Parallelism == 0 {
+		return nil, nil, nil, ErrUnknownPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrUnknownPasswordHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrUnknownPasswordHash
+	}
+
+	return params, salt, key, nil
 }
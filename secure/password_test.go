@@ -0,0 +1,120 @@
+package secure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	testBcryptParams   = PasswordHashParams{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MinCost}
+	testArgon2idParams = PasswordHashParams{Algorithm: PasswordHashArgon2id, Argon2Memory: 64, Argon2Iterations: 1, Argon2Parallelism: 1}
+)
+
+func newTestPasswordHasher(t *testing.T, params PasswordHashParams) PasswordHasher {
+	hasher, err := NewPasswordHasherWithParams(params)
+	assert.NoError(t, err)
+	return hasher
+}
+
+func TestPasswordHasher_HashThenVerify_ShouldAcceptThePassword(t *testing.T) {
+	for _, params := range []PasswordHashParams{testBcryptParams, testArgon2idParams} {
+		hasher := newTestPasswordHasher(t, params)
+
+		hash, err := hasher.Hash("Teste@123")
+		assert.NoError(t, err)
+		assert.NotContains(t, hash, "Teste@123")
+
+		assert.NoError(t, hasher.Verify(hash, "Teste@123"), params.Algorithm)
+		assert.False(t, hasher.NeedsRehash(hash), params.Algorithm)
+	}
+}
+
+func TestPasswordHasher_Verify_WhenPasswordDiffers_ShouldReturnErrPasswordMismatch(t *testing.T) {
+	for _, params := range []PasswordHashParams{testBcryptParams, testArgon2idParams} {
+		hasher := newTestPasswordHasher(t, params)
+
+		hash, err := hasher.Hash("Teste@123")
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, hasher.Verify(hash, "Teste@124"), ErrPasswordMismatch, params.Algorithm)
+	}
+}
+
+func TestPasswordHasher_Verify_WhenHashIsLegacyBcrypt_ShouldAcceptItAndAskForRehash(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("Teste@123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	hasher := newTestPasswordHasher(t, testArgon2idParams)
+
+	assert.NoError(t, hasher.Verify(string(legacy), "Teste@123"))
+	assert.ErrorIs(t, hasher.Verify(string(legacy), "Teste@124"), ErrPasswordMismatch)
+	assert.True(t, hasher.NeedsRehash(string(legacy)))
+}
+
+func TestPasswordHasher_NeedsRehash_WhenParamsChange_ShouldReturnTrue(t *testing.T) {
+	bcryptHash, err := newTestPasswordHasher(t, testBcryptParams).Hash("Teste@123")
+	assert.NoError(t, err)
+
+	argon2idHash, err := newTestPasswordHasher(t, testArgon2idParams).Hash("Teste@123")
+	assert.NoError(t, err)
+
+	higherCost := testBcryptParams
+	higherCost.BcryptCost++
+	assert.True(t, newTestPasswordHasher(t, higherCost).NeedsRehash(bcryptHash))
+	assert.True(t, newTestPasswordHasher(t, testBcryptParams).NeedsRehash(argon2idHash))
+
+	changes := map[string]func(*PasswordHashParams){
+		"memory":      func(p *PasswordHashParams) { p.Argon2Memory *= 2 },
+		"iterations":  func(p *PasswordHashParams) { p.Argon2Iterations++ },
+		"parallelism": func(p *PasswordHashParams) { p.Argon2Parallelism++ },
+	}
+	for name, change := range changes {
+		params := testArgon2idParams
+		change(&params)
+
+		hasher := newTestPasswordHasher(t, params)
+		assert.True(t, hasher.NeedsRehash(argon2idHash), name)
+		assert.NoError(t, hasher.Verify(argon2idHash, "Teste@123"), name)
+	}
+}
+
+func TestPasswordHasher_Verify_WhenHashIsMalformed_ShouldReturnErrUnknownPasswordHash(t *testing.T) {
+	hasher := newTestPasswordHasher(t, testArgon2idParams)
+
+	valid, err := hasher.Hash("Teste@123")
+	assert.NoError(t, err)
+	parts := strings.Split(valid, "$")
+
+	malformed := map[string]string{
+		"empty":          "",
+		"plaintext":      "Teste@123",
+		"argon2i":        strings.Replace(valid, "$argon2id$", "$argon2i$", 1),
+		"missing part":   strings.Join(parts[:5], "$"),
+		"wrong version":  strings.Replace(valid, "$v=19$", "$v=16$", 1),
+		"bad params":     strings.Replace(valid, parts[3], "m=64,t=x,p=1", 1),
+		"zero memory":    strings.Replace(valid, parts[3], "m=0,t=1,p=1", 1),
+		"zero time":      strings.Replace(valid, parts[3], "m=64,t=0,p=1", 1),
+		"zero threads":   strings.Replace(valid, parts[3], "m=64,t=1,p=0", 1),
+		"bad salt":       strings.Replace(valid, parts[4], "not*base64", 1),
+		"empty key":      strings.Join(append(parts[:5:5], ""), "$"),
+		"bad key base64": strings.Join(append(parts[:5:5], "not*base64"), "$"),
+	}
+	for name, hash := range malformed {
+		assert.ErrorIs(t, hasher.Verify(hash, "Teste@123"), ErrUnknownPasswordHash, name)
+		assert.True(t, hasher.NeedsRehash(hash), name)
+	}
+}
+
+func TestNewPasswordHasherWithParams_WhenParamsAreInvalid_ShouldFail(t *testing.T) {
+	_, err := NewPasswordHasherWithParams(PasswordHashParams{Algorithm: "md5"})
+	assert.ErrorIs(t, err, ErrUnsupportedHashAlgorithm)
+
+	_, err = NewPasswordHasherWithParams(PasswordHashParams{Algorithm: PasswordHashBcrypt, BcryptCost: bcrypt.MaxCost + 1})
+	assert.ErrorIs(t, err, ErrInvalidPasswordHashParams)
+
+	_, err = NewPasswordHasherWithParams(PasswordHashParams{Algorithm: PasswordHashArgon2id, Argon2Memory: 64, Argon2Iterations: 1})
+	assert.ErrorIs(t, err, ErrInvalidPasswordHashParams)
+}
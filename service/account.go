@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/samber/do"
+)
+
+type accountService struct {
+	i                  *do.Injector
+	accountRepository  domain.AccountRepository
+	userRepository     domain.UserRepository
+	walletRepository   domain.WalletRepository
+	transferRepository domain.TransferRepository
+	sessionService     domain.SessionService
+	emailService       client.EmailService
+	passwordHasher     secure.PasswordHasher
+}
+
+func NewAccountService(i *do.Injector) (domain.AccountService, error) {
+	accountRepository, err := do.Invoke[domain.AccountRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	walletRepository, err := do.Invoke[domain.WalletRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	transferRepository, err := do.Invoke[domain.TransferRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[client.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHasher, err := do.Invoke[secure.PasswordHasher](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountService{
+		i:                  i,
+		accountRepository:  accountRepository,
+		userRepository:     userRepository,
+		walletRepository:   walletRepository,
+		transferRepository: transferRepository,
+		sessionService:     sessionService,
+		emailService:       emailService,
+		passwordHasher:     passwordHasher,
+	}, nil
+}
+
+// Export gathers the profile, wallets, transfers and sessions of the signed
+// in user.
+func (a *accountService) Export(ctx context.Context) (*domain.AccountExport, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "account"),
+		slog.String("func", "Export"),
+	)
+
+	log.Info("Initializing export account process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	user, err := a.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return nil, domain.ErrExportAccount
+	}
+
+	if user == nil {
+		log.Warn("User of session not found", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrUserNotFound
+	}
+
+	wallets, err := a.walletRepository.GetAllByUserID(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to get wallets", slog.String("error", err.Error()))
+		return nil, domain.ErrExportAccount
+	}
+
+	transfers, err := a.transferRepository.GetAllByUserID(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to get transfers", slog.String("error", err.Error()))
+		return nil, domain.ErrExportAccount
+	}
+
+	sessions, err := a.sessionService.GetAll(ctx)
+	if err != nil {
+		log.Error("Failed to get sessions", slog.String("error", err.Error()))
+		return nil, domain.ErrExportAccount
+	}
+
+	log.Info("Export account process executed successfully", slog.String("userID", user.ID.String()))
+	return domain.NewAccountExport(user, wallets, transfers, sessions), nil
+}
+
+// Close ends the account of the signed in user. Every wallet must be empty
+// and no hold or escrow may be open. Personal data is pseudonymized rather
+// than erased so the transfers of the user remain valid records.
+func (a *accountService) Close(ctx context.Context, payload *domain.CloseAccountPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "account"),
+		slog.String("func", "Close"),
+	)
+
+	log.Info("Initializing close account process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	user, err := a.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return domain.ErrCloseAccount
+	}
+
+	if user == nil {
+		log.Warn("User of session not found", slog.String("userID", session.UserID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	if err := a.passwordHasher.Verify(user.PasswordHash, payload.Password); err != nil {
+		log.Warn("The password entered is invalid", slog.String("userID", user.ID.String()))
+		return domain.ErrInvalidPassword
+	}
+
+	email := &client.Email{
+		To:      user.Email,
+		Subject: "Your account was closed",
+		Text: fmt.Sprintf(
+			"Hi %s,\n\nYour account was closed and your personal data removed. Records of your transfers are kept as required by law.",
+			user.Name,
+		),
+	}
+
+	user.Pseudonymize()
+	if err := a.accountRepository.Close(ctx, user); err != nil {
+		if errors.Is(err, domain.ErrAccountHasBalance) || errors.Is(err, domain.ErrAccountHasOpenFunds) {
+			log.Warn("Account cannot be closed yet", slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+			return err
+		}
+
+		log.Error("Failed to close account", slog.String("error", err.Error()))
+		return domain.ErrCloseAccount
+	}
+
+	// The account is already closed at this point, so a failure here is only
+	// logged: the user and its wallets are soft-deleted, leaving a session
+	// that survives until it expires nothing to act on.
+	if err := a.sessionService.RevokeAll(ctx, user.ID); err != nil {
+		log.Error("Failed to revoke sessions of closed account", slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+	}
+
+	if err := a.emailService.Send(ctx, email); err != nil {
+		log.Error("Failed to send account closure notice", slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+	}
+
+	log.Info("Close account process executed successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountService_Close_WhenWalletHasBalance_ShouldNotRevokeSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRepositoryMock := mocks.NewMockAccountRepository(ctrl)
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	passwordHasher := newTestPasswordHasher(t)
+
+	accountService := &accountService{
+		accountRepository: accountRepositoryMock,
+		userRepository:    userRepositoryMock,
+		sessionService:    sessionServiceMock,
+		passwordHasher:    passwordHasher,
+	}
+
+	hash, err := passwordHasher.Hash("Str0ng!Password")
+	assert.NoError(t, err)
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), userID).Return(&domain.User{ID: userID, Email: "ana@example.com", PasswordHash: hash}, nil)
+	accountRepositoryMock.EXPECT().Close(gomock.Any(), gomock.Any()).Return(domain.ErrAccountHasBalance)
+
+	err = accountService.Close(ctx, &domain.CloseAccountPayload{Password: "Str0ng!Password"})
+
+	assert.ErrorIs(t, err, domain.ErrAccountHasBalance)
+}
+
+func TestAccountService_Close_ShouldPseudonymizeUserAndRevokeSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRepositoryMock := mocks.NewMockAccountRepository(ctrl)
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	emailServiceMock := mocks.NewMockEmailService(ctrl)
+	passwordHasher := newTestPasswordHasher(t)
+
+	accountService := &accountService{
+		accountRepository: accountRepositoryMock,
+		userRepository:    userRepositoryMock,
+		sessionService:    sessionServiceMock,
+		emailService:      emailServiceMock,
+		passwordHasher:    passwordHasher,
+	}
+
+	hash, err := passwordHasher.Hash("Str0ng!Password")
+	assert.NoError(t, err)
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), userID).Return(&domain.User{ID: userID, Name: "Ana", Document: "52998224725", Email: "ana@example.com", Phone: "+5511999999999", PasswordHash: hash}, nil)
+	accountRepositoryMock.EXPECT().Close(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *domain.User) error {
+		assert.Equal(t, domain.ClosedAccountName, user.Name)
+		assert.NotEqual(t, "52998224725", user.Document)
+		assert.NotEqual(t, "ana@example.com", user.Email)
+		assert.Empty(t, user.Phone)
+		assert.Empty(t, user.PasswordHash)
+		return nil
+	})
+	sessionServiceMock.EXPECT().RevokeAll(gomock.Any(), userID).Return(nil)
+	emailServiceMock.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, email *client.Email) error {
+		assert.Equal(t, "ana@example.com", email.To)
+		return nil
+	})
+
+	err = accountService.Close(ctx, &domain.CloseAccountPayload{Password: "Str0ng!Password"})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const (
+	apiKeyPrefixSize = 6
+	apiKeySecretSize = 32
+)
+
+type apiKeyService struct {
+	i                *do.Injector
+	apiKeyRepository domain.APIKeyRepository
+	walletRepository domain.WalletRepository
+}
+
+func NewAPIKeyService(i *do.Injector) (domain.APIKeyService, error) {
+	apiKeyRepository, err := do.Invoke[domain.APIKeyRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	walletRepository, err := do.Invoke[domain.WalletRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKeyService{
+		i:                i,
+		apiKeyRepository: apiKeyRepository,
+		walletRepository: walletRepository,
+	}, nil
+}
+
+// Create issues a key for one of the merchant wallets of the signed in user.
+// The secret is only known to the response; the store keeps its hash.
+func (a *apiKeyService) Create(ctx context.Context, payload *domain.APIKeyPayload) (*domain.CreateAPIKeyResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create api key process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	wallet, err := a.walletRepository.GetByUserID(ctx, session.UserID, payload.Currency)
+	if err != nil {
+		log.Error("Failed to get wallet", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateAPIKey
+	}
+
+	if wallet == nil {
+		log.Warn("Wallet not found", slog.String("userID", session.UserID.String()), slog.String("currency", payload.Currency))
+		return nil, domain.ErrWalletNotFound
+	}
+
+	if wallet.Type != domain.WalletTypeMERCHANT {
+		log.Warn("Api key requested for a non merchant wallet", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrAPIKeyMerchantOnly
+	}
+
+	prefix, err := secure.GenerateToken(apiKeyPrefixSize)
+	if err != nil {
+		log.Error("Failed to generate api key prefix", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateAPIKey
+	}
+
+	secret, err := secure.GenerateToken(apiKeySecretSize)
+	if err != nil {
+		log.Error("Failed to generate api key secret", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateAPIKey
+	}
+
+	apiKey := payload.ToAPIKey(session.UserID, prefix, secure.HashToken(secret))
+	if err := a.apiKeyRepository.Create(ctx, apiKey); err != nil {
+		log.Error("Failed to create api key", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateAPIKey
+	}
+
+	log.Info("Create api key process executed successfully", slog.String("apiKeyID", apiKey.ID.String()))
+	return &domain.CreateAPIKeyResponse{
+		APIKeyResponse: *apiKey.ToResponse(),
+		Key:            domain.APIKeyTokenPrefix + prefix + "." + secret,
+	}, nil
+}
+
+func (a *apiKeyService) GetAll(ctx context.Context) ([]*domain.APIKeyResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get api keys process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	apiKeys, err := a.apiKeyRepository.GetAllByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get api keys", slog.String("error", err.Error()))
+		return nil, domain.ErrGetAPIKeys
+	}
+
+	response := make([]*domain.APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		response = append(response, apiKey.ToResponse())
+	}
+
+	log.Info("Get api keys process executed successfully")
+	return response, nil
+}
+
+func (a *apiKeyService) Revoke(ctx context.Context, apiKeyID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing revoke api key process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if err := a.apiKeyRepository.Delete(ctx, session.UserID, apiKeyID); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			log.Warn("Api key not found", slog.String("apiKeyID", apiKeyID.String()))
+			return err
+		}
+
+		log.Error("Failed to revoke api key", slog.String("error", err.Error()))
+		return domain.ErrRevokeAPIKey
+	}
+
+	log.Info("Revoke api key process executed successfully", slog.String("apiKeyID", apiKeyID.String()))
+	return nil
+}
+
+// Authenticate resolves a ppk_<prefix>.<secret> key sent from ip into the
+// principal of its merchant. Scopes are checked by the caller, which knows
+// what the request is about to do.
+func (a *apiKeyService) Authenticate(ctx context.Context, key, ip string) (*domain.Session, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "Authenticate"),
+	)
+
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(key, domain.APIKeyTokenPrefix), ".")
+	if !ok || prefix == "" || secret == "" {
+		log.Warn("Malformed api key")
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	apiKey, err := a.apiKeyRepository.GetByPrefix(ctx, prefix)
+	if err != nil {
+		log.Error("Failed to get api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if apiKey == nil || subtle.ConstantTimeCompare([]byte(secure.HashToken(secret)), []byte(apiKey.SecretHash)) != 1 {
+		log.Warn("Invalid api key", slog.String("prefix", prefix))
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	if !apiKey.AllowsIP(ip) {
+		log.Warn("Api key used from a non allowed ip", slog.String("apiKeyID", apiKey.ID.String()), slog.String("ip", ip))
+		return nil, domain.ErrAPIKeyIPNotAllowed
+	}
+
+	if err := a.apiKeyRepository.Touch(ctx, apiKey.ID, time.Now().UTC()); err != nil {
+		log.Warn("Failed to record api key use", slog.String("error", err.Error()))
+	}
+
+	return apiKey.ToSession(&apiKey.User), nil
+}
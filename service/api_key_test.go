@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyService_Create_WhenWalletIsNotMerchant_ShouldReturnErrAPIKeyMerchantOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	apiKeyRepositoryMock := mocks.NewMockAPIKeyRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+
+	apiKeyService := &apiKeyService{
+		apiKeyRepository: apiKeyRepositoryMock,
+		walletRepository: walletRepositoryMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	payload := &domain.APIKeyPayload{
+		Name:     "backend",
+		Currency: domain.DefaultCurrency,
+		Scopes:   []domain.APIKeyScope{domain.APIKeyScopeStatementsRead},
+	}
+
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID, domain.DefaultCurrency).Return(&domain.Wallet{UserID: userID, Type: domain.WalletTypeCOMMON}, nil)
+
+	_, err := apiKeyService.Create(ctx, payload)
+
+	assert.ErrorIs(t, err, domain.ErrAPIKeyMerchantOnly)
+}
+
+func TestAPIKeyService_Authenticate_WhenKeyIsValid_ShouldReturnSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	apiKeyRepositoryMock := mocks.NewMockAPIKeyRepository(ctrl)
+
+	apiKeyService := &apiKeyService{
+		apiKeyRepository: apiKeyRepositoryMock,
+	}
+
+	user := domain.User{ID: uuid.New(), Name: "Merchant", Email: "merchant@example.com"}
+	apiKey := &domain.APIKey{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		User:       user,
+		Currency:   domain.DefaultCurrency,
+		Prefix:     "abc123",
+		SecretHash: secure.HashToken("secret"),
+		Scopes:     string(domain.APIKeyScopeChargesWrite),
+		AllowedIPs: "10.0.0.0/8",
+	}
+
+	apiKeyRepositoryMock.EXPECT().GetByPrefix(gomock.Any(), "abc123").Return(apiKey, nil)
+	apiKeyRepositoryMock.EXPECT().Touch(gomock.Any(), apiKey.ID, gomock.Any()).Return(nil)
+
+	session, err := apiKeyService.Authenticate(context.Background(), "ppk_abc123.secret", "10.1.2.3")
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, session.UserID)
+	assert.Equal(t, apiKey, session.APIKey)
+	assert.True(t, session.AllowsCurrency(domain.DefaultCurrency))
+	assert.False(t, session.AllowsCurrency("USD"))
+}
+
+func TestAPIKeyService_Authenticate_WhenSecretIsWrong_ShouldReturnErrAPIKeyInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	apiKeyRepositoryMock := mocks.NewMockAPIKeyRepository(ctrl)
+
+	apiKeyService := &apiKeyService{
+		apiKeyRepository: apiKeyRepositoryMock,
+	}
+
+	apiKey := &domain.APIKey{
+		ID:         uuid.New(),
+		Prefix:     "abc123",
+		SecretHash: secure.HashToken("secret"),
+	}
+
+	apiKeyRepositoryMock.EXPECT().GetByPrefix(gomock.Any(), "abc123").Return(apiKey, nil)
+
+	_, err := apiKeyService.Authenticate(context.Background(), "ppk_abc123.wrong", "10.1.2.3")
+
+	assert.ErrorIs(t, err, domain.ErrAPIKeyInvalid)
+}
+
+func TestAPIKeyService_Authenticate_WhenIPIsNotAllowed_ShouldReturnErrAPIKeyIPNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	apiKeyRepositoryMock := mocks.NewMockAPIKeyRepository(ctrl)
+
+	apiKeyService := &apiKeyService{
+		apiKeyRepository: apiKeyRepositoryMock,
+	}
+
+	apiKey := &domain.APIKey{
+		ID:         uuid.New(),
+		Prefix:     "abc123",
+		SecretHash: secure.HashToken("secret"),
+		AllowedIPs: "192.168.0.10,10.0.0.0/8",
+	}
+
+	apiKeyRepositoryMock.EXPECT().GetByPrefix(gomock.Any(), "abc123").Return(apiKey, nil)
+
+	_, err := apiKeyService.Authenticate(context.Background(), "ppk_abc123.secret", "172.16.0.1")
+
+	assert.ErrorIs(t, err, domain.ErrAPIKeyIPNotAllowed)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/samber/do"
+)
+
+type campaignService struct {
+	i                  *do.Injector
+	campaignRepository domain.CampaignRepository
+	walletRepository   domain.WalletRepository
+}
+
+func NewCampaignService(i *do.Injector) (domain.CampaignService, error) {
+	campaignRepository, err := do.Invoke[domain.CampaignRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	walletRepository, err := do.Invoke[domain.WalletRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaignService{
+		i:                  i,
+		campaignRepository: campaignRepository,
+		walletRepository:   walletRepository,
+	}, nil
+}
+
+func (c *campaignService) Create(ctx context.Context, payload *domain.CampaignPayload) (*domain.CampaignResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "campaign"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create campaign process")
+
+	fundingWallet, err := c.walletRepository.GetByUserID(ctx, payload.FundingWalletID, payload.Currency)
+	if err != nil {
+		log.Error("Failed to get funding wallet", slog.String("error", err.Error()))
+		return nil, domain.ErrGetWallet
+	}
+
+	if fundingWallet == nil {
+		log.Warn("Funding wallet not found", slog.String("fundingWalletID", payload.FundingWalletID.String()))
+		return nil, domain.ErrFundingWalletNotFound
+	}
+
+	for _, merchantID := range payload.MerchantIDs {
+		wallets, err := c.walletRepository.GetAllByUserID(ctx, merchantID)
+		if err != nil {
+			log.Error("Failed to get merchant wallets", slog.String("error", err.Error()))
+			return nil, domain.ErrGetWallet
+		}
+
+		if len(wallets) == 0 || wallets[0].Type != domain.WalletTypeMERCHANT {
+			log.Warn("Campaign merchant has no merchant wallet", slog.String("merchantID", merchantID.String()))
+			return nil, domain.ErrCampaignMerchantInvalid
+		}
+	}
+
+	campaign := payload.ToCampaign()
+	if err := c.campaignRepository.Create(ctx, campaign); err != nil {
+		log.Error("Failed to create campaign", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateCampaign
+	}
+
+	log.Info("Create campaign process executed successfully", slog.String("campaignID", campaign.ID.String()))
+	return campaign.ToResponse(), nil
+}
+
+func (c *campaignService) GetAll(ctx context.Context) ([]*domain.CampaignResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "campaign"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get campaigns process")
+
+	campaigns, err := c.campaignRepository.GetAll(ctx)
+	if err != nil {
+		log.Error("Failed to get campaigns", slog.String("error", err.Error()))
+		return nil, domain.ErrGetCampaigns
+	}
+
+	response := make([]*domain.CampaignResponse, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		response = append(response, campaign.ToResponse())
+	}
+
+	log.Info("Get campaigns process executed successfully")
+	return response, nil
+}
+
+func (c *campaignService) GetRewards(ctx context.Context) ([]*domain.RewardResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "campaign"),
+		slog.String("func", "GetRewards"),
+	)
+
+	log.Info("Initializing get rewards process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	rewards, err := c.campaignRepository.GetRewardsByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get rewards", slog.String("error", err.Error()))
+		return nil, domain.ErrGetRewards
+	}
+
+	response := make([]*domain.RewardResponse, 0, len(rewards))
+	for _, reward := range rewards {
+		response = append(response, reward.ToResponse())
+	}
+
+	log.Info("Get rewards process executed successfully")
+	return response, nil
+}
+
+// EvaluateTransfer grants the cashback of every active campaign covering the
+// payee of a settled transfer. A campaign that cannot pay is skipped so the
+// others are still evaluated.
+func (c *campaignService) EvaluateTransfer(ctx context.Context, transfer *domain.Transfer) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "campaign"),
+		slog.String("func", "EvaluateTransfer"),
+	)
+
+	if transfer.ReversalOfID != nil || transfer.EscrowStatus == domain.EscrowStatusHeld || transfer.EscrowStatus == domain.EscrowStatusDisputed {
+		return nil
+	}
+
+	campaigns, err := c.campaignRepository.GetActiveByMerchantID(ctx, transfer.PayeeID, domain.NormalizeCurrency(transfer.Currency), time.Now().UTC())
+	if err != nil {
+		log.Error("Failed to get active campaigns", slog.String("error", err.Error()))
+		return err
+	}
+
+	for _, campaign := range campaigns {
+		reward, err := c.campaignRepository.GrantReward(ctx, campaign.ID, transfer)
+		if err != nil {
+			if errors.Is(err, domain.ErrCampaignBudgetExhausted) ||
+				errors.Is(err, domain.ErrInsufficientBalance) ||
+				errors.Is(err, domain.ErrFundingWalletNotFound) {
+				log.Warn("Campaign cannot pay reward", slog.String("campaignID", campaign.ID.String()), slog.String("error", err.Error()))
+				continue
+			}
+
+			log.Error("Failed to grant reward", slog.String("campaignID", campaign.ID.String()), slog.String("error", err.Error()))
+			continue
+		}
+
+		if reward != nil {
+			log.Info("Reward granted", slog.String("campaignID", campaign.ID.String()), slog.String("transferID", transfer.ID.String()), slog.Float64("value", reward.Value))
+		}
+	}
+
+	return nil
+}
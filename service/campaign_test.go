@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCampaignService_Create_WhenMerchantHasNoMerchantWallet_ShouldReturnErrCampaignMerchantInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepositoryMock := mocks.NewMockCampaignRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+
+	campaignService := &campaignService{
+		campaignRepository: campaignRepositoryMock,
+		walletRepository:   walletRepositoryMock,
+	}
+
+	payload := &domain.CampaignPayload{
+		Name:            "Black Friday",
+		FundingWalletID: uuid.New(),
+		Currency:        domain.DefaultCurrency,
+		Percentage:      5,
+		PerUserCap:      20,
+		Budget:          1000,
+		StartsAt:        time.Now(),
+		EndsAt:          time.Now().Add(24 * time.Hour),
+		MerchantIDs:     []uuid.UUID{uuid.New()},
+	}
+
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payload.FundingWalletID, domain.DefaultCurrency).Return(&domain.Wallet{UserID: payload.FundingWalletID}, nil)
+	walletRepositoryMock.EXPECT().GetAllByUserID(gomock.Any(), payload.MerchantIDs[0]).Return([]*domain.Wallet{{Type: domain.WalletTypeCOMMON}}, nil)
+
+	_, err := campaignService.Create(context.Background(), payload)
+
+	assert.ErrorIs(t, err, domain.ErrCampaignMerchantInvalid)
+}
+
+func TestCampaignService_EvaluateTransfer_WhenBudgetExhausted_ShouldEvaluateRemainingCampaigns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepositoryMock := mocks.NewMockCampaignRepository(ctrl)
+
+	campaignService := &campaignService{
+		campaignRepository: campaignRepositoryMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:      uuid.New(),
+		PayerID: uuid.New(),
+		PayeeID: uuid.New(),
+		Value:   100,
+	}
+	exhausted := &domain.Campaign{ID: uuid.New()}
+	active := &domain.Campaign{ID: uuid.New()}
+
+	campaignRepositoryMock.EXPECT().GetActiveByMerchantID(gomock.Any(), transfer.PayeeID, domain.DefaultCurrency, gomock.Any()).Return([]*domain.Campaign{exhausted, active}, nil)
+	campaignRepositoryMock.EXPECT().GrantReward(gomock.Any(), exhausted.ID, transfer).Return(nil, domain.ErrCampaignBudgetExhausted)
+	campaignRepositoryMock.EXPECT().GrantReward(gomock.Any(), active.ID, transfer).Return(&domain.Reward{Value: 5}, nil)
+
+	err := campaignService.EvaluateTransfer(context.Background(), transfer)
+
+	assert.NoError(t, err)
+}
+
+func TestCampaignService_EvaluateTransfer_WhenTransferIsHeldInEscrow_ShouldNotGrantRewards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepositoryMock := mocks.NewMockCampaignRepository(ctrl)
+
+	campaignService := &campaignService{
+		campaignRepository: campaignRepositoryMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:           uuid.New(),
+		PayerID:      uuid.New(),
+		PayeeID:      uuid.New(),
+		Value:        100,
+		EscrowStatus: domain.EscrowStatusHeld,
+	}
+
+	err := campaignService.EvaluateTransfer(context.Background(), transfer)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type contactService struct {
+	i                 *do.Injector
+	contactRepository domain.ContactRepository
+	userRepository    domain.UserRepository
+}
+
+func NewContactService(i *do.Injector) (domain.ContactService, error) {
+	contactRepository, err := do.Invoke[domain.ContactRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contactService{
+		i:                 i,
+		contactRepository: contactRepository,
+		userRepository:    userRepository,
+	}, nil
+}
+
+// GetAll lists the contacts of the signed in user, favorites first.
+func (c *contactService) GetAll(ctx context.Context) ([]*domain.ContactResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "contact"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get contacts process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	contacts, err := c.contactRepository.GetAllByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get contacts", slog.String("error", err.Error()))
+		return nil, domain.ErrGetContacts
+	}
+
+	domain.SortContacts(contacts)
+
+	log.Info("Get contacts process executed successfully", slog.Int("count", len(contacts)))
+	return toContactResponses(contacts), nil
+}
+
+// Create adds the user found by document or email as a contact.
+func (c *contactService) Create(ctx context.Context, payload *domain.ContactPayload) (*domain.ContactResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "contact"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing contact creation process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	var user *domain.User
+	var err error
+	if payload.Document != "" {
+		user, err = c.userRepository.GetByDocument(ctx, payload.CleanDocument())
+	} else {
+		user, err = c.userRepository.GetByEmail(ctx, payload.Email)
+	}
+	if err != nil {
+		log.Error("Failed to look up contact user", slog.String("error", err.Error()))
+		return nil, domain.ErrSaveContact
+	}
+
+	if user == nil {
+		log.Warn("No user found for the contact lookup")
+		return nil, domain.ErrContactUserNotFound
+	}
+
+	if user.ID == session.UserID {
+		log.Warn("User tried to add themselves as a contact", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrSelfContactNotAllowed
+	}
+
+	existing, err := c.contactRepository.Get(ctx, session.UserID, user.ID)
+	if err != nil {
+		log.Error("Failed to get contact", slog.String("error", err.Error()))
+		return nil, domain.ErrSaveContact
+	}
+
+	if existing != nil {
+		log.Warn("Contact already exists", slog.String("contactID", user.ID.String()))
+		return nil, domain.ErrContactAlreadyExists
+	}
+
+	now := time.Now().UTC()
+	contact := &domain.Contact{
+		UserID:    session.UserID,
+		ContactID: user.ID,
+		Nickname:  payload.Nickname,
+		Favorite:  payload.Favorite,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := c.contactRepository.Create(ctx, contact); err != nil {
+		log.Error("Failed to create contact", slog.String("error", err.Error()))
+		return nil, domain.ErrSaveContact
+	}
+
+	contact.Payee = *user
+
+	log.Info("Contact creation process executed successfully", slog.String("contactID", user.ID.String()))
+	return contact.ToResponse(), nil
+}
+
+func (c *contactService) Update(ctx context.Context, contactID uuid.UUID, payload *domain.UpdateContactPayload) (*domain.ContactResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "contact"),
+		slog.String("func", "Update"),
+	)
+
+	log.Info("Initializing update contact process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	contact, err := c.contactRepository.Get(ctx, session.UserID, contactID)
+	if err != nil {
+		log.Error("Failed to get contact", slog.String("error", err.Error()))
+		return nil, domain.ErrSaveContact
+	}
+
+	if contact == nil {
+		log.Warn("Contact not found", slog.String("contactID", contactID.String()))
+		return nil, domain.ErrContactNotFound
+	}
+
+	if payload.Nickname != nil {
+		contact.Nickname = *payload.Nickname
+	}
+
+	if payload.Favorite != nil {
+		contact.Favorite = *payload.Favorite
+	}
+
+	if err := c.contactRepository.Update(ctx, contact); err != nil {
+		log.Error("Failed to update contact", slog.String("error", err.Error()))
+		return nil, domain.ErrSaveContact
+	}
+
+	log.Info("Update contact process executed successfully", slog.String("contactID", contactID.String()))
+	return contact.ToResponse(), nil
+}
+
+func (c *contactService) Delete(ctx context.Context, contactID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "contact"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing delete contact process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	deleted, err := c.contactRepository.Delete(ctx, session.UserID, contactID)
+	if err != nil {
+		log.Error("Failed to delete contact", slog.String("error", err.Error()))
+		return domain.ErrDeleteContact
+	}
+
+	if !deleted {
+		log.Warn("Contact not found", slog.String("contactID", contactID.String()))
+		return domain.ErrContactNotFound
+	}
+
+	log.Info("Delete contact process executed successfully", slog.String("contactID", contactID.String()))
+	return nil
+}
+
+// GetRecentPayees ranks the contacts the user paid by how often and how
+// recently they were paid.
+func (c *contactService) GetRecentPayees(ctx context.Context, query *domain.RecentPayeesQuery) ([]*domain.ContactResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "contact"),
+		slog.String("func", "GetRecentPayees"),
+	)
+
+	log.Info("Initializing get recent payees process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	contacts, err := c.contactRepository.GetPaidByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get paid contacts", slog.String("error", err.Error()))
+		return nil, domain.ErrGetContacts
+	}
+
+	domain.SortRecentPayees(contacts, time.Now().UTC())
+	if len(contacts) > query.Limit {
+		contacts = contacts[:query.Limit]
+	}
+
+	log.Info("Get recent payees process executed successfully", slog.Int("count", len(contacts)))
+	return toContactResponses(contacts), nil
+}
+
+// RecordTransfer adds the payee to the contacts of the payer, or counts one
+// more transfer to it.
+func (c *contactService) RecordTransfer(ctx context.Context, transfer *domain.Transfer) error {
+	if err := c.contactRepository.RecordTransfer(ctx, transfer.PayerID, transfer.PayeeID, transfer.CreatedAt); err != nil {
+		return domain.ErrRecordContactTransfer
+	}
+
+	return nil
+}
+
+func toContactResponses(contacts []*domain.Contact) []*domain.ContactResponse {
+	response := make([]*domain.ContactResponse, 0, len(contacts))
+	for _, contact := range contacts {
+		response = append(response, contact.ToResponse())
+	}
+	return response
+}
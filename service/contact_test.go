@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactService_Create_WhenLookupFindsSelf_ShouldReturnErrSelfContactNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	contactRepositoryMock := mocks.NewMockContactRepository(ctrl)
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	contactService := &contactService{
+		contactRepository: contactRepositoryMock,
+		userRepository:    userRepositoryMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	userRepositoryMock.EXPECT().GetByDocument(gomock.Any(), "52998224725").Return(&domain.User{ID: userID}, nil)
+
+	_, err := contactService.Create(ctx, &domain.ContactPayload{Document: "529.982.247-25"})
+
+	assert.ErrorIs(t, err, domain.ErrSelfContactNotAllowed)
+}
+
+func TestContactService_GetRecentPayees_ShouldRankByFrequencyAndRecency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	contactRepositoryMock := mocks.NewMockContactRepository(ctrl)
+
+	contactService := &contactService{
+		contactRepository: contactRepositoryMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	now := time.Now().UTC()
+	yesterday := now.Add(-24 * time.Hour)
+	lastQuarter := now.Add(-90 * 24 * time.Hour)
+
+	frequentLongAgo := &domain.Contact{ContactID: uuid.New(), TransferCount: 6, LastTransferAt: &lastQuarter}
+	occasionalRecent := &domain.Contact{ContactID: uuid.New(), TransferCount: 2, LastTransferAt: &yesterday}
+	frequentRecent := &domain.Contact{ContactID: uuid.New(), TransferCount: 5, LastTransferAt: &yesterday}
+
+	contactRepositoryMock.EXPECT().GetPaidByUserID(gomock.Any(), userID).Return([]*domain.Contact{frequentLongAgo, occasionalRecent, frequentRecent}, nil)
+
+	response, err := contactService.GetRecentPayees(ctx, &domain.RecentPayeesQuery{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, response, 2)
+	assert.Equal(t, frequentRecent.ContactID, response[0].ID)
+	assert.Equal(t, occasionalRecent.ContactID, response[1].ID)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/storage"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type disputeService struct {
+	i                   *do.Injector
+	disputeRepository   domain.DisputeRepository
+	transferRepository  domain.TransferRepository
+	fileStorage         storage.FileStorage
+	notificationService client.NotificationService
+}
+
+func NewDisputeService(i *do.Injector) (domain.DisputeService, error) {
+	disputeRepository, err := do.Invoke[domain.DisputeRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	transferRepository, err := do.Invoke[domain.TransferRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	fileStorage, err := do.Invoke[storage.FileStorage](i)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationService, err := do.Invoke[client.NotificationService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &disputeService{
+		i:                   i,
+		disputeRepository:   disputeRepository,
+		transferRepository:  transferRepository,
+		fileStorage:         fileStorage,
+		notificationService: notificationService,
+	}, nil
+}
+
+func (d *disputeService) Open(ctx context.Context, payload *domain.OpenDisputePayload) (*domain.DisputeResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "dispute"),
+		slog.String("func", "Open"),
+	)
+
+	log.Info("Initializing open dispute process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	transfer, err := d.transferRepository.GetByID(ctx, payload.TransferID)
+	if err != nil {
+		log.Error("Failed to get transfer", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateDispute
+	}
+
+	if transfer == nil {
+		log.Warn("Transfer not found", slog.String("transferID", payload.TransferID.String()))
+		return nil, domain.ErrTransferNotFound
+	}
+
+	if transfer.PayerID != session.UserID {
+		log.Warn("Only the payer can dispute a transfer", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrDisputeForbidden
+	}
+
+	if transfer.ReversalOfID != nil || transfer.EscrowStatus == domain.EscrowStatusHeld ||
+		transfer.EscrowStatus == domain.EscrowStatusDisputed || transfer.EscrowStatus == domain.EscrowStatusRefunded {
+		log.Warn("Transfer cannot be disputed", slog.String("transferID", transfer.ID.String()), slog.String("escrowStatus", string(transfer.EscrowStatus)))
+		return nil, domain.ErrDisputeNotAllowed
+	}
+
+	existing, err := d.disputeRepository.GetByTransferID(ctx, transfer.ID)
+	if err != nil {
+		log.Error("Failed to get dispute by transfer", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateDispute
+	}
+
+	if existing != nil {
+		log.Warn("There is already a dispute for this transfer", slog.String("disputeID", existing.ID.String()))
+		return nil, domain.ErrDisputeAlreadyExists
+	}
+
+	dispute := payload.ToDispute(transfer)
+	if err := d.disputeRepository.Create(ctx, dispute); err != nil {
+		log.Error("Failed to create dispute", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateDispute
+	}
+
+	d.notify(ctx, dispute.MerchantID, "Transfer disputed", fmt.Sprintf("A payment of %.2f you received was disputed by the payer.", transfer.Value))
+
+	log.Info("Open dispute process executed successfully", slog.String("disputeID", dispute.ID.String()))
+	return dispute.ToResponse(), nil
+}
+
+func (d *disputeService) GetByID(ctx context.Context, disputeID uuid.UUID) (*domain.DisputeResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "dispute"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get dispute process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	dispute, err := d.getParticipantDispute(ctx, disputeID, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get dispute", slog.String("disputeID", disputeID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get dispute process executed successfully")
+	return dispute.ToResponse(), nil
+}
+
+func (d *disputeService) Respond(ctx context.Context, disputeID uuid.UUID, payload *domain.RespondDisputePayload) (*domain.DisputeResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "dispute"),
+		slog.String("func", "Respond"),
+	)
+
+	log.Info("Initializing respond dispute process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	dispute, err := d.getParticipantDispute(ctx, disputeID, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get dispute", slog.String("disputeID", disputeID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if dispute.MerchantID != session.UserID {
+		log.Warn("Only the merchant can respond a dispute", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrDisputeForbidden
+	}
+
+	dispute, err = d.disputeRepository.Respond(ctx, disputeID, payload.Response)
+	if err != nil {
+		return nil, d.updateError(log, err)
+	}
+
+	d.notify(ctx, dispute.PayerID, "Dispute answered", "The merchant has responded to your dispute.")
+
+	log.Info("Respond dispute process executed successfully")
+	return dispute.ToResponse(), nil
+}
+
+func (d *disputeService) UploadEvidence(ctx context.Context, disputeID uuid.UUID, upload *domain.EvidenceUpload) (*domain.DisputeEvidenceResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "dispute"),
+		slog.String("func", "UploadEvidence"),
+	)
+
+	log.Info("Initializing upload evidence process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	dispute, err := d.getParticipantDispute(ctx, disputeID, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get dispute", slog.String("disputeID", disputeID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if dispute.Status.IsResolved() {
+		log.Warn("Cannot add evidence to a resolved dispute")
+		return nil, domain.ErrDisputeAlreadyResolved
+	}
+
+	if upload.Size > config.Env.MaxUploadSize {
+		log.Warn("Evidence file is too large", slog.Int64("size", upload.Size))
+		return nil, domain.ErrEvidenceTooLarge
+	}
+
+	evidence := &domain.DisputeEvidence{
+		ID:          uuid.New(),
+		DisputeID:   dispute.ID,
+		UploadedBy:  session.UserID,
+		FileName:    filepath.Base(upload.FileName),
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+		CreatedAt:   time.Now().UTC(),
+	}
+	evidence.StorageKey = fmt.Sprintf("disputes/%s/%s%s", dispute.ID, evidence.ID, strings.ToLower(filepath.Ext(evidence.FileName)))
+
+	if err := d.fileStorage.Save(ctx, evidence.StorageKey, evidence.ContentType, upload.Content, upload.Size); err != nil {
+		log.Error("Failed to store evidence file", slog.String("error", err.Error()))
+		return nil, domain.ErrStoreEvidence
+	}
+
+	if err := d.disputeRepository.AddEvidence(ctx, evidence); err != nil {
+		log.Error("Failed to save evidence", slog.String("error", err.Error()))
+		return nil, domain.ErrStoreEvidence
+	}
+
+	log.Info("Upload evidence process executed successfully", slog.String("evidenceID", evidence.ID.String()))
+	return evidence.ToResponse(), nil
+}
+
+func (d *disputeService) DownloadEvidence(ctx context.Context, disputeID, evidenceID uuid.UUID) (*domain.DisputeEvidence, io.ReadCloser, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "dispute"),
+		slog.String("func", "DownloadEvidence"),
+	)
+
+	log.Info("Initializing download evidence process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, nil, domain.ErrSessionNotFound
+	}
+
+	if _, err := d.getParticipantDispute(ctx, disputeID, session.UserID); err != nil {
+		log.Warn("Failed to get dispute", slog.String("disputeID", disputeID.String()), slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	evidence, err := d.disputeRepository.GetEvidence(ctx, disputeID, evidenceID)
+	if err != nil {
+		log.Error("Failed to get evidence", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	if evidence == nil {
+		log.Warn("Evidence not found", slog.String("evidenceID", evidenceID.String()))
+		return nil, nil, domain.ErrEvidenceNotFound
+	}
+
+	content, err := d.fileStorage.Open(ctx, evidence.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileNotFound) {
+			log.Warn("Evidence file missing from storage", slog.String("key", evidence.StorageKey))
+			return nil, nil, domain.ErrEvidenceNotFound
+		}
+
+		log.Error("Failed to open evidence file", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	log.Info("Download evidence process executed successfully")
+	return evidence, content, nil
+}
+
+func (d *disputeService) Resolve(ctx context.Context, disputeID uuid.UUID, payload *domain.ResolveDisputePayload) (*domain.DisputeResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "dispute"),
+		slog.String("func", "Resolve"),
+	)
+
+	log.Info("Initializing resolve dispute process", slog.String("outcome", string(payload.Outcome)))
+
+	var (
+		dispute *domain.Dispute
+		err     error
+	)
+
+	switch payload.Outcome {
+	case domain.DisputeOutcomePayer:
+		dispute, err = d.disputeRepository.ResolveForPayer(ctx, disputeID)
+	case domain.DisputeOutcomeMerchant:
+		dispute, err = d.disputeRepository.ResolveForMerchant(ctx, disputeID)
+	}
+
+	if err != nil {
+		return nil, d.updateError(log, err)
+	}
+
+	if dispute.Status == domain.DisputeStatusResolvedForPayer {
+		d.notify(ctx, dispute.MerchantID, "Dispute lost", "A dispute was resolved in favour of the payer and the payment was reversed.")
+		d.notify(ctx, dispute.PayerID, "Dispute won", "Your dispute was accepted and the payment was returned to your wallet.")
+	} else {
+		d.notify(ctx, dispute.MerchantID, "Dispute won", "A dispute was resolved in your favour.")
+		d.notify(ctx, dispute.PayerID, "Dispute rejected", "Your dispute was resolved in favour of the merchant.")
+	}
+
+	log.Info("Resolve dispute process executed successfully", slog.String("status", string(dispute.Status)))
+	return dispute.ToResponse(), nil
+}
+
+func (d *disputeService) getParticipantDispute(ctx context.Context, disputeID, userID uuid.UUID) (*domain.Dispute, error) {
+	dispute, err := d.disputeRepository.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispute == nil {
+		return nil, domain.ErrDisputeNotFound
+	}
+
+	if !dispute.IsParticipant(userID) {
+		return nil, domain.ErrDisputeForbidden
+	}
+
+	return dispute, nil
+}
+
+func (d *disputeService) updateError(log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrDisputeNotFound) ||
+		errors.Is(err, domain.ErrDisputeAlreadyResolved) ||
+		errors.Is(err, domain.ErrDisputeInvalidTransition) {
+		log.Warn("Dispute update rejected", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Error("Failed to update dispute", slog.String("error", err.Error()))
+	return domain.ErrUpdateDispute
+}
+
+// notify is best effort: a failing notification API must not undo a
+// dispute transition that has already been committed.
+func (d *disputeService) notify(ctx context.Context, userID uuid.UUID, title, message string) {
+	notification := &client.Notification{
+		UserID:  userID,
+		Title:   title,
+		Message: message,
+	}
+
+	if err := d.notificationService.Notify(ctx, notification); err != nil {
+		domain.LoggerFromContext(ctx).Warn("Failed to send dispute notification", slog.String("userID", userID.String()), slog.String("error", err.Error()))
+	}
+}
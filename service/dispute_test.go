@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisputeService_Open_WhenTransferIsHeldInEscrow_ShouldReturnErrDisputeNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	disputeRepositoryMock := mocks.NewMockDisputeRepository(ctrl)
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+
+	disputeService := &disputeService{
+		disputeRepository:  disputeRepositoryMock,
+		transferRepository: transferRepositoryMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:           uuid.New(),
+		PayerID:      uuid.New(),
+		PayeeID:      uuid.New(),
+		Value:        30,
+		EscrowStatus: domain.EscrowStatusHeld,
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: transfer.PayerID})
+
+	transferRepositoryMock.EXPECT().GetByID(gomock.Any(), transfer.ID).Return(transfer, nil)
+
+	_, err := disputeService.Open(ctx, &domain.OpenDisputePayload{TransferID: transfer.ID, Reason: "not delivered"})
+
+	assert.ErrorIs(t, err, domain.ErrDisputeNotAllowed)
+}
+
+func TestDisputeService_Open_WhenDisputeAlreadyExists_ShouldReturnErrDisputeAlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	disputeRepositoryMock := mocks.NewMockDisputeRepository(ctrl)
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+
+	disputeService := &disputeService{
+		disputeRepository:  disputeRepositoryMock,
+		transferRepository: transferRepositoryMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:      uuid.New(),
+		PayerID: uuid.New(),
+		PayeeID: uuid.New(),
+		Value:   30,
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: transfer.PayerID})
+
+	transferRepositoryMock.EXPECT().GetByID(gomock.Any(), transfer.ID).Return(transfer, nil)
+	disputeRepositoryMock.EXPECT().GetByTransferID(gomock.Any(), transfer.ID).Return(&domain.Dispute{ID: uuid.New()}, nil)
+
+	_, err := disputeService.Open(ctx, &domain.OpenDisputePayload{TransferID: transfer.ID, Reason: "not delivered"})
+
+	assert.ErrorIs(t, err, domain.ErrDisputeAlreadyExists)
+}
+
+func TestDisputeService_Resolve_WhenOutcomeIsPayer_ShouldReverseAndNotifyMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	disputeRepositoryMock := mocks.NewMockDisputeRepository(ctrl)
+	notificationServiceMock := mocks.NewMockNotificationService(ctrl)
+
+	disputeService := &disputeService{
+		disputeRepository:   disputeRepositoryMock,
+		notificationService: notificationServiceMock,
+	}
+
+	reversalID := uuid.New()
+	dispute := &domain.Dispute{
+		ID:                 uuid.New(),
+		PayerID:            uuid.New(),
+		MerchantID:         uuid.New(),
+		Status:             domain.DisputeStatusResolvedForPayer,
+		ReversalTransferID: &reversalID,
+	}
+
+	disputeRepositoryMock.EXPECT().ResolveForPayer(gomock.Any(), dispute.ID).Return(dispute, nil)
+	notificationServiceMock.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	response, err := disputeService.Resolve(context.Background(), dispute.ID, &domain.ResolveDisputePayload{Outcome: domain.DisputeOutcomePayer})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DisputeStatusResolvedForPayer, response.Status)
+	assert.Equal(t, &reversalID, response.ReversalTransferID)
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/exchange"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type holdService struct {
+	i                    *do.Injector
+	holdRepository       domain.HoldRepository
+	walletRepository     domain.WalletRepository
+	pinService           domain.TransactionPINService
+	userService          domain.UserService
+	kycService           domain.KYCService
+	rateProvider         exchange.RateProvider
+	campaignService      domain.CampaignService
+	authorizationService client.AuthorizationService
+}
+
+func NewHoldService(i *do.Injector) (domain.HoldService, error) {
+	holdRepository, err := do.Invoke[domain.HoldRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	walletRepository, err := do.Invoke[domain.WalletRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	pinService, err := do.Invoke[domain.TransactionPINService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userService, err := do.Invoke[domain.UserService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	kycService, err := do.Invoke[domain.KYCService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	rateProvider, err := do.Invoke[exchange.RateProvider](i)
+	if err != nil {
+		return nil, err
+	}
+
+	campaignService, err := do.Invoke[domain.CampaignService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationService, err := do.Invoke[client.AuthorizationService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &holdService{
+		i:                    i,
+		holdRepository:       holdRepository,
+		walletRepository:     walletRepository,
+		pinService:           pinService,
+		userService:          userService,
+		kycService:           kycService,
+		rateProvider:         rateProvider,
+		campaignService:      campaignService,
+		authorizationService: authorizationService,
+	}, nil
+}
+
+func (h *holdService) Create(ctx context.Context, payload *domain.HoldPayload) (*domain.HoldResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "hold"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create hold process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	if session.UserID == payload.PayeeID {
+		log.Warn("Attempted self-hold detected", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrSelfTransactionNotAllowed
+	}
+
+	if err := h.userService.EnsureEmailVerified(ctx, session.UserID); err != nil {
+		log.Warn("Hold blocked", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// A captured hold pays the merchant like a transfer, so the whole amount
+	// must fit the transfer limit of the payer.
+	limits, err := h.kycService.GetLimits(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get kyc limits", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, domain.ErrCreateHold
+	}
+
+	value, err := convertValue(ctx, h.rateProvider, payload.Value, payload.Currency, limits.Currency)
+	if err != nil {
+		log.Error("Failed to convert hold value to the kyc limit currency", slog.String("currency", payload.Currency), slog.String("error", err.Error()))
+		return nil, domain.ErrCreateHold
+	}
+
+	if !limits.AllowsTransfer(value) {
+		log.Warn("Hold over the kyc limit", slog.String("userID", session.UserID.String()), slog.Float64("limit", limits.Transfer))
+		return nil, domain.ErrKYCTransferLimitExceeded
+	}
+
+	if err := h.pinService.Verify(ctx, session.UserID, payload.PIN); err != nil {
+		log.Warn("Hold pin rejected", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	payer, err := h.walletRepository.GetByUserID(ctx, session.UserID, payload.Currency)
+	if err != nil {
+		log.Error("Failed to get wallet by userID", slog.String("error", err.Error()))
+		return nil, domain.ErrGetWallet
+	}
+
+	if payer == nil {
+		log.Warn("No wallets were found for this user", slog.String("userId", session.UserID.String()))
+		return nil, domain.ErrPayerWalletNotFound
+	}
+
+	if payer.Type == domain.WalletTypeMERCHANT {
+		log.Warn("Hold not allowed for merchant wallet", slog.String("walletType", "MERCHANT"))
+		return nil, domain.ErrTransferNotAllowedForWalletType
+	}
+
+	payee, err := h.walletRepository.GetByUserID(ctx, payload.PayeeID, payload.Currency)
+	if err != nil {
+		log.Error("Failed to get wallet by userID", slog.String("error", err.Error()))
+		return nil, domain.ErrGetWallet
+	}
+
+	if payee == nil {
+		log.Warn("No wallets were found for this user", slog.String("userId", payload.PayeeID.String()))
+		return nil, domain.ErrPayeeWalletNotFound
+	}
+
+	if payee.Type != domain.WalletTypeMERCHANT {
+		log.Warn("Hold payee is not a merchant", slog.String("userId", payload.PayeeID.String()))
+		return nil, domain.ErrHoldPayeeNotMerchant
+	}
+
+	held, err := h.holdRepository.GetActiveAmountByPayerID(ctx, payer.UserID, payer.Currency)
+	if err != nil {
+		log.Error("Failed to get active hold amount", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateHold
+	}
+
+	if payer.Balance-held < payload.Value {
+		log.Warn("Insufficient available balance for hold")
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	authorizationData, err := h.authorizationService.CheckAuthorization(ctx)
+	if err != nil {
+		log.Error("Error to check user authorization", slog.String("error", err.Error()))
+		return nil, client.ErrCheckAuthorization
+	}
+
+	if !authorizationData.Data.Authorization {
+		log.Warn("Hold authorization failed")
+		return nil, domain.ErrTransferNotAuthorized
+	}
+
+	hold := payload.ToHold(payer.UserID, config.Env.HoldExpiration)
+	if err := h.holdRepository.Create(ctx, hold); err != nil {
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			log.Warn("Insufficient available balance for hold")
+			return nil, err
+		}
+
+		log.Error("Failed to create hold", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateHold
+	}
+
+	log.Info("Hold creation process executed successfully", slog.String("holdID", hold.ID.String()))
+	return hold.ToResponse(), nil
+}
+
+func (h *holdService) GetByID(ctx context.Context, holdID uuid.UUID) (*domain.HoldResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "hold"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get hold process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	hold, err := h.getParticipantHold(ctx, holdID, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get hold", slog.String("holdID", holdID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Get hold process executed successfully")
+	return hold.ToResponse(), nil
+}
+
+func (h *holdService) Capture(ctx context.Context, holdID uuid.UUID, payload *domain.CaptureHoldPayload) (*domain.HoldResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "hold"),
+		slog.String("func", "Capture"),
+	)
+
+	log.Info("Initializing capture hold process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	hold, err := h.getParticipantHold(ctx, holdID, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get hold", slog.String("holdID", holdID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if hold.PayeeID != session.UserID {
+		log.Warn("Only the payee can capture a hold", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrHoldForbidden
+	}
+
+	if !session.AllowsCurrency(hold.Currency) {
+		log.Warn("Api key used for another wallet", slog.String("currency", hold.Currency))
+		return nil, domain.ErrAPIKeyWalletMismatch
+	}
+
+	balanceCap, err := payeeBalanceCap(ctx, h.kycService, h.walletRepository, h.rateProvider, hold.PayeeID, hold.Currency)
+	if err != nil {
+		log.Error("Failed to get payee balance limit", slog.String("payeeID", hold.PayeeID.String()), slog.String("error", err.Error()))
+		return nil, domain.ErrCaptureHold
+	}
+
+	hold, transfer, err := h.holdRepository.Capture(ctx, holdID, payload.Value, payload.Final, balanceCap)
+	if err != nil {
+		if errors.Is(err, domain.ErrHoldNotFound) ||
+			errors.Is(err, domain.ErrHoldNotActive) ||
+			errors.Is(err, domain.ErrHoldExpired) ||
+			errors.Is(err, domain.ErrHoldCaptureExceedsAmount) ||
+			errors.Is(err, domain.ErrInsufficientBalance) ||
+			errors.Is(err, domain.ErrKYCBalanceLimitExceeded) {
+			log.Warn("Hold capture rejected", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Error("Failed to capture hold", slog.String("error", err.Error()))
+		return nil, domain.ErrCaptureHold
+	}
+
+	// Captures settle like any other transfer, so they earn cashback too.
+	if err := h.campaignService.EvaluateTransfer(ctx, transfer); err != nil {
+		log.Warn("Failed to evaluate cashback campaigns", slog.String("transferID", transfer.ID.String()), slog.String("error", err.Error()))
+	}
+
+	log.Info("Capture hold process executed successfully", slog.String("status", string(hold.Status)))
+	return hold.ToResponse(), nil
+}
+
+func (h *holdService) Void(ctx context.Context, holdID uuid.UUID) (*domain.HoldResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "hold"),
+		slog.String("func", "Void"),
+	)
+
+	log.Info("Initializing void hold process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	if _, err := h.getParticipantHold(ctx, holdID, session.UserID); err != nil {
+		log.Warn("Failed to get hold", slog.String("holdID", holdID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	hold, err := h.holdRepository.Void(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, domain.ErrHoldNotFound) || errors.Is(err, domain.ErrHoldNotActive) {
+			log.Warn("Hold void rejected", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Error("Failed to void hold", slog.String("error", err.Error()))
+		return nil, domain.ErrVoidHold
+	}
+
+	log.Info("Void hold process executed successfully")
+	return hold.ToResponse(), nil
+}
+
+func (h *holdService) ReleaseExpired(ctx context.Context) (int64, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "hold"),
+		slog.String("func", "ReleaseExpired"),
+	)
+
+	released, err := h.holdRepository.ExpireActive(ctx, time.Now().UTC())
+	if err != nil {
+		log.Error("Failed to release expired holds", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if released > 0 {
+		log.Info("Expired holds released", slog.Int64("released", released))
+	}
+
+	return released, nil
+}
+
+func (h *holdService) getParticipantHold(ctx context.Context, holdID, userID uuid.UUID) (*domain.Hold, error) {
+	hold, err := h.holdRepository.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold == nil {
+		return nil, domain.ErrHoldNotFound
+	}
+
+	if hold.PayerID != userID && hold.PayeeID != userID {
+		return nil, domain.ErrHoldForbidden
+	}
+
+	return hold, nil
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoldService_Create_WhenActiveHoldsExceedBalance_ShouldReturnErrInsufficientBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	holdService := &holdService{
+		holdRepository:   holdRepositoryMock,
+		walletRepository: walletRepositoryMock,
+		pinService:       pinServiceMock,
+		userService:      userServiceMock,
+		kycService:       kycServiceMock,
+	}
+
+	payerID := uuid.New()
+	payeeID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	payload := &domain.HoldPayload{
+		PayeeID:  payeeID,
+		Value:    60,
+		Currency: domain.DefaultCurrency,
+		PIN:      "1234",
+	}
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), payerID, "1234").Return(nil)
+
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payerID, domain.DefaultCurrency).Return(&domain.Wallet{UserID: payerID, Currency: domain.DefaultCurrency, Type: domain.WalletTypeCOMMON, Balance: 100}, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payeeID, domain.DefaultCurrency).Return(&domain.Wallet{UserID: payeeID, Type: domain.WalletTypeMERCHANT}, nil)
+	holdRepositoryMock.EXPECT().GetActiveAmountByPayerID(gomock.Any(), payerID, domain.DefaultCurrency).Return(50.0, nil)
+
+	_, err := holdService.Create(ctx, payload)
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
+}
+
+func TestHoldService_Create_WhenPayeeIsNotMerchant_ShouldReturnErrHoldPayeeNotMerchant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	holdService := &holdService{
+		holdRepository:   holdRepositoryMock,
+		walletRepository: walletRepositoryMock,
+		pinService:       pinServiceMock,
+		userService:      userServiceMock,
+		kycService:       kycServiceMock,
+	}
+
+	payerID := uuid.New()
+	payeeID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	payload := &domain.HoldPayload{
+		PayeeID:  payeeID,
+		Value:    10,
+		Currency: domain.DefaultCurrency,
+		PIN:      "1234",
+	}
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), payerID, "1234").Return(nil)
+
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payerID, domain.DefaultCurrency).Return(&domain.Wallet{UserID: payerID, Currency: domain.DefaultCurrency, Type: domain.WalletTypeCOMMON, Balance: 100}, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payeeID, domain.DefaultCurrency).Return(&domain.Wallet{UserID: payeeID, Type: domain.WalletTypeCOMMON}, nil)
+
+	_, err := holdService.Create(ctx, payload)
+
+	assert.ErrorIs(t, err, domain.ErrHoldPayeeNotMerchant)
+}
+
+func TestHoldService_Create_WhenEmailIsNotVerified_ShouldReturnErrEmailNotVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+
+	holdService := &holdService{
+		userService: userServiceMock,
+	}
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	payload := &domain.HoldPayload{
+		PayeeID:  uuid.New(),
+		Value:    10,
+		Currency: domain.DefaultCurrency,
+		PIN:      "1234",
+	}
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(domain.ErrEmailNotVerified)
+
+	_, err := holdService.Create(ctx, payload)
+
+	assert.ErrorIs(t, err, domain.ErrEmailNotVerified)
+}
+
+func TestHoldService_Create_WhenPINIsWrong_ShouldNotReserveFunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	holdService := &holdService{
+		holdRepository:   holdRepositoryMock,
+		walletRepository: walletRepositoryMock,
+		pinService:       pinServiceMock,
+		userService:      userServiceMock,
+		kycService:       kycServiceMock,
+	}
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	payload := &domain.HoldPayload{
+		PayeeID:  uuid.New(),
+		Value:    10,
+		Currency: domain.DefaultCurrency,
+		PIN:      "0000",
+	}
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), payerID, "0000").Return(domain.ErrPINInvalid)
+
+	_, err := holdService.Create(ctx, payload)
+
+	assert.ErrorIs(t, err, domain.ErrPINInvalid)
+}
+
+func TestHoldService_Capture_WhenCallerIsPayer_ShouldReturnErrHoldForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+
+	holdService := &holdService{
+		holdRepository: holdRepositoryMock,
+	}
+
+	hold := &domain.Hold{
+		ID:        uuid.New(),
+		PayerID:   uuid.New(),
+		PayeeID:   uuid.New(),
+		Amount:    100,
+		Status:    domain.HoldStatusActive,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: hold.PayerID})
+
+	holdRepositoryMock.EXPECT().GetByID(gomock.Any(), hold.ID).Return(hold, nil)
+
+	_, err := holdService.Capture(ctx, hold.ID, &domain.CaptureHoldPayload{Value: 10})
+
+	assert.ErrorIs(t, err, domain.ErrHoldForbidden)
+}
+
+func TestHoldService_Capture_WhenPartial_ShouldReturnUpdatedHoldAndEvaluateCampaigns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+	campaignServiceMock := mocks.NewMockCampaignService(ctrl)
+
+	holdService := &holdService{
+		holdRepository:  holdRepositoryMock,
+		kycService:      kycServiceMock,
+		campaignService: campaignServiceMock,
+	}
+
+	hold := &domain.Hold{
+		ID:        uuid.New(),
+		PayerID:   uuid.New(),
+		PayeeID:   uuid.New(),
+		Amount:    100,
+		Status:    domain.HoldStatusActive,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: hold.PayeeID})
+
+	captured := *hold
+	captured.CapturedAmount = 40
+	transfer := &domain.Transfer{ID: uuid.New(), PayerID: hold.PayerID, PayeeID: hold.PayeeID, Value: 40, HoldID: &hold.ID}
+
+	holdRepositoryMock.EXPECT().GetByID(gomock.Any(), hold.ID).Return(hold, nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), hold.PayeeID).Return(&domain.KYCLimits{}, nil)
+	holdRepositoryMock.EXPECT().Capture(gomock.Any(), hold.ID, 40.0, false, nil).Return(&captured, transfer, nil)
+	campaignServiceMock.EXPECT().EvaluateTransfer(gomock.Any(), transfer).Return(nil)
+
+	response, err := holdService.Capture(ctx, hold.ID, &domain.CaptureHoldPayload{Value: 40})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HoldStatusActive, response.Status)
+	assert.Equal(t, 40.0, response.CapturedAmount)
+}
+
+func TestHoldService_Create_WhenValueExceedsKYCTransferLimit_ShouldReturnErrKYCTransferLimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+	rateProviderMock := mocks.NewMockRateProvider(ctrl)
+
+	holdService := &holdService{
+		userService:  userServiceMock,
+		kycService:   kycServiceMock,
+		rateProvider: rateProviderMock,
+	}
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	payload := &domain.HoldPayload{
+		PayeeID:  uuid.New(),
+		Value:    100,
+		Currency: "USD",
+		PIN:      "1234",
+	}
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{Transfer: 500, Currency: "BRL"}, nil)
+	rateProviderMock.EXPECT().Rate(gomock.Any(), "USD", "BRL").Return(5.5, nil)
+
+	_, err := holdService.Create(ctx, payload)
+
+	assert.ErrorIs(t, err, domain.ErrKYCTransferLimitExceeded)
+}
+
+func TestHoldService_Capture_WhenPayeeHasBalanceLimit_ShouldCaptureWithinTheCap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	holdService := &holdService{
+		holdRepository:   holdRepositoryMock,
+		walletRepository: walletRepositoryMock,
+		kycService:       kycServiceMock,
+	}
+
+	hold := &domain.Hold{
+		ID:        uuid.New(),
+		PayerID:   uuid.New(),
+		PayeeID:   uuid.New(),
+		Currency:  domain.DefaultCurrency,
+		Amount:    100,
+		Status:    domain.HoldStatusActive,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: hold.PayeeID})
+
+	limits := &domain.KYCLimits{Balance: 2000}
+	holdRepositoryMock.EXPECT().GetByID(gomock.Any(), hold.ID).Return(hold, nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), hold.PayeeID).Return(limits, nil)
+	walletRepositoryMock.EXPECT().GetAllByUserID(gomock.Any(), hold.PayeeID).Return([]*domain.Wallet{{UserID: hold.PayeeID, Currency: domain.DefaultCurrency, Balance: 1990}}, nil)
+	holdRepositoryMock.EXPECT().Capture(gomock.Any(), hold.ID, 0.0, false, &domain.KYCBalanceCap{Limits: limits, Rates: map[string]float64{domain.DefaultCurrency: 1}}).
+		Return(nil, nil, domain.ErrKYCBalanceLimitExceeded)
+
+	_, err := holdService.Capture(ctx, hold.ID, &domain.CaptureHoldPayload{})
+
+	assert.ErrorIs(t, err, domain.ErrKYCBalanceLimitExceeded)
+}
+
+func TestHoldService_Void_WhenHoldNotFound_ShouldReturnErrHoldNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+
+	holdService := &holdService{
+		holdRepository: holdRepositoryMock,
+	}
+
+	holdID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: uuid.New()})
+
+	holdRepositoryMock.EXPECT().GetByID(gomock.Any(), holdID).Return(nil, nil)
+
+	_, err := holdService.Void(ctx, holdID)
+
+	assert.ErrorIs(t, err, domain.ErrHoldNotFound)
+}
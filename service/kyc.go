@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/storage"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+// kycContentTypes are the formats accepted for identity documents, selfies
+// and proofs of address.
+var kycContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+type kycService struct {
+	i              *do.Injector
+	kycRepository  domain.KYCRepository
+	userRepository domain.UserRepository
+	fileStorage    storage.FileStorage
+	emailService   client.EmailService
+}
+
+func NewKYCService(i *do.Injector) (domain.KYCService, error) {
+	kycRepository, err := do.Invoke[domain.KYCRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	fileStorage, err := do.Invoke[storage.FileStorage](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[client.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kycService{
+		i:              i,
+		kycRepository:  kycRepository,
+		userRepository: userRepository,
+		fileStorage:    fileStorage,
+		emailService:   emailService,
+	}, nil
+}
+
+func (k *kycService) GetStatus(ctx context.Context) (*domain.KYCStatusResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "kyc"),
+		slog.String("func", "GetStatus"),
+	)
+
+	log.Info("Initializing get kyc status process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	user, err := k.getUser(ctx, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get user", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	response := &domain.KYCStatusResponse{
+		Tier:   currentKYCTier(user),
+		Limits: kycLimits(user.KYCTier),
+	}
+
+	if next, ok := response.Tier.Next(); ok {
+		response.Upgrade = &domain.KYCUpgradeResponse{
+			Tier:         next,
+			Limits:       kycLimits(next),
+			Requirements: next.Requirements(),
+		}
+	}
+
+	pending, err := k.kycRepository.GetPendingByUserID(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to get pending kyc submission", slog.String("error", err.Error()))
+		return nil, domain.ErrGetKYC
+	}
+
+	if pending != nil {
+		response.PendingSubmission = pending.ToResponse()
+	}
+
+	log.Info("Get kyc status process executed successfully")
+	return response, nil
+}
+
+// Submit applies for the tier above the current one with the documents it
+// requires. Uploads of other kinds are ignored.
+func (k *kycService) Submit(ctx context.Context, uploads []*domain.KYCDocumentUpload) (*domain.KYCSubmissionResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "kyc"),
+		slog.String("func", "Submit"),
+	)
+
+	log.Info("Initializing kyc submission process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	user, err := k.getUser(ctx, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get user", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		log.Warn("Email verification pending", slog.String("userID", user.ID.String()))
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	tier, ok := currentKYCTier(user).Next()
+	if !ok {
+		log.Warn("User already has the highest kyc tier", slog.String("userID", user.ID.String()))
+		return nil, domain.ErrKYCMaxTier
+	}
+
+	pending, err := k.kycRepository.GetPendingByUserID(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to get pending kyc submission", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateKYCSubmission
+	}
+
+	if pending != nil {
+		log.Warn("Kyc submission already under review", slog.String("submissionID", pending.ID.String()))
+		return nil, domain.ErrKYCSubmissionPending
+	}
+
+	submission := &domain.KYCSubmission{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Tier:      tier,
+		Status:    domain.KYCStatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for _, kind := range tier.Requirements() {
+		upload := findKYCUpload(uploads, kind)
+		if upload == nil {
+			log.Warn("Kyc document missing", slog.String("kind", string(kind)))
+			return nil, domain.ErrKYCDocumentMissing
+		}
+
+		if upload.Size > config.Env.MaxUploadSize {
+			log.Warn("Kyc document is too large", slog.String("kind", string(kind)), slog.Int64("size", upload.Size))
+			return nil, domain.ErrKYCDocumentTooLarge
+		}
+
+		if !kycContentTypes[upload.ContentType] {
+			log.Warn("Kyc document has an unsupported type", slog.String("kind", string(kind)), slog.String("contentType", upload.ContentType))
+			return nil, domain.ErrKYCDocumentType
+		}
+
+		document := domain.KYCDocument{
+			ID:           uuid.New(),
+			SubmissionID: submission.ID,
+			Kind:         kind,
+			FileName:     filepath.Base(upload.FileName),
+			ContentType:  upload.ContentType,
+			Size:         upload.Size,
+			CreatedAt:    submission.CreatedAt,
+		}
+		document.StorageKey = fmt.Sprintf("kyc/%s/%s/%s%s", user.ID, submission.ID, document.ID, strings.ToLower(filepath.Ext(document.FileName)))
+
+		if err := k.fileStorage.Save(ctx, document.StorageKey, document.ContentType, upload.Content, upload.Size); err != nil {
+			log.Error("Failed to store kyc document", slog.String("error", err.Error()))
+			return nil, domain.ErrStoreKYCDocument
+		}
+
+		submission.Documents = append(submission.Documents, document)
+	}
+
+	if err := k.kycRepository.CreateSubmission(ctx, submission); err != nil {
+		log.Error("Failed to create kyc submission", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateKYCSubmission
+	}
+
+	log.Info("Kyc submission process executed successfully", slog.String("submissionID", submission.ID.String()), slog.String("tier", string(tier)))
+	return submission.ToResponse(), nil
+}
+
+func (k *kycService) GetPendingSubmissions(ctx context.Context) ([]*domain.KYCSubmissionResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "kyc"),
+		slog.String("func", "GetPendingSubmissions"),
+	)
+
+	log.Info("Initializing get pending kyc submissions process")
+
+	submissions, err := k.kycRepository.GetPendingSubmissions(ctx)
+	if err != nil {
+		log.Error("Failed to get pending kyc submissions", slog.String("error", err.Error()))
+		return nil, domain.ErrGetKYC
+	}
+
+	response := make([]*domain.KYCSubmissionResponse, 0, len(submissions))
+	for _, submission := range submissions {
+		response = append(response, submission.ToResponse())
+	}
+
+	log.Info("Get pending kyc submissions process executed successfully")
+	return response, nil
+}
+
+func (k *kycService) DownloadDocument(ctx context.Context, submissionID, documentID uuid.UUID) (*domain.KYCDocument, io.ReadCloser, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "kyc"),
+		slog.String("func", "DownloadDocument"),
+	)
+
+	log.Info("Initializing download kyc document process")
+
+	document, err := k.kycRepository.GetDocument(ctx, submissionID, documentID)
+	if err != nil {
+		log.Error("Failed to get kyc document", slog.String("error", err.Error()))
+		return nil, nil, domain.ErrGetKYC
+	}
+
+	if document == nil {
+		log.Warn("Kyc document not found", slog.String("documentID", documentID.String()))
+		return nil, nil, domain.ErrKYCDocumentNotFound
+	}
+
+	content, err := k.fileStorage.Open(ctx, document.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileNotFound) {
+			log.Warn("Kyc document missing from storage", slog.String("key", document.StorageKey))
+			return nil, nil, domain.ErrKYCDocumentNotFound
+		}
+
+		log.Error("Failed to open kyc document", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	log.Info("Download kyc document process executed successfully")
+	return document, content, nil
+}
+
+// Review approves or rejects a pending submission. Approval moves the user
+// to the tier of the submission; either way the user is told by email.
+func (k *kycService) Review(ctx context.Context, submissionID uuid.UUID, payload *domain.ReviewKYCPayload) (*domain.KYCSubmissionResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "kyc"),
+		slog.String("func", "Review"),
+	)
+
+	log.Info("Initializing review kyc submission process", slog.String("decision", string(payload.Decision)))
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	status, reason := domain.KYCStatusApproved, ""
+	if payload.Decision == domain.KYCDecisionReject {
+		if payload.Reason == "" {
+			log.Warn("Kyc rejection without reason")
+			return nil, domain.ErrKYCRejectionReasonMissing
+		}
+		status, reason = domain.KYCStatusRejected, payload.Reason
+	}
+
+	submission, err := k.kycRepository.Review(ctx, submissionID, status, reason, session.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrKYCSubmissionNotFound) || errors.Is(err, domain.ErrKYCSubmissionReviewed) || errors.Is(err, domain.ErrKYCSelfReview) {
+			log.Warn("Kyc submission cannot be reviewed", slog.String("submissionID", submissionID.String()), slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Error("Failed to review kyc submission", slog.String("error", err.Error()))
+		return nil, domain.ErrReviewKYCSubmission
+	}
+
+	k.notifyReview(ctx, submission)
+
+	log.Info("Review kyc submission process executed successfully", slog.String("submissionID", submissionID.String()), slog.String("status", string(status)))
+	return submission.ToResponse(), nil
+}
+
+// GetLimits returns the limits of the current tier of the user.
+func (k *kycService) GetLimits(ctx context.Context, userID uuid.UUID) (*domain.KYCLimits, error) {
+	user, err := k.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return kycLimits(user.KYCTier), nil
+}
+
+func (k *kycService) getUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	user, err := k.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrGetKYC
+	}
+
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (k *kycService) notifyReview(ctx context.Context, submission *domain.KYCSubmission) {
+	user, err := k.userRepository.GetByID(ctx, submission.UserID)
+	if err != nil || user == nil {
+		domain.LoggerFromContext(ctx).Error("Failed to get user to notify kyc review", slog.String("service", "kyc"), slog.String("userID", submission.UserID.String()))
+		return
+	}
+
+	email := &client.Email{
+		To:      user.Email,
+		Subject: "Your verification was approved",
+		Text: fmt.Sprintf(
+			"Hi %s,\n\nYour account is now at the %s verification level, with its higher limits.",
+			user.Name, submission.Tier,
+		),
+	}
+
+	if submission.Status == domain.KYCStatusRejected {
+		email.Subject = "Your verification was not approved"
+		email.Text = fmt.Sprintf(
+			"Hi %s,\n\nWe could not approve the documents you sent for the %s verification level: %s\n\nYou can send new documents at any time.",
+			user.Name, submission.Tier, submission.RejectionReason,
+		)
+	}
+
+	if err := k.emailService.Send(ctx, email); err != nil {
+		domain.LoggerFromContext(ctx).Error("Failed to send kyc review notice", slog.String("service", "kyc"), slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+	}
+}
+
+func findKYCUpload(uploads []*domain.KYCDocumentUpload, kind domain.KYCDocumentKind) *domain.KYCDocumentUpload {
+	for _, upload := range uploads {
+		if upload.Kind == kind {
+			return upload
+		}
+	}
+	return nil
+}
+
+// currentKYCTier treats users loaded without a tier as basic.
+func currentKYCTier(user *domain.User) domain.KYCTier {
+	if user.KYCTier == "" {
+		return domain.KYCTierBasic
+	}
+	return user.KYCTier
+}
+
+// kycLimits maps a tier to its configured limits. Unknown tiers get those of
+// basic.
+func kycLimits(tier domain.KYCTier) *domain.KYCLimits {
+	switch tier {
+	case domain.KYCTierVerified:
+		return &domain.KYCLimits{Currency: config.Env.KYCLimitCurrency, Transfer: config.Env.KYCVerifiedTransferLimit, Balance: config.Env.KYCVerifiedBalanceLimit}
+	case domain.KYCTierEnhanced:
+		return &domain.KYCLimits{Currency: config.Env.KYCLimitCurrency, Transfer: config.Env.KYCEnhancedTransferLimit, Balance: config.Env.KYCEnhancedBalanceLimit}
+	}
+	return &domain.KYCLimits{Currency: config.Env.KYCLimitCurrency, Transfer: config.Env.KYCBasicTransferLimit, Balance: config.Env.KYCBasicBalanceLimit}
+}
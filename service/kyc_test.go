@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKYCService_Submit_WhenRequiredDocumentIsMissing_ShouldReturnErrKYCDocumentMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kycRepositoryMock := mocks.NewMockKYCRepository(ctrl)
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	kycService := &kycService{
+		kycRepository:  kycRepositoryMock,
+		userRepository: userRepositoryMock,
+	}
+
+	userID := uuid.New()
+	verifiedAt := time.Now()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), userID).Return(&domain.User{ID: userID, KYCTier: domain.KYCTierBasic, EmailVerifiedAt: &verifiedAt}, nil)
+	kycRepositoryMock.EXPECT().GetPendingByUserID(gomock.Any(), userID).Return(nil, nil)
+
+	_, err := kycService.Submit(ctx, []*domain.KYCDocumentUpload{
+		{Kind: domain.KYCDocumentSelfie, FileName: "selfie.jpg", ContentType: "image/jpeg"},
+	})
+
+	assert.ErrorIs(t, err, domain.ErrKYCDocumentMissing)
+}
+
+func TestKYCService_Review_WhenRejectedWithoutReason_ShouldReturnErrKYCRejectionReasonMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	kycRepositoryMock := mocks.NewMockKYCRepository(ctrl)
+
+	kycService := &kycService{
+		kycRepository: kycRepositoryMock,
+	}
+
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: uuid.New()})
+
+	_, err := kycService.Review(ctx, uuid.New(), &domain.ReviewKYCPayload{Decision: domain.KYCDecisionReject})
+
+	assert.ErrorIs(t, err, domain.ErrKYCRejectionReasonMissing)
+}
@@ -0,0 +1,725 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/keyring"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const (
+	oauthClientSecretSize = 32
+	oauthCodeSize         = 32
+	oauthRefreshTokenSize = 32
+
+	// PKCE verifiers are 43 to 128 characters long, and an S256 challenge is
+	// always 43 (RFC 7636).
+	oauthCodeVerifierMinLength = 43
+	oauthCodeVerifierMaxLength = 128
+	oauthCodeChallengeLength   = 43
+)
+
+type oauthService struct {
+	i                     *do.Injector
+	oauthClientRepository domain.OAuthClientRepository
+	oauthTokenRepository  domain.OAuthTokenRepository
+	userRepository        domain.UserRepository
+	keyring               keyring.Keyring
+}
+
+func NewOAuthService(i *do.Injector) (domain.OAuthService, error) {
+	oauthClientRepository, err := do.Invoke[domain.OAuthClientRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	oauthTokenRepository, err := do.Invoke[domain.OAuthTokenRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := do.Invoke[keyring.Keyring](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauthService{
+		i:                     i,
+		oauthClientRepository: oauthClientRepository,
+		oauthTokenRepository:  oauthTokenRepository,
+		userRepository:        userRepository,
+		keyring:               keys,
+	}, nil
+}
+
+// CreateClient registers a third-party app owned by the signed in user.
+// Confidential clients get a secret, returned only in this response.
+func (o *oauthService) CreateClient(ctx context.Context, payload *domain.OAuthClientPayload) (*domain.CreateOAuthClientResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "CreateClient"),
+	)
+
+	log.Info("Initializing create oauth client process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	var secret, secretHash string
+	if payload.Confidential {
+		generated, err := secure.GenerateToken(oauthClientSecretSize)
+		if err != nil {
+			log.Error("Failed to generate client secret", slog.String("error", err.Error()))
+			return nil, domain.ErrCreateOAuthClient
+		}
+		secret, secretHash = generated, secure.HashToken(generated)
+	}
+
+	client := payload.ToOAuthClient(session.UserID, secretHash)
+	if err := o.oauthClientRepository.Create(ctx, client); err != nil {
+		log.Error("Failed to create oauth client", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateOAuthClient
+	}
+
+	log.Info("Create oauth client process executed successfully", slog.String("clientID", client.ID.String()))
+	return &domain.CreateOAuthClientResponse{
+		OAuthClientResponse: *client.ToResponse(),
+		ClientSecret:        secret,
+	}, nil
+}
+
+func (o *oauthService) GetClients(ctx context.Context) ([]*domain.OAuthClientResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "GetClients"),
+	)
+
+	log.Info("Initializing get oauth clients process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	clients, err := o.oauthClientRepository.GetAllByOwnerID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get oauth clients", slog.String("error", err.Error()))
+		return nil, domain.ErrGetOAuthClients
+	}
+
+	response := make([]*domain.OAuthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		response = append(response, client.ToResponse())
+	}
+
+	log.Info("Get oauth clients process executed successfully")
+	return response, nil
+}
+
+func (o *oauthService) DeleteClient(ctx context.Context, clientID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "DeleteClient"),
+	)
+
+	log.Info("Initializing delete oauth client process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if err := o.oauthClientRepository.Delete(ctx, session.UserID, clientID); err != nil {
+		if errors.Is(err, domain.ErrOAuthClientNotFound) {
+			log.Warn("Oauth client not found", slog.String("clientID", clientID.String()))
+			return err
+		}
+
+		log.Error("Failed to delete oauth client", slog.String("error", err.Error()))
+		return domain.ErrDeleteOAuthClient
+	}
+
+	// Refresh tokens die with the client, which can no longer authenticate,
+	// but access tokens it already holds stay valid until revoked.
+	revoked, err := o.oauthTokenRepository.RevokeClientGrants(ctx, clientID)
+	if err != nil {
+		log.Error("Failed to revoke tokens of deleted oauth client", slog.String("clientID", clientID.String()), slog.String("error", err.Error()))
+		return domain.ErrDeleteOAuthClient
+	}
+
+	log.Info("Delete oauth client process executed successfully", slog.String("clientID", clientID.String()), slog.Int("revokedGrants", revoked))
+	return nil
+}
+
+// GetConsent checks an authorization request and describes it for the
+// consent screen of the signed in user.
+func (o *oauthService) GetConsent(ctx context.Context, request *domain.OAuthAuthorizeRequest) (*domain.OAuthConsentResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "GetConsent"),
+	)
+
+	log.Info("Initializing get oauth consent process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	client, redirectURI, scopes, err := o.validateAuthorizeRequest(ctx, request)
+	if err != nil {
+		log.Warn("Invalid authorization request", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	response := &domain.OAuthConsentResponse{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		RedirectURI: redirectURI,
+		Scopes:      make([]*domain.OAuthScopeResponse, 0, len(scopes)),
+		State:       request.State,
+	}
+	for _, scope := range scopes {
+		response.Scopes = append(response.Scopes, &domain.OAuthScopeResponse{Scope: scope, Description: scope.Description()})
+	}
+
+	log.Info("Get oauth consent process executed successfully", slog.String("clientID", client.ID.String()))
+	return response, nil
+}
+
+// Authorize records the answer of the user to an authorization request.
+// Approving it issues a single use code bound to the PKCE challenge;
+// either way the user is sent back to the client.
+func (o *oauthService) Authorize(ctx context.Context, request *domain.OAuthAuthorizeRequest) (*domain.OAuthAuthorizeResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "Authorize"),
+	)
+
+	log.Info("Initializing oauth authorize process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	client, redirectURI, scopes, err := o.validateAuthorizeRequest(ctx, request)
+	if err != nil {
+		log.Warn("Invalid authorization request", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !request.Approved {
+		log.Info("Authorization denied by the user", slog.String("clientID", client.ID.String()))
+		return &domain.OAuthAuthorizeResponse{
+			RedirectURI: domain.ErrOAuthAccessDenied.WithRedirect(redirectURI, request.State).RedirectURI,
+		}, nil
+	}
+
+	code, err := secure.GenerateToken(oauthCodeSize)
+	if err != nil {
+		log.Error("Failed to generate authorization code", slog.String("error", err.Error()))
+		return nil, domain.ErrOAuthAuthorize
+	}
+
+	authorizationCode := &domain.OAuthAuthorizationCode{
+		ClientID:      client.ID,
+		UserID:        session.UserID,
+		RedirectURI:   redirectURI,
+		Scopes:        scopes,
+		CodeChallenge: request.CodeChallenge,
+	}
+
+	if err := o.oauthTokenRepository.CreateCode(ctx, secure.HashToken(code), authorizationCode); err != nil {
+		log.Error("Failed to save authorization code", slog.String("error", err.Error()))
+		return nil, domain.ErrOAuthAuthorize
+	}
+
+	params := url.Values{"code": {code}}
+	if request.State != "" {
+		params.Set("state", request.State)
+	}
+
+	log.Info("Oauth authorize process executed successfully", slog.String("clientID", client.ID.String()))
+	return &domain.OAuthAuthorizeResponse{RedirectURI: domain.AppendQuery(redirectURI, params)}, nil
+}
+
+// Token is the token endpoint, exchanging an authorization code or a
+// refresh token for a new access and refresh token pair.
+func (o *oauthService) Token(ctx context.Context, request *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "Token"),
+	)
+
+	log.Info("Initializing oauth token process", slog.String("grantType", request.GrantType))
+
+	client, err := o.authenticateClient(ctx, &request.OAuthClientCredentials)
+	if err != nil {
+		log.Warn("Client authentication failed", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var response *domain.OAuthTokenResponse
+	switch request.GrantType {
+	case domain.OAuthGrantTypeAuthorizationCode:
+		response, err = o.exchangeCode(ctx, client, request)
+	case domain.OAuthGrantTypeRefreshToken:
+		response, err = o.exchangeRefreshToken(ctx, client, request)
+	default:
+		err = domain.ErrOAuthUnsupportedGrantType
+	}
+
+	if err != nil {
+		log.Warn("Token request rejected", slog.String("clientID", client.ID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Oauth token process executed successfully", slog.String("clientID", client.ID.String()))
+	return response, nil
+}
+
+// Introspect describes a token issued to the calling client (RFC 7662).
+// Tokens of other clients are reported as inactive.
+func (o *oauthService) Introspect(ctx context.Context, credentials *domain.OAuthClientCredentials, token string) (*domain.OAuthIntrospectionResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "Introspect"),
+	)
+
+	log.Info("Initializing oauth introspect process")
+
+	client, err := o.authenticateClient(ctx, credentials)
+	if err != nil {
+		log.Warn("Client authentication failed", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if grant, subject, issuedAt, err := o.parseAccessToken(ctx, token); err == nil {
+		if grant.ClientID != client.ID {
+			return &domain.OAuthIntrospectionResponse{}, nil
+		}
+
+		return &domain.OAuthIntrospectionResponse{
+			Active:    true,
+			Scope:     domain.JoinOAuthScopes(grant.Scopes),
+			ClientID:  grant.ClientID.String(),
+			Subject:   subject.String(),
+			TokenType: domain.OAuthTokenTypeBearer,
+			ExpiresAt: grant.ExpiresAt.Unix(),
+			IssuedAt:  issuedAt.Unix(),
+		}, nil
+	}
+
+	refreshToken, err := o.oauthTokenRepository.GetRefreshToken(ctx, secure.HashToken(token))
+	if err != nil {
+		log.Error("Failed to get refresh token", slog.String("error", err.Error()))
+		return nil, domain.ErrOAuthToken
+	}
+
+	if refreshToken == nil || refreshToken.ClientID != client.ID {
+		return &domain.OAuthIntrospectionResponse{}, nil
+	}
+
+	log.Info("Oauth introspect process executed successfully")
+	return &domain.OAuthIntrospectionResponse{
+		Active:   true,
+		Scope:    domain.JoinOAuthScopes(refreshToken.Scopes),
+		ClientID: refreshToken.ClientID.String(),
+		Subject:  refreshToken.UserID.String(),
+	}, nil
+}
+
+// Revoke invalidates an access or refresh token of the calling client (RFC
+// 7009). Unknown tokens are not an error.
+func (o *oauthService) Revoke(ctx context.Context, credentials *domain.OAuthClientCredentials, token string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing oauth revoke process")
+
+	client, err := o.authenticateClient(ctx, credentials)
+	if err != nil {
+		log.Warn("Client authentication failed", slog.String("error", err.Error()))
+		return err
+	}
+
+	if grant, _, _, err := o.parseAccessToken(ctx, token); err == nil {
+		if grant.ClientID != client.ID {
+			return nil
+		}
+
+		if err := o.oauthTokenRepository.RevokeAccessToken(ctx, grant.TokenID, time.Until(grant.ExpiresAt)); err != nil {
+			log.Error("Failed to revoke access token", slog.String("error", err.Error()))
+			return domain.ErrOAuthToken
+		}
+
+		log.Info("Oauth revoke process executed successfully", slog.String("clientID", client.ID.String()))
+		return nil
+	}
+
+	hash := secure.HashToken(token)
+	refreshToken, err := o.oauthTokenRepository.GetRefreshToken(ctx, hash)
+	if err != nil {
+		log.Error("Failed to get refresh token", slog.String("error", err.Error()))
+		return domain.ErrOAuthToken
+	}
+
+	if refreshToken == nil || refreshToken.ClientID != client.ID {
+		return nil
+	}
+
+	if _, err := o.oauthTokenRepository.TakeRefreshToken(ctx, hash); err != nil {
+		log.Error("Failed to revoke refresh token", slog.String("error", err.Error()))
+		return domain.ErrOAuthToken
+	}
+
+	log.Info("Oauth revoke process executed successfully", slog.String("clientID", client.ID.String()))
+	return nil
+}
+
+// Authenticate resolves an OAuth access token into the principal of the
+// user it was issued for. Scopes are checked by the caller.
+func (o *oauthService) Authenticate(ctx context.Context, token string) (*domain.Session, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "oauth"),
+		slog.String("func", "Authenticate"),
+	)
+
+	grant, subject, _, err := o.parseAccessToken(ctx, token)
+	if err != nil {
+		log.Warn("Invalid oauth access token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	user, err := o.userRepository.GetByID(ctx, subject)
+	if err != nil {
+		log.Error("Failed to get user", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil {
+		log.Warn("Oauth access token of an unknown user", slog.String("userID", subject.String()))
+		return nil, domain.ErrTokenInvalid
+	}
+
+	return &domain.Session{
+		Name:   user.Name,
+		UserID: user.ID,
+		Email:  user.Email,
+		OAuth:  grant,
+	}, nil
+}
+
+func (o *oauthService) UserInfo(ctx context.Context) (*domain.OAuthUserInfoResponse, error) {
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	return &domain.OAuthUserInfoResponse{
+		Subject: session.UserID,
+		Name:    session.Name,
+		Email:   session.Email,
+	}, nil
+}
+
+// validateAuthorizeRequest resolves the client and redirect URI of an
+// authorization request first: until both are known errors are shown to the
+// user, and after that they are sent back to the client.
+func (o *oauthService) validateAuthorizeRequest(ctx context.Context, request *domain.OAuthAuthorizeRequest) (*domain.OAuthClient, string, []domain.OAuthScope, error) {
+	clientID, err := uuid.Parse(request.ClientID)
+	if err != nil {
+		return nil, "", nil, domain.ErrOAuthUnknownClient
+	}
+
+	client, err := o.oauthClientRepository.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, "", nil, domain.ErrOAuthAuthorize
+	}
+
+	if client == nil {
+		return nil, "", nil, domain.ErrOAuthUnknownClient
+	}
+
+	redirectURI, ok := client.ResolveRedirectURI(request.RedirectURI)
+	if !ok {
+		return nil, "", nil, domain.ErrOAuthInvalidRedirectURI
+	}
+
+	if request.ResponseType != domain.OAuthResponseTypeCode {
+		return nil, "", nil, domain.ErrOAuthUnsupportedResponseType.WithRedirect(redirectURI, request.State)
+	}
+
+	if request.CodeChallengeMethod != domain.OAuthCodeChallengeMethodS256 || len(request.CodeChallenge) != oauthCodeChallengeLength {
+		return nil, "", nil, domain.ErrOAuthPKCERequired.WithRedirect(redirectURI, request.State)
+	}
+
+	scopes, ok := domain.ParseOAuthScopes(request.Scope)
+	if !ok || len(scopes) == 0 || !client.AllowsScopes(scopes) {
+		return nil, "", nil, domain.ErrOAuthInvalidScope.WithRedirect(redirectURI, request.State)
+	}
+
+	return client, redirectURI, scopes, nil
+}
+
+// authenticateClient checks the credentials of a client calling the token
+// endpoints. Confidential clients must send their secret and public ones
+// must not send any.
+func (o *oauthService) authenticateClient(ctx context.Context, credentials *domain.OAuthClientCredentials) (*domain.OAuthClient, error) {
+	clientID, err := uuid.Parse(credentials.ClientID)
+	if err != nil {
+		return nil, domain.ErrOAuthInvalidClient
+	}
+
+	client, err := o.oauthClientRepository.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	if client == nil {
+		return nil, domain.ErrOAuthInvalidClient
+	}
+
+	if !client.IsConfidential() {
+		if credentials.Secret != "" {
+			return nil, domain.ErrOAuthInvalidClient
+		}
+		return client, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secure.HashToken(credentials.Secret)), []byte(client.SecretHash)) != 1 {
+		return nil, domain.ErrOAuthInvalidClient
+	}
+
+	return client, nil
+}
+
+func (o *oauthService) exchangeCode(ctx context.Context, client *domain.OAuthClient, request *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	if request.Code == "" || len(request.CodeVerifier) < oauthCodeVerifierMinLength || len(request.CodeVerifier) > oauthCodeVerifierMaxLength {
+		return nil, domain.ErrOAuthInvalidRequest
+	}
+
+	code, err := o.oauthTokenRepository.TakeCode(ctx, secure.HashToken(request.Code))
+	if err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	if code == nil || code.ClientID != client.ID {
+		return nil, domain.ErrOAuthInvalidGrant
+	}
+
+	if redirectURI, ok := client.ResolveRedirectURI(request.RedirectURI); !ok || redirectURI != code.RedirectURI {
+		return nil, domain.ErrOAuthInvalidGrant
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secure.CodeChallengeS256(request.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, domain.ErrOAuthInvalidGrant
+	}
+
+	return o.issueTokens(ctx, client, code.UserID, code.Scopes)
+}
+
+// exchangeRefreshToken rotates a refresh token. The client may ask for
+// fewer scopes than it was granted, never for more.
+func (o *oauthService) exchangeRefreshToken(ctx context.Context, client *domain.OAuthClient, request *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	if request.RefreshToken == "" {
+		return nil, domain.ErrOAuthInvalidRequest
+	}
+
+	refreshToken, err := o.oauthTokenRepository.TakeRefreshToken(ctx, secure.HashToken(request.RefreshToken))
+	if err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	if refreshToken == nil || refreshToken.ClientID != client.ID {
+		return nil, domain.ErrOAuthInvalidGrant
+	}
+
+	scopes := refreshToken.Scopes
+	if request.Scope != "" {
+		requested, ok := domain.ParseOAuthScopes(request.Scope)
+		if !ok || len(requested) == 0 {
+			return nil, domain.ErrOAuthInvalidScope
+		}
+
+		granted := &domain.OAuthGrant{Scopes: refreshToken.Scopes}
+		for _, scope := range requested {
+			if !granted.HasScope(scope) {
+				return nil, domain.ErrOAuthInvalidScope
+			}
+		}
+		scopes = requested
+	}
+
+	return o.issueTokens(ctx, client, refreshToken.UserID, scopes)
+}
+
+func (o *oauthService) issueTokens(ctx context.Context, client *domain.OAuthClient, userID uuid.UUID, scopes []domain.OAuthScope) (*domain.OAuthTokenResponse, error) {
+	user, err := o.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	if user == nil {
+		return nil, domain.ErrOAuthInvalidGrant
+	}
+
+	accessToken, grant, err := o.createAccessToken(client, user, scopes)
+	if err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	refreshToken, err := secure.GenerateToken(oauthRefreshTokenSize)
+	if err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	stored := &domain.OAuthRefreshToken{
+		ClientID: client.ID,
+		UserID:   user.ID,
+		Scopes:   scopes,
+	}
+
+	grant.RefreshTokenHash = secure.HashToken(refreshToken)
+	if err := o.oauthTokenRepository.CreateRefreshToken(ctx, grant.RefreshTokenHash, stored); err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	if err := o.oauthTokenRepository.RecordGrant(ctx, grant); err != nil {
+		return nil, domain.ErrOAuthToken
+	}
+
+	return &domain.OAuthTokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    domain.OAuthTokenTypeBearer,
+		ExpiresIn:    int64(config.Env.OAuthAccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        domain.JoinOAuthScopes(scopes),
+	}, nil
+}
+
+// createAccessToken signs a JWT access token (RFC 9068) with the same keys
+// as session tokens. The at+jwt type keeps either from being accepted as
+// the other. The returned grant identifies the token for revocation.
+func (o *oauthService) createAccessToken(client *domain.OAuthClient, user *domain.User, scopes []domain.OAuthScope) (string, *domain.OAuthIssuedGrant, error) {
+	key, err := o.keyring.SigningKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now().UTC()
+	grant := &domain.OAuthIssuedGrant{
+		ClientID:        client.ID,
+		UserID:          user.ID,
+		AccessTokenID:   uuid.NewString(),
+		AccessExpiresAt: now.Add(config.Env.OAuthAccessTokenTTL),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"sub":       user.ID.String(),
+		"iss":       config.Env.TokenIssuer,
+		"aud":       config.Env.TokenAudience,
+		"iat":       now.Unix(),
+		"exp":       grant.AccessExpiresAt.Unix(),
+		"jti":       grant.AccessTokenID,
+		"client_id": client.ID.String(),
+		"scope":     domain.JoinOAuthScopes(scopes),
+	})
+
+	token.Header["typ"] = domain.OAuthAccessTokenType
+	token.Header["kid"] = key.ID
+
+	signed, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, grant, nil
+}
+
+// parseAccessToken verifies an OAuth access token and returns what it
+// grants, to whom, and when it was issued. Revoked tokens are invalid.
+func (o *oauthService) parseAccessToken(ctx context.Context, tokenString string) (*domain.OAuthGrant, uuid.UUID, time.Time, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, domain.ErrorUnexpectedMethod
+		}
+
+		if typ, _ := token.Header["typ"].(string); typ != domain.OAuthAccessTokenType {
+			return nil, domain.ErrTokenInvalid
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return o.keyring.VerificationKey(kid)
+	})
+	if err != nil || !token.Valid {
+		return nil, uuid.Nil, time.Time{}, domain.ErrTokenInvalid
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, uuid.Nil, time.Time{}, domain.ErrTokenInvalid
+	}
+
+	if err := verifyRegisteredClaims(claims); err != nil {
+		return nil, uuid.Nil, time.Time{}, err
+	}
+
+	subjectClaim, _ := claims["sub"].(string)
+	subject, err := uuid.Parse(subjectClaim)
+	if err != nil {
+		return nil, uuid.Nil, time.Time{}, domain.ErrTokenInvalid
+	}
+
+	clientIDClaim, _ := claims["client_id"].(string)
+	clientID, err := uuid.Parse(clientIDClaim)
+	if err != nil {
+		return nil, uuid.Nil, time.Time{}, domain.ErrTokenInvalid
+	}
+
+	scopeClaim, _ := claims["scope"].(string)
+	scopes, ok := domain.ParseOAuthScopes(scopeClaim)
+	if !ok {
+		return nil, uuid.Nil, time.Time{}, domain.ErrTokenInvalid
+	}
+
+	tokenID, _ := claims["jti"].(string)
+	revoked, err := o.oauthTokenRepository.IsAccessTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return nil, uuid.Nil, time.Time{}, err
+	}
+
+	if revoked {
+		return nil, uuid.Nil, time.Time{}, domain.ErrTokenInvalid
+	}
+
+	expiresAt, _ := claims["exp"].(float64)
+	issuedAt, _ := claims["iat"].(float64)
+
+	return &domain.OAuthGrant{
+		ClientID:  clientID,
+		Scopes:    scopes,
+		TokenID:   tokenID,
+		ExpiresAt: time.Unix(int64(expiresAt), 0).UTC(),
+	}, subject, time.Unix(int64(issuedAt), 0).UTC(), nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/keyring"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const oauthTestVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+func newOAuthTestClient() *domain.OAuthClient {
+	return &domain.OAuthClient{
+		ID:           uuid.New(),
+		OwnerID:      uuid.New(),
+		Name:         "Budget App",
+		RedirectURIs: "https://app.example.com/callback",
+		Scopes:       "profile wallets:read",
+	}
+}
+
+func TestOAuthService_Authorize_WhenPKCEIsMissing_ShouldRedirectWithInvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oauthClientRepositoryMock := mocks.NewMockOAuthClientRepository(ctrl)
+
+	oauthService := &oauthService{
+		oauthClientRepository: oauthClientRepositoryMock,
+	}
+
+	client := newOAuthTestClient()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: uuid.New()})
+
+	request := &domain.OAuthAuthorizeRequest{
+		ResponseType: domain.OAuthResponseTypeCode,
+		ClientID:     client.ID.String(),
+		Scope:        "profile",
+		State:        "xyz",
+		Approved:     true,
+	}
+
+	oauthClientRepositoryMock.EXPECT().GetByID(gomock.Any(), client.ID).Return(client, nil)
+
+	_, err := oauthService.Authorize(ctx, request)
+
+	assert.ErrorIs(t, err, domain.ErrOAuthPKCERequired)
+
+	var oauthError *domain.OAuthError
+	assert.ErrorAs(t, err, &oauthError)
+	assert.True(t, strings.HasPrefix(oauthError.RedirectURI, "https://app.example.com/callback?"))
+	assert.Contains(t, oauthError.RedirectURI, "state=xyz")
+}
+
+func TestOAuthService_Authorize_WhenScopeWasNotRegistered_ShouldReturnErrOAuthInvalidScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oauthClientRepositoryMock := mocks.NewMockOAuthClientRepository(ctrl)
+
+	oauthService := &oauthService{
+		oauthClientRepository: oauthClientRepositoryMock,
+	}
+
+	client := newOAuthTestClient()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: uuid.New()})
+
+	request := &domain.OAuthAuthorizeRequest{
+		ResponseType:        domain.OAuthResponseTypeCode,
+		ClientID:            client.ID.String(),
+		Scope:               "profile transfers:write",
+		CodeChallenge:       secure.CodeChallengeS256(oauthTestVerifier),
+		CodeChallengeMethod: domain.OAuthCodeChallengeMethodS256,
+		Approved:            true,
+	}
+
+	oauthClientRepositoryMock.EXPECT().GetByID(gomock.Any(), client.ID).Return(client, nil)
+
+	_, err := oauthService.Authorize(ctx, request)
+
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidScope)
+}
+
+func TestOAuthService_Token_WhenSecretIsWrong_ShouldReturnErrOAuthInvalidClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oauthClientRepositoryMock := mocks.NewMockOAuthClientRepository(ctrl)
+
+	oauthService := &oauthService{
+		oauthClientRepository: oauthClientRepositoryMock,
+	}
+
+	client := newOAuthTestClient()
+	client.SecretHash = secure.HashToken("secret")
+
+	request := &domain.OAuthTokenRequest{
+		OAuthClientCredentials: domain.OAuthClientCredentials{ClientID: client.ID.String(), Secret: "wrong"},
+		GrantType:              domain.OAuthGrantTypeAuthorizationCode,
+		Code:                   "code",
+		CodeVerifier:           oauthTestVerifier,
+	}
+
+	oauthClientRepositoryMock.EXPECT().GetByID(gomock.Any(), client.ID).Return(client, nil)
+
+	_, err := oauthService.Token(context.Background(), request)
+
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidClient)
+}
+
+func TestOAuthService_Token_WhenCodeVerifierDoesNotMatch_ShouldReturnErrOAuthInvalidGrant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oauthClientRepositoryMock := mocks.NewMockOAuthClientRepository(ctrl)
+	oauthTokenRepositoryMock := mocks.NewMockOAuthTokenRepository(ctrl)
+
+	oauthService := &oauthService{
+		oauthClientRepository: oauthClientRepositoryMock,
+		oauthTokenRepository:  oauthTokenRepositoryMock,
+	}
+
+	client := newOAuthTestClient()
+	code := &domain.OAuthAuthorizationCode{
+		ClientID:      client.ID,
+		UserID:        uuid.New(),
+		RedirectURI:   "https://app.example.com/callback",
+		Scopes:        []domain.OAuthScope{domain.OAuthScopeProfile},
+		CodeChallenge: secure.CodeChallengeS256(oauthTestVerifier),
+	}
+
+	request := &domain.OAuthTokenRequest{
+		OAuthClientCredentials: domain.OAuthClientCredentials{ClientID: client.ID.String()},
+		GrantType:              domain.OAuthGrantTypeAuthorizationCode,
+		Code:                   "code",
+		CodeVerifier:           strings.Repeat("a", 43),
+	}
+
+	oauthClientRepositoryMock.EXPECT().GetByID(gomock.Any(), client.ID).Return(client, nil)
+	oauthTokenRepositoryMock.EXPECT().TakeCode(gomock.Any(), secure.HashToken("code")).Return(code, nil)
+
+	_, err := oauthService.Token(context.Background(), request)
+
+	assert.ErrorIs(t, err, domain.ErrOAuthInvalidGrant)
+}
+
+func TestOAuthService_AuthorizationCodeFlow_ShouldIssueScopedAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oauthClientRepositoryMock := mocks.NewMockOAuthClientRepository(ctrl)
+	oauthTokenRepositoryMock := mocks.NewMockOAuthTokenRepository(ctrl)
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	keyringMock := mocks.NewMockKeyring(ctrl)
+
+	oauthService := &oauthService{
+		oauthClientRepository: oauthClientRepositoryMock,
+		oauthTokenRepository:  oauthTokenRepositoryMock,
+		userRepository:        userRepositoryMock,
+		keyring:               keyringMock,
+	}
+
+	config.Env.TokenIssuer = "issuer"
+	config.Env.TokenAudience = "audience"
+	config.Env.OAuthAccessTokenTTL = 15 * time.Minute
+	defer func() {
+		config.Env.TokenIssuer = ""
+		config.Env.TokenAudience = ""
+		config.Env.OAuthAccessTokenTTL = 0
+	}()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	client := newOAuthTestClient()
+	user := &domain.User{ID: uuid.New(), Name: "Jane", Email: "jane@example.com"}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: user.ID})
+
+	var stored *domain.OAuthAuthorizationCode
+	oauthClientRepositoryMock.EXPECT().GetByID(gomock.Any(), client.ID).Return(client, nil).Times(2)
+	oauthTokenRepositoryMock.EXPECT().CreateCode(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, code *domain.OAuthAuthorizationCode) error {
+			stored = code
+			return nil
+		})
+
+	authorized, err := oauthService.Authorize(ctx, &domain.OAuthAuthorizeRequest{
+		ResponseType:        domain.OAuthResponseTypeCode,
+		ClientID:            client.ID.String(),
+		Scope:               "profile",
+		State:               "xyz",
+		CodeChallenge:       secure.CodeChallengeS256(oauthTestVerifier),
+		CodeChallengeMethod: domain.OAuthCodeChallengeMethodS256,
+		Approved:            true,
+	})
+	assert.NoError(t, err)
+
+	redirect, err := url.Parse(authorized.RedirectURI)
+	assert.NoError(t, err)
+	assert.Equal(t, "xyz", redirect.Query().Get("state"))
+	code := redirect.Query().Get("code")
+
+	oauthTokenRepositoryMock.EXPECT().TakeCode(gomock.Any(), secure.HashToken(code)).Return(stored, nil)
+	var refreshTokenHash string
+	oauthTokenRepositoryMock.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tokenHash string, _ *domain.OAuthRefreshToken) error {
+			refreshTokenHash = tokenHash
+			return nil
+		})
+	var grant *domain.OAuthIssuedGrant
+	oauthTokenRepositoryMock.EXPECT().RecordGrant(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, issued *domain.OAuthIssuedGrant) error {
+			grant = issued
+			return nil
+		})
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil).Times(2)
+	keyringMock.EXPECT().SigningKey().Return(&keyring.Key{ID: "kid", PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}, nil)
+	keyringMock.EXPECT().VerificationKey("kid").Return(&privateKey.PublicKey, nil)
+	oauthTokenRepositoryMock.EXPECT().IsAccessTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+
+	response, err := oauthService.Token(context.Background(), &domain.OAuthTokenRequest{
+		OAuthClientCredentials: domain.OAuthClientCredentials{ClientID: client.ID.String()},
+		GrantType:              domain.OAuthGrantTypeAuthorizationCode,
+		Code:                   code,
+		CodeVerifier:           oauthTestVerifier,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "profile", response.Scope)
+
+	session, err := oauthService.Authenticate(context.Background(), response.AccessToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, session.UserID)
+	assert.Equal(t, client.ID, session.OAuth.ClientID)
+	assert.True(t, session.OAuth.HasScope(domain.OAuthScopeProfile))
+	assert.False(t, session.OAuth.HasScope(domain.OAuthScopeWalletsRead))
+
+	assert.Equal(t, client.ID, grant.ClientID)
+	assert.Equal(t, user.ID, grant.UserID)
+	assert.Equal(t, session.OAuth.TokenID, grant.AccessTokenID)
+	assert.Equal(t, refreshTokenHash, grant.RefreshTokenHash)
+	assert.Equal(t, secure.HashToken(response.RefreshToken), grant.RefreshTokenHash)
+}
+
+func TestOAuthService_DeleteClient_ShouldRevokeTheTokensIssuedToTheClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oauthClientRepositoryMock := mocks.NewMockOAuthClientRepository(ctrl)
+	oauthTokenRepositoryMock := mocks.NewMockOAuthTokenRepository(ctrl)
+
+	oauthService := &oauthService{
+		oauthClientRepository: oauthClientRepositoryMock,
+		oauthTokenRepository:  oauthTokenRepositoryMock,
+	}
+
+	client := newOAuthTestClient()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: client.OwnerID})
+
+	gomock.InOrder(
+		oauthClientRepositoryMock.EXPECT().Delete(gomock.Any(), client.OwnerID, client.ID).Return(nil),
+		oauthTokenRepositoryMock.EXPECT().RevokeClientGrants(gomock.Any(), client.ID).Return(2, nil),
+	)
+
+	err := oauthService.DeleteClient(ctx, client.ID)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/exchange"
+	"github.com/samber/do"
+)
+
+type quoteService struct {
+	i               *do.Injector
+	quoteRepository domain.QuoteRepository
+	rateProvider    exchange.RateProvider
+}
+
+func NewQuoteService(i *do.Injector) (domain.QuoteService, error) {
+	quoteRepository, err := do.Invoke[domain.QuoteRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	rateProvider, err := do.Invoke[exchange.RateProvider](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quoteService{
+		i:               i,
+		quoteRepository: quoteRepository,
+		rateProvider:    rateProvider,
+	}, nil
+}
+
+func (q *quoteService) Create(ctx context.Context, payload *domain.QuotePayload) (*domain.QuoteResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "quote"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing create quote process", slog.String("from", payload.From), slog.String("to", payload.To))
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	rate, err := q.rateProvider.Rate(ctx, payload.From, payload.To)
+	if err != nil {
+		if errors.Is(err, exchange.ErrRateNotFound) {
+			log.Warn("No exchange rate for currency pair", slog.String("from", payload.From), slog.String("to", payload.To))
+			return nil, domain.ErrUnsupportedCurrency
+		}
+
+		log.Error("Failed to get exchange rate", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateQuote
+	}
+
+	quote := payload.ToQuote(session.UserID, rate, config.Env.QuoteTTL)
+	if err := q.quoteRepository.Create(ctx, quote); err != nil {
+		log.Error("Failed to create quote", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateQuote
+	}
+
+	log.Info("Create quote process executed successfully", slog.String("quoteID", quote.ID.String()))
+	return quote.ToResponse(), nil
+}
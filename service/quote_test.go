@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/exchange"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteService_Create_WhenRateNotFound_ShouldReturnErrUnsupportedCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	quoteRepositoryMock := mocks.NewMockQuoteRepository(ctrl)
+	rateProviderMock := mocks.NewMockRateProvider(ctrl)
+
+	quoteService := &quoteService{
+		quoteRepository: quoteRepositoryMock,
+		rateProvider:    rateProviderMock,
+	}
+
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: uuid.New()})
+
+	rateProviderMock.EXPECT().Rate(gomock.Any(), "BRL", "JPY").Return(0.0, exchange.ErrRateNotFound)
+
+	_, err := quoteService.Create(ctx, &domain.QuotePayload{From: "BRL", To: "JPY", Value: 100})
+
+	assert.ErrorIs(t, err, domain.ErrUnsupportedCurrency)
+}
+
+func TestQuoteService_Create_WhenSuccess_ShouldLockConvertedValue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	quoteRepositoryMock := mocks.NewMockQuoteRepository(ctrl)
+	rateProviderMock := mocks.NewMockRateProvider(ctrl)
+
+	quoteService := &quoteService{
+		quoteRepository: quoteRepositoryMock,
+		rateProvider:    rateProviderMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	rateProviderMock.EXPECT().Rate(gomock.Any(), "BRL", "USD").Return(0.18, nil)
+	quoteRepositoryMock.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, quote *domain.Quote) error {
+		assert.Equal(t, userID, quote.UserID)
+		return nil
+	})
+
+	response, err := quoteService.Create(ctx, &domain.QuotePayload{From: "BRL", To: "USD", Value: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.18, response.Rate)
+	assert.Equal(t, 18.0, response.TargetValue)
+}
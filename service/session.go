@@ -2,18 +2,34 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/keyring"
+	"github.com/GSVillas/pic-pay-desafio/secure"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/samber/do"
 )
 
+const (
+	refreshTokenSize = 32
+
+	// sessionTouchInterval bounds how often a request rewrites the session
+	// just to move its last-seen time.
+	sessionTouchInterval = time.Minute
+)
+
 type sessionService struct {
-	i                 *do.Injector
-	sessionRepository domain.SessionRepository
+	i                      *do.Injector
+	sessionRepository      domain.SessionRepository
+	refreshTokenRepository domain.RefreshTokenRepository
+	oauthTokenRepository   domain.OAuthTokenRepository
+	keyring                keyring.Keyring
 }
 
 func NewSessionService(i *do.Injector) (domain.SessionService, error) {
@@ -22,58 +38,77 @@ func NewSessionService(i *do.Injector) (domain.SessionService, error) {
 		return nil, err
 	}
 
+	refreshTokenRepository, err := do.Invoke[domain.RefreshTokenRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	oauthTokenRepository, err := do.Invoke[domain.OAuthTokenRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := do.Invoke[keyring.Keyring](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &sessionService{
-		i:                 i,
-		sessionRepository: sessionRepository,
+		i:                      i,
+		sessionRepository:      sessionRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		oauthTokenRepository:   oauthTokenRepository,
+		keyring:                keys,
 	}, nil
 }
 
-func (s *sessionService) Create(ctx context.Context, user *domain.User) (string, error) {
-	log := slog.With(
+func (s *sessionService) Create(ctx context.Context, user *domain.User, device *domain.Device) (*domain.SignInResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "session"),
 		slog.String("func", "Create"),
 	)
 
 	log.Info("Initializing create user session process")
 
-	token, err := s.createToken(user)
-	if err != nil {
-		log.Error("Failed to create token", slog.String("error", err.Error()))
-		return "", err
-	}
-
+	now := time.Now().UTC()
 	session := &domain.Session{
-		Token:  token,
-		Name:   user.Name,
-		UserID: user.ID,
-		Email:  user.Email,
+		ID:         uuid.New(),
+		Name:       user.Name,
+		UserID:     user.ID,
+		Email:      user.Email,
+		Role:       user.Role,
+		DeviceName: device.Name,
+		IP:         device.IP,
+		UserAgent:  device.UserAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
 	}
 
-	if err := s.sessionRepository.Create(ctx, session); err != nil {
-		log.Error("Failed to save user session", slog.String("error", err.Error()))
-		return "", err
+	response, err := s.issueTokens(ctx, session)
+	if err != nil {
+		log.Error("Failed to issue session tokens", slog.String("error", err.Error()))
+		return nil, err
 	}
 
 	log.Info("session creation process excuted succefully")
-	return token, err
+	return response, nil
 }
 
 func (s *sessionService) GetSession(ctx context.Context, token string) (*domain.Session, error) {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "session"),
 		slog.String("func", "GetSession"),
-		slog.String("token", token),
 	)
 
 	log.Info("Starting session retrieval process")
 
-	sessionToken, err := s.extractSessionFromToken(token)
+	sessionToken, err := s.extractSessionFromToken(ctx, token)
 	if err != nil {
 		log.Error("Failed to extract session from token", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	session, err := s.sessionRepository.GetSession(ctx, sessionToken.UserID)
+	session, err := s.sessionRepository.GetSession(ctx, sessionToken.UserID, sessionToken.ID)
 	if err != nil {
 		log.Error("Failed to retrieve session from repository", slog.Any("userID", sessionToken.UserID), slog.String("error", err.Error()))
 		return nil, err
@@ -89,25 +124,346 @@ func (s *sessionService) GetSession(ctx context.Context, token string) (*domain.
 		return nil, domain.ErrSessionMismatch
 	}
 
+	if now := time.Now().UTC(); now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		session.LastSeenAt = now
+		if err := s.sessionRepository.Touch(ctx, session); err != nil {
+			log.Warn("Failed to update session last seen time", slog.String("error", err.Error()))
+		}
+	}
+
 	log.Info("Session retrieved successfully", slog.String("userID", sessionToken.UserID.String()))
 	return session, nil
 }
 
-func (s *sessionService) createToken(user *domain.User) (string, error) {
-	log := slog.With(
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// Each refresh token works once: presenting one that was already rotated
+// revokes its whole family and signs out the session it belongs to.
+func (s *sessionService) Refresh(ctx context.Context, refreshToken string) (*domain.SignInResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "session"),
+		slog.String("func", "Refresh"),
+	)
+
+	log.Info("Initializing refresh session process")
+
+	hash := secure.HashToken(refreshToken)
+
+	stored, err := s.refreshTokenRepository.GetByHash(ctx, hash)
+	if err != nil {
+		log.Error("Failed to get refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if stored == nil {
+		log.Warn("Refresh token not found")
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+
+	active, err := s.refreshTokenRepository.IsFamilyActive(ctx, stored.FamilyID)
+	if err != nil {
+		log.Error("Failed to check refresh token family", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !active {
+		log.Warn("Refresh token family revoked", slog.String("familyID", stored.FamilyID.String()))
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+
+	first, err := s.refreshTokenRepository.MarkUsed(ctx, hash)
+	if err != nil {
+		log.Error("Failed to mark refresh token as used", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !first {
+		log.Warn("Refresh token reuse detected, revoking family", slog.String("userID", stored.UserID.String()), slog.String("familyID", stored.FamilyID.String()))
+		if err := s.end(ctx, stored.UserID, stored.FamilyID); err != nil {
+			log.Error("Failed to revoke refresh token family", slog.String("error", err.Error()))
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	session, err := s.sessionRepository.GetSession(ctx, stored.UserID, stored.FamilyID)
+	if err != nil {
+		log.Warn("Failed to get session for refresh token", slog.String("error", err.Error()))
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+
+	session.LastSeenAt = time.Now().UTC()
+
+	response, err := s.issueTokens(ctx, session)
+	if err != nil {
+		log.Error("Failed to issue session tokens", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Refresh session process executed successfully", slog.String("userID", stored.UserID.String()))
+	return response, nil
+}
+
+// issueTokens signs a new access token for session, stores it as the current
+// one and pairs it with the next refresh token of the session family.
+func (s *sessionService) issueTokens(ctx context.Context, session *domain.Session) (*domain.SignInResponse, error) {
+	token, err := s.createToken(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := secure.GenerateToken(refreshTokenSize)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Token = token
+	if err := s.sessionRepository.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	stored := &domain.RefreshToken{
+		Hash:      secure.HashToken(refreshToken),
+		UserID:    session.UserID,
+		FamilyID:  session.ID,
+		ExpiresAt: time.Now().UTC().Add(time.Duration(config.Env.SessionExp) * time.Hour),
+	}
+
+	if err := s.refreshTokenRepository.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &domain.SignInResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(config.Env.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *sessionService) GetAll(ctx context.Context) ([]*domain.SessionResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "session"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get sessions process")
+
+	current, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || current == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	sessions, err := s.sessionRepository.GetAllByUserID(ctx, current.UserID)
+	if err != nil {
+		log.Error("Failed to get sessions", slog.String("error", err.Error()))
+		return nil, domain.ErrGetSessions
+	}
+
+	response := make([]*domain.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, session.ToResponse(current.ID))
+	}
+
+	log.Info("Get sessions process executed successfully")
+	return response, nil
+}
+
+func (s *sessionService) Revoke(ctx context.Context, sessionID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "session"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing revoke session process", slog.String("sessionID", sessionID.String()))
+
+	current, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || current == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if _, err := s.sessionRepository.GetSession(ctx, current.UserID, sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Session to revoke not found", slog.String("sessionID", sessionID.String()))
+			return domain.ErrUnknownSession
+		}
+
+		log.Error("Failed to get session", slog.String("error", err.Error()))
+		return domain.ErrSignOut
+	}
+
+	if err := s.end(ctx, current.UserID, sessionID); err != nil {
+		log.Error("Failed to revoke session", slog.String("error", err.Error()))
+		return domain.ErrSignOut
+	}
+
+	log.Info("Revoke session process executed successfully")
+	return nil
+}
+
+// SignOut ends the session of the request, or every session of its user
+// when payload.All is set.
+func (s *sessionService) SignOut(ctx context.Context, payload *domain.SignOutPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "session"),
+		slog.String("func", "SignOut"),
+	)
+
+	log.Info("Initializing sign out process", slog.Bool("all", payload.All))
+
+	current, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || current == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if !payload.All {
+		if err := s.end(ctx, current.UserID, current.ID); err != nil {
+			log.Error("Failed to sign out session", slog.String("error", err.Error()))
+			return domain.ErrSignOut
+		}
+
+		log.Info("Sign out process executed successfully")
+		return nil
+	}
+
+	if err := s.RevokeAll(ctx, current.UserID); err != nil {
+		log.Error("Failed to sign out sessions", slog.String("error", err.Error()))
+		return domain.ErrSignOut
+	}
+
+	log.Info("Sign out process executed successfully")
+	return nil
+}
+
+// RevokeAll ends every session of the user, on every device, and revokes the
+// OAuth tokens issued to third-party clients on their behalf.
+func (s *sessionService) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "session"),
+		slog.String("func", "RevokeAll"),
+	)
+
+	sessions, err := s.sessionRepository.GetAllByUserID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := s.end(ctx, session.UserID, session.ID); err != nil {
+			log.Error("Failed to end session", slog.String("sessionID", session.ID.String()), slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	grants, err := s.oauthTokenRepository.RevokeUserGrants(ctx, userID)
+	if err != nil {
+		log.Error("Failed to revoke oauth grants", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Revoke all sessions process executed successfully", slog.Int("count", len(sessions)), slog.Int("oauthGrants", grants))
+	return nil
+}
+
+// UpdateRole moves every live session of the user to role, so a role
+// change applies on the next request instead of the next sign-in.
+func (s *sessionService) UpdateRole(ctx context.Context, userID uuid.UUID, role domain.Role) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "session"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	count, err := s.updateAll(ctx, userID, func(session *domain.Session) {
+		session.Role = role
+	})
+	if err != nil {
+		log.Error("Failed to update sessions role", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Update sessions role process executed successfully", slog.Int("count", count))
+	return nil
+}
+
+// UpdateProfile copies the name and email of the user into every live
+// session, so they match the profile without signing in again.
+func (s *sessionService) UpdateProfile(ctx context.Context, user *domain.User) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "session"),
+		slog.String("func", "UpdateProfile"),
+	)
+
+	count, err := s.updateAll(ctx, user.ID, func(session *domain.Session) {
+		session.Name = user.Name
+		session.Email = user.Email
+	})
+	if err != nil {
+		log.Error("Failed to update sessions profile", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Update sessions profile process executed successfully", slog.Int("count", count))
+	return nil
+}
+
+// updateAll applies update to every live session of the user and saves
+// them without extending their expiration.
+func (s *sessionService) updateAll(ctx context.Context, userID uuid.UUID, update func(session *domain.Session)) (int, error) {
+	sessions, err := s.sessionRepository.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, session := range sessions {
+		update(session)
+		if err := s.sessionRepository.Touch(ctx, session); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(sessions), nil
+}
+
+// end deletes a session and revokes its refresh tokens.
+func (s *sessionService) end(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := s.refreshTokenRepository.RevokeFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return s.sessionRepository.Delete(ctx, userID, sessionID)
+}
+
+func (s *sessionService) createToken(ctx context.Context, session *domain.Session) (string, error) {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "session"),
 		slog.String("func", "createToken"),
 	)
 
 	log.Info("Initializing create token process")
 
+	key, err := s.keyring.SigningKey()
+	if err != nil {
+		log.Error("Failed to get signing key", slog.String("error", err.Error()))
+		return "", err
+	}
+
+	now := time.Now().UTC()
 	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
-		"picPayId": user.ID,
-		"name":     user.Name,
-		"email":    user.Email,
+		"sub":      session.UserID.String(),
+		"iss":      config.Env.TokenIssuer,
+		"aud":      config.Env.TokenAudience,
+		"iat":      now.Unix(),
+		"exp":      now.Add(config.Env.AccessTokenTTL).Unix(),
+		"jti":      uuid.NewString(),
+		"sid":      session.ID,
+		"picPayId": session.UserID,
+		"name":     session.Name,
+		"email":    session.Email,
+		"role":     session.Role,
 	})
 
-	tokenString, err := token.SignedString(config.Env.PrivateKey)
+	token.Header["kid"] = key.ID
+
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		log.Error("Error to signed token string", slog.String("error", err.Error()))
 		return "", err
@@ -117,8 +473,8 @@ func (s *sessionService) createToken(user *domain.User) (string, error) {
 	return tokenString, nil
 }
 
-func (s *sessionService) extractSessionFromToken(tokenString string) (*domain.Session, error) {
-	log := slog.With(
+func (s *sessionService) extractSessionFromToken(ctx context.Context, tokenString string) (*domain.Session, error) {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "session"),
 		slog.String("func", "extractSessionFromToken"),
 	)
@@ -131,16 +487,23 @@ func (s *sessionService) extractSessionFromToken(tokenString string) (*domain.Se
 			return nil, domain.ErrorUnexpectedMethod
 		}
 		log.Info("Token signing method validated", slog.String("method", token.Method.Alg()))
-		return config.Env.PublicKey, nil
+
+		kid, _ := token.Header["kid"].(string)
+		return s.keyring.VerificationKey(kid)
 	})
 
 	if err != nil {
-		log.Error("Failed to parse token", slog.String("error ", err.Error()))
-		return nil, err
+		log.Warn("Failed to parse token", slog.String("error ", err.Error()))
+		return nil, domain.ErrTokenInvalid
+	}
+
+	if typ, _ := token.Header["typ"].(string); typ == domain.OAuthAccessTokenType {
+		log.Warn("OAuth access token used as a session token")
+		return nil, domain.ErrTokenInvalid
 	}
 
 	if !token.Valid {
-		log.Warn("Invalid token", slog.String("token", tokenString))
+		log.Warn("Invalid token")
 		return nil, domain.ErrTokenInvalid
 	}
 
@@ -150,14 +513,17 @@ func (s *sessionService) extractSessionFromToken(tokenString string) (*domain.Se
 		return nil, domain.ErrTokenInvalid
 	}
 
+	if err := verifyRegisteredClaims(claims); err != nil {
+		log.Warn("Token registered claims rejected", slog.String("error", err.Error()))
+		return nil, err
+	}
+
 	sessionJSON, err := jsoniter.Marshal(claims)
 	if err != nil {
 		log.Error("Failed to marshal claims to JSON", slog.String("error ", err.Error()))
 		return nil, err
 	}
 
-	log.Info("Claims marshaled to JSON successfully", slog.String("json", string(sessionJSON)))
-
 	var session domain.Session
 	err = jsoniter.Unmarshal(sessionJSON, &session)
 	if err != nil {
@@ -165,6 +531,36 @@ func (s *sessionService) extractSessionFromToken(tokenString string) (*domain.Se
 		return nil, err
 	}
 
-	log.Info("Session successfully extracted from token", slog.Any("session", session))
+	if session.ID == uuid.Nil {
+		log.Warn("Token has no session ID")
+		return nil, domain.ErrTokenInvalid
+	}
+
+	if subject, _ := claims["sub"].(string); subject != session.UserID.String() {
+		log.Warn("Token subject does not match session user")
+		return nil, domain.ErrTokenInvalid
+	}
+
+	log.Info("Session successfully extracted from token", slog.String("sessionID", session.ID.String()))
 	return &session, nil
 }
+
+// verifyRegisteredClaims requires every registered claim issued by
+// createToken, so tokens signed before they existed are rejected. OAuth
+// access tokens carry the same ones.
+func verifyRegisteredClaims(claims jwt.MapClaims) error {
+	now := time.Now().UTC().Unix()
+
+	if !claims.VerifyExpiresAt(now, true) ||
+		!claims.VerifyIssuedAt(now, true) ||
+		!claims.VerifyIssuer(config.Env.TokenIssuer, true) ||
+		!claims.VerifyAudience(config.Env.TokenAudience, true) {
+		return domain.ErrTokenInvalid
+	}
+
+	if jti, _ := claims["jti"].(string); jti == "" {
+		return domain.ErrTokenInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionService_Refresh_WhenTokenUnknown_ShouldReturnErrRefreshTokenInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepositoryMock := mocks.NewMockSessionRepository(ctrl)
+	refreshTokenRepositoryMock := mocks.NewMockRefreshTokenRepository(ctrl)
+
+	sessionService := &sessionService{
+		sessionRepository:      sessionRepositoryMock,
+		refreshTokenRepository: refreshTokenRepositoryMock,
+	}
+
+	refreshTokenRepositoryMock.EXPECT().GetByHash(gomock.Any(), secure.HashToken("unknown")).Return(nil, nil)
+
+	_, err := sessionService.Refresh(context.Background(), "unknown")
+
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+}
+
+func TestSessionService_Refresh_WhenTokenReused_ShouldRevokeFamilyAndReturnErrRefreshTokenReused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepositoryMock := mocks.NewMockSessionRepository(ctrl)
+	refreshTokenRepositoryMock := mocks.NewMockRefreshTokenRepository(ctrl)
+
+	sessionService := &sessionService{
+		sessionRepository:      sessionRepositoryMock,
+		refreshTokenRepository: refreshTokenRepositoryMock,
+	}
+
+	hash := secure.HashToken("rotated")
+	stored := &domain.RefreshToken{
+		Hash:      hash,
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	refreshTokenRepositoryMock.EXPECT().GetByHash(gomock.Any(), hash).Return(stored, nil)
+	refreshTokenRepositoryMock.EXPECT().IsFamilyActive(gomock.Any(), stored.FamilyID).Return(true, nil)
+	refreshTokenRepositoryMock.EXPECT().MarkUsed(gomock.Any(), hash).Return(false, nil)
+	refreshTokenRepositoryMock.EXPECT().RevokeFamily(gomock.Any(), stored.FamilyID).Return(nil)
+	sessionRepositoryMock.EXPECT().Delete(gomock.Any(), stored.UserID, stored.FamilyID).Return(nil)
+
+	_, err := sessionService.Refresh(context.Background(), "rotated")
+
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+}
+
+func TestSessionService_Refresh_WhenFamilyRevoked_ShouldReturnErrRefreshTokenInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepositoryMock := mocks.NewMockSessionRepository(ctrl)
+	refreshTokenRepositoryMock := mocks.NewMockRefreshTokenRepository(ctrl)
+
+	sessionService := &sessionService{
+		sessionRepository:      sessionRepositoryMock,
+		refreshTokenRepository: refreshTokenRepositoryMock,
+	}
+
+	hash := secure.HashToken("revoked")
+	stored := &domain.RefreshToken{Hash: hash, UserID: uuid.New(), FamilyID: uuid.New()}
+
+	refreshTokenRepositoryMock.EXPECT().GetByHash(gomock.Any(), hash).Return(stored, nil)
+	refreshTokenRepositoryMock.EXPECT().IsFamilyActive(gomock.Any(), stored.FamilyID).Return(false, nil)
+
+	_, err := sessionService.Refresh(context.Background(), "revoked")
+
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+}
+
+func TestSessionService_Revoke_WhenSessionOfAnotherUser_ShouldReturnErrUnknownSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepositoryMock := mocks.NewMockSessionRepository(ctrl)
+	refreshTokenRepositoryMock := mocks.NewMockRefreshTokenRepository(ctrl)
+
+	sessionService := &sessionService{
+		sessionRepository:      sessionRepositoryMock,
+		refreshTokenRepository: refreshTokenRepositoryMock,
+	}
+
+	current := &domain.Session{ID: uuid.New(), UserID: uuid.New()}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, current)
+	sessionID := uuid.New()
+
+	sessionRepositoryMock.EXPECT().GetSession(gomock.Any(), current.UserID, sessionID).Return(nil, domain.ErrSessionNotFound)
+
+	err := sessionService.Revoke(ctx, sessionID)
+
+	assert.ErrorIs(t, err, domain.ErrUnknownSession)
+}
+
+func TestSessionService_SignOut_WhenAll_ShouldEndEverySession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepositoryMock := mocks.NewMockSessionRepository(ctrl)
+	refreshTokenRepositoryMock := mocks.NewMockRefreshTokenRepository(ctrl)
+	oauthTokenRepositoryMock := mocks.NewMockOAuthTokenRepository(ctrl)
+
+	sessionService := &sessionService{
+		sessionRepository:      sessionRepositoryMock,
+		refreshTokenRepository: refreshTokenRepositoryMock,
+		oauthTokenRepository:   oauthTokenRepositoryMock,
+	}
+
+	userID := uuid.New()
+	current := &domain.Session{ID: uuid.New(), UserID: userID}
+	other := &domain.Session{ID: uuid.New(), UserID: userID}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, current)
+
+	sessionRepositoryMock.EXPECT().GetAllByUserID(gomock.Any(), userID).Return([]*domain.Session{current, other}, nil)
+	for _, session := range []*domain.Session{current, other} {
+		refreshTokenRepositoryMock.EXPECT().RevokeFamily(gomock.Any(), session.ID).Return(nil)
+		sessionRepositoryMock.EXPECT().Delete(gomock.Any(), userID, session.ID).Return(nil)
+	}
+	oauthTokenRepositoryMock.EXPECT().RevokeUserGrants(gomock.Any(), userID).Return(1, nil)
+
+	err := sessionService.SignOut(ctx, &domain.SignOutPayload{All: true})
+
+	assert.NoError(t, err)
+}
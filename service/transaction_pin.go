@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type transactionPINService struct {
+	i                        *do.Injector
+	transactionPINRepository domain.TransactionPINRepository
+	userService              domain.UserService
+	twoFactorService         domain.TwoFactorService
+	passwordHasher           secure.PasswordHasher
+}
+
+func NewTransactionPINService(i *do.Injector) (domain.TransactionPINService, error) {
+	transactionPINRepository, err := do.Invoke[domain.TransactionPINRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userService, err := do.Invoke[domain.UserService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactorService, err := do.Invoke[domain.TwoFactorService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHasher, err := do.Invoke[secure.PasswordHasher](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionPINService{
+		i:                        i,
+		transactionPINRepository: transactionPINRepository,
+		userService:              userService,
+		twoFactorService:         twoFactorService,
+		passwordHasher:           passwordHasher,
+	}, nil
+}
+
+func (t *transactionPINService) Set(ctx context.Context, payload *domain.SetPINPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transactionPIN"),
+		slog.String("func", "Set"),
+	)
+
+	log.Info("Initializing set transaction pin process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if err := t.checkPassword(ctx, session.UserID, payload.Password, payload.IP); err != nil {
+		log.Warn("Set transaction pin rejected", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return err
+	}
+
+	current, err := t.transactionPINRepository.GetByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get transaction pin", slog.String("error", err.Error()))
+		return domain.ErrSavePIN
+	}
+
+	if current != nil {
+		log.Warn("Transaction pin already set", slog.String("userID", session.UserID.String()))
+		return domain.ErrPINAlreadySet
+	}
+
+	if err := t.save(ctx, session.UserID, payload.PIN); err != nil {
+		log.Error("Failed to set transaction pin", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Set transaction pin process executed successfully", slog.String("userID", session.UserID.String()))
+	return nil
+}
+
+// Change replaces the PIN after checking the current one, so wrong entries
+// here count towards the lock like those of a transfer.
+func (t *transactionPINService) Change(ctx context.Context, payload *domain.ChangePINPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transactionPIN"),
+		slog.String("func", "Change"),
+	)
+
+	log.Info("Initializing change transaction pin process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if err := t.Verify(ctx, session.UserID, payload.CurrentPIN); err != nil {
+		log.Warn("Change transaction pin rejected", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.save(ctx, session.UserID, payload.PIN); err != nil {
+		log.Error("Failed to change transaction pin", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Change transaction pin process executed successfully", slog.String("userID", session.UserID.String()))
+	return nil
+}
+
+// Reset replaces a forgotten PIN, even a locked one, once the user proves
+// their identity with the password and, when enabled, a TOTP code.
+func (t *transactionPINService) Reset(ctx context.Context, payload *domain.ResetPINPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transactionPIN"),
+		slog.String("func", "Reset"),
+	)
+
+	log.Info("Initializing reset transaction pin process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if err := t.checkPassword(ctx, session.UserID, payload.Password, payload.IP); err != nil {
+		log.Warn("Reset transaction pin rejected", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return err
+	}
+
+	twoFactorEnabled, err := t.twoFactorService.IsEnabled(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to check two-factor settings", slog.String("error", err.Error()))
+		return domain.ErrSavePIN
+	}
+
+	if twoFactorEnabled {
+		if err := t.twoFactorService.VerifyStepUp(ctx, session.UserID, payload.TOTPCode); err != nil {
+			log.Warn("Reset transaction pin step-up rejected", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	if err := t.save(ctx, session.UserID, payload.PIN); err != nil {
+		log.Error("Failed to reset transaction pin", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.transactionPINRepository.ResetAttempts(ctx, session.UserID); err != nil {
+		log.Error("Failed to unlock transaction pin", slog.String("error", err.Error()))
+		return domain.ErrSavePIN
+	}
+
+	log.Info("Reset transaction pin process executed successfully", slog.String("userID", session.UserID.String()))
+	return nil
+}
+
+// Verify checks pin against the PIN of the user. After
+// TransactionPINMaxAttempts wrong entries the PIN is locked for
+// TransactionPINLockoutDuration, or until it is reset.
+func (t *transactionPINService) Verify(ctx context.Context, userID uuid.UUID, pin string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transactionPIN"),
+		slog.String("func", "Verify"),
+	)
+
+	lock, err := t.transactionPINRepository.GetLock(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get transaction pin lock", slog.String("error", err.Error()))
+		return domain.ErrVerifyPIN
+	}
+
+	if lock > 0 {
+		log.Warn("Transaction pin locked", slog.String("userID", userID.String()))
+		return &domain.RetryAfterError{Err: domain.ErrPINLocked, RetryAfter: lock}
+	}
+
+	current, err := t.transactionPINRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get transaction pin", slog.String("error", err.Error()))
+		return domain.ErrVerifyPIN
+	}
+
+	if current == nil {
+		log.Warn("Transaction pin not set", slog.String("userID", userID.String()))
+		return domain.ErrPINNotSet
+	}
+
+	if err := t.passwordHasher.Verify(current.PINHash, pin); err != nil {
+		return t.registerFailure(ctx, log, userID)
+	}
+
+	if err := t.transactionPINRepository.ResetAttempts(ctx, userID); err != nil {
+		log.Error("Failed to reset transaction pin attempts", slog.String("error", err.Error()))
+	}
+
+	if t.passwordHasher.NeedsRehash(current.PINHash) {
+		if err := t.save(ctx, userID, pin); err != nil {
+			log.Error("Failed to rehash transaction pin", slog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func (t *transactionPINService) registerFailure(ctx context.Context, log *slog.Logger, userID uuid.UUID) error {
+	failures, err := t.transactionPINRepository.RegisterFailure(ctx, userID)
+	if err != nil {
+		log.Error("Failed to register transaction pin failure", slog.String("error", err.Error()))
+		return domain.ErrPINInvalid
+	}
+
+	maxAttempts := config.Env.TransactionPINMaxAttempts
+	if maxAttempts <= 0 || failures < maxAttempts {
+		log.Warn("Invalid transaction pin", slog.String("userID", userID.String()), slog.Int64("failures", failures))
+		return domain.ErrPINInvalid
+	}
+
+	duration := config.Env.TransactionPINLockoutDuration
+	if err := t.transactionPINRepository.Lock(ctx, userID, duration); err != nil {
+		log.Error("Failed to lock transaction pin", slog.String("error", err.Error()))
+		return domain.ErrPINInvalid
+	}
+
+	log.Warn("Transaction pin locked after too many wrong attempts", slog.String("userID", userID.String()))
+	return &domain.RetryAfterError{Err: domain.ErrPINLocked, RetryAfter: duration}
+}
+
+// checkPassword confirms the password through the user service, so wrong
+// entries count towards the sign-in limits of the account.
+func (t *transactionPINService) checkPassword(ctx context.Context, userID uuid.UUID, password, ip string) error {
+	if err := t.userService.VerifyPassword(ctx, userID, password, ip); err != nil {
+		if errors.Is(err, domain.ErrVerifyPassword) {
+			return domain.ErrSavePIN
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (t *transactionPINService) save(ctx context.Context, userID uuid.UUID, pin string) error {
+	hash, err := t.passwordHasher.Hash(pin)
+	if err != nil {
+		return domain.ErrHashingPassword
+	}
+
+	now := time.Now().UTC()
+	transactionPIN := &domain.TransactionPIN{
+		UserID:    userID,
+		PINHash:   hash,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := t.transactionPINRepository.Save(ctx, transactionPIN); err != nil {
+		return domain.ErrSavePIN
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionPINService_Verify_WhenPINIsCorrect_ShouldResetAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transactionPINRepositoryMock := mocks.NewMockTransactionPINRepository(ctrl)
+	passwordHasher := newTestPasswordHasher(t)
+
+	transactionPINService := &transactionPINService{
+		transactionPINRepository: transactionPINRepositoryMock,
+		passwordHasher:           passwordHasher,
+	}
+
+	userID := uuid.New()
+	hash, err := passwordHasher.Hash("2580")
+	assert.NoError(t, err)
+
+	transactionPINRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(time.Duration(0), nil)
+	transactionPINRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TransactionPIN{UserID: userID, PINHash: hash}, nil)
+	transactionPINRepositoryMock.EXPECT().ResetAttempts(gomock.Any(), userID).Return(nil)
+
+	err = transactionPINService.Verify(context.Background(), userID, "2580")
+
+	assert.NoError(t, err)
+}
+
+func TestTransactionPINService_Verify_WhenMaxAttemptsReached_ShouldLockPIN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transactionPINRepositoryMock := mocks.NewMockTransactionPINRepository(ctrl)
+	passwordHasher := newTestPasswordHasher(t)
+
+	transactionPINService := &transactionPINService{
+		transactionPINRepository: transactionPINRepositoryMock,
+		passwordHasher:           passwordHasher,
+	}
+
+	config.Env.TransactionPINMaxAttempts = 3
+	config.Env.TransactionPINLockoutDuration = time.Hour
+	defer func() {
+		config.Env.TransactionPINMaxAttempts = 0
+		config.Env.TransactionPINLockoutDuration = 0
+	}()
+
+	userID := uuid.New()
+	hash, err := passwordHasher.Hash("2580")
+	assert.NoError(t, err)
+
+	transactionPINRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(time.Duration(0), nil)
+	transactionPINRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TransactionPIN{UserID: userID, PINHash: hash}, nil)
+	transactionPINRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), userID).Return(int64(3), nil)
+	transactionPINRepositoryMock.EXPECT().Lock(gomock.Any(), userID, time.Hour).Return(nil)
+
+	err = transactionPINService.Verify(context.Background(), userID, "1357")
+
+	assert.ErrorIs(t, err, domain.ErrPINLocked)
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.Equal(t, time.Hour, retryAfterErr.RetryAfter)
+}
+
+func TestTransactionPINService_Verify_WhenLocked_ShouldNotCheckPIN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transactionPINRepositoryMock := mocks.NewMockTransactionPINRepository(ctrl)
+	passwordHasher := newTestPasswordHasher(t)
+
+	transactionPINService := &transactionPINService{
+		transactionPINRepository: transactionPINRepositoryMock,
+		passwordHasher:           passwordHasher,
+	}
+
+	userID := uuid.New()
+
+	transactionPINRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(10*time.Minute, nil)
+
+	err := transactionPINService.Verify(context.Background(), userID, "2580")
+
+	assert.ErrorIs(t, err, domain.ErrPINLocked)
+}
+
+func TestTransactionPINService_Reset_WhenTwoFactorEnabled_ShouldRequireStepUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	twoFactorServiceMock := mocks.NewMockTwoFactorService(ctrl)
+
+	transactionPINService := &transactionPINService{
+		userService:      userServiceMock,
+		twoFactorService: twoFactorServiceMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	userServiceMock.EXPECT().VerifyPassword(gomock.Any(), userID, "Password@123", "10.0.0.1").Return(nil)
+	twoFactorServiceMock.EXPECT().IsEnabled(gomock.Any(), userID).Return(true, nil)
+	twoFactorServiceMock.EXPECT().VerifyStepUp(gomock.Any(), userID, "").Return(domain.ErrStepUpRequired)
+
+	err := transactionPINService.Reset(ctx, &domain.ResetPINPayload{Password: "Password@123", PIN: "2580", ConfirmPIN: "2580", IP: "10.0.0.1"})
+
+	assert.ErrorIs(t, err, domain.ErrStepUpRequired)
+}
+
+func TestTransactionPINService_Set_WhenPasswordChecksAreThrottled_ShouldNotSavePIN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+
+	transactionPINService := &transactionPINService{
+		userService: userServiceMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	userServiceMock.EXPECT().VerifyPassword(gomock.Any(), userID, "wrong", "10.0.0.1").
+		Return(&domain.RetryAfterError{Err: domain.ErrAccountLocked, RetryAfter: time.Minute})
+
+	err := transactionPINService.Set(ctx, &domain.SetPINPayload{Password: "wrong", PIN: "2580", ConfirmPIN: "2580", IP: "10.0.0.1"})
+
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+}
@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/exchange"
+	"github.com/google/uuid"
 	"github.com/samber/do"
 )
 
@@ -13,6 +18,15 @@ type transactionService struct {
 	i                    *do.Injector
 	transferRepository   domain.TransferRepository
 	walletRepository     domain.WalletRepository
+	holdRepository       domain.HoldRepository
+	quoteRepository      domain.QuoteRepository
+	campaignService      domain.CampaignService
+	contactService       domain.ContactService
+	twoFactorService     domain.TwoFactorService
+	pinService           domain.TransactionPINService
+	userService          domain.UserService
+	kycService           domain.KYCService
+	rateProvider         exchange.RateProvider
 	authorizationService client.AuthorizationService
 }
 
@@ -27,6 +41,51 @@ func NewTransferService(i *do.Injector) (domain.TransferService, error) {
 		return nil, err
 	}
 
+	holdRepository, err := do.Invoke[domain.HoldRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	quoteRepository, err := do.Invoke[domain.QuoteRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	campaignService, err := do.Invoke[domain.CampaignService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	contactService, err := do.Invoke[domain.ContactService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactorService, err := do.Invoke[domain.TwoFactorService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	pinService, err := do.Invoke[domain.TransactionPINService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userService, err := do.Invoke[domain.UserService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	kycService, err := do.Invoke[domain.KYCService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	rateProvider, err := do.Invoke[exchange.RateProvider](i)
+	if err != nil {
+		return nil, err
+	}
+
 	authorizationService, err := do.Invoke[client.AuthorizationService](i)
 	if err != nil {
 		return nil, err
@@ -36,12 +95,21 @@ func NewTransferService(i *do.Injector) (domain.TransferService, error) {
 		i:                    i,
 		transferRepository:   transactionRepository,
 		walletRepository:     walletRepository,
+		holdRepository:       holdRepository,
+		quoteRepository:      quoteRepository,
+		campaignService:      campaignService,
+		contactService:       contactService,
+		twoFactorService:     twoFactorService,
+		pinService:           pinService,
+		userService:          userService,
+		kycService:           kycService,
+		rateProvider:         rateProvider,
 		authorizationService: authorizationService,
 	}, nil
 }
 
-func (t *transactionService) Transfer(ctx context.Context, payload *domain.TransferPayload) error {
-	log := slog.With(
+func (t *transactionService) Transfer(ctx context.Context, payload *domain.TransferPayload) (*domain.TransferResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "transaction"),
 		slog.String("func", "Transfer"),
 	)
@@ -50,53 +118,139 @@ func (t *transactionService) Transfer(ctx context.Context, payload *domain.Trans
 
 	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
 	if !ok || session == nil {
-		return domain.ErrSessionNotFound
+		return nil, domain.ErrSessionNotFound
 	}
 
 	if session.UserID == payload.PayeeID {
 		log.Warn("Attempted self-transfer detected", slog.String("userID", session.UserID.String()), slog.String("action", "transaction to self"))
-		return domain.ErrSelfTransactionNotAllowed
+		return nil, domain.ErrSelfTransactionNotAllowed
+	}
+
+	if err := t.userService.EnsureEmailVerified(ctx, session.UserID); err != nil {
+		log.Warn("Transfer blocked", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	limits, err := t.kycService.GetLimits(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get kyc limits", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, domain.ErrCreateTransfer
 	}
 
-	payer, err := t.walletRepository.GetByUserID(ctx, session.UserID)
+	value, err := convertValue(ctx, t.rateProvider, payload.Value, payload.Currency, limits.Currency)
+	if err != nil {
+		log.Error("Failed to convert transfer value to the kyc limit currency", slog.String("currency", payload.Currency), slog.String("error", err.Error()))
+		return nil, domain.ErrCreateTransfer
+	}
+
+	if !limits.AllowsTransfer(value) {
+		log.Warn("Transfer over the kyc limit", slog.String("userID", session.UserID.String()), slog.Float64("limit", limits.Transfer))
+		return nil, domain.ErrKYCTransferLimitExceeded
+	}
+
+	// The step-up threshold is set in the kyc limit currency, like the limits.
+	stepUpValue := value
+	if limits.Currency != config.Env.KYCLimitCurrency {
+		stepUpValue, err = convertValue(ctx, t.rateProvider, payload.Value, payload.Currency, config.Env.KYCLimitCurrency)
+		if err != nil {
+			log.Error("Failed to convert transfer value to the kyc limit currency", slog.String("currency", payload.Currency), slog.String("error", err.Error()))
+			return nil, domain.ErrCreateTransfer
+		}
+	}
+
+	if threshold := config.Env.StepUpTransferValue; threshold > 0 && stepUpValue > threshold {
+		if err := t.twoFactorService.VerifyStepUp(ctx, session.UserID, payload.TOTPCode); err != nil {
+			log.Warn("Transfer step-up rejected", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	if err := t.pinService.Verify(ctx, session.UserID, payload.PIN); err != nil {
+		log.Warn("Transfer pin rejected", slog.String("userID", session.UserID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	quote, err := t.takeQuote(ctx, payload, session.UserID)
+	if err != nil {
+		log.Warn("Transfer quote rejected", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// The quote is taken before the checks below so concurrent transfers
+	// cannot both use it. It is put back unless the transfer goes through.
+	transferred := false
+	defer func() {
+		if !transferred {
+			t.restoreQuote(ctx, quote)
+		}
+	}()
+
+	payeeCurrency := payload.Currency
+	if quote != nil {
+		payeeCurrency = quote.To
+	}
+
+	payer, err := t.walletRepository.GetByUserID(ctx, session.UserID, payload.Currency)
 	if err != nil {
 		log.Error("Failed to get wallet by userID ", slog.String("Error: ", err.Error()))
-		return domain.ErrGetWallet
+		return nil, domain.ErrGetWallet
 	}
 
 	if payer == nil {
-		log.Warn("No wallets were found for this user", slog.String("userId: ", session.UserID.String()))
-		return domain.ErrPayerWalletNotFound
+		log.Warn("No wallets were found for this user", slog.String("userId: ", session.UserID.String()), slog.String("currency", payload.Currency))
+		return nil, domain.ErrPayerWalletNotFound
 	}
 
-	payee, err := t.walletRepository.GetByUserID(ctx, payload.PayeeID)
+	payee, err := t.walletRepository.GetByUserID(ctx, payload.PayeeID, payeeCurrency)
 	if err != nil {
 		log.Error("Failed to get wallet by userID ", slog.String("Error", err.Error()))
-		return domain.ErrGetWallet
+		return nil, domain.ErrGetWallet
 	}
 
 	if payee == nil {
-		log.Warn("No wallets were found for this user", slog.String("userId", payload.PayeeID.String()))
-		return domain.ErrPayeeWalletNotFound
+		log.Warn("No wallets were found for this user", slog.String("userId", payload.PayeeID.String()), slog.String("currency", payeeCurrency))
+		return nil, t.missingPayeeWalletError(ctx, payload.PayeeID, quote)
+	}
+
+	balanceCap, err := payeeBalanceCap(ctx, t.kycService, t.walletRepository, t.rateProvider, payee.UserID, payee.Currency)
+	if err != nil {
+		log.Error("Failed to get payee balance limit", slog.String("payeeID", payee.UserID.String()), slog.String("error", err.Error()))
+		return nil, domain.ErrCreateTransfer
 	}
 
 	if err := t.validateTransfer(ctx, payload, payer); err != nil {
 		log.Warn("Transfer validation failed", slog.String("error", err.Error()))
-		return err
+		return nil, err
 	}
 
-	transaction := payload.ToTansaction(payer.UserID)
+	transaction := payload.ToTansaction(payer.UserID, quote, config.Env.EscrowTimeout)
+	transaction.PayeeBalanceCap = balanceCap
 	if err := t.transferRepository.Transfer(ctx, transaction); err != nil {
+		if errors.Is(err, domain.ErrInsufficientBalance) {
+			log.Warn("Insufficient available balance for transaction")
+			return nil, err
+		}
+
+		if errors.Is(err, domain.ErrKYCBalanceLimitExceeded) {
+			log.Warn("Payee balance would exceed the kyc limit", slog.String("payeeID", payee.UserID.String()))
+			return nil, err
+		}
+
 		log.Error("Failed to create transaction the user's wallet", slog.String("error", err.Error()))
-		return domain.ErrCreateTransfer
+		return nil, domain.ErrCreateTransfer
 	}
 
-	log.Info("Session retrieved successfully")
-	return nil
+	transferred = true
+
+	t.rewardTransfer(ctx, transaction)
+	t.recordContact(ctx, transaction)
+
+	log.Info("Transfer process executed successfully", slog.String("transferID", transaction.ID.String()))
+	return transaction.ToResponse(), nil
 }
 
 func (t *transactionService) validateTransfer(ctx context.Context, payload *domain.TransferPayload, payer *domain.Wallet) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "transaction"),
 		slog.String("func", "validateTransfer"),
 	)
@@ -108,8 +262,14 @@ func (t *transactionService) validateTransfer(ctx context.Context, payload *doma
 		return domain.ErrTransferNotAllowedForWalletType
 	}
 
-	if payer.Balance < payload.Value {
-		log.Warn("Insufficient balance for transaction")
+	held, err := t.holdRepository.GetActiveAmountByPayerID(ctx, payer.UserID, payer.Currency)
+	if err != nil {
+		log.Error("Failed to get active hold amount", slog.String("error", err.Error()))
+		return domain.ErrGetWallet
+	}
+
+	if payer.Balance-held < payload.Value {
+		log.Warn("Insufficient available balance for transaction", slog.Float64("held", held))
 		return domain.ErrInsufficientBalance
 	}
 
@@ -127,3 +287,375 @@ func (t *transactionService) validateTransfer(ctx context.Context, payload *doma
 	log.Info("Validation transfer process successfully")
 	return nil
 }
+
+// payeeBalanceCap returns the balance limit of the kyc tier of payeeID, with
+// the rates converting each of their wallets and currency, the one being
+// credited, into the limit currency. The repository checks it while crediting
+// the payee. It is nil when the tier has no balance limit.
+func payeeBalanceCap(ctx context.Context, kycService domain.KYCService, walletRepository domain.WalletRepository, rateProvider exchange.RateProvider, payeeID uuid.UUID, currency string) (*domain.KYCBalanceCap, error) {
+	limits, err := kycService.GetLimits(ctx, payeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limits.Balance <= 0 {
+		return nil, nil
+	}
+
+	wallets, err := walletRepository.GetAllByUserID(ctx, payeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	balanceCap := &domain.KYCBalanceCap{Limits: limits, Rates: make(map[string]float64, len(wallets)+1)}
+	for _, walletCurrency := range append(walletCurrencies(wallets), currency) {
+		if _, ok := balanceCap.Rates[walletCurrency]; ok {
+			continue
+		}
+
+		rate, err := convertValue(ctx, rateProvider, 1, walletCurrency, limits.Currency)
+		if err != nil {
+			return nil, err
+		}
+		balanceCap.Rates[walletCurrency] = rate
+	}
+
+	return balanceCap, nil
+}
+
+// convertValue returns value, in from, converted to the currency to. Limits
+// without a currency are compared as they are.
+func convertValue(ctx context.Context, rateProvider exchange.RateProvider, value float64, from, to string) (float64, error) {
+	if to == "" || from == to {
+		return value, nil
+	}
+
+	rate, err := rateProvider.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return value * rate, nil
+}
+
+func walletCurrencies(wallets []*domain.Wallet) []string {
+	currencies := make([]string, 0, len(wallets))
+	for _, wallet := range wallets {
+		currencies = append(currencies, wallet.Currency)
+	}
+	return currencies
+}
+
+// missingPayeeWalletError tells a payee without any wallet apart from one
+// holding only other currencies, which can be paid through a quote.
+func (t *transactionService) missingPayeeWalletError(ctx context.Context, payeeID uuid.UUID, quote *domain.Quote) error {
+	if quote != nil {
+		return domain.ErrPayeeWalletNotFound
+	}
+
+	wallets, err := t.walletRepository.GetAllByUserID(ctx, payeeID)
+	if err != nil || len(wallets) == 0 {
+		return domain.ErrPayeeWalletNotFound
+	}
+
+	return domain.ErrQuoteRequired
+}
+
+// takeQuote consumes the quote referenced by payload, if any. Quotes belonging
+// to someone else are reported as not found. A rejected quote is put back, so
+// only a transfer that goes through uses it up.
+func (t *transactionService) takeQuote(ctx context.Context, payload *domain.TransferPayload, userID uuid.UUID) (*domain.Quote, error) {
+	if payload.QuoteID == nil {
+		return nil, nil
+	}
+
+	quote, err := t.quoteRepository.Take(ctx, *payload.QuoteID)
+	if err != nil {
+		return nil, domain.ErrCreateTransfer
+	}
+
+	if quote == nil {
+		return nil, domain.ErrQuoteNotFound
+	}
+
+	if quote.UserID != userID {
+		t.restoreQuote(ctx, quote)
+		return nil, domain.ErrQuoteNotFound
+	}
+
+	if !quote.Matches(payload.Currency, payload.Value) {
+		t.restoreQuote(ctx, quote)
+		return nil, domain.ErrQuoteMismatch
+	}
+
+	return quote, nil
+}
+
+// restoreQuote puts back a quote taken by a transfer that did not use it.
+func (t *transactionService) restoreQuote(ctx context.Context, quote *domain.Quote) {
+	if quote == nil {
+		return
+	}
+
+	if err := t.quoteRepository.Restore(ctx, quote); err != nil {
+		domain.LoggerFromContext(ctx).Error("Failed to restore quote", slog.String("service", "transaction"), slog.String("quoteID", quote.ID.String()), slog.String("error", err.Error()))
+	}
+}
+
+func (t *transactionService) ConfirmEscrow(ctx context.Context, transferID uuid.UUID) (*domain.TransferResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("func", "ConfirmEscrow"),
+	)
+
+	log.Info("Initializing confirm escrow process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	transfer, err := t.getParticipantTransfer(ctx, transferID, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get transfer", slog.String("transferID", transferID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if transfer.PayerID != session.UserID {
+		log.Warn("Only the payer can confirm delivery", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrTransferForbidden
+	}
+
+	transfer, err = t.releaseEscrow(ctx, transfer, domain.EscrowStatusHeld)
+	if err != nil {
+		return nil, t.escrowError(log, err)
+	}
+
+	t.rewardTransfer(ctx, transfer)
+
+	log.Info("Confirm escrow process executed successfully", slog.String("transferID", transferID.String()))
+	return transfer.ToResponse(), nil
+}
+
+func (t *transactionService) DisputeEscrow(ctx context.Context, transferID uuid.UUID, payload *domain.DisputeEscrowPayload) (*domain.TransferResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("func", "DisputeEscrow"),
+	)
+
+	log.Info("Initializing dispute escrow process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	if _, err := t.getParticipantTransfer(ctx, transferID, session.UserID); err != nil {
+		log.Warn("Failed to get transfer", slog.String("transferID", transferID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	transfer, err := t.transferRepository.DisputeEscrow(ctx, transferID, payload.Reason)
+	if err != nil {
+		return nil, t.escrowError(log, err)
+	}
+
+	log.Info("Dispute escrow process executed successfully", slog.String("transferID", transferID.String()))
+	return transfer.ToResponse(), nil
+}
+
+func (t *transactionService) ResolveEscrow(ctx context.Context, transferID uuid.UUID, payload *domain.ResolveEscrowPayload) (*domain.TransferResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("func", "ResolveEscrow"),
+	)
+
+	log.Info("Initializing resolve escrow process", slog.String("resolution", string(payload.Resolution)))
+
+	var (
+		transfer *domain.Transfer
+		err      error
+	)
+
+	switch payload.Resolution {
+	case domain.EscrowResolutionRelease:
+		transfer, err = t.getTransfer(ctx, transferID)
+		if err == nil {
+			transfer, err = t.releaseEscrow(ctx, transfer, domain.EscrowStatusDisputed)
+		}
+	case domain.EscrowResolutionRefund:
+		transfer, err = t.transferRepository.RefundEscrow(ctx, transferID, domain.EscrowStatusDisputed)
+	}
+
+	if err != nil {
+		return nil, t.escrowError(log, err)
+	}
+
+	if payload.Resolution == domain.EscrowResolutionRelease {
+		t.rewardTransfer(ctx, transfer)
+	}
+
+	log.Info("Resolve escrow process executed successfully", slog.String("transferID", transferID.String()))
+	return transfer.ToResponse(), nil
+}
+
+// ReleaseDueEscrows releases the escrow transfers whose timeout passed. An
+// escrow the payee can no longer receive within their kyc balance limit is
+// refunded to the payer instead, as nobody is left to act on it.
+func (t *transactionService) ReleaseDueEscrows(ctx context.Context) (int, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("func", "ReleaseDueEscrows"),
+	)
+
+	transfers, err := t.transferRepository.GetDueEscrows(ctx, time.Now().UTC(), 100)
+	if err != nil {
+		log.Error("Failed to get due escrow transfers", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	released := 0
+	for _, transfer := range transfers {
+		settled, err := t.releaseEscrow(ctx, transfer, domain.EscrowStatusHeld)
+		if errors.Is(err, domain.ErrKYCBalanceLimitExceeded) {
+			log.Warn("Escrow transfer over the payee balance limit, refunding it", slog.String("transferID", transfer.ID.String()))
+			if _, err := t.transferRepository.RefundEscrow(ctx, transfer.ID, domain.EscrowStatusHeld); err != nil {
+				log.Warn("Failed to refund escrow transfer", slog.String("transferID", transfer.ID.String()), slog.String("error", err.Error()))
+			}
+			continue
+		}
+
+		if err != nil {
+			log.Warn("Failed to release escrow transfer", slog.String("transferID", transfer.ID.String()), slog.String("error", err.Error()))
+			continue
+		}
+
+		t.rewardTransfer(ctx, settled)
+		released++
+	}
+
+	if released > 0 {
+		log.Info("Due escrow transfers released", slog.Int("released", released))
+	}
+
+	return released, nil
+}
+
+// Refund lets the payee give a settled transfer back to the payer, in whole
+// or in parts.
+func (t *transactionService) Refund(ctx context.Context, transferID uuid.UUID, payload *domain.RefundPayload) (*domain.TransferResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "transaction"),
+		slog.String("func", "Refund"),
+	)
+
+	log.Info("Initializing refund process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	transfer, err := t.getParticipantTransfer(ctx, transferID, session.UserID)
+	if err != nil {
+		log.Warn("Failed to get transfer", slog.String("transferID", transferID.String()), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if transfer.PayeeID != session.UserID {
+		log.Warn("Only the payee can refund a transfer", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrTransferForbidden
+	}
+
+	if payeeCurrency, _ := transfer.PayeeAmount(transfer.Value); !session.AllowsCurrency(payeeCurrency) {
+		log.Warn("Api key used for another wallet", slog.String("currency", payeeCurrency))
+		return nil, domain.ErrAPIKeyWalletMismatch
+	}
+
+	if !transfer.IsRefundable() {
+		log.Warn("Transfer cannot be refunded", slog.String("transferID", transfer.ID.String()))
+		return nil, domain.ErrRefundNotAllowed
+	}
+
+	reversal, err := t.transferRepository.Refund(ctx, transferID, payload.Value)
+	if err != nil {
+		if errors.Is(err, domain.ErrTransferNotFound) ||
+			errors.Is(err, domain.ErrRefundNotAllowed) ||
+			errors.Is(err, domain.ErrRefundExceedsValue) ||
+			errors.Is(err, domain.ErrInsufficientBalance) {
+			log.Warn("Refund rejected", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Error("Failed to refund transfer", slog.String("error", err.Error()))
+		return nil, domain.ErrRefundTransfer
+	}
+
+	log.Info("Refund process executed successfully", slog.String("reversalID", reversal.ID.String()))
+	return reversal.ToResponse(), nil
+}
+
+func (t *transactionService) getTransfer(ctx context.Context, transferID uuid.UUID) (*domain.Transfer, error) {
+	transfer, err := t.transferRepository.GetByID(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer == nil {
+		return nil, domain.ErrTransferNotFound
+	}
+
+	return transfer, nil
+}
+
+func (t *transactionService) getParticipantTransfer(ctx context.Context, transferID, userID uuid.UUID) (*domain.Transfer, error) {
+	transfer, err := t.getTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !transfer.IsParticipant(userID) {
+		return nil, domain.ErrTransferForbidden
+	}
+
+	return transfer, nil
+}
+
+// rewardTransfer evaluates cashback campaigns for a settled transfer. Rewards
+// are best effort and never fail the transfer that earned them.
+func (t *transactionService) rewardTransfer(ctx context.Context, transfer *domain.Transfer) {
+	if err := t.campaignService.EvaluateTransfer(ctx, transfer); err != nil {
+		domain.LoggerFromContext(ctx).Warn("Failed to evaluate cashback campaigns", slog.String("transferID", transfer.ID.String()), slog.String("error", err.Error()))
+	}
+}
+
+func (t *transactionService) recordContact(ctx context.Context, transfer *domain.Transfer) {
+	if err := t.contactService.RecordTransfer(ctx, transfer); err != nil {
+		domain.LoggerFromContext(ctx).Warn("Failed to record payee as contact", slog.String("transferID", transfer.ID.String()), slog.String("error", err.Error()))
+	}
+}
+
+// releaseEscrow releases transfer to the payee, checking the balance limit of
+// their kyc tier at the time of the release.
+func (t *transactionService) releaseEscrow(ctx context.Context, transfer *domain.Transfer, from domain.EscrowStatus) (*domain.Transfer, error) {
+	currency, _ := transfer.PayeeAmount(transfer.Value)
+	balanceCap, err := payeeBalanceCap(ctx, t.kycService, t.walletRepository, t.rateProvider, transfer.PayeeID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.transferRepository.ReleaseEscrow(ctx, transfer.ID, balanceCap, from)
+}
+
+func (t *transactionService) escrowError(log *slog.Logger, err error) error {
+	if errors.Is(err, domain.ErrTransferNotFound) ||
+		errors.Is(err, domain.ErrEscrowNotHeld) ||
+		errors.Is(err, domain.ErrEscrowNotDisputed) ||
+		errors.Is(err, domain.ErrKYCBalanceLimitExceeded) {
+		log.Warn("Escrow operation rejected", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Error("Failed to settle escrow transfer", slog.String("error", err.Error()))
+	return domain.ErrSettleEscrow
+}
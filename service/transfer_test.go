@@ -1 +1,550 @@
 package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferService_ConfirmEscrow_WhenCallerIsPayee_ShouldReturnErrTransferForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+
+	transferService := &transactionService{
+		transferRepository: transferRepositoryMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:           uuid.New(),
+		PayerID:      uuid.New(),
+		PayeeID:      uuid.New(),
+		Value:        50,
+		EscrowStatus: domain.EscrowStatusHeld,
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: transfer.PayeeID})
+
+	transferRepositoryMock.EXPECT().GetByID(gomock.Any(), transfer.ID).Return(transfer, nil)
+
+	_, err := transferService.ConfirmEscrow(ctx, transfer.ID)
+
+	assert.ErrorIs(t, err, domain.ErrTransferForbidden)
+}
+
+func TestTransferService_ConfirmEscrow_WhenCallerIsPayer_ShouldReleaseEscrow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+	campaignServiceMock := mocks.NewMockCampaignService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		transferRepository: transferRepositoryMock,
+		campaignService:    campaignServiceMock,
+		kycService:         kycServiceMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:           uuid.New(),
+		PayerID:      uuid.New(),
+		PayeeID:      uuid.New(),
+		Value:        50,
+		EscrowStatus: domain.EscrowStatusHeld,
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: transfer.PayerID})
+
+	released := *transfer
+	released.EscrowStatus = domain.EscrowStatusReleased
+
+	transferRepositoryMock.EXPECT().GetByID(gomock.Any(), transfer.ID).Return(transfer, nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), transfer.PayeeID).Return(&domain.KYCLimits{}, nil)
+	transferRepositoryMock.EXPECT().ReleaseEscrow(gomock.Any(), transfer.ID, nil, domain.EscrowStatusHeld).Return(&released, nil)
+	campaignServiceMock.EXPECT().EvaluateTransfer(gomock.Any(), &released).Return(nil)
+
+	response, err := transferService.ConfirmEscrow(ctx, transfer.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EscrowStatusReleased, response.EscrowStatus)
+}
+
+func TestTransferService_ReleaseDueEscrows_WhenPayeeIsOverBalanceLimit_ShouldRefundThePayer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		transferRepository: transferRepositoryMock,
+		walletRepository:   walletRepositoryMock,
+		kycService:         kycServiceMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:           uuid.New(),
+		PayerID:      uuid.New(),
+		PayeeID:      uuid.New(),
+		Currency:     domain.DefaultCurrency,
+		Value:        50,
+		EscrowStatus: domain.EscrowStatusHeld,
+	}
+
+	limits := &domain.KYCLimits{Balance: 2000}
+	balanceCap := &domain.KYCBalanceCap{Limits: limits, Rates: map[string]float64{domain.DefaultCurrency: 1}}
+	refunded := *transfer
+	refunded.EscrowStatus = domain.EscrowStatusRefunded
+
+	transferRepositoryMock.EXPECT().GetDueEscrows(gomock.Any(), gomock.Any(), gomock.Any()).Return([]*domain.Transfer{transfer}, nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), transfer.PayeeID).Return(limits, nil)
+	walletRepositoryMock.EXPECT().GetAllByUserID(gomock.Any(), transfer.PayeeID).Return([]*domain.Wallet{{UserID: transfer.PayeeID, Currency: domain.DefaultCurrency, Balance: 1990}}, nil)
+	transferRepositoryMock.EXPECT().ReleaseEscrow(gomock.Any(), transfer.ID, balanceCap, domain.EscrowStatusHeld).Return(nil, domain.ErrKYCBalanceLimitExceeded)
+	transferRepositoryMock.EXPECT().RefundEscrow(gomock.Any(), transfer.ID, domain.EscrowStatusHeld).Return(&refunded, nil)
+
+	released, err := transferService.ReleaseDueEscrows(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, released)
+}
+
+func TestTransferService_ResolveEscrow_WhenRefund_ShouldRefundDisputedEscrow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+
+	transferService := &transactionService{
+		transferRepository: transferRepositoryMock,
+	}
+
+	transferID := uuid.New()
+	refunded := &domain.Transfer{
+		ID:           transferID,
+		EscrowStatus: domain.EscrowStatusRefunded,
+	}
+
+	transferRepositoryMock.EXPECT().RefundEscrow(gomock.Any(), transferID, domain.EscrowStatusDisputed).Return(refunded, nil)
+
+	response, err := transferService.ResolveEscrow(context.Background(), transferID, &domain.ResolveEscrowPayload{Resolution: domain.EscrowResolutionRefund})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EscrowStatusRefunded, response.EscrowStatus)
+}
+
+func TestTransferService_Transfer_WhenQuoteBelongsToAnotherUser_ShouldReturnErrQuoteNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	quoteRepositoryMock := mocks.NewMockQuoteRepository(ctrl)
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		userService:     userServiceMock,
+		pinService:      pinServiceMock,
+		kycService:      kycServiceMock,
+		quoteRepository: quoteRepositoryMock,
+	}
+
+	quote := &domain.Quote{ID: uuid.New(), UserID: uuid.New(), From: "BRL", To: "USD", Rate: 0.18, SourceValue: 100, TargetValue: 18}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: uuid.New()})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), gomock.Any()).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), gomock.Any()).Return(&domain.KYCLimits{}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), gomock.Any(), "2580").Return(nil)
+	quoteRepositoryMock.EXPECT().Take(gomock.Any(), quote.ID).Return(quote, nil)
+	quoteRepositoryMock.EXPECT().Restore(gomock.Any(), quote).Return(nil)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: uuid.New(), Value: 100, Currency: "BRL", QuoteID: &quote.ID, PIN: "2580"})
+
+	assert.ErrorIs(t, err, domain.ErrQuoteNotFound)
+}
+
+func TestTransferService_Transfer_WhenQuotedTransferFails_ShouldRestoreQuote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	quoteRepositoryMock := mocks.NewMockQuoteRepository(ctrl)
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		userService:      userServiceMock,
+		pinService:       pinServiceMock,
+		kycService:       kycServiceMock,
+		walletRepository: walletRepositoryMock,
+		holdRepository:   holdRepositoryMock,
+		quoteRepository:  quoteRepositoryMock,
+	}
+
+	payerID := uuid.New()
+	payeeID := uuid.New()
+	quote := &domain.Quote{ID: uuid.New(), UserID: payerID, From: "BRL", To: "USD", Rate: 0.18, SourceValue: 100, TargetValue: 18}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), payerID, "2580").Return(nil)
+	quoteRepositoryMock.EXPECT().Take(gomock.Any(), quote.ID).Return(quote, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payerID, "BRL").Return(&domain.Wallet{UserID: payerID, Currency: "BRL", Type: domain.WalletTypeCOMMON, Balance: 50}, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payeeID, "USD").Return(&domain.Wallet{UserID: payeeID, Currency: "USD"}, nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payeeID).Return(&domain.KYCLimits{}, nil)
+	holdRepositoryMock.EXPECT().GetActiveAmountByPayerID(gomock.Any(), payerID, "BRL").Return(0.0, nil)
+	quoteRepositoryMock.EXPECT().Restore(gomock.Any(), quote).Return(nil)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: payeeID, Value: 100, Currency: "BRL", QuoteID: &quote.ID, PIN: "2580"})
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientBalance)
+}
+
+func TestTransferService_Transfer_WhenPayeeOnlyHoldsOtherCurrencies_ShouldReturnErrQuoteRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		userService:      userServiceMock,
+		pinService:       pinServiceMock,
+		kycService:       kycServiceMock,
+		walletRepository: walletRepositoryMock,
+	}
+
+	payerID := uuid.New()
+	payeeID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), gomock.Any()).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), gomock.Any(), "2580").Return(nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payerID, "BRL").Return(&domain.Wallet{UserID: payerID, Currency: "BRL", Balance: 100}, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payeeID, "BRL").Return(nil, nil)
+	walletRepositoryMock.EXPECT().GetAllByUserID(gomock.Any(), payeeID).Return([]*domain.Wallet{{UserID: payeeID, Currency: "USD"}}, nil)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: payeeID, Value: 50, Currency: "BRL", PIN: "2580"})
+
+	assert.ErrorIs(t, err, domain.ErrQuoteRequired)
+}
+
+func TestTransferService_Transfer_WhenQuoted_ShouldCreditPayeeInQuotedCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	quoteRepositoryMock := mocks.NewMockQuoteRepository(ctrl)
+	campaignServiceMock := mocks.NewMockCampaignService(ctrl)
+	contactServiceMock := mocks.NewMockContactService(ctrl)
+	authorizationServiceMock := mocks.NewMockAuthorizationService(ctrl)
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		userService:          userServiceMock,
+		pinService:           pinServiceMock,
+		kycService:           kycServiceMock,
+		transferRepository:   transferRepositoryMock,
+		walletRepository:     walletRepositoryMock,
+		holdRepository:       holdRepositoryMock,
+		quoteRepository:      quoteRepositoryMock,
+		campaignService:      campaignServiceMock,
+		contactService:       contactServiceMock,
+		authorizationService: authorizationServiceMock,
+	}
+
+	payerID := uuid.New()
+	payeeID := uuid.New()
+	quote := &domain.Quote{ID: uuid.New(), UserID: payerID, From: "BRL", To: "USD", Rate: 0.18, SourceValue: 100, TargetValue: 18}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), gomock.Any()).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{Transfer: 500}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), gomock.Any(), "2580").Return(nil)
+	quoteRepositoryMock.EXPECT().Take(gomock.Any(), quote.ID).Return(quote, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payerID, "BRL").Return(&domain.Wallet{UserID: payerID, Currency: "BRL", Type: domain.WalletTypeCOMMON, Balance: 150}, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payeeID, "USD").Return(&domain.Wallet{UserID: payeeID, Currency: "USD"}, nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payeeID).Return(&domain.KYCLimits{Balance: 18}, nil)
+	walletRepositoryMock.EXPECT().GetAllByUserID(gomock.Any(), payeeID).Return([]*domain.Wallet{{UserID: payeeID, Currency: "USD"}}, nil)
+	holdRepositoryMock.EXPECT().GetActiveAmountByPayerID(gomock.Any(), payerID, "BRL").Return(0.0, nil)
+	authorizationServiceMock.EXPECT().CheckAuthorization(gomock.Any()).Return(&client.AuthorizationResponse{Data: client.AuthorizationData{Authorization: true}}, nil)
+	transferRepositoryMock.EXPECT().Transfer(gomock.Any(), gomock.Any()).Return(nil)
+	campaignServiceMock.EXPECT().EvaluateTransfer(gomock.Any(), gomock.Any()).Return(nil)
+	contactServiceMock.EXPECT().RecordTransfer(gomock.Any(), gomock.Any()).Return(nil)
+
+	response, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: payeeID, Value: 100, Currency: "BRL", QuoteID: &quote.ID, PIN: "2580"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", response.PayeeCurrency)
+	assert.Equal(t, 18.0, response.PayeeValue)
+	assert.Equal(t, 0.18, response.Rate)
+}
+
+func TestTransferService_Transfer_WhenEmailNotVerified_ShouldReturnErrEmailNotVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+
+	transferService := &transactionService{
+		userService: userServiceMock,
+	}
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(domain.ErrEmailNotVerified)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: uuid.New(), Value: 50, Currency: "BRL"})
+
+	assert.ErrorIs(t, err, domain.ErrEmailNotVerified)
+}
+
+func TestTransferService_Transfer_WhenPINIsInvalid_ShouldNotCheckAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+	authorizationServiceMock := mocks.NewMockAuthorizationService(ctrl)
+
+	transferService := &transactionService{
+		userService:          userServiceMock,
+		pinService:           pinServiceMock,
+		kycService:           kycServiceMock,
+		authorizationService: authorizationServiceMock,
+	}
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), payerID, "1357").Return(domain.ErrPINInvalid)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: uuid.New(), Value: 50, Currency: "BRL", PIN: "1357"})
+
+	assert.ErrorIs(t, err, domain.ErrPINInvalid)
+}
+
+func TestTransferService_Transfer_WhenValueExceedsKYCLimit_ShouldNotVerifyPIN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		userService: userServiceMock,
+		kycService:  kycServiceMock,
+	}
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{Transfer: 500}, nil)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: uuid.New(), Value: 501, Currency: "BRL", PIN: "2580"})
+
+	assert.ErrorIs(t, err, domain.ErrKYCTransferLimitExceeded)
+}
+
+func TestTransferService_Transfer_WhenValueInAnotherCurrencyExceedsKYCLimit_ShouldReturnErrKYCTransferLimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+	rateProviderMock := mocks.NewMockRateProvider(ctrl)
+
+	transferService := &transactionService{
+		userService:  userServiceMock,
+		kycService:   kycServiceMock,
+		rateProvider: rateProviderMock,
+	}
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{Currency: "BRL", Transfer: 500}, nil)
+	rateProviderMock.EXPECT().Rate(gomock.Any(), "USD", "BRL").Return(5.0, nil)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: uuid.New(), Value: 101, Currency: "USD", PIN: "2580"})
+
+	assert.ErrorIs(t, err, domain.ErrKYCTransferLimitExceeded)
+}
+
+func TestTransferService_Transfer_WhenConvertedValueExceedsStepUpThreshold_ShouldRequireStepUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+	rateProviderMock := mocks.NewMockRateProvider(ctrl)
+	twoFactorServiceMock := mocks.NewMockTwoFactorService(ctrl)
+
+	transferService := &transactionService{
+		userService:      userServiceMock,
+		kycService:       kycServiceMock,
+		rateProvider:     rateProviderMock,
+		twoFactorService: twoFactorServiceMock,
+	}
+
+	config.Env.KYCLimitCurrency = "BRL"
+	config.Env.StepUpTransferValue = 1000
+	defer func() {
+		config.Env.KYCLimitCurrency = ""
+		config.Env.StepUpTransferValue = 0
+	}()
+
+	payerID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{Currency: "BRL"}, nil)
+	rateProviderMock.EXPECT().Rate(gomock.Any(), "USD", "BRL").Return(5.0, nil)
+	twoFactorServiceMock.EXPECT().VerifyStepUp(gomock.Any(), payerID, "").Return(domain.ErrStepUpRequired)
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: uuid.New(), Value: 300, Currency: "USD", PIN: "2580"})
+
+	assert.ErrorIs(t, err, domain.ErrStepUpRequired)
+}
+
+func TestTransferService_Transfer_WhenPayeeBalanceWouldExceedKYCLimit_ShouldReturnErrKYCBalanceLimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+	holdRepositoryMock := mocks.NewMockHoldRepository(ctrl)
+	authorizationServiceMock := mocks.NewMockAuthorizationService(ctrl)
+	rateProviderMock := mocks.NewMockRateProvider(ctrl)
+
+	userServiceMock := mocks.NewMockUserService(ctrl)
+	pinServiceMock := mocks.NewMockTransactionPINService(ctrl)
+	kycServiceMock := mocks.NewMockKYCService(ctrl)
+
+	transferService := &transactionService{
+		userService:          userServiceMock,
+		pinService:           pinServiceMock,
+		kycService:           kycServiceMock,
+		transferRepository:   transferRepositoryMock,
+		walletRepository:     walletRepositoryMock,
+		holdRepository:       holdRepositoryMock,
+		rateProvider:         rateProviderMock,
+		authorizationService: authorizationServiceMock,
+	}
+
+	payerID := uuid.New()
+	payeeID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: payerID})
+
+	payeeWallets := []*domain.Wallet{
+		{UserID: payeeID, Currency: "BRL", Balance: 1500},
+		{UserID: payeeID, Currency: "USD", Balance: 80},
+	}
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), payerID).Return(nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payerID).Return(&domain.KYCLimits{Currency: "BRL"}, nil)
+	pinServiceMock.EXPECT().Verify(gomock.Any(), payerID, "2580").Return(nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payerID, "BRL").Return(&domain.Wallet{UserID: payerID, Currency: "BRL", Type: domain.WalletTypeCOMMON, Balance: 500}, nil)
+	walletRepositoryMock.EXPECT().GetByUserID(gomock.Any(), payeeID, "BRL").Return(payeeWallets[0], nil)
+	kycServiceMock.EXPECT().GetLimits(gomock.Any(), payeeID).Return(&domain.KYCLimits{Currency: "BRL", Balance: 2000}, nil)
+	walletRepositoryMock.EXPECT().GetAllByUserID(gomock.Any(), payeeID).Return(payeeWallets, nil)
+	rateProviderMock.EXPECT().Rate(gomock.Any(), "USD", "BRL").Return(5.0, nil)
+	holdRepositoryMock.EXPECT().GetActiveAmountByPayerID(gomock.Any(), payerID, "BRL").Return(0.0, nil)
+	authorizationServiceMock.EXPECT().CheckAuthorization(gomock.Any()).Return(&client.AuthorizationResponse{Data: client.AuthorizationData{Authorization: true}}, nil)
+	transferRepositoryMock.EXPECT().Transfer(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, transfer *domain.Transfer) error {
+		// 1500 BRL + 80 USD * 5 = 1900 BRL, so 100 BRL more fits and 101 does not.
+		assert.True(t, transfer.PayeeBalanceCap.Allows(payeeWallets, "BRL", 100))
+		assert.False(t, transfer.PayeeBalanceCap.Allows(payeeWallets, "BRL", 101))
+		assert.False(t, transfer.PayeeBalanceCap.Allows(append(payeeWallets, &domain.Wallet{Currency: "EUR", Balance: 1}), "BRL", 1))
+
+		if !transfer.PayeeBalanceCap.Allows(payeeWallets, transfer.PayeeCurrency, transfer.PayeeValue) {
+			return domain.ErrKYCBalanceLimitExceeded
+		}
+		return nil
+	})
+
+	_, err := transferService.Transfer(ctx, &domain.TransferPayload{PayeeID: payeeID, Value: 101, Currency: "BRL", PIN: "2580"})
+
+	assert.ErrorIs(t, err, domain.ErrKYCBalanceLimitExceeded)
+}
+
+func TestTransferService_Refund_WhenCallerIsPayer_ShouldReturnErrTransferForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+
+	transferService := &transactionService{
+		transferRepository: transferRepositoryMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:       uuid.New(),
+		PayerID:  uuid.New(),
+		PayeeID:  uuid.New(),
+		Value:    50,
+		Currency: domain.DefaultCurrency,
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: transfer.PayerID})
+
+	transferRepositoryMock.EXPECT().GetByID(gomock.Any(), transfer.ID).Return(transfer, nil)
+
+	_, err := transferService.Refund(ctx, transfer.ID, &domain.RefundPayload{})
+
+	assert.ErrorIs(t, err, domain.ErrTransferForbidden)
+}
+
+func TestTransferService_Refund_WhenEscrowIsHeld_ShouldReturnErrRefundNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepositoryMock := mocks.NewMockTransferRepository(ctrl)
+
+	transferService := &transactionService{
+		transferRepository: transferRepositoryMock,
+	}
+
+	transfer := &domain.Transfer{
+		ID:           uuid.New(),
+		PayerID:      uuid.New(),
+		PayeeID:      uuid.New(),
+		Value:        50,
+		Currency:     domain.DefaultCurrency,
+		EscrowStatus: domain.EscrowStatusHeld,
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: transfer.PayeeID})
+
+	transferRepositoryMock.EXPECT().GetByID(gomock.Any(), transfer.ID).Return(transfer, nil)
+
+	_, err := transferService.Refund(ctx, transfer.ID, &domain.RefundPayload{})
+
+	assert.ErrorIs(t, err, domain.ErrRefundNotAllowed)
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const signInChallengeSize = 32
+
+type twoFactorService struct {
+	i                   *do.Injector
+	twoFactorRepository domain.TwoFactorRepository
+}
+
+func NewTwoFactorService(i *do.Injector) (domain.TwoFactorService, error) {
+	twoFactorRepository, err := do.Invoke[domain.TwoFactorRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactorService{
+		i:                   i,
+		twoFactorRepository: twoFactorRepository,
+	}, nil
+}
+
+// Enroll starts a new enrollment for the signed-in user. The secret only
+// takes effect once Activate confirms a code generated from it.
+func (t *twoFactorService) Enroll(ctx context.Context) (*domain.TwoFactorEnrollResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "Enroll"),
+	)
+
+	log.Info("Initializing two-factor enrollment process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	current, err := t.twoFactorRepository.GetByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get two-factor settings", slog.String("error", err.Error()))
+		return nil, domain.ErrEnableTwoFactor
+	}
+
+	if current.IsEnabled() {
+		log.Warn("Two-factor already enabled", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := secure.GenerateTOTPSecret()
+	if err != nil {
+		log.Error("Failed to generate two-factor secret", slog.String("error", err.Error()))
+		return nil, domain.ErrEnableTwoFactor
+	}
+
+	twoFactor := &domain.TwoFactor{
+		UserID:    session.UserID,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := t.twoFactorRepository.Save(ctx, twoFactor); err != nil {
+		log.Error("Failed to save two-factor enrollment", slog.String("error", err.Error()))
+		return nil, domain.ErrEnableTwoFactor
+	}
+
+	log.Info("Two-factor enrollment process executed successfully")
+	return &domain.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: secure.TOTPProvisioningURI(secret, config.Env.TwoFactorIssuer, session.Email),
+	}, nil
+}
+
+// Activate confirms the pending enrollment with a code from the
+// authenticator and returns the recovery codes, which are never shown again.
+func (t *twoFactorService) Activate(ctx context.Context, payload *domain.TwoFactorCodePayload) (*domain.RecoveryCodesResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "Activate"),
+	)
+
+	log.Info("Initializing two-factor activation process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	twoFactor, err := t.twoFactorRepository.GetByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get two-factor settings", slog.String("error", err.Error()))
+		return nil, domain.ErrEnableTwoFactor
+	}
+
+	if twoFactor == nil {
+		log.Warn("Two-factor enrollment not started", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+
+	if twoFactor.IsEnabled() {
+		log.Warn("Two-factor already enabled", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := secure.ValidateTOTP(twoFactor.Secret, payload.Code, time.Now().UTC())
+	if !ok {
+		log.Warn("Invalid two-factor activation code", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrTwoFactorCodeInvalid
+	}
+
+	codes, err := secure.GenerateRecoveryCodes(domain.RecoveryCodeCount)
+	if err != nil {
+		log.Error("Failed to generate recovery codes", slog.String("error", err.Error()))
+		return nil, domain.ErrEnableTwoFactor
+	}
+
+	now := time.Now().UTC()
+	recoveryCodes := make([]*domain.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, &domain.RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    session.UserID,
+			CodeHash:  secure.HashToken(secure.NormalizeRecoveryCode(code)),
+			CreatedAt: now,
+		})
+	}
+
+	if err := t.twoFactorRepository.Enable(ctx, session.UserID, step, recoveryCodes); err != nil {
+		if errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+			return nil, err
+		}
+
+		log.Error("Failed to enable two-factor", slog.String("error", err.Error()))
+		return nil, domain.ErrEnableTwoFactor
+	}
+
+	log.Info("Two-factor activation process executed successfully", slog.String("userID", session.UserID.String()))
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns two-factor authentication off after checking a TOTP or
+// recovery code.
+func (t *twoFactorService) Disable(ctx context.Context, payload *domain.TwoFactorCodePayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "Disable"),
+	)
+
+	log.Info("Initializing two-factor disable process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	twoFactor, err := t.twoFactorRepository.GetByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get two-factor settings", slog.String("error", err.Error()))
+		return err
+	}
+
+	if !twoFactor.IsEnabled() {
+		log.Warn("Two-factor not enabled", slog.String("userID", session.UserID.String()))
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	if err := t.verifyWithLockout(ctx, log, twoFactor, payload.Code, true); err != nil {
+		log.Warn("Invalid two-factor code to disable", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.twoFactorRepository.Delete(ctx, session.UserID); err != nil {
+		log.Error("Failed to disable two-factor", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Two-factor disable process executed successfully", slog.String("userID", session.UserID.String()))
+	return nil
+}
+
+func (t *twoFactorService) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	twoFactor, err := t.twoFactorRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return twoFactor.IsEnabled(), nil
+}
+
+// CreateChallenge records that userID passed the password step from device
+// and returns the opaque token that completes the sign-in.
+func (t *twoFactorService) CreateChallenge(ctx context.Context, userID uuid.UUID, device *domain.Device) (string, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "CreateChallenge"),
+	)
+
+	token, err := secure.GenerateToken(signInChallengeSize)
+	if err != nil {
+		log.Error("Failed to generate sign-in challenge", slog.String("error", err.Error()))
+		return "", err
+	}
+
+	challenge := &domain.SignInChallenge{
+		UserID: userID,
+		Device: *device,
+	}
+
+	if err := t.twoFactorRepository.CreateChallenge(ctx, secure.HashToken(token), challenge); err != nil {
+		log.Error("Failed to save sign-in challenge", slog.String("error", err.Error()))
+		return "", err
+	}
+
+	return token, nil
+}
+
+// CompleteChallenge checks the second factor of a sign-in. A challenge is
+// discarded once it succeeds or after too many wrong codes, and wrong codes
+// also count towards the lockout of the user, since a new challenge is only
+// a correct password away.
+func (t *twoFactorService) CompleteChallenge(ctx context.Context, payload *domain.SignInTwoFactorPayload) (*domain.SignInChallenge, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "CompleteChallenge"),
+	)
+
+	log.Info("Initializing complete sign-in challenge process")
+
+	tokenHash := secure.HashToken(payload.ChallengeToken)
+
+	challenge, err := t.twoFactorRepository.GetChallenge(ctx, tokenHash)
+	if err != nil {
+		log.Error("Failed to get sign-in challenge", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if challenge == nil {
+		log.Warn("Sign-in challenge not found")
+		return nil, domain.ErrSignInChallengeInvalid
+	}
+
+	attempts, err := t.twoFactorRepository.IncrementChallengeAttempts(ctx, tokenHash)
+	if err != nil {
+		log.Error("Failed to count sign-in challenge attempt", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if attempts > domain.SignInChallengeMaxAttempts {
+		log.Warn("Too many sign-in challenge attempts", slog.String("userID", challenge.UserID.String()))
+		if err := t.twoFactorRepository.DeleteChallenge(ctx, tokenHash); err != nil {
+			log.Error("Failed to delete sign-in challenge", slog.String("error", err.Error()))
+		}
+		return nil, domain.ErrSignInChallengeInvalid
+	}
+
+	twoFactor, err := t.twoFactorRepository.GetByUserID(ctx, challenge.UserID)
+	if err != nil {
+		log.Error("Failed to get two-factor settings", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !twoFactor.IsEnabled() {
+		log.Warn("Two-factor disabled since the challenge was issued", slog.String("userID", challenge.UserID.String()))
+		return nil, domain.ErrSignInChallengeInvalid
+	}
+
+	if err := t.verifyWithLockout(ctx, log, twoFactor, payload.Code, true); err != nil {
+		log.Warn("Invalid sign-in two-factor code", slog.String("userID", challenge.UserID.String()))
+		return nil, err
+	}
+
+	if err := t.twoFactorRepository.DeleteChallenge(ctx, tokenHash); err != nil {
+		log.Error("Failed to delete sign-in challenge", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Complete sign-in challenge process executed successfully", slog.String("userID", challenge.UserID.String()))
+	return challenge, nil
+}
+
+// VerifyStepUp requires a fresh TOTP code from userID. Recovery codes are
+// not accepted here. Wrong codes count towards the same lockout as Disable.
+func (t *twoFactorService) VerifyStepUp(ctx context.Context, userID uuid.UUID, code string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "VerifyStepUp"),
+	)
+
+	twoFactor, err := t.twoFactorRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get two-factor settings", slog.String("error", err.Error()))
+		return err
+	}
+
+	if !twoFactor.IsEnabled() {
+		log.Warn("Step-up requested without two-factor enabled", slog.String("userID", userID.String()))
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	if code == "" {
+		return domain.ErrStepUpRequired
+	}
+
+	return t.verifyWithLockout(ctx, log, twoFactor, code, false)
+}
+
+// verifyWithLockout checks code like verify. After TwoFactorMaxAttempts
+// wrong codes, on any endpoint, the user is locked out of them for
+// TwoFactorLockoutDuration.
+func (t *twoFactorService) verifyWithLockout(ctx context.Context, log *slog.Logger, twoFactor *domain.TwoFactor, code string, allowRecovery bool) error {
+	lock, err := t.twoFactorRepository.GetLock(ctx, twoFactor.UserID)
+	if err != nil {
+		log.Error("Failed to get two-factor lock", slog.String("error", err.Error()))
+		return err
+	}
+
+	if lock > 0 {
+		log.Warn("Two-factor locked", slog.String("userID", twoFactor.UserID.String()))
+		return &domain.RetryAfterError{Err: domain.ErrTwoFactorLocked, RetryAfter: lock}
+	}
+
+	if err := t.verify(ctx, twoFactor, code, allowRecovery); err != nil {
+		if !errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			return err
+		}
+		return t.registerFailure(ctx, log, twoFactor.UserID)
+	}
+
+	if err := t.twoFactorRepository.ResetAttempts(ctx, twoFactor.UserID); err != nil {
+		log.Error("Failed to reset two-factor attempts", slog.String("error", err.Error()))
+	}
+
+	return nil
+}
+
+func (t *twoFactorService) registerFailure(ctx context.Context, log *slog.Logger, userID uuid.UUID) error {
+	failures, err := t.twoFactorRepository.RegisterFailure(ctx, userID)
+	if err != nil {
+		log.Error("Failed to register two-factor failure", slog.String("error", err.Error()))
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	maxAttempts := config.Env.TwoFactorMaxAttempts
+	if maxAttempts <= 0 || failures < maxAttempts {
+		log.Warn("Invalid two-factor code", slog.String("userID", userID.String()), slog.Int64("failures", failures))
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	duration := config.Env.TwoFactorLockoutDuration
+	if err := t.twoFactorRepository.Lock(ctx, userID, duration); err != nil {
+		log.Error("Failed to lock two-factor", slog.String("error", err.Error()))
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	log.Warn("Two-factor locked after too many wrong codes", slog.String("userID", userID.String()))
+	return &domain.RetryAfterError{Err: domain.ErrTwoFactorLocked, RetryAfter: duration}
+}
+
+// verify accepts a TOTP code whose time step was not used yet or, when
+// allowRecovery is set, an unused recovery code.
+func (t *twoFactorService) verify(ctx context.Context, twoFactor *domain.TwoFactor, code string, allowRecovery bool) error {
+	if step, ok := secure.ValidateTOTP(twoFactor.Secret, code, time.Now().UTC()); ok {
+		fresh, err := t.twoFactorRepository.UseStep(ctx, twoFactor.UserID, step)
+		if err != nil {
+			return err
+		}
+
+		if !fresh {
+			return domain.ErrTwoFactorCodeInvalid
+		}
+		return nil
+	}
+
+	if !allowRecovery {
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	used, err := t.twoFactorRepository.UseRecoveryCode(ctx, twoFactor.UserID, secure.HashToken(secure.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
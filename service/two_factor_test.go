@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/GSVillas/pic-pay-desafio/secure"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactorService_VerifyStepUp_WhenCodeMissing_ShouldReturnErrStepUpRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	userID := uuid.New()
+	enabledAt := time.Now().UTC()
+
+	twoFactorRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TwoFactor{UserID: userID, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+
+	err := twoFactorService.VerifyStepUp(context.Background(), userID, "")
+
+	assert.ErrorIs(t, err, domain.ErrStepUpRequired)
+}
+
+func TestTwoFactorService_VerifyStepUp_WhenNotEnabled_ShouldReturnErrTwoFactorNotEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	userID := uuid.New()
+
+	twoFactorRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(nil, nil)
+
+	err := twoFactorService.VerifyStepUp(context.Background(), userID, "123456")
+
+	assert.ErrorIs(t, err, domain.ErrTwoFactorNotEnabled)
+}
+
+func TestTwoFactorService_CompleteChallenge_WhenTooManyAttempts_ShouldDiscardChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	payload := &domain.SignInTwoFactorPayload{ChallengeToken: "challenge", Code: "123456"}
+	tokenHash := secure.HashToken(payload.ChallengeToken)
+
+	twoFactorRepositoryMock.EXPECT().GetChallenge(gomock.Any(), tokenHash).Return(&domain.SignInChallenge{UserID: uuid.New()}, nil)
+	twoFactorRepositoryMock.EXPECT().IncrementChallengeAttempts(gomock.Any(), tokenHash).Return(int64(domain.SignInChallengeMaxAttempts+1), nil)
+	twoFactorRepositoryMock.EXPECT().DeleteChallenge(gomock.Any(), tokenHash).Return(nil)
+
+	_, err := twoFactorService.CompleteChallenge(context.Background(), payload)
+
+	assert.ErrorIs(t, err, domain.ErrSignInChallengeInvalid)
+}
+
+func TestTwoFactorService_CompleteChallenge_WhenRecoveryCodeUsed_ShouldReturnErrTwoFactorCodeInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	userID := uuid.New()
+	enabledAt := time.Now().UTC()
+	payload := &domain.SignInTwoFactorPayload{ChallengeToken: "challenge", Code: "abcde-fghij"}
+	tokenHash := secure.HashToken(payload.ChallengeToken)
+
+	twoFactorRepositoryMock.EXPECT().GetChallenge(gomock.Any(), tokenHash).Return(&domain.SignInChallenge{UserID: userID}, nil)
+	twoFactorRepositoryMock.EXPECT().IncrementChallengeAttempts(gomock.Any(), tokenHash).Return(int64(1), nil)
+	twoFactorRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TwoFactor{UserID: userID, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+	twoFactorRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(time.Duration(0), nil)
+	twoFactorRepositoryMock.EXPECT().UseRecoveryCode(gomock.Any(), userID, secure.HashToken("abcdefghij")).Return(false, nil)
+	twoFactorRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), userID).Return(int64(1), nil)
+
+	_, err := twoFactorService.CompleteChallenge(context.Background(), payload)
+
+	assert.ErrorIs(t, err, domain.ErrTwoFactorCodeInvalid)
+}
+
+func TestTwoFactorService_CompleteChallenge_WhenCodesKeepFailingAcrossChallenges_ShouldLockTheUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config.Env.TwoFactorMaxAttempts = 5
+	config.Env.TwoFactorLockoutDuration = 15 * time.Minute
+	defer func() {
+		config.Env.TwoFactorMaxAttempts = 0
+		config.Env.TwoFactorLockoutDuration = 0
+	}()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	userID := uuid.New()
+	enabledAt := time.Now().UTC()
+	payload := &domain.SignInTwoFactorPayload{ChallengeToken: "fresh-challenge", Code: "000000"}
+	tokenHash := secure.HashToken(payload.ChallengeToken)
+
+	twoFactorRepositoryMock.EXPECT().GetChallenge(gomock.Any(), tokenHash).Return(&domain.SignInChallenge{UserID: userID}, nil)
+	twoFactorRepositoryMock.EXPECT().IncrementChallengeAttempts(gomock.Any(), tokenHash).Return(int64(1), nil)
+	twoFactorRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TwoFactor{UserID: userID, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+	twoFactorRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(time.Duration(0), nil)
+	twoFactorRepositoryMock.EXPECT().UseRecoveryCode(gomock.Any(), userID, gomock.Any()).Return(false, nil)
+	twoFactorRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), userID).Return(int64(5), nil)
+	twoFactorRepositoryMock.EXPECT().Lock(gomock.Any(), userID, 15*time.Minute).Return(nil)
+
+	_, err := twoFactorService.CompleteChallenge(context.Background(), payload)
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorIs(t, err, domain.ErrTwoFactorLocked)
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.Equal(t, 15*time.Minute, retryAfterErr.RetryAfter)
+}
+
+func TestTwoFactorService_VerifyStepUp_WhenLocked_ShouldReturnErrTwoFactorLocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	userID := uuid.New()
+	enabledAt := time.Now().UTC()
+
+	twoFactorRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TwoFactor{UserID: userID, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+	twoFactorRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(10*time.Minute, nil)
+
+	err := twoFactorService.VerifyStepUp(context.Background(), userID, "123456")
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorIs(t, err, domain.ErrTwoFactorLocked)
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.Equal(t, 10*time.Minute, retryAfterErr.RetryAfter)
+}
+
+func TestTwoFactorService_Disable_WhenCodeIsWrong_ShouldCountTheFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config.Env.TwoFactorMaxAttempts = 5
+	config.Env.TwoFactorLockoutDuration = 15 * time.Minute
+	defer func() {
+		config.Env.TwoFactorMaxAttempts = 0
+		config.Env.TwoFactorLockoutDuration = 0
+	}()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	userID := uuid.New()
+	enabledAt := time.Now().UTC()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	twoFactorRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TwoFactor{UserID: userID, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+	twoFactorRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(time.Duration(0), nil)
+	twoFactorRepositoryMock.EXPECT().UseRecoveryCode(gomock.Any(), userID, secure.HashToken("abcdefghij")).Return(false, nil)
+	twoFactorRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), userID).Return(int64(4), nil)
+
+	err := twoFactorService.Disable(ctx, &domain.TwoFactorCodePayload{Code: "abcde-fghij"})
+
+	assert.ErrorIs(t, err, domain.ErrTwoFactorCodeInvalid)
+}
+
+func TestTwoFactorService_Disable_WhenMaxAttemptsReached_ShouldLockAndNotDisable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config.Env.TwoFactorMaxAttempts = 5
+	config.Env.TwoFactorLockoutDuration = 15 * time.Minute
+	defer func() {
+		config.Env.TwoFactorMaxAttempts = 0
+		config.Env.TwoFactorLockoutDuration = 0
+	}()
+
+	twoFactorRepositoryMock := mocks.NewMockTwoFactorRepository(ctrl)
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: twoFactorRepositoryMock,
+	}
+
+	userID := uuid.New()
+	enabledAt := time.Now().UTC()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	twoFactorRepositoryMock.EXPECT().GetByUserID(gomock.Any(), userID).Return(&domain.TwoFactor{UserID: userID, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+	twoFactorRepositoryMock.EXPECT().GetLock(gomock.Any(), userID).Return(time.Duration(0), nil)
+	twoFactorRepositoryMock.EXPECT().UseRecoveryCode(gomock.Any(), userID, secure.HashToken("abcdefghij")).Return(false, nil)
+	twoFactorRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), userID).Return(int64(5), nil)
+	twoFactorRepositoryMock.EXPECT().Lock(gomock.Any(), userID, 15*time.Minute).Return(nil)
+
+	err := twoFactorService.Disable(ctx, &domain.TwoFactorCodePayload{Code: "abcde-fghij"})
+
+	assert.ErrorIs(t, err, domain.ErrTwoFactorLocked)
+}
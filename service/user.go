@@ -2,18 +2,34 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/url"
+	"time"
 
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/domain"
 	"github.com/GSVillas/pic-pay-desafio/secure"
-	"github.com/klassmann/cpfcnpj"
+	"github.com/google/uuid"
 	"github.com/samber/do"
 )
 
+const (
+	passwordResetTokenSize     = 32
+	emailVerificationTokenSize = 32
+)
+
 type userService struct {
-	i              *do.Injector
-	userRepository domain.UserRepository
-	sessionService domain.SessionService
+	i                *do.Injector
+	userRepository   domain.UserRepository
+	sessionService   domain.SessionService
+	twoFactorService domain.TwoFactorService
+	emailService     client.EmailService
+	passwordHasher   secure.PasswordHasher
+
+	signInAttemptRepository domain.SignInAttemptRepository
 }
 
 func NewUserService(i *do.Injector) (domain.UserService, error) {
@@ -27,15 +43,39 @@ func NewUserService(i *do.Injector) (domain.UserService, error) {
 		return nil, err
 	}
 
+	twoFactorService, err := do.Invoke[domain.TwoFactorService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[client.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	signInAttemptRepository, err := do.Invoke[domain.SignInAttemptRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHasher, err := do.Invoke[secure.PasswordHasher](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &userService{
-		i:              i,
-		userRepository: userRepository,
-		sessionService: sessionService,
+		i:                       i,
+		userRepository:          userRepository,
+		sessionService:          sessionService,
+		twoFactorService:        twoFactorService,
+		emailService:            emailService,
+		signInAttemptRepository: signInAttemptRepository,
+		passwordHasher:          passwordHasher,
 	}, nil
 }
 
 func (u *userService) Create(ctx context.Context, payload *domain.UserPayload) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "user"),
 		slog.String("func", "Create"),
 	)
@@ -45,50 +85,59 @@ func (u *userService) Create(ctx context.Context, payload *domain.UserPayload) e
 	user, err := u.userRepository.GetByEmail(ctx, payload.Email)
 	if err != nil {
 		log.Error("Failed to get user by email", slog.String("error", err.Error()))
-		return domain.ErrGetUserByCPF
+		return domain.ErrGetUserByEmail
 	}
 
 	if user != nil {
-		log.Warn("There is already a user with this ", slog.String("email:", payload.Email))
+		log.Warn("There is already a user with this email")
 		return domain.ErrEmailAlreadyRegister
 	}
 
-	user, err = u.userRepository.GetByCPF(ctx, string(cpfcnpj.NewCPF(payload.CPF)))
+	user, err = u.userRepository.GetByDocument(ctx, payload.CleanDocument())
 	if err != nil {
-		log.Error("Failed to get user by cpf", slog.String("error", err.Error()))
-		return domain.ErrGetUserByCPF
+		log.Error("Failed to get user by document", slog.String("error", err.Error()))
+		return domain.ErrGetUserByDocument
 	}
 
 	if user != nil {
-		log.Warn("There is already a user with this cpf")
-		return domain.ErrCPFAlreadyRegister
+		log.Warn("There is already a user with this document", slog.String("documentType", string(payload.DocumentType)))
+		return domain.ErrDocumentAlreadyRegister
 	}
 
-	passwordHash, err := secure.Hash(payload.Password)
+	passwordHash, err := u.passwordHasher.Hash(payload.Password)
 	if err != nil {
 		log.Error("Failed to hash password", slog.String("error", err.Error()))
 		return domain.ErrHashingPassword
 	}
 
-	user = payload.ToUser(string(passwordHash))
+	user = payload.ToUser(passwordHash)
 
 	if err := u.userRepository.Create(ctx, user); err != nil {
 		log.Error("Failed to create user", slog.String("error", err.Error()))
 		return domain.ErrCreateUser
 	}
 
+	if err := u.sendEmailVerification(ctx, user); err != nil {
+		log.Error("Failed to send email verification", slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+	}
+
 	log.Info("User creation process executed successfully")
 	return nil
 }
 
 func (u *userService) SignIn(ctx context.Context, payload *domain.SignInPayload) (*domain.SignInResponse, error) {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "user"),
 		slog.String("func", "SignIn"),
 	)
 
 	log.Info("Initializing user sign in process")
 
+	if err := u.checkSignInAttempt(ctx, payload); err != nil {
+		log.Warn("Sign in attempt refused", slog.String("error", err.Error()))
+		return nil, err
+	}
+
 	user, err := u.userRepository.GetByEmail(ctx, payload.Email)
 	if err != nil {
 		log.Error("Failed to get user by email", slog.String("error", err.Error()))
@@ -96,23 +145,859 @@ func (u *userService) SignIn(ctx context.Context, payload *domain.SignInPayload)
 	}
 
 	if user == nil {
-		log.Warn("No user found with this ", slog.String("email:", payload.Email))
-		return nil, domain.ErrUserNotFound
+		log.Warn("No user found with this email")
+		return nil, u.registerSignInFailure(ctx, payload, nil, domain.ErrUserNotFound)
+	}
+
+	if err := u.passwordHasher.Verify(user.PasswordHash, payload.Password); err != nil {
+		log.Warn("The password entered is invalid", slog.String("userID", user.ID.String()))
+		return nil, u.registerSignInFailure(ctx, payload, user, domain.ErrInvalidPassword)
 	}
 
-	if err := secure.CheckPassword(user.PasswordHash, payload.Password); err != nil {
-		log.Warn("The password entered is invalid for the ", slog.String("email: ", payload.Email))
-		return nil, domain.ErrInvalidPassword
+	u.rehashPassword(ctx, user, payload.Password)
+
+	twoFactorEnabled, err := u.twoFactorService.IsEnabled(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to check two-factor settings", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateSession
+	}
+
+	// With two-factor the attempts are only reset by SignInTwoFactor, so the
+	// password alone does not clear the limits.
+	if twoFactorEnabled {
+		challengeToken, err := u.twoFactorService.CreateChallenge(ctx, user.ID, payload.ToDevice())
+		if err != nil {
+			log.Error("Failed to create sign-in challenge", slog.String("error", err.Error()))
+			return nil, domain.ErrCreateSession
+		}
+
+		log.Info("user sign in waiting for two-factor code")
+		return &domain.SignInResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
 	}
 
-	token, err := u.sessionService.Create(ctx, user)
+	if err := u.signInAttemptRepository.Reset(ctx, payload.Email); err != nil {
+		log.Warn("Failed to reset sign in attempts", slog.String("error", err.Error()))
+	}
+
+	response, err := u.sessionService.Create(ctx, user, payload.ToDevice())
 	if err != nil {
 		log.Error("Was not possible create the session for the user", slog.String("error:", err.Error()))
 		return nil, domain.ErrCreateSession
 	}
 
 	log.Info("user sign in process executed successfully")
-	return &domain.SignInResponse{
-		Token: token,
-	}, nil
+	return response, nil
+}
+
+// SignInTwoFactor completes a sign-in started by SignIn with the second
+// factor and creates the session on the device of the password step.
+func (u *userService) SignInTwoFactor(ctx context.Context, payload *domain.SignInTwoFactorPayload) (*domain.SignInResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "SignInTwoFactor"),
+	)
+
+	log.Info("Initializing user two-factor sign in process")
+
+	challenge, err := u.twoFactorService.CompleteChallenge(ctx, payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrSignInChallengeInvalid) || errors.Is(err, domain.ErrTwoFactorCodeInvalid) || errors.Is(err, domain.ErrTwoFactorLocked) {
+			log.Warn("Two-factor sign in rejected", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Error("Failed to complete sign-in challenge", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateSession
+	}
+
+	user, err := u.userRepository.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateSession
+	}
+
+	if user == nil {
+		log.Warn("User of sign-in challenge not found", slog.String("userID", challenge.UserID.String()))
+		return nil, domain.ErrUserNotFound
+	}
+
+	if err := u.signInAttemptRepository.Reset(ctx, user.Email); err != nil {
+		log.Warn("Failed to reset sign in attempts", slog.String("error", err.Error()))
+	}
+
+	response, err := u.sessionService.Create(ctx, user, &challenge.Device)
+	if err != nil {
+		log.Error("Was not possible create the session for the user", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateSession
+	}
+
+	log.Info("user two-factor sign in process executed successfully")
+	return response, nil
+}
+
+func (u *userService) Refresh(ctx context.Context, payload *domain.RefreshPayload) (*domain.SignInResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "Refresh"),
+	)
+
+	log.Info("Initializing user refresh process")
+
+	response, err := u.sessionService.Refresh(ctx, payload.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenInvalid) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			log.Warn("Refresh token rejected", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Error("Was not possible refresh the session for the user", slog.String("error", err.Error()))
+		return nil, domain.ErrCreateSession
+	}
+
+	log.Info("user refresh process executed successfully")
+	return response, nil
+}
+
+// ForgotPassword emails a reset link when the email belongs to a user. It
+// reports success either way so the endpoint cannot be used to find out
+// which emails are registered.
+func (u *userService) ForgotPassword(ctx context.Context, payload *domain.ForgotPasswordPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "ForgotPassword"),
+	)
+
+	log.Info("Initializing forgot password process")
+
+	if err := u.checkPasswordResetRequest(ctx, payload); err != nil {
+		log.Warn("Password reset request throttled", slog.String("error", err.Error()))
+		return err
+	}
+
+	user, err := u.userRepository.GetByEmail(ctx, payload.Email)
+	if err != nil {
+		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		return domain.ErrGetUserByEmail
+	}
+
+	if user == nil {
+		log.Warn("Password reset requested for unknown email")
+		return nil
+	}
+
+	token, err := secure.GenerateToken(passwordResetTokenSize)
+	if err != nil {
+		log.Error("Failed to generate password reset token", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.userRepository.CreatePasswordReset(ctx, user.ID, secure.HashToken(token)); err != nil {
+		log.Error("Failed to save password reset", slog.String("error", err.Error()))
+		return err
+	}
+
+	email := &client.Email{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Text:    passwordResetEmailText(user.Name, token),
+	}
+
+	if err := u.emailService.Send(ctx, email); err != nil {
+		log.Error("Failed to send password reset email", slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+		return nil
+	}
+
+	log.Info("Forgot password process executed successfully")
+	return nil
+}
+
+// checkPasswordResetRequest counts the request against both the email and
+// the IP and refuses it once either is over its budget. Unknown emails are
+// counted too so the limit does not reveal which emails are registered.
+// Zero limits disable the matching check.
+func (u *userService) checkPasswordResetRequest(ctx context.Context, payload *domain.ForgotPasswordPayload) error {
+	requests, err := u.signInAttemptRepository.RegisterPasswordReset(ctx, payload.Email, payload.IP)
+	if err != nil {
+		return err
+	}
+
+	if maxRequests := config.Env.PasswordResetIPMaxRequests; maxRequests > 0 && requests.IP > maxRequests {
+		return &domain.RetryAfterError{Err: domain.ErrTooManyResetRequests, RetryAfter: requests.IPRetryAfter}
+	}
+
+	if maxRequests := config.Env.PasswordResetMaxRequests; maxRequests > 0 && requests.Email > maxRequests {
+		return &domain.RetryAfterError{Err: domain.ErrTooManyResetRequests, RetryAfter: requests.EmailRetryAfter}
+	}
+
+	return nil
+}
+
+func (u *userService) ResetPassword(ctx context.Context, payload *domain.ResetPasswordPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "ResetPassword"),
+	)
+
+	log.Info("Initializing reset password process")
+
+	userID, err := u.userRepository.TakePasswordReset(ctx, secure.HashToken(payload.Token))
+	if err != nil {
+		log.Error("Failed to take password reset", slog.String("error", err.Error()))
+		return domain.ErrUpdatePassword
+	}
+
+	if userID == uuid.Nil {
+		log.Warn("Invalid password reset token")
+		return domain.ErrResetTokenInvalid
+	}
+
+	if err := u.updatePassword(ctx, userID, payload.Password); err != nil {
+		log.Error("Failed to reset password", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Reset password process executed successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (u *userService) ChangePassword(ctx context.Context, payload *domain.ChangePasswordPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "ChangePassword"),
+	)
+
+	log.Info("Initializing change password process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	user, err := u.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return domain.ErrUpdatePassword
+	}
+
+	if user == nil {
+		log.Warn("User of session not found", slog.String("userID", session.UserID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	if err := u.passwordHasher.Verify(user.PasswordHash, payload.CurrentPassword); err != nil {
+		log.Warn("The current password entered is invalid", slog.String("userID", user.ID.String()))
+		return domain.ErrInvalidPassword
+	}
+
+	if err := u.updatePassword(ctx, user.ID, payload.Password); err != nil {
+		log.Error("Failed to change password", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Change password process executed successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
+
+func (u *userService) VerifyEmail(ctx context.Context, payload *domain.VerifyEmailPayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "VerifyEmail"),
+	)
+
+	log.Info("Initializing verify email process")
+
+	userID, err := u.userRepository.TakeEmailVerification(ctx, secure.HashToken(payload.Token))
+	if err != nil {
+		log.Error("Failed to take email verification", slog.String("error", err.Error()))
+		return domain.ErrVerifyEmail
+	}
+
+	if userID == uuid.Nil {
+		log.Warn("Invalid email verification token")
+		return domain.ErrVerifyTokenInvalid
+	}
+
+	if err := u.userRepository.MarkEmailVerified(ctx, userID); err != nil {
+		log.Error("Failed to mark email verified", slog.String("error", err.Error()))
+		return domain.ErrVerifyEmail
+	}
+
+	log.Info("Verify email process executed successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+// ResendEmailVerification sends a new verification link to the signed in
+// user, at most once per EmailVerificationResendInterval.
+func (u *userService) ResendEmailVerification(ctx context.Context) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "ResendEmailVerification"),
+	)
+
+	log.Info("Initializing resend email verification process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	user, err := u.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return domain.ErrVerifyEmail
+	}
+
+	if user == nil {
+		log.Warn("User of session not found", slog.String("userID", session.UserID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	if user.IsEmailVerified() {
+		log.Warn("Email already verified", slog.String("userID", user.ID.String()))
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	held, err := u.userRepository.HoldEmailVerificationResend(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to hold email verification resend", slog.String("error", err.Error()))
+		return domain.ErrVerifyEmail
+	}
+
+	if !held {
+		log.Warn("Email verification resent too soon", slog.String("userID", user.ID.String()))
+		return domain.ErrVerificationTooSoon
+	}
+
+	if err := u.sendEmailVerification(ctx, user); err != nil {
+		log.Error("Failed to send email verification", slog.String("error", err.Error()))
+		return domain.ErrVerifyEmail
+	}
+
+	log.Info("Resend email verification process executed successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
+
+// EnsureEmailVerified returns ErrEmailNotVerified until the user confirms
+// the email, so unverified accounts cannot hold or move money.
+func (u *userService) EnsureEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "EnsureEmailVerified"),
+	)
+
+	user, err := u.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return domain.ErrVerifyEmail
+	}
+
+	if user == nil {
+		log.Warn("User not found", slog.String("userID", userID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	if !user.IsEmailVerified() {
+		log.Warn("Email verification pending", slog.String("userID", userID.String()))
+		return domain.ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+// EnsureCompany returns ErrMerchantRequiresCNPJ unless the user registered
+// with a CNPJ, since only companies can receive payments as merchants.
+func (u *userService) EnsureCompany(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "EnsureCompany"),
+	)
+
+	user, err := u.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found", slog.String("userID", userID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	if !user.IsCompany() {
+		log.Warn("Merchant wallet requested without cnpj", slog.String("userID", userID.String()))
+		return domain.ErrMerchantRequiresCNPJ
+	}
+
+	return nil
+}
+
+// Unlock lifts a sign-in lockout before it expires.
+func (u *userService) Unlock(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "Unlock"),
+	)
+
+	log.Info("Initializing unlock user process")
+
+	user, err := u.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return domain.ErrUnlockUser
+	}
+
+	if user == nil {
+		log.Warn("User not found", slog.String("userID", userID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	if err := u.signInAttemptRepository.Reset(ctx, user.Email); err != nil {
+		log.Error("Failed to reset sign in attempts", slog.String("error", err.Error()))
+		return domain.ErrUnlockUser
+	}
+
+	log.Info("Unlock user process executed successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+// UpdateRole grants a new role to the user, effective on their live
+// sessions too. Nobody may change their own role, so the last admin cannot
+// demote themselves by mistake.
+func (u *userService) UpdateRole(ctx context.Context, userID uuid.UUID, payload *domain.RolePayload) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	log.Info("Initializing update role process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return domain.ErrSessionNotFound
+	}
+
+	if session.UserID == userID {
+		log.Warn("Attempt to change own role", slog.String("userID", userID.String()))
+		return domain.ErrRoleSelfChange
+	}
+
+	if err := u.userRepository.UpdateRole(ctx, userID, payload.Role); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("User not found", slog.String("userID", userID.String()))
+			return err
+		}
+
+		log.Error("Failed to update role", slog.String("error", err.Error()))
+		return domain.ErrUpdateRole
+	}
+
+	if err := u.sessionService.UpdateRole(ctx, userID, payload.Role); err != nil {
+		log.Error("Failed to update role of sessions", slog.String("error", err.Error()))
+		return domain.ErrUpdateRole
+	}
+
+	log.Info("Update role process executed successfully", slog.String("userID", userID.String()), slog.String("role", string(payload.Role)), slog.String("by", session.UserID.String()))
+	return nil
+}
+
+// GrantMerchantAdmin makes a customer who opened a merchant wallet the
+// admin of their merchant. Users already holding another role keep it.
+func (u *userService) GrantMerchantAdmin(ctx context.Context, userID uuid.UUID) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "GrantMerchantAdmin"),
+	)
+
+	user, err := u.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return domain.ErrUpdateRole
+	}
+
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+
+	if user.Role != domain.RoleCustomer {
+		return nil
+	}
+
+	if err := u.userRepository.UpdateRole(ctx, userID, domain.RoleMerchantAdmin); err != nil {
+		log.Error("Failed to update role", slog.String("error", err.Error()))
+		return domain.ErrUpdateRole
+	}
+
+	if err := u.sessionService.UpdateRole(ctx, userID, domain.RoleMerchantAdmin); err != nil {
+		log.Error("Failed to update role of sessions", slog.String("error", err.Error()))
+		return domain.ErrUpdateRole
+	}
+
+	log.Info("Grant merchant admin process executed successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (u *userService) GetProfile(ctx context.Context) (*domain.UserProfileResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "GetProfile"),
+	)
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	user, err := u.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return nil, domain.ErrGetProfile
+	}
+
+	if user == nil {
+		log.Warn("User of session not found", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrUserNotFound
+	}
+
+	return user.ToProfileResponse(), nil
+}
+
+// UpdateProfile applies payload to the profile at version ifMatch, the ETag
+// the client read. A new email must be verified again before the user can
+// move money, and the previous address is told about the change.
+func (u *userService) UpdateProfile(ctx context.Context, payload *domain.UpdateProfilePayload, ifMatch string) (*domain.UserProfileResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "UpdateProfile"),
+	)
+
+	log.Info("Initializing update profile process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	user, err := u.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return nil, domain.ErrUpdateProfile
+	}
+
+	if user == nil {
+		log.Warn("User of session not found", slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrUserNotFound
+	}
+
+	if user.ETag() != ifMatch {
+		log.Warn("Profile version mismatch", slog.String("userID", user.ID.String()))
+		return nil, domain.ErrProfileModified
+	}
+
+	version := user.UpdatedAt
+	previousEmail := user.Email
+
+	if payload.Name != nil {
+		user.Name = *payload.Name
+	}
+
+	if payload.Phone != nil {
+		user.Phone = *payload.Phone
+	}
+
+	emailChanged := payload.Email != nil && *payload.Email != user.Email
+	if emailChanged {
+		other, err := u.userRepository.GetByEmail(ctx, *payload.Email)
+		if err != nil {
+			log.Error("Failed to get user by email", slog.String("error", err.Error()))
+			return nil, domain.ErrUpdateProfile
+		}
+
+		if other != nil {
+			log.Warn("Email already registered", slog.String("userID", user.ID.String()))
+			return nil, domain.ErrEmailAlreadyRegister
+		}
+
+		user.Email = *payload.Email
+		user.EmailVerifiedAt = nil
+	}
+
+	user.UpdatedAt = time.Now().UTC().Truncate(time.Millisecond)
+
+	updated, err := u.userRepository.UpdateProfile(ctx, user, version)
+	if err != nil {
+		log.Error("Failed to update profile", slog.String("error", err.Error()))
+		return nil, domain.ErrUpdateProfile
+	}
+
+	if !updated {
+		log.Warn("Profile updated concurrently", slog.String("userID", user.ID.String()))
+		return nil, domain.ErrProfileModified
+	}
+
+	if err := u.sessionService.UpdateProfile(ctx, user); err != nil {
+		log.Error("Failed to update profile of sessions", slog.String("error", err.Error()))
+	}
+
+	if emailChanged {
+		if err := u.sendEmailVerification(ctx, user); err != nil {
+			log.Error("Failed to send email verification", slog.String("error", err.Error()))
+		}
+		u.notifyEmailChange(ctx, user, previousEmail)
+	}
+
+	log.Info("Update profile process executed successfully", slog.String("userID", user.ID.String()), slog.Bool("emailChanged", emailChanged))
+	return user.ToProfileResponse(), nil
+}
+
+// VerifyPassword confirms the password of userID before a sensitive change
+// made with a session. Wrong passwords count towards the sign-in limits of
+// the email and IP, so a stolen session cannot be used to guess it.
+func (u *userService) VerifyPassword(ctx context.Context, userID uuid.UUID, password, ip string) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "VerifyPassword"),
+	)
+
+	user, err := u.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return domain.ErrVerifyPassword
+	}
+
+	if user == nil {
+		log.Warn("User not found", slog.String("userID", userID.String()))
+		return domain.ErrUserNotFound
+	}
+
+	attempt := &domain.SignInPayload{Email: user.Email, IP: ip}
+	if err := u.checkSignInAttempt(ctx, attempt); err != nil {
+		var retryAfterErr *domain.RetryAfterError
+		if !errors.As(err, &retryAfterErr) {
+			log.Error("Failed to check sign in attempts", slog.String("error", err.Error()))
+			return domain.ErrVerifyPassword
+		}
+
+		log.Warn("Password check refused", slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.passwordHasher.Verify(user.PasswordHash, password); err != nil {
+		log.Warn("The password entered is invalid", slog.String("userID", user.ID.String()))
+		return u.registerSignInFailure(ctx, attempt, user, domain.ErrInvalidPassword)
+	}
+
+	if err := u.signInAttemptRepository.Reset(ctx, user.Email); err != nil {
+		log.Warn("Failed to reset sign in attempts", slog.String("error", err.Error()))
+	}
+
+	return nil
+}
+
+// checkSignInAttempt refuses the attempt while the IP is over its failure
+// budget or the email is locked or waiting out a delay.
+func (u *userService) checkSignInAttempt(ctx context.Context, payload *domain.SignInPayload) error {
+	status, err := u.signInAttemptRepository.GetStatus(ctx, payload.Email, payload.IP)
+	if err != nil {
+		return domain.ErrCreateSession
+	}
+
+	if maxAttempts := config.Env.SignInIPMaxAttempts; maxAttempts > 0 && status.IPFailures >= maxAttempts {
+		return &domain.RetryAfterError{Err: domain.ErrTooManySignInAttempts, RetryAfter: status.IPRetryAfter}
+	}
+
+	if status.Lock > 0 {
+		return &domain.RetryAfterError{Err: domain.ErrAccountLocked, RetryAfter: status.Lock}
+	}
+
+	if status.Delay > 0 {
+		return &domain.RetryAfterError{Err: domain.ErrTooManySignInAttempts, RetryAfter: status.Delay}
+	}
+
+	return nil
+}
+
+// registerSignInFailure counts the failed attempt and returns the error for
+// the caller. Each failure doubles the wait before the next attempt, and
+// reaching SignInMaxAttempts locks the email for SignInLockoutDuration and
+// warns the owner. Zero limits disable the matching protection. Unknown
+// emails are throttled the same way so responses do not reveal which emails
+// are registered.
+func (u *userService) registerSignInFailure(ctx context.Context, payload *domain.SignInPayload, user *domain.User, cause error) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "registerSignInFailure"),
+	)
+
+	failures, err := u.signInAttemptRepository.RegisterFailure(ctx, payload.Email, payload.IP)
+	if err != nil {
+		log.Error("Failed to register sign in failure", slog.String("error", err.Error()))
+		return cause
+	}
+
+	lockout := config.Env.SignInLockoutDuration
+	if maxAttempts := config.Env.SignInMaxAttempts; maxAttempts > 0 && lockout > 0 && failures >= maxAttempts {
+		if err := u.signInAttemptRepository.Lock(ctx, payload.Email, lockout); err != nil {
+			log.Error("Failed to lock sign in", slog.String("error", err.Error()))
+			return cause
+		}
+
+		if user != nil {
+			log.Warn("Account locked after failed sign in attempts", slog.String("userID", user.ID.String()))
+			u.notifyLockout(ctx, user, payload.IP)
+		}
+
+		return &domain.RetryAfterError{Err: domain.ErrAccountLocked, RetryAfter: lockout}
+	}
+
+	if delay := signInDelay(failures); delay > 0 {
+		if err := u.signInAttemptRepository.SetDelay(ctx, payload.Email, delay); err != nil {
+			log.Error("Failed to set sign in delay", slog.String("error", err.Error()))
+		}
+	}
+
+	return cause
+}
+
+// signInDelay doubles SignInDelayBase for every failure after the first,
+// never waiting longer than the lockout itself.
+func signInDelay(failures int64) time.Duration {
+	base := config.Env.SignInDelayBase
+	if base <= 0 || failures < 1 {
+		return 0
+	}
+
+	lockout := config.Env.SignInLockoutDuration
+	delay := base << (failures - 1)
+	if lockout > 0 && (delay <= 0 || delay > lockout) {
+		return lockout
+	}
+
+	return delay
+}
+
+func (u *userService) notifyLockout(ctx context.Context, user *domain.User, ip string) {
+	email := &client.Email{
+		To:      user.Email,
+		Subject: "Your account was locked",
+		Text: fmt.Sprintf(
+			"Hi %s,\n\nWe locked sign-in to your account for %s after several attempts with a wrong password, the last one from %s.\n\nIf it was not you, reset your password once the lock expires.",
+			user.Name, config.Env.SignInLockoutDuration, ip,
+		),
+	}
+
+	if err := u.emailService.Send(ctx, email); err != nil {
+		domain.LoggerFromContext(ctx).Error("Failed to send lockout email", slog.String("service", "user"), slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+	}
+}
+
+func (u *userService) notifyEmailChange(ctx context.Context, user *domain.User, previousEmail string) {
+	email := &client.Email{
+		To:      previousEmail,
+		Subject: "Your email was changed",
+		Text: fmt.Sprintf(
+			"Hi %s,\n\nThe email of your account was changed to %s.\n\nIf it was not you, reset your password and contact support.",
+			user.Name, user.Email,
+		),
+	}
+
+	if err := u.emailService.Send(ctx, email); err != nil {
+		domain.LoggerFromContext(ctx).Error("Failed to send email change notice", slog.String("service", "user"), slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+	}
+}
+
+func (u *userService) sendEmailVerification(ctx context.Context, user *domain.User) error {
+	token, err := secure.GenerateToken(emailVerificationTokenSize)
+	if err != nil {
+		return err
+	}
+
+	if err := u.userRepository.CreateEmailVerification(ctx, user.ID, secure.HashToken(token)); err != nil {
+		return err
+	}
+
+	return u.emailService.Send(ctx, &client.Email{
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Text:    emailVerificationEmailText(user.Name, token),
+	})
+}
+
+// updatePassword stores the new password and signs the user out everywhere,
+// so whoever knew the old one loses access.
+func (u *userService) updatePassword(ctx context.Context, userID uuid.UUID, password string) error {
+	passwordHash, err := u.passwordHasher.Hash(password)
+	if err != nil {
+		return domain.ErrHashingPassword
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, userID, passwordHash); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrResetTokenInvalid
+		}
+		return domain.ErrUpdatePassword
+	}
+
+	if err := u.sessionService.RevokeAll(ctx, userID); err != nil {
+		return domain.ErrUpdatePassword
+	}
+
+	return nil
+}
+
+// rehashPassword upgrades the stored hash of a password that was just
+// verified when it was made with an older algorithm or cost. Failures only
+// postpone the upgrade to a later sign in.
+func (u *userService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "user"),
+		slog.String("func", "rehashPassword"),
+	)
+
+	if !u.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	passwordHash, err := u.passwordHasher.Hash(password)
+	if err != nil {
+		log.Error("Failed to rehash password", slog.String("error", err.Error()))
+		return
+	}
+
+	replaced, err := u.userRepository.ReplacePasswordHash(ctx, user.ID, user.PasswordHash, passwordHash)
+	if err != nil {
+		log.Error("Failed to store rehashed password", slog.String("error", err.Error()))
+		return
+	}
+
+	if replaced {
+		log.Info("Password hash upgraded", slog.String("userID", user.ID.String()))
+		user.PasswordHash = passwordHash
+	}
+}
+
+func passwordResetEmailText(name, token string) string {
+	link := token
+	if config.Env.PasswordResetURL != "" {
+		link = fmt.Sprintf("%s?token=%s", config.Env.PasswordResetURL, url.QueryEscape(token))
+	}
+
+	return fmt.Sprintf(
+		"Hi %s,\n\nWe received a request to reset your password. Use the link below within %s:\n\n%s\n\nIf you did not ask for it, ignore this email and your password will stay the same.",
+		name, config.Env.PasswordResetTTL, link,
+	)
+}
+
+func emailVerificationEmailText(name, token string) string {
+	link := token
+	if config.Env.EmailVerificationURL != "" {
+		link = fmt.Sprintf("%s?token=%s", config.Env.EmailVerificationURL, url.QueryEscape(token))
+	}
+
+	return fmt.Sprintf(
+		"Hi %s,\n\nConfirm your email to start creating wallets and making transfers. Use the link below within %s:\n\n%s\n\nIf you did not create an account, ignore this email.",
+		name, config.Env.EmailVerificationTTL, link,
+	)
 }
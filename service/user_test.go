@@ -3,15 +3,33 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/GSVillas/pic-pay-desafio/client"
+	"github.com/GSVillas/pic-pay-desafio/config"
 	"github.com/GSVillas/pic-pay-desafio/domain"
 	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/GSVillas/pic-pay-desafio/secure"
 	"github.com/GSVillas/pic-pay-desafio/utils"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
+// newTestPasswordHasher verifies any hash but creates bcrypt hashes at the
+// cost of utils.PasswordHash, so tests only see a rehash when they ask for it.
+func newTestPasswordHasher(t *testing.T) secure.PasswordHasher {
+	passwordHasher, err := secure.NewPasswordHasherWithParams(secure.PasswordHashParams{
+		Algorithm:  secure.PasswordHashBcrypt,
+		BcryptCost: 10,
+	})
+	assert.NoError(t, err)
+
+	return passwordHasher
+}
+
 func TestUserService_Create_WhenUserAlreadyExistsByEmail_ShouldReturnErrEmailAlreadyRegister(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -26,7 +44,8 @@ func TestUserService_Create_WhenUserAlreadyExistsByEmail_ShouldReturnErrEmailAlr
 
 	payload := &domain.UserPayload{
 		Name:            "Test User",
-		CPF:             "12345678901",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "12345678901",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        "password123",
@@ -41,7 +60,7 @@ func TestUserService_Create_WhenUserAlreadyExistsByEmail_ShouldReturnErrEmailAlr
 	assert.ErrorIs(t, err, domain.ErrEmailAlreadyRegister)
 }
 
-func TestUserService_Create_WhenUserAlreadyExistsByCPF_ShouldReturnErrCPFAlreadyRegister(t *testing.T) {
+func TestUserService_Create_WhenUserAlreadyExistsByDocument_ShouldReturnErrDocumentAlreadyRegister(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -55,7 +74,8 @@ func TestUserService_Create_WhenUserAlreadyExistsByCPF_ShouldReturnErrCPFAlready
 
 	payload := &domain.UserPayload{
 		Name:            "Test User",
-		CPF:             "12345678901",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "12345678901",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        "password123",
@@ -64,11 +84,11 @@ func TestUserService_Create_WhenUserAlreadyExistsByCPF_ShouldReturnErrCPFAlready
 
 	existingUser := &domain.User{}
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(nil, nil)
-	userRepositoryMock.EXPECT().GetByCPF(gomock.Any(), payload.CPF).Return(existingUser, nil)
+	userRepositoryMock.EXPECT().GetByDocument(gomock.Any(), payload.Document).Return(existingUser, nil)
 
 	err := userService.Create(context.Background(), payload)
 
-	assert.ErrorIs(t, err, domain.ErrCPFAlreadyRegister)
+	assert.ErrorIs(t, err, domain.ErrDocumentAlreadyRegister)
 }
 
 func TestUserService_Create_WhenCreateUserFails_ShouldReturnError(t *testing.T) {
@@ -81,11 +101,13 @@ func TestUserService_Create_WhenCreateUserFails_ShouldReturnError(t *testing.T)
 	userService := &userService{
 		userRepository: userRepositoryMock,
 		sessionService: sessionServiceMock,
+		passwordHasher: newTestPasswordHasher(t),
 	}
 
 	payload := &domain.UserPayload{
 		Name:            "Test User",
-		CPF:             "12345678901",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "12345678901",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        "password123",
@@ -93,7 +115,7 @@ func TestUserService_Create_WhenCreateUserFails_ShouldReturnError(t *testing.T)
 	}
 
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(nil, nil)
-	userRepositoryMock.EXPECT().GetByCPF(gomock.Any(), payload.CPF).Return(nil, nil)
+	userRepositoryMock.EXPECT().GetByDocument(gomock.Any(), payload.Document).Return(nil, nil)
 
 	userRepositoryMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
 
@@ -112,11 +134,13 @@ func TestUserService_Create_WhenHashingPasswordFails_ShouldReturnErrHashingPassw
 	userService := &userService{
 		userRepository: userRepositoryMock,
 		sessionService: sessionServiceMock,
+		passwordHasher: newTestPasswordHasher(t),
 	}
 
 	payload := &domain.UserPayload{
 		Name:            "Test User",
-		CPF:             "12345678901",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "12345678901",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        utils.LargeString,
@@ -124,7 +148,7 @@ func TestUserService_Create_WhenHashingPasswordFails_ShouldReturnErrHashingPassw
 	}
 
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(nil, nil)
-	userRepositoryMock.EXPECT().GetByCPF(gomock.Any(), payload.CPF).Return(nil, nil)
+	userRepositoryMock.EXPECT().GetByDocument(gomock.Any(), payload.Document).Return(nil, nil)
 
 	err := userService.Create(context.Background(), payload)
 
@@ -137,15 +161,19 @@ func TestUserService_Create_WhenSuccess_ShouldReturnNil(t *testing.T) {
 
 	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
 	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	emailServiceMock := mocks.NewMockEmailService(ctrl)
 
 	userService := &userService{
 		userRepository: userRepositoryMock,
 		sessionService: sessionServiceMock,
+		emailService:   emailServiceMock,
+		passwordHasher: newTestPasswordHasher(t),
 	}
 
 	payload := &domain.UserPayload{
 		Name:            "Test User",
-		CPF:             "12345678901",
+		DocumentType:    domain.DocumentTypeCPF,
+		Document:        "12345678901",
 		Email:           "test@example.com",
 		ConfirmEmail:    "test@example.com",
 		Password:        "password123",
@@ -153,9 +181,14 @@ func TestUserService_Create_WhenSuccess_ShouldReturnNil(t *testing.T) {
 	}
 
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(nil, nil)
-	userRepositoryMock.EXPECT().GetByCPF(gomock.Any(), payload.CPF).Return(nil, nil)
+	userRepositoryMock.EXPECT().GetByDocument(gomock.Any(), payload.Document).Return(nil, nil)
 
 	userRepositoryMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	userRepositoryMock.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	emailServiceMock.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, email *client.Email) error {
+		assert.Equal(t, payload.Email, email.To)
+		return nil
+	})
 
 	err := userService.Create(context.Background(), payload)
 
@@ -168,10 +201,12 @@ func TestUserService_SignIn_WhenUserNotFound_ShouldReturnErrUserNotFound(t *test
 
 	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
 	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
 
 	userService := &userService{
-		userRepository: userRepositoryMock,
-		sessionService: sessionServiceMock,
+		userRepository:          userRepositoryMock,
+		sessionService:          sessionServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
 	}
 
 	payload := &domain.SignInPayload{
@@ -179,7 +214,9 @@ func TestUserService_SignIn_WhenUserNotFound_ShouldReturnErrUserNotFound(t *test
 		Password: "password123",
 	}
 
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.SignInAttemptStatus{}, nil)
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(nil, nil)
+	signInAttemptRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
 
 	_, err := userService.SignIn(context.Background(), payload)
 
@@ -192,10 +229,13 @@ func TestUserService_SignIn_WhenPasswordIsInvalid_ShouldReturnErrInvalidPassword
 
 	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
 	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
 
 	userService := &userService{
-		userRepository: userRepositoryMock,
-		sessionService: sessionServiceMock,
+		userRepository:          userRepositoryMock,
+		sessionService:          sessionServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+		passwordHasher:          newTestPasswordHasher(t),
 	}
 
 	payload := &domain.SignInPayload{
@@ -208,7 +248,9 @@ func TestUserService_SignIn_WhenPasswordIsInvalid_ShouldReturnErrInvalidPassword
 		PasswordHash: "wrong_password",
 	}
 
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.SignInAttemptStatus{}, nil)
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(user, nil)
+	signInAttemptRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
 
 	_, err := userService.SignIn(context.Background(), payload)
 
@@ -221,10 +263,15 @@ func TestUserService_SignIn_WhenSuccess_ShouldReturnSignInResponseAndNil(t *test
 
 	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
 	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	twoFactorServiceMock := mocks.NewMockTwoFactorService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
 
 	userService := &userService{
-		userRepository: userRepositoryMock,
-		sessionService: sessionServiceMock,
+		userRepository:          userRepositoryMock,
+		sessionService:          sessionServiceMock,
+		twoFactorService:        twoFactorServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+		passwordHasher:          newTestPasswordHasher(t),
 	}
 
 	payload := &domain.SignInPayload{
@@ -237,8 +284,11 @@ func TestUserService_SignIn_WhenSuccess_ShouldReturnSignInResponseAndNil(t *test
 		PasswordHash: utils.PasswordHash,
 	}
 
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.SignInAttemptStatus{}, nil)
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(user, nil)
-	sessionServiceMock.EXPECT().Create(gomock.Any(), user).Return("validtoken", nil)
+	twoFactorServiceMock.EXPECT().IsEnabled(gomock.Any(), user.ID).Return(false, nil)
+	sessionServiceMock.EXPECT().Create(gomock.Any(), user, gomock.Any()).Return(&domain.SignInResponse{Token: "validtoken", RefreshToken: "refreshtoken"}, nil)
+	signInAttemptRepositoryMock.EXPECT().Reset(gomock.Any(), gomock.Any()).Return(nil)
 
 	response, err := userService.SignIn(context.Background(), payload)
 
@@ -253,10 +303,15 @@ func TestUserService_SignIn_WhenCreateSessionFails_ShouldReturnErrCreateSession(
 
 	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
 	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	twoFactorServiceMock := mocks.NewMockTwoFactorService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
 
 	userService := &userService{
-		userRepository: userRepositoryMock,
-		sessionService: sessionServiceMock,
+		userRepository:          userRepositoryMock,
+		sessionService:          sessionServiceMock,
+		twoFactorService:        twoFactorServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+		passwordHasher:          newTestPasswordHasher(t),
 	}
 
 	payload := &domain.SignInPayload{
@@ -269,11 +324,573 @@ func TestUserService_SignIn_WhenCreateSessionFails_ShouldReturnErrCreateSession(
 		PasswordHash: utils.PasswordHash,
 	}
 
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.SignInAttemptStatus{}, nil)
 	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(user, nil)
+	twoFactorServiceMock.EXPECT().IsEnabled(gomock.Any(), user.ID).Return(false, nil)
 
-	sessionServiceMock.EXPECT().Create(gomock.Any(), user).Return("", errors.New("session error"))
+	sessionServiceMock.EXPECT().Create(gomock.Any(), user, gomock.Any()).Return(nil, errors.New("session error"))
+	signInAttemptRepositoryMock.EXPECT().Reset(gomock.Any(), gomock.Any()).Return(nil)
 
 	_, err := userService.SignIn(context.Background(), payload)
 
 	assert.ErrorIs(t, err, domain.ErrCreateSession)
 }
+
+func TestUserService_SignIn_WhenTwoFactorEnabled_ShouldReturnChallengeWithoutSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	twoFactorServiceMock := mocks.NewMockTwoFactorService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		sessionService:          sessionServiceMock,
+		twoFactorService:        twoFactorServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+		passwordHasher:          newTestPasswordHasher(t),
+	}
+
+	payload := &domain.SignInPayload{
+		Email:    "test@example.com",
+		Password: utils.Password,
+	}
+
+	user := &domain.User{
+		ID:           uuid.New(),
+		Email:        payload.Email,
+		PasswordHash: utils.PasswordHash,
+	}
+
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.SignInAttemptStatus{}, nil)
+	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(user, nil)
+	twoFactorServiceMock.EXPECT().IsEnabled(gomock.Any(), user.ID).Return(true, nil)
+	twoFactorServiceMock.EXPECT().CreateChallenge(gomock.Any(), user.ID, gomock.Any()).Return("challenge", nil)
+
+	response, err := userService.SignIn(context.Background(), payload)
+
+	assert.NoError(t, err)
+	assert.True(t, response.TwoFactorRequired)
+	assert.Equal(t, "challenge", response.ChallengeToken)
+	assert.Empty(t, response.Token)
+}
+
+func TestUserService_SignInTwoFactor_ShouldResetSignInAttemptsOnlyAfterTheSecondFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	twoFactorServiceMock := mocks.NewMockTwoFactorService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		sessionService:          sessionServiceMock,
+		twoFactorService:        twoFactorServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+	}
+
+	payload := &domain.SignInTwoFactorPayload{ChallengeToken: "challenge", Code: "123456"}
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com"}
+	challenge := &domain.SignInChallenge{UserID: user.ID}
+
+	gomock.InOrder(
+		twoFactorServiceMock.EXPECT().CompleteChallenge(gomock.Any(), payload).Return(challenge, nil),
+		userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil),
+		signInAttemptRepositoryMock.EXPECT().Reset(gomock.Any(), user.Email).Return(nil),
+		sessionServiceMock.EXPECT().Create(gomock.Any(), user, &challenge.Device).Return(&domain.SignInResponse{Token: "validtoken"}, nil),
+	)
+
+	response, err := userService.SignInTwoFactor(context.Background(), payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "validtoken", response.Token)
+}
+
+func TestUserService_SignIn_WhenHashIsOutdated_ShouldRehashPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	twoFactorServiceMock := mocks.NewMockTwoFactorService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	passwordHasher, err := secure.NewPasswordHasherWithParams(secure.PasswordHashParams{
+		Algorithm:         secure.PasswordHashArgon2id,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	})
+	assert.NoError(t, err)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		sessionService:          sessionServiceMock,
+		twoFactorService:        twoFactorServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+		passwordHasher:          passwordHasher,
+	}
+
+	payload := &domain.SignInPayload{
+		Email:    "test@example.com",
+		Password: utils.Password,
+	}
+
+	user := &domain.User{
+		ID:           uuid.New(),
+		Email:        payload.Email,
+		PasswordHash: utils.PasswordHash,
+	}
+
+	var rehashed string
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.SignInAttemptStatus{}, nil)
+	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(user, nil)
+	signInAttemptRepositoryMock.EXPECT().Reset(gomock.Any(), gomock.Any()).Return(nil)
+	userRepositoryMock.EXPECT().ReplacePasswordHash(gomock.Any(), user.ID, utils.PasswordHash, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _, passwordHash string) (bool, error) {
+			rehashed = passwordHash
+			return true, nil
+		})
+	twoFactorServiceMock.EXPECT().IsEnabled(gomock.Any(), user.ID).Return(false, nil)
+	sessionServiceMock.EXPECT().Create(gomock.Any(), user, gomock.Any()).Return(&domain.SignInResponse{Token: "validtoken"}, nil)
+
+	_, err = userService.SignIn(context.Background(), payload)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rehashed, "$argon2id$"))
+	assert.NoError(t, passwordHasher.Verify(rehashed, utils.Password))
+	assert.False(t, passwordHasher.NeedsRehash(rehashed))
+}
+
+func TestUserService_ForgotPassword_WhenEmailUnknown_ShouldReturnNilWithoutEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	emailServiceMock := mocks.NewMockEmailService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		emailService:            emailServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+	}
+
+	signInAttemptRepositoryMock.EXPECT().RegisterPasswordReset(gomock.Any(), "unknown@example.com", "").Return(&domain.PasswordResetRequests{Email: 1}, nil)
+	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), "unknown@example.com").Return(nil, nil)
+
+	err := userService.ForgotPassword(context.Background(), &domain.ForgotPasswordPayload{Email: "unknown@example.com"})
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_ForgotPassword_WhenEmailIsOverItsBudget_ShouldReturnRetryAfterWithoutLookingUpTheUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+	}
+
+	config.Env.PasswordResetMaxRequests = 3
+	defer func() { config.Env.PasswordResetMaxRequests = 0 }()
+
+	signInAttemptRepositoryMock.EXPECT().RegisterPasswordReset(gomock.Any(), "test@example.com", "10.0.0.1").
+		Return(&domain.PasswordResetRequests{Email: 4, EmailRetryAfter: 30 * time.Minute, IP: 4}, nil)
+
+	err := userService.ForgotPassword(context.Background(), &domain.ForgotPasswordPayload{Email: "test@example.com", IP: "10.0.0.1"})
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.ErrorIs(t, err, domain.ErrTooManyResetRequests)
+	assert.Equal(t, 30*time.Minute, retryAfterErr.RetryAfter)
+}
+
+func TestUserService_ForgotPassword_WhenIPIsOverItsBudget_ShouldReturnRetryAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		signInAttemptRepository: signInAttemptRepositoryMock,
+	}
+
+	config.Env.PasswordResetIPMaxRequests = 20
+	defer func() { config.Env.PasswordResetIPMaxRequests = 0 }()
+
+	signInAttemptRepositoryMock.EXPECT().RegisterPasswordReset(gomock.Any(), "new@example.com", "10.0.0.1").
+		Return(&domain.PasswordResetRequests{Email: 1, IP: 21, IPRetryAfter: 10 * time.Minute}, nil)
+
+	err := userService.ForgotPassword(context.Background(), &domain.ForgotPasswordPayload{Email: "new@example.com", IP: "10.0.0.1"})
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.Equal(t, 10*time.Minute, retryAfterErr.RetryAfter)
+}
+
+func TestUserService_ResetPassword_WhenTokenInvalid_ShouldReturnErrResetTokenInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+	}
+
+	userRepositoryMock.EXPECT().TakePasswordReset(gomock.Any(), gomock.Any()).Return(uuid.Nil, nil)
+
+	err := userService.ResetPassword(context.Background(), &domain.ResetPasswordPayload{Token: "expired", Password: utils.Password, ConfirmPassword: utils.Password})
+
+	assert.ErrorIs(t, err, domain.ErrResetTokenInvalid)
+}
+
+func TestUserService_ChangePassword_WhenSuccess_ShouldRevokeAllSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+		sessionService: sessionServiceMock,
+		passwordHasher: newTestPasswordHasher(t),
+	}
+
+	user := &domain.User{
+		ID:           uuid.New(),
+		PasswordHash: utils.PasswordHash,
+	}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: user.ID})
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+	userRepositoryMock.EXPECT().UpdatePassword(gomock.Any(), user.ID, gomock.Any()).Return(nil)
+	sessionServiceMock.EXPECT().RevokeAll(gomock.Any(), user.ID).Return(nil)
+
+	err := userService.ChangePassword(ctx, &domain.ChangePasswordPayload{CurrentPassword: utils.Password, Password: "N3w@Password", ConfirmPassword: "N3w@Password"})
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_ResendEmailVerification_WhenSentRecently_ShouldReturnErrVerificationTooSoon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	emailServiceMock := mocks.NewMockEmailService(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+		emailService:   emailServiceMock,
+	}
+
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com"}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: user.ID})
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+	userRepositoryMock.EXPECT().HoldEmailVerificationResend(gomock.Any(), user.ID).Return(false, nil)
+
+	err := userService.ResendEmailVerification(ctx)
+
+	assert.ErrorIs(t, err, domain.ErrVerificationTooSoon)
+}
+
+func TestUserService_VerifyEmail_WhenTokenValid_ShouldMarkEmailVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+	}
+
+	userID := uuid.New()
+	userRepositoryMock.EXPECT().TakeEmailVerification(gomock.Any(), secure.HashToken("token")).Return(userID, nil)
+	userRepositoryMock.EXPECT().MarkEmailVerified(gomock.Any(), userID).Return(nil)
+
+	err := userService.VerifyEmail(context.Background(), &domain.VerifyEmailPayload{Token: "token"})
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_EnsureEmailVerified_WhenPending_ShouldReturnErrEmailNotVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+	}
+
+	user := &domain.User{ID: uuid.New()}
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+	err := userService.EnsureEmailVerified(context.Background(), user.ID)
+
+	assert.ErrorIs(t, err, domain.ErrEmailNotVerified)
+}
+
+func TestUserService_SignIn_WhenMaxAttemptsReached_ShouldLockAndNotifyUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	emailServiceMock := mocks.NewMockEmailService(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		emailService:            emailServiceMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+		passwordHasher:          newTestPasswordHasher(t),
+	}
+
+	config.Env.SignInMaxAttempts = 5
+	config.Env.SignInLockoutDuration = 15 * time.Minute
+	defer func() {
+		config.Env.SignInMaxAttempts = 0
+		config.Env.SignInLockoutDuration = 0
+	}()
+
+	payload := &domain.SignInPayload{Email: "test@example.com", Password: "wrong", IP: "10.0.0.1"}
+	user := &domain.User{ID: uuid.New(), Email: payload.Email, PasswordHash: utils.PasswordHash}
+
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), payload.Email, payload.IP).Return(&domain.SignInAttemptStatus{}, nil)
+	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), payload.Email).Return(user, nil)
+	signInAttemptRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), payload.Email, payload.IP).Return(int64(5), nil)
+	signInAttemptRepositoryMock.EXPECT().Lock(gomock.Any(), payload.Email, 15*time.Minute).Return(nil)
+	emailServiceMock.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := userService.SignIn(context.Background(), payload)
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+	assert.Equal(t, 15*time.Minute, retryAfterErr.RetryAfter)
+}
+
+func TestUserService_SignIn_WhenLocked_ShouldReturnRetryAfterWithoutCheckingPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+	}
+
+	payload := &domain.SignInPayload{Email: "test@example.com", Password: "password123"}
+
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), payload.Email, payload.IP).Return(&domain.SignInAttemptStatus{Lock: time.Minute}, nil)
+
+	_, err := userService.SignIn(context.Background(), payload)
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+	assert.Equal(t, time.Minute, retryAfterErr.RetryAfter)
+}
+
+func TestUserService_VerifyPassword_WhenPasswordIsWrong_ShouldCountASignInFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+		passwordHasher:          newTestPasswordHasher(t),
+	}
+
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: utils.PasswordHash}
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), user.Email, "10.0.0.1").Return(&domain.SignInAttemptStatus{}, nil)
+	signInAttemptRepositoryMock.EXPECT().RegisterFailure(gomock.Any(), user.Email, "10.0.0.1").Return(int64(1), nil)
+
+	err := userService.VerifyPassword(context.Background(), user.ID, "wrong", "10.0.0.1")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidPassword)
+}
+
+func TestUserService_VerifyPassword_WhenLocked_ShouldReturnRetryAfterWithoutCheckingPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	signInAttemptRepositoryMock := mocks.NewMockSignInAttemptRepository(ctrl)
+
+	userService := &userService{
+		userRepository:          userRepositoryMock,
+		signInAttemptRepository: signInAttemptRepositoryMock,
+	}
+
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", PasswordHash: utils.PasswordHash}
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+	signInAttemptRepositoryMock.EXPECT().GetStatus(gomock.Any(), user.Email, "10.0.0.1").Return(&domain.SignInAttemptStatus{Lock: time.Minute}, nil)
+
+	err := userService.VerifyPassword(context.Background(), user.ID, "password123", "10.0.0.1")
+
+	var retryAfterErr *domain.RetryAfterError
+	assert.ErrorAs(t, err, &retryAfterErr)
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+}
+
+func TestUserService_UpdateRole_WhenChangingOwnRole_ShouldReturnErrRoleSelfChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+	}
+
+	adminID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: adminID, Role: domain.RoleAdmin})
+
+	err := userService.UpdateRole(ctx, adminID, &domain.RolePayload{Role: domain.RoleCustomer})
+
+	assert.ErrorIs(t, err, domain.ErrRoleSelfChange)
+}
+
+func TestUserService_UpdateRole_WhenSuccess_ShouldUpdateLiveSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+		sessionService: sessionServiceMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: uuid.New(), Role: domain.RoleAdmin})
+
+	userRepositoryMock.EXPECT().UpdateRole(gomock.Any(), userID, domain.RoleSupport).Return(nil)
+	sessionServiceMock.EXPECT().UpdateRole(gomock.Any(), userID, domain.RoleSupport).Return(nil)
+
+	err := userService.UpdateRole(ctx, userID, &domain.RolePayload{Role: domain.RoleSupport})
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_GrantMerchantAdmin_WhenUserIsSupport_ShouldKeepRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+	}
+
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleSupport}
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+	err := userService.GrantMerchantAdmin(context.Background(), user.ID)
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_UpdateProfile_WhenETagIsStale_ShouldReturnErrProfileModified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+	}
+
+	user := &domain.User{ID: uuid.New(), Name: "Test User", UpdatedAt: time.Now().UTC()}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: user.ID})
+	name := "New Name"
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+	response, err := userService.UpdateProfile(ctx, &domain.UpdateProfilePayload{Name: &name}, `"0"`)
+
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, domain.ErrProfileModified)
+}
+
+func TestUserService_UpdateProfile_WhenEmailChanges_ShouldRequireVerificationAndNotifyOldEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+	sessionServiceMock := mocks.NewMockSessionService(ctrl)
+	emailServiceMock := mocks.NewMockEmailService(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+		sessionService: sessionServiceMock,
+		emailService:   emailServiceMock,
+	}
+
+	verifiedAt := time.Now().UTC()
+	version := time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+	user := &domain.User{ID: uuid.New(), Name: "Test User", Email: "old@example.com", EmailVerifiedAt: &verifiedAt, UpdatedAt: version}
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: user.ID})
+	email := "new@example.com"
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+	userRepositoryMock.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, nil)
+	userRepositoryMock.EXPECT().UpdateProfile(gomock.Any(), user, version).Return(true, nil)
+	sessionServiceMock.EXPECT().UpdateProfile(gomock.Any(), user).Return(nil)
+	userRepositoryMock.EXPECT().CreateEmailVerification(gomock.Any(), user.ID, gomock.Any()).Return(nil)
+
+	var recipients []string
+	emailServiceMock.EXPECT().Send(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(_ context.Context, email *client.Email) error {
+		recipients = append(recipients, email.To)
+		return nil
+	})
+
+	response, err := userService.UpdateProfile(ctx, &domain.UpdateProfilePayload{Email: &email}, user.ETag())
+
+	assert.NoError(t, err)
+	assert.Equal(t, email, response.Email)
+	assert.False(t, response.EmailVerified)
+	assert.NotEqual(t, version, user.UpdatedAt)
+	assert.ElementsMatch(t, []string{"new@example.com", "old@example.com"}, recipients)
+}
+
+func TestUserService_EnsureCompany_WhenUserHasCPF_ShouldReturnErrMerchantRequiresCNPJ(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepositoryMock := mocks.NewMockUserRepository(ctrl)
+
+	userService := &userService{
+		userRepository: userRepositoryMock,
+	}
+
+	user := &domain.User{ID: uuid.New(), DocumentType: domain.DocumentTypeCPF}
+
+	userRepositoryMock.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+	err := userService.EnsureCompany(context.Background(), user.ID)
+
+	assert.ErrorIs(t, err, domain.ErrMerchantRequiresCNPJ)
+}
@@ -9,8 +9,10 @@ import (
 )
 
 type walletService struct {
-	i                *do.Injector
-	walletRepository domain.WalletRepository
+	i                  *do.Injector
+	walletRepository   domain.WalletRepository
+	transferRepository domain.TransferRepository
+	userService        domain.UserService
 }
 
 func NewWalletService(i *do.Injector) (domain.WalletService, error) {
@@ -19,14 +21,26 @@ func NewWalletService(i *do.Injector) (domain.WalletService, error) {
 		return nil, err
 	}
 
+	transferRepository, err := do.Invoke[domain.TransferRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userService, err := do.Invoke[domain.UserService](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &walletService{
-		i:                i,
-		walletRepository: walletRepository,
+		i:                  i,
+		walletRepository:   walletRepository,
+		transferRepository: transferRepository,
+		userService:        userService,
 	}, nil
 }
 
 func (w *walletService) Create(ctx context.Context, payload *domain.WalletPayload) error {
-	log := slog.With(
+	log := domain.LoggerFromContext(ctx).With(
 		slog.String("service", "wallet"),
 		slog.String("func", "Create"),
 	)
@@ -38,23 +52,126 @@ func (w *walletService) Create(ctx context.Context, payload *domain.WalletPayloa
 		return domain.ErrSessionNotFound
 	}
 
-	wallet, err := w.walletRepository.GetByUserID(ctx, session.UserID)
+	if err := w.userService.EnsureEmailVerified(ctx, session.UserID); err != nil {
+		log.Warn("Wallet creation blocked", slog.String("userId", session.UserID.String()), slog.String("error", err.Error()))
+		return err
+	}
+
+	if payload.Type == domain.WalletTypeMERCHANT {
+		if err := w.userService.EnsureCompany(ctx, session.UserID); err != nil {
+			log.Warn("Merchant wallet creation blocked", slog.String("userId", session.UserID.String()), slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	wallets, err := w.walletRepository.GetAllByUserID(ctx, session.UserID)
 	if err != nil {
-		log.Error("Failed to get wallet by ", slog.String("userId", session.UserID.String()))
+		log.Error("Failed to get wallets by ", slog.String("userId", session.UserID.String()))
 		return err
 	}
 
-	if wallet != nil {
-		log.Warn("There is already a wallet for this user ", slog.String("userId", session.UserID.String()))
-		return domain.ErrWalletAlredyRegister
+	for _, wallet := range wallets {
+		if wallet.Currency == payload.Currency {
+			log.Warn("There is already a wallet for this user ", slog.String("userId", session.UserID.String()), slog.String("currency", payload.Currency))
+			return domain.ErrWalletAlredyRegister
+		}
+
+		if wallet.Type != payload.Type {
+			log.Warn("Wallet type differs from the user's other wallets", slog.String("userId", session.UserID.String()))
+			return domain.ErrWalletTypeMismatch
+		}
 	}
 
-	wallet = payload.ToWallet(session.UserID)
+	wallet := payload.ToWallet(session.UserID)
 	if err := w.walletRepository.Create(ctx, wallet); err != nil {
 		log.Error("Failed to create wallet", slog.String("error", err.Error()))
 		return err
 	}
 
+	if wallet.Type == domain.WalletTypeMERCHANT {
+		if err := w.userService.GrantMerchantAdmin(ctx, session.UserID); err != nil {
+			log.Error("Failed to grant merchant admin role", slog.String("userId", session.UserID.String()), slog.String("error", err.Error()))
+		}
+	}
+
 	log.Info("Wallet creation process executed successfully")
 	return nil
 }
+
+func (w *walletService) GetAll(ctx context.Context) ([]*domain.WalletResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "wallet"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get wallets process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	wallets, err := w.walletRepository.GetAllByUserID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get wallets", slog.String("error", err.Error()))
+		return nil, domain.ErrGetWallet
+	}
+
+	response := make([]*domain.WalletResponse, 0, len(wallets))
+	for _, wallet := range wallets {
+		response = append(response, wallet.ToResponse())
+	}
+
+	log.Info("Get wallets process executed successfully")
+	return response, nil
+}
+
+func (w *walletService) GetStatement(ctx context.Context, currency string, query *domain.StatementQuery) (*domain.StatementResponse, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("service", "wallet"),
+		slog.String("func", "GetStatement"),
+	)
+
+	log.Info("Initializing get statement process")
+
+	session, ok := ctx.Value(domain.SessionKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	currency = domain.NormalizeCurrency(currency)
+	if !session.AllowsCurrency(currency) {
+		log.Warn("Api key used for another wallet", slog.String("currency", currency))
+		return nil, domain.ErrAPIKeyWalletMismatch
+	}
+
+	wallet, err := w.walletRepository.GetByUserID(ctx, session.UserID, currency)
+	if err != nil {
+		log.Error("Failed to get wallet", slog.String("error", err.Error()))
+		return nil, domain.ErrGetStatement
+	}
+
+	if wallet == nil {
+		log.Warn("Wallet not found", slog.String("userId", session.UserID.String()), slog.String("currency", currency))
+		return nil, domain.ErrWalletNotFound
+	}
+
+	transfers, err := w.transferRepository.GetStatement(ctx, session.UserID, currency, query)
+	if err != nil {
+		log.Error("Failed to get statement transfers", slog.String("error", err.Error()))
+		return nil, domain.ErrGetStatement
+	}
+
+	response := &domain.StatementResponse{
+		Currency: wallet.Currency,
+		Balance:  wallet.Balance,
+		Entries:  make([]*domain.StatementEntry, 0, len(transfers)),
+	}
+
+	for _, transfer := range transfers {
+		response.Entries = append(response.Entries, transfer.ToStatementEntry(session.UserID, currency))
+	}
+
+	log.Info("Get statement process executed successfully", slog.Int("count", len(response.Entries)))
+	return response, nil
+}
@@ -1 +1,35 @@
 package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+	"github.com/GSVillas/pic-pay-desafio/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_Create_WhenMerchantWithoutCNPJ_ShouldReturnErrMerchantRequiresCNPJ(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	walletRepositoryMock := mocks.NewMockWalletRepository(ctrl)
+	userServiceMock := mocks.NewMockUserService(ctrl)
+
+	walletService := &walletService{
+		walletRepository: walletRepositoryMock,
+		userService:      userServiceMock,
+	}
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), domain.SessionKey, &domain.Session{UserID: userID})
+
+	userServiceMock.EXPECT().EnsureEmailVerified(gomock.Any(), userID).Return(nil)
+	userServiceMock.EXPECT().EnsureCompany(gomock.Any(), userID).Return(domain.ErrMerchantRequiresCNPJ)
+
+	err := walletService.Create(ctx, &domain.WalletPayload{Type: domain.WalletTypeMERCHANT, Currency: domain.DefaultCurrency})
+
+	assert.ErrorIs(t, err, domain.ErrMerchantRequiresCNPJ)
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/GSVillas/pic-pay-desafio/domain"
+)
+
+type localStorage struct {
+	basePath string
+}
+
+func newLocalStorage(basePath string) (FileStorage, error) {
+	if err := os.MkdirAll(basePath, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &localStorage{
+		basePath: basePath,
+	}, nil
+}
+
+func (l *localStorage) Save(ctx context.Context, key string, contentType string, content io.Reader, size int64) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("storage", "local"),
+		slog.String("func", "Save"),
+	)
+
+	log.Info("Initializing save file process", slog.String("key", key))
+
+	path := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		log.Error("Failed to create directory", slog.String("error", err.Error()))
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		log.Error("Failed to create file", slog.String("error", err.Error()))
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error("Failed to close file", slog.String("error", err.Error()))
+		}
+	}()
+
+	if _, err := io.Copy(file, content); err != nil {
+		log.Error("Failed to write file", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Save file process executed successfully", slog.String("key", key))
+	return nil
+}
+
+func (l *localStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(l.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrFileNotFound
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// path resolves key inside basePath, discarding any ".." that would escape it.
+func (l *localStorage) path(key string) string {
+	return filepath.Join(l.basePath, filepath.FromSlash(filepath.Clean("/"+key)))
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/GSVillas/pic-pay-desafio/domain"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Storage talks to any S3-compatible object storage (AWS S3, MinIO, R2...)
+// using plain HTTP requests signed with AWS Signature Version 4.
+type s3Storage struct {
+	httpClient *http.Client
+	endpoint   *url.URL
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool
+}
+
+func newS3Storage(httpClient *http.Client) (FileStorage, error) {
+	endpoint, err := url.Parse(config.Env.S3Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Host == "" || config.Env.S3Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket must be configured")
+	}
+
+	return &s3Storage{
+		httpClient: httpClient,
+		endpoint:   endpoint,
+		region:     config.Env.S3Region,
+		bucket:     config.Env.S3Bucket,
+		accessKey:  config.Env.S3AccessKey,
+		secretKey:  config.Env.S3SecretKey,
+		pathStyle:  config.Env.S3UsePathStyle,
+	}, nil
+}
+
+func (s *s3Storage) Save(ctx context.Context, key string, contentType string, content io.Reader, size int64) error {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("storage", "s3"),
+		slog.String("func", "Save"),
+	)
+
+	log.Info("Initializing save object process", slog.String("key", key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), content)
+	if err != nil {
+		log.Error("Failed to create request", slog.String("error", err.Error()))
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		log.Error("Failed to perform HTTP request", slog.String("error", err.Error()))
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error("Failed to close response body", slog.String("error", err.Error()))
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Warn("Unexpected status code received", slog.Int("statusCode", resp.StatusCode))
+		return ErrUnexpectedResponse(resp.StatusCode)
+	}
+
+	log.Info("Save object process executed successfully", slog.String("key", key))
+	return nil
+}
+
+func (s *s3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	log := domain.LoggerFromContext(ctx).With(
+		slog.String("storage", "s3"),
+		slog.String("func", "Open"),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		log.Error("Failed to create request", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		log.Error("Failed to perform HTTP request", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		log.Error("Failed to close response body", slog.String("error", err.Error()))
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrFileNotFound
+	}
+
+	log.Warn("Unexpected status code received", slog.Int("statusCode", resp.StatusCode))
+	return nil, ErrUnexpectedResponse(resp.StatusCode)
+}
+
+func (s *s3Storage) objectURL(key string) string {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+
+	u.RawPath = uriEncode(u.Path)
+	return u.String()
+}
+
+func (s *s3Storage) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, unsignedPayload, amzDate)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(hashedRequest[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode applies the SigV4 URI encoding: everything but unreserved
+// characters and the path separator is percent-encoded.
+func uriEncode(path string) string {
+	var builder strings.Builder
+	for _, b := range []byte(path) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,49 @@
+package storage
+
+//go:generate mockgen -source=storage.go -destination=../mocks/storage_mock.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/GSVillas/pic-pay-desafio/config"
+	"github.com/samber/do"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrFileNotFound       = errors.New("file not found in storage")
+	ErrUnsupportedDriver  = errors.New("unsupported storage driver")
+	ErrUnexpectedResponse = func(statusCode int) error {
+		return fmt.Errorf("unexpected status code from object storage: %d", statusCode)
+	}
+)
+
+// FileStorage persists uploaded files such as dispute evidence. Keys are
+// slash separated paths relative to the storage root or bucket.
+type FileStorage interface {
+	Save(ctx context.Context, key string, contentType string, content io.Reader, size int64) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+func NewFileStorage(i *do.Injector) (FileStorage, error) {
+	switch config.Env.StorageDriver {
+	case DriverLocal:
+		return newLocalStorage(config.Env.StorageLocalPath)
+	case DriverS3:
+		httpClient, err := do.Invoke[*http.Client](i)
+		if err != nil {
+			return nil, err
+		}
+		return newS3Storage(httpClient)
+	}
+
+	return nil, ErrUnsupportedDriver
+}